@@ -18,15 +18,21 @@ import (
 	"google.golang.org/grpc/credentials/insecure"
 	"google.golang.org/grpc/reflection"
 	"google.golang.org/protobuf/encoding/protojson"
+	"k8s.io/apimachinery/pkg/util/wait"
 	"k8s.io/klog/v2"
 
 	grpc_middleware "github.com/grpc-ecosystem/go-grpc-middleware"
 	grpc_prometheus "github.com/grpc-ecosystem/go-grpc-prometheus"
+	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"github.com/ray-project/kuberay/apiserver/pkg/auth"
+	"github.com/ray-project/kuberay/apiserver/pkg/client"
 	"github.com/ray-project/kuberay/apiserver/pkg/interceptor"
 	"github.com/ray-project/kuberay/apiserver/pkg/manager"
+	"github.com/ray-project/kuberay/apiserver/pkg/metrics"
 	"github.com/ray-project/kuberay/apiserver/pkg/server"
 	"github.com/ray-project/kuberay/apiserver/pkg/swagger"
+	"github.com/ray-project/kuberay/apiserver/pkg/util"
 	api "github.com/ray-project/kuberay/proto/go_client"
 )
 
@@ -36,11 +42,23 @@ var (
 	collectMetricsFlag = flag.Bool("collectMetricsFlag", true, "Whether to collect Prometheus metrics in API server.")
 	logFile            = flag.String("logFilePath", "", "Synchronize logs to local file")
 	localSwaggerPath   = flag.String("localSwaggerPath", "", "Specify the root directory for `*.swagger.json` the swagger files.")
+	authTokenFile      = flag.String("authTokenFile", "", "Path to a static bearer token CSV file (token,user,uid,\"group1,group2\"). Enables authentication.")
+	oidcJWKSFile       = flag.String("oidcJWKSFile", "", "Path to a local JWKS file used to validate OIDC JWT bearer tokens. Enables authentication.")
+	oidcIssuer         = flag.String("oidcIssuer", "", "Required iss claim of OIDC JWT bearer tokens. Must be set with --oidcJWKSFile.")
+	oidcAudience       = flag.String("oidcAudience", "", "Required aud claim of OIDC JWT bearer tokens. Must be set with --oidcJWKSFile.")
+	oidcUsernameClaim  = flag.String("oidcUsernameClaim", "sub", "JWT claim used as the user name.")
+	oidcGroupsClaim    = flag.String("oidcGroupsClaim", "groups", "JWT claim used as the user groups.")
+	oidcUsernamePrefix = flag.String("oidcUsernamePrefix", "oidc-", "Prefix prepended to OIDC user names so that they can not collide with static token users. Empty disables it.")
+	oidcGroupsPrefix   = flag.String("oidcGroupsPrefix", "oidc-", "Prefix prepended to OIDC groups so that they can not collide with static token groups. Empty disables it.")
+	authPolicyFile     = flag.String("authPolicyFile", "", "Path to a JSON authorization policy with admins and namespace owners.")
 	healthy            int32
 )
 
 func main() {
 	flag.Parse()
+	if *oidcJWKSFile != "" && (*oidcIssuer == "" || *oidcAudience == "") {
+		klog.Fatal("--oidcIssuer and --oidcAudience are required when --oidcJWKSFile is set")
+	}
 
 	if *logFile != "" {
 		flagSet := flag.NewFlagSet(os.Args[0], flag.ExitOnError)
@@ -80,27 +98,48 @@ func startRpcServer(resourceManager *manager.ResourceManager) {
 
 	clusterServer := server.NewClusterServer(resourceManager, &server.ClusterServerOptions{CollectMetrics: *collectMetricsFlag})
 	templateServer := server.NewComputeTemplateServer(resourceManager, &server.ComputeTemplateServerOptions{CollectMetrics: *collectMetricsFlag})
+	imageTemplateServer := server.NewImageTemplateServer(resourceManager, &server.ImageTemplateServerOptions{CollectMetrics: *collectMetricsFlag})
 	jobServer := server.NewRayJobServer(resourceManager, &server.JobServerOptions{CollectMetrics: *collectMetricsFlag})
 	jobSubmissionServer := server.NewRayJobSubmissionServiceServer(clusterServer, &server.RayJobSubmissionServiceServerOptions{CollectMetrics: *collectMetricsFlag})
 	serveServer := server.NewRayServiceServer(resourceManager, &server.ServiceServerOptions{CollectMetrics: *collectMetricsFlag})
 
+	var unaryInterceptors []grpc.UnaryServerInterceptor
+	var streamInterceptors []grpc.StreamServerInterceptor
+	if *collectMetricsFlag {
+		unaryInterceptors = append(unaryInterceptors, grpc_prometheus.UnaryServerInterceptor)
+		streamInterceptors = append(streamInterceptors, grpc_prometheus.StreamServerInterceptor)
+	}
+	unaryInterceptors = append(unaryInterceptors, interceptor.ApiServerInterceptor)
+	streamInterceptors = append(streamInterceptors, interceptor.ApiServerStreamInterceptor)
+	if authenticator, authorizer := newAuth(resourceManager); authenticator != nil {
+		unaryInterceptors = append(unaryInterceptors, interceptor.AuthInterceptor(authenticator, authorizer))
+		streamInterceptors = append(streamInterceptors, interceptor.AuthStreamInterceptor(authenticator, authorizer))
+	}
+	// The access log runs after the auth interceptors to record the authenticated user.
+	unaryInterceptors = append(unaryInterceptors, interceptor.AccessLogInterceptor)
+	streamInterceptors = append(streamInterceptors, interceptor.AccessLogStreamInterceptor)
+
 	s := grpc.NewServer(
-		grpc.StreamInterceptor(grpc_prometheus.StreamServerInterceptor),
-		grpc.UnaryInterceptor(grpc_middleware.ChainUnaryServer(grpc_prometheus.UnaryServerInterceptor, interceptor.ApiServerInterceptor)),
+		grpc.StreamInterceptor(grpc_middleware.ChainStreamServer(streamInterceptors...)),
+		grpc.UnaryInterceptor(grpc_middleware.ChainUnaryServer(unaryInterceptors...)),
 		grpc.MaxRecvMsgSize(math.MaxInt32))
 	api.RegisterClusterServiceServer(s, clusterServer)
 	api.RegisterComputeTemplateServiceServer(s, templateServer)
+	api.RegisterImageTemplateServiceServer(s, imageTemplateServer)
 	api.RegisterRayJobServiceServer(s, jobServer)
 	api.RegisterRayJobSubmissionServiceServer(s, jobSubmissionServer)
 	api.RegisterRayServeServiceServer(s, serveServer)
 
 	// Register reflection service on gRPC server.
 	reflection.Register(s)
-	// Make sure all of the Prometheus metrics are initialized.
-	grpc_prometheus.Register(s)
-	// This is to enable `grpc_server_handling_seconds`, otherwise we won't have latency metrics.
-	// see https://github.com/grpc-ecosystem/go-grpc-prometheus/blob/master/README.md#histograms for details.
-	grpc_prometheus.EnableHandlingTimeHistogram()
+	if *collectMetricsFlag {
+		// Make sure all of the Prometheus metrics are initialized.
+		grpc_prometheus.Register(s)
+		// This is to enable `grpc_server_handling_seconds`, otherwise we won't have latency metrics.
+		// see https://github.com/grpc-ecosystem/go-grpc-prometheus/blob/master/README.md#histograms for details.
+		grpc_prometheus.EnableHandlingTimeHistogram()
+		prometheus.MustRegister(newResourceCollector())
+	}
 	if err := s.Serve(listener); err != nil {
 		klog.Fatalf("Failed to serve gRPC listener: %v", err)
 	}
@@ -108,6 +147,66 @@ func startRpcServer(resourceManager *manager.ResourceManager) {
 	klog.Info("gRPC server started")
 }
 
+// newResourceCollector builds the resource metrics collector and starts the informers it reads from.
+func newResourceCollector() *metrics.ResourceCollector {
+	factory := client.NewRayInformerFactoryOrFatal(0, util.ClientOptions{QPS: 5, Burst: 10})
+	collector := metrics.NewResourceCollector(factory)
+	factory.Start(wait.NeverStop)
+	for informer, synced := range factory.WaitForCacheSync(wait.NeverStop) {
+		if !synced {
+			klog.Fatalf("Failed to sync the %v informer cache", informer)
+		}
+	}
+	return collector
+}
+
+// newAuth builds the authenticator and the authorizer from the auth flags.
+// It returns a nil authenticator if neither a token file nor a JWKS file is configured.
+func newAuth(resourceManager *manager.ResourceManager) (auth.Authenticator, *auth.Authorizer) {
+	var authenticators auth.UnionAuthenticator
+	if *authTokenFile != "" {
+		authenticator, err := auth.NewStaticTokenAuthenticatorFromFile(*authTokenFile)
+		if err != nil {
+			klog.Fatalf("Failed to load static token authenticator: %v", err)
+		}
+		if *oidcJWKSFile != "" {
+			// The prefixes keep OIDC principals apart from static token users only if no static name carries them.
+			if err := authenticator.RejectPrefixes(*oidcUsernamePrefix, *oidcGroupsPrefix); err != nil {
+				klog.Fatalf("Failed to load static token authenticator: %v", err)
+			}
+		}
+		authenticators = append(authenticators, authenticator)
+	}
+	if *oidcJWKSFile != "" {
+		authenticator, err := auth.NewJWTAuthenticatorFromFile(*oidcJWKSFile, auth.JWTAuthenticatorOptions{
+			Issuer:         *oidcIssuer,
+			Audience:       *oidcAudience,
+			UsernameClaim:  *oidcUsernameClaim,
+			GroupsClaim:    *oidcGroupsClaim,
+			UsernamePrefix: *oidcUsernamePrefix,
+			GroupsPrefix:   *oidcGroupsPrefix,
+		})
+		if err != nil {
+			klog.Fatalf("Failed to load OIDC JWT authenticator: %v", err)
+		}
+		authenticators = append(authenticators, authenticator)
+	}
+	if len(authenticators) == 0 {
+		klog.Warning("No authenticator is configured, API requests are not authenticated")
+		return nil, nil
+	}
+
+	policy := &auth.Policy{}
+	if *authPolicyFile != "" {
+		var err error
+		if policy, err = auth.LoadPolicyFile(*authPolicyFile); err != nil {
+			klog.Fatalf("Failed to load authorization policy: %v", err)
+		}
+	}
+
+	return authenticators, auth.NewAuthorizer(policy, auth.NewResourceOwnerLookup(resourceManager))
+}
+
 func startHttpProxy() {
 	klog.Info("Starting Http Proxy")
 
@@ -116,21 +215,25 @@ func startHttpProxy() {
 	defer cancel()
 
 	// Create gRPC HTTP MUX and register services.
+	jsonMarshaler := &runtime.JSONPb{
+		MarshalOptions: protojson.MarshalOptions{
+			UseProtoNames:  false,
+			UseEnumNumbers: true,
+		},
+		UnmarshalOptions: protojson.UnmarshalOptions{
+			DiscardUnknown: true,
+		},
+	}
 	runtimeMux := runtime.NewServeMux(
-		runtime.WithMarshalerOption(runtime.MIMEWildcard, &runtime.JSONPb{
-			MarshalOptions: protojson.MarshalOptions{
-				UseProtoNames:  false,
-				UseEnumNumbers: true,
-			},
-			UnmarshalOptions: protojson.UnmarshalOptions{
-				DiscardUnknown: true,
-			},
-		}),
+		runtime.WithMarshalerOption(runtime.MIMEWildcard, jsonMarshaler),
+		// Streaming endpoints like job log tailing are served as server-sent events on request.
+		runtime.WithMarshalerOption(util.EventStreamContentType, &util.EventStreamMarshaler{Marshaler: jsonMarshaler}),
 		runtime.WithErrorHandler(runtime.DefaultHTTPErrorHandler),
 	)
 	// Register endpoints
 	registerHttpHandlerFromEndpoint(api.RegisterClusterServiceHandlerFromEndpoint, "ClusterService", ctx, runtimeMux)
 	registerHttpHandlerFromEndpoint(api.RegisterComputeTemplateServiceHandlerFromEndpoint, "ComputeTemplateService", ctx, runtimeMux)
+	registerHttpHandlerFromEndpoint(api.RegisterImageTemplateServiceHandlerFromEndpoint, "ImageTemplateService", ctx, runtimeMux)
 	registerHttpHandlerFromEndpoint(api.RegisterRayJobServiceHandlerFromEndpoint, "JobService", ctx, runtimeMux)
 	registerHttpHandlerFromEndpoint(api.RegisterRayServeServiceHandlerFromEndpoint, "ServeService", ctx, runtimeMux)
 	registerHttpHandlerFromEndpoint(api.RegisterRayJobSubmissionServiceHandlerFromEndpoint, "RayJobSubmissionService", ctx, runtimeMux)
@@ -0,0 +1,66 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Principal is the authenticated identity that issued an API request.
+type Principal struct {
+	Name   string
+	Groups []string
+}
+
+// Authenticator verifies a bearer token and returns the identity it belongs to.
+type Authenticator interface {
+	Authenticate(token string) (*Principal, error)
+}
+
+// ErrUnknownToken is returned when an authenticator does not recognize the presented token.
+var ErrUnknownToken = errors.New("unknown bearer token")
+
+// UnionAuthenticator tries each authenticator in order and returns the first identity that is accepted.
+// The authenticators share one name namespace, so their principal names must not overlap.
+type UnionAuthenticator []Authenticator
+
+func (u UnionAuthenticator) Authenticate(token string) (*Principal, error) {
+	var errs []string
+	for _, authenticator := range u {
+		principal, err := authenticator.Authenticate(token)
+		if err == nil {
+			return principal, nil
+		}
+		errs = append(errs, err.Error())
+	}
+	if len(errs) == 0 {
+		return nil, ErrUnknownToken
+	}
+	return nil, fmt.Errorf("%s", strings.Join(errs, "; "))
+}
+
+// BearerToken extracts the token from an `Authorization: Bearer <token>` header value.
+func BearerToken(header string) (string, error) {
+	if header == "" {
+		return "", errors.New("missing authorization header")
+	}
+	parts := strings.SplitN(header, " ", 2)
+	if len(parts) != 2 || !strings.EqualFold(parts[0], "Bearer") || strings.TrimSpace(parts[1]) == "" {
+		return "", errors.New("authorization header is not a bearer token")
+	}
+	return strings.TrimSpace(parts[1]), nil
+}
+
+type principalKey struct{}
+
+// NewContext returns a copy of ctx that carries the principal.
+func NewContext(ctx context.Context, principal *Principal) context.Context {
+	return context.WithValue(ctx, principalKey{}, principal)
+}
+
+// FromContext returns the principal stored in ctx, if any.
+func FromContext(ctx context.Context) (*Principal, bool) {
+	principal, ok := ctx.Value(principalKey{}).(*Principal)
+	return principal, ok
+}
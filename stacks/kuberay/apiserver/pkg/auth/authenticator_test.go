@@ -0,0 +1,286 @@
+package auth
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStaticTokenAuthenticator(t *testing.T) {
+	authenticator, err := NewStaticTokenAuthenticator(strings.NewReader(`# token,user,uid,groups
+token-alice,alice,1,"admins,dev"
+token-bob,bob
+`))
+	require.NoError(t, err)
+
+	principal, err := authenticator.Authenticate("token-alice")
+	require.NoError(t, err)
+	assert.Equal(t, &Principal{Name: "alice", Groups: []string{"admins", "dev"}}, principal)
+
+	principal, err = authenticator.Authenticate("token-bob")
+	require.NoError(t, err)
+	assert.Equal(t, &Principal{Name: "bob"}, principal)
+
+	_, err = authenticator.Authenticate("token-eve")
+	assert.ErrorIs(t, err, ErrUnknownToken)
+
+	_, err = NewStaticTokenAuthenticator(strings.NewReader("token-only\n"))
+	assert.Error(t, err)
+}
+
+func TestBearerToken(t *testing.T) {
+	token, err := BearerToken("Bearer abc")
+	require.NoError(t, err)
+	assert.Equal(t, "abc", token)
+
+	token, err = BearerToken("bearer abc")
+	require.NoError(t, err)
+	assert.Equal(t, "abc", token)
+
+	for _, header := range []string{"", "Basic abc", "Bearer", "Bearer  "} {
+		_, err := BearerToken(header)
+		assert.Error(t, err, header)
+	}
+}
+
+func encodeSegment(t *testing.T, v interface{}) string {
+	data, err := json.Marshal(v)
+	require.NoError(t, err)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+func encodeBigInt(i *big.Int) string {
+	return base64.RawURLEncoding.EncodeToString(i.Bytes())
+}
+
+func TestJWTAuthenticator(t *testing.T) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	jwks := fmt.Sprintf(`{"keys": [
+		{"kty": "RSA", "kid": "rsa", "use": "sig", "n": %q, "e": %q},
+		{"kty": "EC", "kid": "ec", "crv": "P-256", "x": %q, "y": %q},
+		{"kty": "oct", "kid": "enc", "use": "enc", "k": "c2VjcmV0"}
+	]}`, encodeBigInt(rsaKey.N), encodeBigInt(big.NewInt(int64(rsaKey.E))), encodeBigInt(ecKey.X), encodeBigInt(ecKey.Y))
+
+	authenticator, err := NewJWTAuthenticator([]byte(jwks), JWTAuthenticatorOptions{
+		Issuer:        "https://issuer.example.com",
+		Audience:      "kuberay",
+		UsernameClaim: "email",
+	})
+	require.NoError(t, err)
+	now := time.Unix(1700000000, 0)
+	authenticator.now = func() time.Time { return now }
+
+	sign := func(alg, kid string, claims map[string]interface{}) string {
+		input := encodeSegment(t, map[string]string{"alg": alg, "kid": kid, "typ": "JWT"}) + "." + encodeSegment(t, claims)
+		digest := sha256.Sum256([]byte(input))
+		var signature []byte
+		switch alg {
+		case "RS256":
+			signature, err = rsa.SignPKCS1v15(rand.Reader, rsaKey, crypto.SHA256, digest[:])
+			require.NoError(t, err)
+		case "ES256":
+			r, s, err := ecdsa.Sign(rand.Reader, ecKey, digest[:])
+			require.NoError(t, err)
+			signature = make([]byte, 64)
+			r.FillBytes(signature[:32])
+			s.FillBytes(signature[32:])
+		}
+		return input + "." + base64.RawURLEncoding.EncodeToString(signature)
+	}
+	validClaims := func() map[string]interface{} {
+		return map[string]interface{}{
+			"iss":    "https://issuer.example.com",
+			"aud":    []string{"other", "kuberay"},
+			"email":  "alice@example.com",
+			"groups": []string{"dev"},
+			"exp":    now.Add(time.Hour).Unix(),
+		}
+	}
+
+	principal, err := authenticator.Authenticate(sign("RS256", "rsa", validClaims()))
+	require.NoError(t, err)
+	assert.Equal(t, &Principal{Name: "alice@example.com", Groups: []string{"dev"}}, principal)
+
+	principal, err = authenticator.Authenticate(sign("ES256", "", validClaims()))
+	require.NoError(t, err)
+	assert.Equal(t, "alice@example.com", principal.Name)
+
+	// Prefixed names can not collide with static token users.
+	prefixed, err := NewJWTAuthenticator([]byte(jwks), JWTAuthenticatorOptions{
+		Issuer:         "https://issuer.example.com",
+		Audience:       "kuberay",
+		UsernamePrefix: "oidc-",
+		GroupsPrefix:   "oidc-",
+	})
+	require.NoError(t, err)
+	prefixed.now = authenticator.now
+	adminClaims := validClaims()
+	adminClaims["sub"] = "admin"
+	principal, err = prefixed.Authenticate(sign("RS256", "rsa", adminClaims))
+	require.NoError(t, err)
+	assert.Equal(t, &Principal{Name: "oidc-admin", Groups: []string{"oidc-dev"}}, principal)
+
+	// Tokens that expired within the clock skew leeway are still accepted.
+	skewedClaims := validClaims()
+	skewedClaims["exp"] = now.Add(-30 * time.Second).Unix()
+	_, err = authenticator.Authenticate(sign("RS256", "rsa", skewedClaims))
+	require.NoError(t, err)
+
+	tests := []struct {
+		name   string
+		token  func() string
+		errMsg string
+	}{
+		{
+			name:   "not a JWT",
+			token:  func() string { return "static-token" },
+			errMsg: ErrUnknownToken.Error(),
+		},
+		{
+			name:   "unknown key",
+			token:  func() string { return sign("RS256", "missing", validClaims()) },
+			errMsg: `JWT is signed by unknown key "missing"`,
+		},
+		{
+			name:   "unsupported algorithm",
+			token:  func() string { return sign("HS256", "rsa", validClaims()) },
+			errMsg: `unsupported JWT signing algorithm "HS256"`,
+		},
+		{
+			name:   "key does not match algorithm",
+			token:  func() string { return sign("ES256", "rsa", validClaims()) },
+			errMsg: "JWT signature verification failed",
+		},
+		{
+			name: "tampered claims",
+			token: func() string {
+				parts := strings.Split(sign("RS256", "rsa", validClaims()), ".")
+				claims := validClaims()
+				claims["email"] = "eve@example.com"
+				return parts[0] + "." + encodeSegment(t, claims) + "." + parts[2]
+			},
+			errMsg: "JWT signature verification failed",
+		},
+		{
+			name: "expired",
+			token: func() string {
+				claims := validClaims()
+				claims["exp"] = now.Add(-5 * time.Minute).Unix()
+				return sign("RS256", "rsa", claims)
+			},
+			errMsg: "JWT has expired",
+		},
+		{
+			name: "not valid yet",
+			token: func() string {
+				claims := validClaims()
+				claims["nbf"] = now.Add(5 * time.Minute).Unix()
+				return sign("RS256", "rsa", claims)
+			},
+			errMsg: "JWT is not valid yet",
+		},
+		{
+			name: "wrong issuer",
+			token: func() string {
+				claims := validClaims()
+				claims["iss"] = "https://evil.example.com"
+				return sign("RS256", "rsa", claims)
+			},
+			errMsg: `JWT issuer "https://evil.example.com" is not trusted`,
+		},
+		{
+			name: "wrong audience",
+			token: func() string {
+				claims := validClaims()
+				claims["aud"] = "other"
+				return sign("RS256", "rsa", claims)
+			},
+			errMsg: `JWT is not issued for audience "kuberay"`,
+		},
+		{
+			name: "missing username",
+			token: func() string {
+				claims := validClaims()
+				delete(claims, "email")
+				return sign("RS256", "rsa", claims)
+			},
+			errMsg: `JWT does not contain the username claim "email"`,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := authenticator.Authenticate(tc.token())
+			require.EqualError(t, err, tc.errMsg)
+		})
+	}
+}
+
+func TestJWTAuthenticatorRequiresIssuerAndAudience(t *testing.T) {
+	_, err := NewJWTAuthenticator([]byte(`{"keys": []}`), JWTAuthenticatorOptions{Issuer: "https://issuer.example.com"})
+	assert.ErrorContains(t, err, "audience")
+	_, err = NewJWTAuthenticator([]byte(`{"keys": []}`), JWTAuthenticatorOptions{Audience: "kuberay"})
+	assert.ErrorContains(t, err, "issuer")
+}
+
+func TestJWTAuthenticatorValidatesPrefixes(t *testing.T) {
+	for _, prefix := range []string{"-oidc", "https://issuer.example.com#", "oidc:"} {
+		_, err := NewJWTAuthenticator([]byte(`{"keys": []}`), JWTAuthenticatorOptions{
+			Issuer:         "https://issuer.example.com",
+			Audience:       "kuberay",
+			UsernamePrefix: prefix,
+		})
+		assert.ErrorContains(t, err, "prefix", prefix)
+		_, err = NewJWTAuthenticator([]byte(`{"keys": []}`), JWTAuthenticatorOptions{
+			Issuer:       "https://issuer.example.com",
+			Audience:     "kuberay",
+			GroupsPrefix: prefix,
+		})
+		assert.ErrorContains(t, err, "prefix", prefix)
+	}
+}
+
+func TestStaticTokenAuthenticatorRejectPrefixes(t *testing.T) {
+	static, err := NewStaticTokenAuthenticator(strings.NewReader("token-bob,bob,,\"dev,ops\"\n"))
+	require.NoError(t, err)
+	require.NoError(t, static.RejectPrefixes("oidc-", "oidc-"))
+	require.NoError(t, static.RejectPrefixes("", ""))
+
+	static, err = NewStaticTokenAuthenticator(strings.NewReader("token-bob,oidc-bob\n"))
+	require.NoError(t, err)
+	assert.EqualError(t, static.RejectPrefixes("oidc-", ""), `static token user "oidc-bob" must not start with the reserved prefix "oidc-"`)
+
+	static, err = NewStaticTokenAuthenticator(strings.NewReader("token-bob,bob,,\"dev,oidc-ops\"\n"))
+	require.NoError(t, err)
+	assert.NoError(t, static.RejectPrefixes("oidc-", ""))
+	assert.EqualError(t, static.RejectPrefixes("", "oidc-"), `static token group "oidc-ops" must not start with the reserved prefix "oidc-"`)
+}
+
+func TestUnionAuthenticator(t *testing.T) {
+	static, err := NewStaticTokenAuthenticator(strings.NewReader("token-bob,bob\n"))
+	require.NoError(t, err)
+
+	principal, err := UnionAuthenticator{static}.Authenticate("token-bob")
+	require.NoError(t, err)
+	assert.Equal(t, "bob", principal.Name)
+
+	_, err = UnionAuthenticator{static, static}.Authenticate("token-eve")
+	assert.Error(t, err)
+}
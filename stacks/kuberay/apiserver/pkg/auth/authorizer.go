@@ -0,0 +1,367 @@
+package auth
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/ray-project/kuberay/apiserver/pkg/manager"
+	"github.com/ray-project/kuberay/apiserver/pkg/util"
+	api "github.com/ray-project/kuberay/proto/go_client"
+)
+
+// clusterSelectorKey is the cluster selector key naming the existing cluster a job runs on.
+const clusterSelectorKey = "ray.io/cluster"
+
+// groupPrefix marks a namespace owner entry that refers to a group instead of a user.
+const groupPrefix = "group:"
+
+// Policy describes who may access which resources.
+//
+// Admins may access everything. Namespaces listed in NamespaceOwners are exclusive: only their owners
+// (and admins) may access them, and owners may manage every resource inside. Every other namespace is
+// shared: users may only create resources on their own behalf and access resources they own, i.e.
+// resources whose `ray.io/user` label matches their name.
+type Policy struct {
+	// Admins are the users that may access every resource.
+	Admins []string `json:"admins,omitempty"`
+	// AdminGroups are the groups whose members may access every resource.
+	AdminGroups []string `json:"adminGroups,omitempty"`
+	// NamespaceOwners maps a namespace to its owners. Entries prefixed with `group:` refer to groups.
+	NamespaceOwners map[string][]string `json:"namespaceOwners,omitempty"`
+}
+
+// LoadPolicyFile reads an authorization policy from a JSON file.
+func LoadPolicyFile(path string) (*Policy, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read authorization policy file %s: %w", path, err)
+	}
+	policy := &Policy{}
+	if err := json.Unmarshal(data, policy); err != nil {
+		return nil, fmt.Errorf("failed to parse authorization policy file %s: %w", path, err)
+	}
+	return policy, nil
+}
+
+// OwnerLookup resolves the user that owns an existing resource.
+type OwnerLookup interface {
+	ClusterOwner(ctx context.Context, namespace, name string) (string, error)
+	JobOwner(ctx context.Context, namespace, name string) (string, error)
+	ServiceOwner(ctx context.Context, namespace, name string) (string, error)
+}
+
+// resourceKind names the kind of resource a request addresses in error messages.
+type resourceKind string
+
+const (
+	clusterKind resourceKind = "cluster"
+	jobKind     resourceKind = "job"
+	serviceKind resourceKind = "service"
+)
+
+// Authorizer decides whether a principal may issue an API request.
+type Authorizer struct {
+	policy *Policy
+	owners OwnerLookup
+}
+
+func NewAuthorizer(policy *Policy, owners OwnerLookup) *Authorizer {
+	if policy == nil {
+		policy = &Policy{}
+	}
+	return &Authorizer{policy: policy, owners: owners}
+}
+
+// Authorize returns a PermissionDenied error if the principal is not allowed to issue the request.
+// Create and list requests issued by users that do not own the namespace are rewritten so that the
+// resource user defaults to, and list results are filtered by, the principal name.
+func (a *Authorizer) Authorize(ctx context.Context, principal *Principal, req interface{}) error {
+	if a.isAdmin(principal) {
+		return nil
+	}
+
+	switch request := req.(type) {
+	// Clusters
+	case *api.CreateClusterRequest:
+		if request.Cluster == nil {
+			return nil
+		}
+		return a.authorizeCreate(principal, request.Namespace, &request.Cluster.User)
+	case *api.GetClusterRequest:
+		return a.authorizeOwned(ctx, principal, request.Namespace, request.Name, clusterKind)
+	case *api.UpdateClusterRequest:
+		return a.authorizeOwned(ctx, principal, request.Namespace, request.Name, clusterKind)
+	case *api.SuspendClusterRequest:
+		return a.authorizeOwned(ctx, principal, request.Namespace, request.Name, clusterKind)
+	case *api.ResumeClusterRequest:
+		return a.authorizeOwned(ctx, principal, request.Namespace, request.Name, clusterKind)
+	case *api.DeleteClusterRequest:
+		return a.authorizeOwned(ctx, principal, request.Namespace, request.Name, clusterKind)
+	case *api.ListClustersRequest:
+		return a.authorizeList(principal, request.Namespace, &request.User)
+	case *api.ListAllClustersRequest:
+		return a.authorizeList(principal, "", &request.User)
+
+	// Jobs
+	case *api.CreateRayJobRequest:
+		if request.Job == nil {
+			return nil
+		}
+		if err := a.authorizeCreate(principal, request.Namespace, &request.Job.User); err != nil {
+			return err
+		}
+		if len(request.Job.ClusterSelector) == 0 {
+			return nil
+		}
+		return a.authorizeClusterSelector(ctx, principal, request.Namespace, request.Job.ClusterSelector)
+	case *api.GetRayJobRequest:
+		return a.authorizeOwned(ctx, principal, request.Namespace, request.Name, jobKind)
+	case *api.DeleteRayJobRequest:
+		return a.authorizeOwned(ctx, principal, request.Namespace, request.Name, jobKind)
+	case *api.ListRayJobsRequest:
+		return a.authorizeList(principal, request.Namespace, &request.User)
+	case *api.ListAllRayJobsRequest:
+		return a.authorizeList(principal, "", &request.User)
+
+	// Services
+	case *api.CreateRayServiceRequest:
+		if request.Service == nil {
+			return nil
+		}
+		return a.authorizeCreate(principal, request.Namespace, &request.Service.User)
+	case *api.UpdateRayServiceRequest:
+		if err := a.authorizeOwned(ctx, principal, request.Namespace, request.Name, serviceKind); err != nil {
+			return err
+		}
+		if request.Service == nil {
+			return nil
+		}
+		return a.authorizeCreate(principal, request.Namespace, &request.Service.User)
+	case *api.GetRayServiceRequest:
+		return a.authorizeOwned(ctx, principal, request.Namespace, request.Name, serviceKind)
+	case *api.DeleteRayServiceRequest:
+		return a.authorizeOwned(ctx, principal, request.Namespace, request.Name, serviceKind)
+	case *api.ListRayServicesRequest:
+		return a.authorizeList(principal, request.Namespace, &request.User)
+	case *api.ListAllRayServicesRequest:
+		return a.authorizeList(principal, "", &request.User)
+
+	// Templates are shared by everyone in a namespace, so only namespace owners may change them.
+	// Listing templates across namespaces would disclose the ones of exclusive namespaces, so it is
+	// reserved to admins.
+	case *api.CreateComputeTemplateRequest:
+		return a.authorizeNamespaceOwner(principal, request.Namespace)
+	case *api.DeleteComputeTemplateRequest:
+		return a.authorizeNamespaceOwner(principal, request.Namespace)
+	case *api.GetComputeTemplateRequest:
+		return a.authorizeNamespace(principal, request.Namespace)
+	case *api.ListComputeTemplatesRequest:
+		return a.authorizeNamespace(principal, request.Namespace)
+	case *api.ListAllComputeTemplatesRequest:
+		return a.authorizeAdmin(principal, req)
+	case *api.CreateImageTemplateRequest:
+		return a.authorizeNamespaceOwner(principal, request.Namespace)
+	case *api.DeleteImageTemplateRequest:
+		return a.authorizeNamespaceOwner(principal, request.Namespace)
+	case *api.GetImageTemplateRequest:
+		return a.authorizeNamespace(principal, request.Namespace)
+	case *api.ListImageTemplatesRequest:
+		return a.authorizeNamespace(principal, request.Namespace)
+	case *api.ListAllImageTemplatesRequest:
+		return a.authorizeAdmin(principal, req)
+
+	// Job submissions go to the dashboard of an existing cluster.
+	case clusterScopedRequest:
+		return a.authorizeOwned(ctx, principal, request.GetNamespace(), request.GetClustername(), clusterKind)
+	}
+
+	return util.NewPermissionDeniedError(errors.New("unsupported request"),
+		"User %s is not permitted to issue %T requests.", principal.Name, req)
+}
+
+// clusterScopedRequest is implemented by the job submission requests, which all address a cluster.
+type clusterScopedRequest interface {
+	GetNamespace() string
+	GetClustername() string
+}
+
+func (a *Authorizer) isAdmin(principal *Principal) bool {
+	for _, admin := range a.policy.Admins {
+		if admin == principal.Name {
+			return true
+		}
+	}
+	for _, group := range a.policy.AdminGroups {
+		if principal.inGroup(group) {
+			return true
+		}
+	}
+	return false
+}
+
+// ownsNamespace reports whether the namespace is exclusive and whether the principal owns it.
+func (a *Authorizer) ownsNamespace(principal *Principal, namespace string) (exclusive bool, owner bool) {
+	owners, exclusive := a.policy.NamespaceOwners[namespace]
+	for _, entry := range owners {
+		if group := strings.TrimPrefix(entry, groupPrefix); group != entry {
+			if principal.inGroup(group) {
+				return true, true
+			}
+		} else if entry == principal.Name {
+			return true, true
+		}
+	}
+	return exclusive, false
+}
+
+// authorizeAdmin denies requests only admins may issue. Admins are accepted before it is called.
+func (a *Authorizer) authorizeAdmin(principal *Principal, req interface{}) error {
+	return util.NewPermissionDeniedError(errors.New("user is not an admin"),
+		"User %s is not permitted to issue %T requests.", principal.Name, req)
+}
+
+func (a *Authorizer) authorizeNamespace(principal *Principal, namespace string) error {
+	if exclusive, owner := a.ownsNamespace(principal, namespace); exclusive && !owner {
+		return util.NewPermissionDeniedError(errors.New("namespace is not owned by user"),
+			"User %s is not permitted to access namespace %s.", principal.Name, namespace)
+	}
+	return nil
+}
+
+func (a *Authorizer) authorizeNamespaceOwner(principal *Principal, namespace string) error {
+	if _, owner := a.ownsNamespace(principal, namespace); !owner {
+		return util.NewPermissionDeniedError(errors.New("namespace is not owned by user"),
+			"User %s is not permitted to manage templates in namespace %s.", principal.Name, namespace)
+	}
+	return nil
+}
+
+func (a *Authorizer) authorizeCreate(principal *Principal, namespace string, user *string) error {
+	exclusive, owner := a.ownsNamespace(principal, namespace)
+	if exclusive && !owner {
+		return util.NewPermissionDeniedError(errors.New("namespace is not owned by user"),
+			"User %s is not permitted to access namespace %s.", principal.Name, namespace)
+	}
+	if *user == "" {
+		*user = principal.Name
+	}
+	if !owner && *user != principal.Name {
+		return util.NewPermissionDeniedError(errors.New("resource is not owned by user"),
+			"User %s is not permitted to manage resources on behalf of user %s in namespace %s.", principal.Name, *user, namespace)
+	}
+	return nil
+}
+
+func (a *Authorizer) authorizeList(principal *Principal, namespace string, user *string) error {
+	if namespace != "" {
+		exclusive, owner := a.ownsNamespace(principal, namespace)
+		if exclusive && !owner {
+			return util.NewPermissionDeniedError(errors.New("namespace is not owned by user"),
+				"User %s is not permitted to access namespace %s.", principal.Name, namespace)
+		}
+		if owner {
+			return nil
+		}
+	}
+	if *user == "" {
+		*user = principal.Name
+	}
+	if *user != principal.Name {
+		return util.NewPermissionDeniedError(errors.New("resource is not owned by user"),
+			"User %s is not permitted to list resources of user %s.", principal.Name, *user)
+	}
+	return nil
+}
+
+func (a *Authorizer) authorizeOwned(ctx context.Context, principal *Principal, namespace, name string, kind resourceKind) error {
+	exclusive, owner := a.ownsNamespace(principal, namespace)
+	if owner {
+		return nil
+	}
+	if exclusive {
+		return util.NewPermissionDeniedError(errors.New("namespace is not owned by user"),
+			"User %s is not permitted to access namespace %s.", principal.Name, namespace)
+	}
+	if namespace == "" || name == "" {
+		// Let request validation report the missing field.
+		return nil
+	}
+	user, err := a.lookupOwner(ctx, namespace, name, kind)
+	if err != nil {
+		return util.Wrapf(err, "Failed to resolve the owner of %s %s/%s.", kind, namespace, name)
+	}
+	if user != principal.Name {
+		return util.NewPermissionDeniedError(errors.New("resource is not owned by user"),
+			"User %s is not permitted to access %s %s in namespace %s.", principal.Name, kind, name, namespace)
+	}
+	return nil
+}
+
+// authorizeClusterSelector checks that a job submitted to an existing cluster addresses a single cluster
+// the principal may access.
+func (a *Authorizer) authorizeClusterSelector(ctx context.Context, principal *Principal, namespace string, selector map[string]string) error {
+	name := selector[clusterSelectorKey]
+	if len(selector) != 1 || name == "" {
+		return util.NewPermissionDeniedError(errors.New("cluster selector does not name a single cluster"),
+			"User %s is not permitted to submit jobs with a cluster selector other than %s=<cluster name>.", principal.Name, clusterSelectorKey)
+	}
+	return a.authorizeOwned(ctx, principal, namespace, name, clusterKind)
+}
+
+func (a *Authorizer) lookupOwner(ctx context.Context, namespace, name string, kind resourceKind) (string, error) {
+	switch kind {
+	case jobKind:
+		return a.owners.JobOwner(ctx, namespace, name)
+	case serviceKind:
+		return a.owners.ServiceOwner(ctx, namespace, name)
+	default:
+		return a.owners.ClusterOwner(ctx, namespace, name)
+	}
+}
+
+func (p *Principal) inGroup(group string) bool {
+	for _, g := range p.Groups {
+		if g == group {
+			return true
+		}
+	}
+	return false
+}
+
+// resourceOwnerLookup reads the owner from the `ray.io/user` label of the Kubernetes resource.
+type resourceOwnerLookup struct {
+	resourceManager *manager.ResourceManager
+}
+
+// NewResourceOwnerLookup returns an OwnerLookup backed by the resource manager.
+func NewResourceOwnerLookup(resourceManager *manager.ResourceManager) OwnerLookup {
+	return &resourceOwnerLookup{resourceManager: resourceManager}
+}
+
+func (l *resourceOwnerLookup) ClusterOwner(ctx context.Context, namespace, name string) (string, error) {
+	cluster, err := l.resourceManager.GetCluster(ctx, name, namespace)
+	if err != nil {
+		return "", err
+	}
+	return cluster.Labels[util.RayClusterUserLabelKey], nil
+}
+
+func (l *resourceOwnerLookup) JobOwner(ctx context.Context, namespace, name string) (string, error) {
+	job, err := l.resourceManager.GetJob(ctx, name, namespace)
+	if err != nil {
+		return "", err
+	}
+	return job.Labels[util.RayClusterUserLabelKey], nil
+}
+
+func (l *resourceOwnerLookup) ServiceOwner(ctx context.Context, namespace, name string) (string, error) {
+	service, err := l.resourceManager.GetService(ctx, name, namespace)
+	if err != nil {
+		return "", err
+	}
+	return service.Labels[util.RayClusterUserLabelKey], nil
+}
@@ -0,0 +1,119 @@
+package auth
+
+import (
+	"context"
+	"testing"
+
+	"github.com/ray-project/kuberay/apiserver/pkg/util"
+	api "github.com/ray-project/kuberay/proto/go_client"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+)
+
+type fakeOwnerLookup map[string]string
+
+func (f fakeOwnerLookup) ClusterOwner(_ context.Context, namespace, name string) (string, error) {
+	return f.owner("cluster", namespace, name)
+}
+
+func (f fakeOwnerLookup) JobOwner(_ context.Context, namespace, name string) (string, error) {
+	return f.owner("job", namespace, name)
+}
+
+func (f fakeOwnerLookup) ServiceOwner(_ context.Context, namespace, name string) (string, error) {
+	return f.owner("service", namespace, name)
+}
+
+func (f fakeOwnerLookup) owner(kind, namespace, name string) (string, error) {
+	owner, ok := f[kind+"/"+namespace+"/"+name]
+	if !ok {
+		return "", util.NewResourceNotFoundError(kind, name)
+	}
+	return owner, nil
+}
+
+func TestAuthorize(t *testing.T) {
+	authorizer := NewAuthorizer(&Policy{
+		Admins:      []string{"root"},
+		AdminGroups: []string{"admins"},
+		NamespaceOwners: map[string][]string{
+			"team-a": {"alice", "group:team-a"},
+		},
+	}, fakeOwnerLookup{
+		"cluster/shared/bob-cluster":   "bob",
+		"cluster/shared/carol-cluster": "carol",
+		"job/shared/bob-job":           "bob",
+		"service/shared/carol-service": "carol",
+		"cluster/team-a/alice-cluster": "alice",
+	})
+
+	root := &Principal{Name: "root"}
+	admin := &Principal{Name: "dave", Groups: []string{"admins"}}
+	alice := &Principal{Name: "alice"}
+	teamA := &Principal{Name: "erin", Groups: []string{"team-a"}}
+	bob := &Principal{Name: "bob"}
+
+	tests := []struct {
+		name      string
+		principal *Principal
+		request   interface{}
+		code      codes.Code
+	}{
+		{"admin user may delete any cluster", root, &api.DeleteClusterRequest{Namespace: "team-a", Name: "alice-cluster"}, codes.OK},
+		{"admin group may list all clusters", admin, &api.ListAllClustersRequest{User: "alice"}, codes.OK},
+		{"namespace owner may delete clusters of others", alice, &api.DeleteClusterRequest{Namespace: "team-a", Name: "unknown"}, codes.OK},
+		{"namespace owner group may create templates", teamA, &api.CreateComputeTemplateRequest{Namespace: "team-a"}, codes.OK},
+		{"non owner may not access exclusive namespace", bob, &api.GetClusterRequest{Namespace: "team-a", Name: "alice-cluster"}, codes.PermissionDenied},
+		{"non owner may not list exclusive namespace", bob, &api.ListClustersRequest{Namespace: "team-a"}, codes.PermissionDenied},
+		{"owner may get own cluster", bob, &api.GetClusterRequest{Namespace: "shared", Name: "bob-cluster"}, codes.OK},
+		{"owner may suspend own cluster", bob, &api.SuspendClusterRequest{Namespace: "shared", Name: "bob-cluster"}, codes.OK},
+		{"user may not delete cluster of others", bob, &api.DeleteClusterRequest{Namespace: "shared", Name: "carol-cluster"}, codes.PermissionDenied},
+		{"user may not update cluster of others", bob, &api.UpdateClusterRequest{Namespace: "shared", Name: "carol-cluster"}, codes.PermissionDenied},
+		{"missing cluster is reported as not found", bob, &api.DeleteClusterRequest{Namespace: "shared", Name: "missing"}, codes.NotFound},
+		{"user may create cluster for self", bob, &api.CreateClusterRequest{Namespace: "shared", Cluster: &api.Cluster{User: "bob"}}, codes.OK},
+		{"user may not create cluster for others", bob, &api.CreateClusterRequest{Namespace: "shared", Cluster: &api.Cluster{User: "carol"}}, codes.PermissionDenied},
+		{"user may not list clusters of others", bob, &api.ListClustersRequest{Namespace: "shared", User: "carol"}, codes.PermissionDenied},
+		{"owner may delete own job", bob, &api.DeleteRayJobRequest{Namespace: "shared", Name: "bob-job"}, codes.OK},
+		{"user may not get service of others", bob, &api.GetRayServiceRequest{Namespace: "shared", Name: "carol-service"}, codes.PermissionDenied},
+		{"user may not take over service of others", bob, &api.UpdateRayServiceRequest{Namespace: "shared", Name: "carol-service", Service: &api.RayService{User: "bob"}}, codes.PermissionDenied},
+		{"user may create job on own cluster", bob, &api.CreateRayJobRequest{Namespace: "shared", Job: &api.RayJob{ClusterSelector: map[string]string{"ray.io/cluster": "bob-cluster"}}}, codes.OK},
+		{"user may not create job on cluster of others", bob, &api.CreateRayJobRequest{Namespace: "shared", Job: &api.RayJob{ClusterSelector: map[string]string{"ray.io/cluster": "carol-cluster"}}}, codes.PermissionDenied},
+		{"user may not create job on clusters selected by other labels", bob, &api.CreateRayJobRequest{Namespace: "shared", Job: &api.RayJob{ClusterSelector: map[string]string{"team": "ml"}}}, codes.PermissionDenied},
+		{"user may not create job with extra cluster selector labels", bob, &api.CreateRayJobRequest{Namespace: "shared", Job: &api.RayJob{ClusterSelector: map[string]string{"ray.io/cluster": "bob-cluster", "team": "ml"}}}, codes.PermissionDenied},
+		{"namespace owner may create job on any cluster", alice, &api.CreateRayJobRequest{Namespace: "team-a", Job: &api.RayJob{ClusterSelector: map[string]string{"ray.io/cluster": "unknown"}}}, codes.OK},
+		{"user may submit jobs to own cluster", bob, &api.SubmitRayJobRequest{Namespace: "shared", Clustername: "bob-cluster"}, codes.OK},
+		{"user may not submit jobs to cluster of others", bob, &api.SubmitRayJobRequest{Namespace: "shared", Clustername: "carol-cluster"}, codes.PermissionDenied},
+		{"user may read templates in shared namespace", bob, &api.GetComputeTemplateRequest{Namespace: "shared", Name: "default"}, codes.OK},
+		{"user may not create templates in shared namespace", bob, &api.CreateImageTemplateRequest{Namespace: "shared"}, codes.PermissionDenied},
+		{"admin may list templates of all namespaces", admin, &api.ListAllComputeTemplatesRequest{}, codes.OK},
+		{"user may not list compute templates of all namespaces", bob, &api.ListAllComputeTemplatesRequest{}, codes.PermissionDenied},
+		{"namespace owner may not list image templates of all namespaces", alice, &api.ListAllImageTemplatesRequest{}, codes.PermissionDenied},
+		{"unknown request is denied", bob, &api.Cluster{}, codes.PermissionDenied},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			err := authorizer.Authorize(context.Background(), tc.principal, tc.request)
+			if tc.code == codes.OK {
+				require.NoError(t, err)
+				return
+			}
+			require.Error(t, err)
+			assert.True(t, util.IsUserErrorCodeMatch(err, tc.code), "unexpected error: %v", err)
+		})
+	}
+}
+
+func TestAuthorizeDefaultsUser(t *testing.T) {
+	authorizer := NewAuthorizer(nil, fakeOwnerLookup{})
+	bob := &Principal{Name: "bob"}
+
+	createRequest := &api.CreateClusterRequest{Namespace: "shared", Cluster: &api.Cluster{}}
+	require.NoError(t, authorizer.Authorize(context.Background(), bob, createRequest))
+	assert.Equal(t, "bob", createRequest.Cluster.User)
+
+	listRequest := &api.ListAllRayJobsRequest{}
+	require.NoError(t, authorizer.Authorize(context.Background(), bob, listRequest))
+	assert.Equal(t, "bob", listRequest.User)
+}
@@ -0,0 +1,200 @@
+package auth
+
+import (
+	"crypto/ecdsa"
+	"crypto/rsa"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	jose "github.com/go-jose/go-jose/v3"
+	"github.com/go-jose/go-jose/v3/jwt"
+	"k8s.io/apimachinery/pkg/util/validation"
+)
+
+// JWTAuthenticatorOptions configures how OIDC ID tokens are validated.
+type JWTAuthenticatorOptions struct {
+	// Issuer must match the `iss` claim. Required.
+	Issuer string
+	// Audience must be contained in the `aud` claim. Required, so that tokens issued for other
+	// clients of the same issuer are rejected.
+	Audience string
+	// UsernameClaim is the claim used as the principal name, `sub` by default.
+	UsernameClaim string
+	// GroupsClaim is the claim used as the principal groups, `groups` by default.
+	GroupsClaim string
+	// UsernamePrefix is prepended to the principal name, so that OIDC users can not take the name of a static token
+	// user, e.g. an `admin` listed in the authorization policy. It must be usable in a label value.
+	UsernamePrefix string
+	// GroupsPrefix is prepended to every principal group, like UsernamePrefix.
+	GroupsPrefix string
+}
+
+// jwtSigningAlgorithms are the accepted JWT signing algorithms and the elliptic curve each ECDSA algorithm is defined
+// for. The RSA algorithms have no curve.
+var jwtSigningAlgorithms = map[jose.SignatureAlgorithm]string{
+	jose.RS256: "",
+	jose.RS384: "",
+	jose.RS512: "",
+	jose.ES256: "P-256",
+	jose.ES384: "P-384",
+	jose.ES512: "P-521",
+}
+
+// JWTAuthenticator validates OIDC JWTs signed by one of the keys in a JSON Web Key Set.
+type JWTAuthenticator struct {
+	options JWTAuthenticatorOptions
+	keys    jose.JSONWebKeySet
+	now     func() time.Time
+}
+
+// NewJWTAuthenticatorFromFile loads the signing keys from a local JWKS file.
+func NewJWTAuthenticatorFromFile(path string, options JWTAuthenticatorOptions) (*JWTAuthenticator, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read JWKS file %s: %w", path, err)
+	}
+	return NewJWTAuthenticator(data, options)
+}
+
+// NewJWTAuthenticator parses a JSON Web Key Set and builds an authenticator from it.
+// Only RSA and EC signing keys are used.
+func NewJWTAuthenticator(jwks []byte, options JWTAuthenticatorOptions) (*JWTAuthenticator, error) {
+	if options.Issuer == "" || options.Audience == "" {
+		return nil, errors.New("the issuer and the audience of OIDC JWTs must be set")
+	}
+	for _, prefix := range []string{options.UsernamePrefix, options.GroupsPrefix} {
+		// Principal names are stored in the `ray.io/user` label of created resources.
+		if prefix != "" && len(validation.IsValidLabelValue(prefix+"a")) > 0 {
+			return nil, fmt.Errorf("OIDC name prefix %q must start with an alphanumeric character and only contain alphanumeric characters, '-', '_' or '.'", prefix)
+		}
+	}
+	var keySet jose.JSONWebKeySet
+	if err := json.Unmarshal(jwks, &keySet); err != nil {
+		return nil, fmt.Errorf("failed to parse JWKS: %w", err)
+	}
+
+	signingKeys := jose.JSONWebKeySet{}
+	for _, key := range keySet.Keys {
+		if key.Use != "" && key.Use != "sig" {
+			continue
+		}
+		public := key.Public()
+		switch public.Key.(type) {
+		case *rsa.PublicKey, *ecdsa.PublicKey:
+			signingKeys.Keys = append(signingKeys.Keys, public)
+		}
+	}
+	if len(signingKeys.Keys) == 0 {
+		return nil, errors.New("JWKS does not contain any signing key")
+	}
+
+	if options.UsernameClaim == "" {
+		options.UsernameClaim = "sub"
+	}
+	if options.GroupsClaim == "" {
+		options.GroupsClaim = "groups"
+	}
+
+	return &JWTAuthenticator{options: options, keys: signingKeys, now: time.Now}, nil
+}
+
+func (a *JWTAuthenticator) Authenticate(token string) (*Principal, error) {
+	if strings.Count(token, ".") != 2 {
+		return nil, ErrUnknownToken
+	}
+	parsed, err := jwt.ParseSigned(token)
+	if err != nil {
+		return nil, fmt.Errorf("invalid JWT: %w", err)
+	}
+	if len(parsed.Headers) != 1 {
+		return nil, errors.New("JWT must have exactly one signature")
+	}
+	header := parsed.Headers[0]
+	algorithm := jose.SignatureAlgorithm(header.Algorithm)
+	if _, ok := jwtSigningAlgorithms[algorithm]; !ok {
+		return nil, fmt.Errorf("unsupported JWT signing algorithm %q", header.Algorithm)
+	}
+
+	candidates := a.keys.Keys
+	if header.KeyID != "" {
+		if candidates = a.keys.Key(header.KeyID); len(candidates) == 0 {
+			return nil, fmt.Errorf("JWT is signed by unknown key %q", header.KeyID)
+		}
+	}
+
+	var claims jwt.Claims
+	var rawClaims map[string]interface{}
+	verified := false
+	for _, key := range candidates {
+		if !keyMatchesAlgorithm(key, algorithm) {
+			continue
+		}
+		if err := parsed.Claims(key.Key, &claims, &rawClaims); err == nil {
+			verified = true
+			break
+		}
+	}
+	if !verified {
+		return nil, errors.New("JWT signature verification failed")
+	}
+	return a.validateClaims(claims, rawClaims)
+}
+
+// keyMatchesAlgorithm returns whether a JWT signed with the algorithm can be verified with the key, i.e. whether the
+// key has the type, and for ECDSA the curve, the algorithm is defined for.
+func keyMatchesAlgorithm(key jose.JSONWebKey, algorithm jose.SignatureAlgorithm) bool {
+	curve := jwtSigningAlgorithms[algorithm]
+	switch publicKey := key.Key.(type) {
+	case *rsa.PublicKey:
+		return curve == "" && strings.HasPrefix(string(algorithm), "RS")
+	case *ecdsa.PublicKey:
+		return curve != "" && publicKey.Curve.Params().Name == curve
+	}
+	return false
+}
+
+func (a *JWTAuthenticator) validateClaims(claims jwt.Claims, rawClaims map[string]interface{}) (*Principal, error) {
+	if claims.Expiry == nil {
+		return nil, errors.New("JWT does not contain an expiration time")
+	}
+	// The default leeway tolerates small clock differences between the issuer and the API server.
+	err := claims.ValidateWithLeeway(jwt.Expected{
+		Issuer:   a.options.Issuer,
+		Audience: jwt.Audience{a.options.Audience},
+		Time:     a.now(),
+	}, jwt.DefaultLeeway)
+	switch {
+	case err == nil:
+	case errors.Is(err, jwt.ErrExpired):
+		return nil, errors.New("JWT has expired")
+	case errors.Is(err, jwt.ErrNotValidYet), errors.Is(err, jwt.ErrIssuedInTheFuture):
+		return nil, errors.New("JWT is not valid yet")
+	case errors.Is(err, jwt.ErrInvalidIssuer):
+		return nil, fmt.Errorf("JWT issuer %q is not trusted", claims.Issuer)
+	case errors.Is(err, jwt.ErrInvalidAudience):
+		return nil, fmt.Errorf("JWT is not issued for audience %q", a.options.Audience)
+	default:
+		return nil, fmt.Errorf("invalid JWT claims: %w", err)
+	}
+
+	name, _ := rawClaims[a.options.UsernameClaim].(string)
+	if name == "" {
+		return nil, fmt.Errorf("JWT does not contain the username claim %q", a.options.UsernameClaim)
+	}
+	principal := &Principal{Name: a.options.UsernamePrefix + name}
+	switch groups := rawClaims[a.options.GroupsClaim].(type) {
+	case string:
+		principal.Groups = []string{a.options.GroupsPrefix + groups}
+	case []interface{}:
+		for _, group := range groups {
+			if value, ok := group.(string); ok {
+				principal.Groups = append(principal.Groups, a.options.GroupsPrefix+value)
+			}
+		}
+	}
+	return principal, nil
+}
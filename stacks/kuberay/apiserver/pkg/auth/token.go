@@ -0,0 +1,89 @@
+package auth
+
+import (
+	"crypto/subtle"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+// StaticTokenAuthenticator authenticates requests against a fixed set of bearer tokens.
+type StaticTokenAuthenticator struct {
+	tokens map[string]*Principal
+}
+
+// NewStaticTokenAuthenticatorFromFile loads tokens from a CSV file using the same layout as the
+// Kubernetes static token file: `token,user,uid,"group1,group2"`. The uid and groups columns are optional.
+func NewStaticTokenAuthenticatorFromFile(path string) (*StaticTokenAuthenticator, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open token file %s: %w", path, err)
+	}
+	defer file.Close()
+	return NewStaticTokenAuthenticator(file)
+}
+
+// NewStaticTokenAuthenticator parses tokens in the static token CSV format from r.
+func NewStaticTokenAuthenticator(r io.Reader) (*StaticTokenAuthenticator, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+	reader.Comment = '#'
+
+	tokens := map[string]*Principal{}
+	line := 0
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		line++
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse token file: %w", err)
+		}
+		if len(record) < 2 || record[0] == "" || record[1] == "" {
+			return nil, fmt.Errorf("token file record %d must contain at least a token and a user name", line)
+		}
+		if _, exist := tokens[record[0]]; exist {
+			return nil, fmt.Errorf("token file record %d contains a duplicated token", line)
+		}
+		principal := &Principal{Name: record[1]}
+		if len(record) >= 4 && record[3] != "" {
+			for _, group := range strings.Split(record[3], ",") {
+				if group = strings.TrimSpace(group); group != "" {
+					principal.Groups = append(principal.Groups, group)
+				}
+			}
+		}
+		tokens[record[0]] = principal
+	}
+
+	return &StaticTokenAuthenticator{tokens: tokens}, nil
+}
+
+func (a *StaticTokenAuthenticator) Authenticate(token string) (*Principal, error) {
+	for candidate, principal := range a.tokens {
+		if subtle.ConstantTimeCompare([]byte(candidate), []byte(token)) == 1 {
+			return principal, nil
+		}
+	}
+	return nil, ErrUnknownToken
+}
+
+// RejectPrefixes returns an error if a user or group name of the tokens starts with the given prefixes, which another
+// authenticator reserves for its principals. Empty prefixes are ignored.
+func (a *StaticTokenAuthenticator) RejectPrefixes(usernamePrefix, groupsPrefix string) error {
+	for _, principal := range a.tokens {
+		if usernamePrefix != "" && strings.HasPrefix(principal.Name, usernamePrefix) {
+			return fmt.Errorf("static token user %q must not start with the reserved prefix %q", principal.Name, usernamePrefix)
+		}
+		for _, group := range principal.Groups {
+			if groupsPrefix != "" && strings.HasPrefix(group, groupsPrefix) {
+				return fmt.Errorf("static token group %q must not start with the reserved prefix %q", group, groupsPrefix)
+			}
+		}
+	}
+	return nil
+}
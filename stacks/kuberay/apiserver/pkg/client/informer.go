@@ -0,0 +1,31 @@
+package client
+
+import (
+	"time"
+
+	klog "k8s.io/klog/v2"
+
+	"github.com/ray-project/kuberay/apiserver/pkg/util"
+	rayclient "github.com/ray-project/kuberay/ray-operator/pkg/client/clientset/versioned"
+	rayinformers "github.com/ray-project/kuberay/ray-operator/pkg/client/informers/externalversions"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"sigs.k8s.io/controller-runtime/pkg/client/config"
+)
+
+// NewRayInformerFactoryOrFatal creates an informer factory for the Ray resources managed by the API server in all
+// namespaces. The informers are only created for the listers requested from the factory, which must be started.
+func NewRayInformerFactoryOrFatal(resyncPeriod time.Duration, options util.ClientOptions) rayinformers.SharedInformerFactory {
+	cfg, err := config.GetConfig()
+	if err != nil {
+		klog.Fatalf("Failed to create Ray informer factory. Error: %v", err)
+	}
+	cfg.QPS = options.QPS
+	cfg.Burst = options.Burst
+
+	managedSelector := labels.Set{util.KubernetesManagedByLabelKey: util.ComponentName}.String()
+	return rayinformers.NewSharedInformerFactoryWithOptions(rayclient.NewForConfigOrDie(cfg), resyncPeriod,
+		rayinformers.WithTweakListOptions(func(options *metav1.ListOptions) {
+			options.LabelSelector = managedSelector
+		}))
+}
@@ -1,20 +1,29 @@
 package http
 
 import (
+	"bufio"
 	"bytes"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
 	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
 
 	api "github.com/ray-project/kuberay/proto/go_client"
 	rpcStatus "google.golang.org/genproto/googleapis/rpc/status"
 	"google.golang.org/protobuf/encoding/protojson"
 )
 
+// maxEventSize is the largest server-sent event accepted from a streaming endpoint.
+const maxEventSize = 16 * 1024 * 1024
+
 type KuberayAPIServerClient struct {
 	httpClient  *http.Client
 	baseURL     string
+	bearerToken string
 	marshaler   *protojson.MarshalOptions
 	unmarshaler *protojson.UnmarshalOptions
 }
@@ -58,6 +67,11 @@ func NewKuberayAPIServerClient(baseURL string, httpClient *http.Client) *Kuberay
 	}
 }
 
+// SetBearerToken sets the token sent in the `Authorization` header of every request.
+func (krc *KuberayAPIServerClient) SetBearerToken(token string) {
+	krc.bearerToken = token
+}
+
 // CreateComputeTemplate creates a new compute template.
 func (krc *KuberayAPIServerClient) CreateComputeTemplate(request *api.CreateComputeTemplateRequest) (*api.ComputeTemplate, *rpcStatus.Status, error) {
 	createURL := krc.baseURL + "/apis/v1/namespaces/" + request.Namespace + "/compute_templates"
@@ -137,7 +151,9 @@ func (krc *KuberayAPIServerClient) GetAllComputeTemplates() (*api.ListAllCompute
 
 // GetAllComputeTemplatesInNamespace Finds all compute templates in a given namespace.
 func (krc *KuberayAPIServerClient) GetAllComputeTemplatesInNamespace(request *api.ListComputeTemplatesRequest) (*api.ListComputeTemplatesResponse, *rpcStatus.Status, error) {
-	getURL := krc.baseURL + "/apis/v1/namespaces/" + request.Namespace + "/compute_templates"
+	getURL := krc.baseURL + "/apis/v1/namespaces/" + request.Namespace + "/compute_templates" + listQuery("", 0, map[string]string{
+		"labelSelector": request.LabelSelector,
+	})
 	httpRequest, err := krc.createHttpRequest("GET", getURL, nil)
 	if err != nil {
 		return nil, nil, fmt.Errorf("failed to create http request for url '%s': %w", getURL, err)
@@ -156,6 +172,104 @@ func (krc *KuberayAPIServerClient) GetAllComputeTemplatesInNamespace(request *ap
 	return response, nil, nil
 }
 
+// CreateImageTemplate creates a new image template.
+func (krc *KuberayAPIServerClient) CreateImageTemplate(request *api.CreateImageTemplateRequest) (*api.ImageTemplate, *rpcStatus.Status, error) {
+	createURL := krc.baseURL + "/apis/v1/namespaces/" + request.Namespace + "/image_templates"
+
+	bytez, err := krc.marshaler.Marshal(request.ImageTemplate)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to marshal api.ImageTemplate to JSON: %w", err)
+	}
+
+	httpRequest, err := krc.createHttpRequest("POST", createURL, bytes.NewReader(bytez))
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to create http request for url '%s': %w", createURL, err)
+	}
+
+	httpRequest.Header.Add("Accept", "application/json")
+	httpRequest.Header.Add("Content-Type", "application/json")
+
+	bodyBytes, status, err := krc.executeRequest(httpRequest, createURL)
+	if err != nil {
+		return nil, status, err
+	}
+	imageTemplate := &api.ImageTemplate{}
+	if err := krc.unmarshaler.Unmarshal(bodyBytes, imageTemplate); err != nil {
+		return nil, status, nil
+	}
+
+	return imageTemplate, nil, nil
+}
+
+// DeleteImageTemplate deletes an image template.
+func (krc *KuberayAPIServerClient) DeleteImageTemplate(request *api.DeleteImageTemplateRequest) (*rpcStatus.Status, error) {
+	deleteURL := krc.baseURL + "/apis/v1/namespaces/" + request.Namespace + "/image_templates/" + request.Name
+	return krc.doDelete(deleteURL)
+}
+
+// GetImageTemplate finds a specific image template by its name and namespace.
+func (krc *KuberayAPIServerClient) GetImageTemplate(request *api.GetImageTemplateRequest) (*api.ImageTemplate, *rpcStatus.Status, error) {
+	getURL := krc.baseURL + "/apis/v1/namespaces/" + request.Namespace + "/image_templates/" + request.Name
+	httpRequest, err := krc.createHttpRequest("GET", getURL, nil)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to create http request for url '%s': %w", getURL, err)
+	}
+
+	httpRequest.Header.Add("Accept", "application/json")
+
+	bodyBytes, status, err := krc.executeRequest(httpRequest, getURL)
+	if err != nil {
+		return nil, status, err
+	}
+	imageTemplate := &api.ImageTemplate{}
+	if err := krc.unmarshaler.Unmarshal(bodyBytes, imageTemplate); err != nil {
+		return nil, status, nil
+	}
+	return imageTemplate, nil, nil
+}
+
+// GetAllImageTemplates finds all image templates in all namespaces.
+func (krc *KuberayAPIServerClient) GetAllImageTemplates() (*api.ListAllImageTemplatesResponse, *rpcStatus.Status, error) {
+	getURL := krc.baseURL + "/apis/v1/image_templates"
+	httpRequest, err := krc.createHttpRequest("GET", getURL, nil)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to create http request for url '%s': %w", getURL, err)
+	}
+
+	httpRequest.Header.Add("Accept", "application/json")
+
+	bodyBytes, status, err := krc.executeRequest(httpRequest, getURL)
+	if err != nil {
+		return nil, status, err
+	}
+	response := &api.ListAllImageTemplatesResponse{}
+	if err := krc.unmarshaler.Unmarshal(bodyBytes, response); err != nil {
+		return nil, status, nil
+	}
+	return response, nil, nil
+}
+
+// GetAllImageTemplatesInNamespace finds all image templates in a given namespace.
+func (krc *KuberayAPIServerClient) GetAllImageTemplatesInNamespace(request *api.ListImageTemplatesRequest) (*api.ListImageTemplatesResponse, *rpcStatus.Status, error) {
+	getURL := krc.baseURL + "/apis/v1/namespaces/" + request.Namespace + "/image_templates"
+	httpRequest, err := krc.createHttpRequest("GET", getURL, nil)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to create http request for url '%s': %w", getURL, err)
+	}
+
+	httpRequest.Header.Add("Accept", "application/json")
+
+	bodyBytes, status, err := krc.executeRequest(httpRequest, getURL)
+	if err != nil {
+		return nil, status, err
+	}
+	response := &api.ListImageTemplatesResponse{}
+	if err := krc.unmarshaler.Unmarshal(bodyBytes, response); err != nil {
+		return nil, status, nil
+	}
+	return response, nil, nil
+}
+
 // CreateCluster creates a new cluster.
 func (krc *KuberayAPIServerClient) CreateCluster(request *api.CreateClusterRequest) (*api.Cluster, *rpcStatus.Status, error) {
 	createURL := krc.baseURL + "/apis/v1/namespaces/" + request.Namespace + "/clusters"
@@ -211,9 +325,75 @@ func (krc *KuberayAPIServerClient) GetCluster(request *api.GetClusterRequest) (*
 	return cluster, nil, nil
 }
 
+// UpdateCluster updates the fields of a cluster listed in the update mask.
+func (krc *KuberayAPIServerClient) UpdateCluster(request *api.UpdateClusterRequest) (*api.Cluster, *rpcStatus.Status, error) {
+	updateURL := krc.baseURL + "/apis/v1/namespaces/" + request.Namespace + "/clusters/" + request.Name
+	if len(request.UpdateMask.GetPaths()) > 0 {
+		updateURL += "?" + url.Values{"updateMask": {strings.Join(request.UpdateMask.GetPaths(), ",")}}.Encode()
+	}
+	bytez, err := krc.marshaler.Marshal(request.Cluster)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to marshal api.Cluster to JSON: %w", err)
+	}
+
+	httpRequest, err := krc.createHttpRequest("PATCH", updateURL, bytes.NewReader(bytez))
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to create http request for url '%s': %w", updateURL, err)
+	}
+
+	httpRequest.Header.Add("Accept", "application/json")
+	httpRequest.Header.Add("Content-Type", "application/json")
+
+	bodyBytes, status, err := krc.executeRequest(httpRequest, updateURL)
+	if err != nil {
+		return nil, status, err
+	}
+	cluster := &api.Cluster{}
+	if err := krc.unmarshaler.Unmarshal(bodyBytes, cluster); err != nil {
+		return nil, status, nil
+	}
+	return cluster, nil, nil
+}
+
+// SuspendCluster suspends a cluster by its name and namespace
+func (krc *KuberayAPIServerClient) SuspendCluster(request *api.SuspendClusterRequest) (*api.Cluster, *rpcStatus.Status, error) {
+	suspendURL := krc.baseURL + "/apis/v1/namespaces/" + request.Namespace + "/clusters/" + request.Name + ":suspend"
+	return krc.doClusterAction(suspendURL)
+}
+
+// ResumeCluster resumes a suspended cluster by its name and namespace
+func (krc *KuberayAPIServerClient) ResumeCluster(request *api.ResumeClusterRequest) (*api.Cluster, *rpcStatus.Status, error) {
+	resumeURL := krc.baseURL + "/apis/v1/namespaces/" + request.Namespace + "/clusters/" + request.Name + ":resume"
+	return krc.doClusterAction(resumeURL)
+}
+
+func (krc *KuberayAPIServerClient) doClusterAction(actionURL string) (*api.Cluster, *rpcStatus.Status, error) {
+	httpRequest, err := krc.createHttpRequest("POST", actionURL, nil)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to create http request for url '%s': %w", actionURL, err)
+	}
+
+	httpRequest.Header.Add("Accept", "application/json")
+
+	bodyBytes, status, err := krc.executeRequest(httpRequest, actionURL)
+	if err != nil {
+		return nil, status, err
+	}
+	cluster := &api.Cluster{}
+	if err := krc.unmarshaler.Unmarshal(bodyBytes, cluster); err != nil {
+		return nil, status, nil
+	}
+	return cluster, nil, nil
+}
+
 // ListCluster finds all clusters in a given namespace.
 func (krc *KuberayAPIServerClient) ListClusters(request *api.ListClustersRequest) (*api.ListClustersResponse, *rpcStatus.Status, error) {
-	getURL := krc.baseURL + "/apis/v1/namespaces/" + request.Namespace + "/clusters"
+	getURL := krc.baseURL + "/apis/v1/namespaces/" + request.Namespace + "/clusters" + listQuery(request.PageToken, request.PageSize, map[string]string{
+		"user":          request.User,
+		"environment":   request.Environment,
+		"labelSelector": request.LabelSelector,
+		"clusterState":  request.ClusterState,
+	})
 	httpRequest, err := krc.createHttpRequest("GET", getURL, nil)
 	if err != nil {
 		return nil, nil, fmt.Errorf("failed to create http request for url '%s': %w", getURL, err)
@@ -233,8 +413,13 @@ func (krc *KuberayAPIServerClient) ListClusters(request *api.ListClustersRequest
 }
 
 // ListAllClusters finds all Clusters in all namespaces. Supports pagination, and sorting on certain fields.
-func (krc *KuberayAPIServerClient) ListAllClusters() (*api.ListAllClustersResponse, *rpcStatus.Status, error) {
-	getURL := krc.baseURL + "/apis/v1/clusters"
+func (krc *KuberayAPIServerClient) ListAllClusters(request *api.ListAllClustersRequest) (*api.ListAllClustersResponse, *rpcStatus.Status, error) {
+	getURL := krc.baseURL + "/apis/v1/clusters" + listQuery(request.PageToken, request.PageSize, map[string]string{
+		"user":          request.User,
+		"environment":   request.Environment,
+		"labelSelector": request.LabelSelector,
+		"clusterState":  request.ClusterState,
+	})
 	httpRequest, err := krc.createHttpRequest("GET", getURL, nil)
 	if err != nil {
 		return nil, nil, fmt.Errorf("failed to create http request for url '%s': %w", getURL, err)
@@ -303,7 +488,10 @@ func (krc *KuberayAPIServerClient) GetRayJob(request *api.GetRayJobRequest) (*ap
 
 // Finds all job in a given namespace.
 func (krc *KuberayAPIServerClient) ListRayJobs(request *api.ListRayJobsRequest) (*api.ListRayJobsResponse, *rpcStatus.Status, error) {
-	getURL := krc.baseURL + "/apis/v1/namespaces/" + request.Namespace + "/jobs"
+	getURL := krc.baseURL + "/apis/v1/namespaces/" + request.Namespace + "/jobs" + listQuery(request.PageToken, request.PageSize, map[string]string{
+		"user":          request.User,
+		"labelSelector": request.LabelSelector,
+	})
 	httpRequest, err := krc.createHttpRequest("GET", getURL, nil)
 	if err != nil {
 		return nil, nil, fmt.Errorf("failed to create http request for url '%s': %w", getURL, err)
@@ -323,8 +511,11 @@ func (krc *KuberayAPIServerClient) ListRayJobs(request *api.ListRayJobsRequest)
 }
 
 // ListAllRayJobs Finds all job in all namespaces.
-func (krc *KuberayAPIServerClient) ListAllRayJobs() (*api.ListAllRayJobsResponse, *rpcStatus.Status, error) {
-	getURL := krc.baseURL + "/apis/v1/jobs"
+func (krc *KuberayAPIServerClient) ListAllRayJobs(request *api.ListAllRayJobsRequest) (*api.ListAllRayJobsResponse, *rpcStatus.Status, error) {
+	getURL := krc.baseURL + "/apis/v1/jobs" + listQuery(request.PageToken, request.PageSize, map[string]string{
+		"user":          request.User,
+		"labelSelector": request.LabelSelector,
+	})
 	httpRequest, err := krc.createHttpRequest("GET", getURL, nil)
 	if err != nil {
 		return nil, nil, fmt.Errorf("failed to create http request for url '%s': %w", getURL, err)
@@ -426,7 +617,10 @@ func (krc *KuberayAPIServerClient) GetRayService(request *api.GetRayServiceReque
 
 // Finds all ray services in a given namespace. Supports pagination, and sorting on certain fields.
 func (krc *KuberayAPIServerClient) ListRayServices(request *api.ListRayServicesRequest) (*api.ListRayServicesResponse, *rpcStatus.Status, error) {
-	getURL := krc.baseURL + "/apis/v1/namespaces/" + request.Namespace + "/services"
+	getURL := krc.baseURL + "/apis/v1/namespaces/" + request.Namespace + "/services" + listQuery(request.PageToken, request.PageSize, map[string]string{
+		"user":          request.User,
+		"labelSelector": request.LabelSelector,
+	})
 	httpRequest, err := krc.createHttpRequest("GET", getURL, nil)
 	if err != nil {
 		return nil, nil, fmt.Errorf("failed to create http request for url '%s': %w", getURL, err)
@@ -446,8 +640,11 @@ func (krc *KuberayAPIServerClient) ListRayServices(request *api.ListRayServicesR
 }
 
 // Finds all ray services in a given namespace. Supports pagination, and sorting on certain fields.
-func (krc *KuberayAPIServerClient) ListAllRayServices() (*api.ListAllRayServicesResponse, *rpcStatus.Status, error) {
-	getURL := krc.baseURL + "/apis/v1/services"
+func (krc *KuberayAPIServerClient) ListAllRayServices(request *api.ListAllRayServicesRequest) (*api.ListAllRayServicesResponse, *rpcStatus.Status, error) {
+	getURL := krc.baseURL + "/apis/v1/services" + listQuery(request.PageToken, request.PageSize, map[string]string{
+		"user":          request.User,
+		"labelSelector": request.LabelSelector,
+	})
 	httpRequest, err := krc.createHttpRequest("GET", getURL, nil)
 	if err != nil {
 		return nil, nil, fmt.Errorf("failed to create http request for url '%s': %w", getURL, err)
@@ -541,6 +738,71 @@ func (krc *KuberayAPIServerClient) GetRayJobLog(request *api.GetJobLogRequest) (
 	return response, nil, nil
 }
 
+// TailRayJobLog. Follow the log of a specific job on a given cluster as server-sent events.
+// The handler is called with every received log chunk until the job finishes or the handler returns an error.
+func (krc *KuberayAPIServerClient) TailRayJobLog(request *api.TailJobLogRequest, handler func(*api.TailJobLogReply) error) (*rpcStatus.Status, error) {
+	getURL := krc.baseURL + "/apis/v1/namespaces/" + request.Namespace + "/jobsubmissions/" + request.Clustername + "/log/" + request.Submissionid + "/tail"
+	httpRequest, err := krc.createHttpRequest("GET", getURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create http request for url '%s': %w", getURL, err)
+	}
+
+	httpRequest.Header.Add("Accept", "text/event-stream")
+
+	response, err := krc.httpClient.Do(httpRequest)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute http request for url '%s': %w", getURL, err)
+	}
+	defer response.Body.Close()
+	if response.StatusCode != http.StatusOK {
+		bodyBytes, err := io.ReadAll(response.Body)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read response body bytes: %w", err)
+		}
+		status, err := krc.extractStatus(bytes.TrimPrefix(bodyBytes, []byte("data: ")))
+		if err != nil {
+			return nil, err
+		}
+		return status, &KuberayAPIServerClientError{
+			HTTPStatusCode: response.StatusCode,
+		}
+	}
+
+	scanner := bufio.NewScanner(response.Body)
+	scanner.Buffer(make([]byte, 64*1024), maxEventSize)
+	for scanner.Scan() {
+		data, ok := bytes.CutPrefix(scanner.Bytes(), []byte("data: "))
+		if !ok {
+			continue
+		}
+		chunk := struct {
+			Result json.RawMessage `json:"result"`
+			Error  json.RawMessage `json:"error"`
+		}{}
+		if err := json.Unmarshal(data, &chunk); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal stream chunk: %w", err)
+		}
+		if len(chunk.Error) > 0 {
+			status, err := krc.extractStatus(chunk.Error)
+			if err != nil {
+				return nil, err
+			}
+			return status, fmt.Errorf("kuberay api server stream failed: %s", status.GetMessage())
+		}
+		reply := &api.TailJobLogReply{}
+		if err := krc.unmarshaler.Unmarshal(chunk.Result, reply); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal log chunk: %w", err)
+		}
+		if err := handler(reply); err != nil {
+			return nil, err
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read log stream: %w", err)
+	}
+	return nil, nil
+}
+
 // ListRayJobsCluster. List Ray jobs on a given cluster.
 func (krc *KuberayAPIServerClient) ListRayJobsCluster(request *api.ListJobDetailsRequest) (*api.ListJobSubmissionInfo, *rpcStatus.Status, error) {
 	getURL := krc.baseURL + "/apis/v1/namespaces/" + request.Namespace + "/jobsubmissions/" + request.Clustername
@@ -628,10 +890,34 @@ func (krc *KuberayAPIServerClient) extractStatus(bodyBytes []byte) (*rpcStatus.S
 	return status, nil
 }
 
+// Build the query string carrying the pagination and filter parameters of a list request.
+// Empty filters are left out.
+func listQuery(pageToken string, pageSize int32, filters map[string]string) string {
+	query := url.Values{}
+	for key, value := range filters {
+		if value != "" {
+			query.Set(key, value)
+		}
+	}
+	if pageToken != "" {
+		query.Set("pageToken", pageToken)
+	}
+	if pageSize != 0 {
+		query.Set("pageSize", strconv.FormatInt(int64(pageSize), 10))
+	}
+	if len(query) == 0 {
+		return ""
+	}
+	return "?" + query.Encode()
+}
+
 func (krc *KuberayAPIServerClient) createHttpRequest(method string, endPoint string, body io.Reader) (*http.Request, error) {
 	req, err := http.NewRequest(method, endPoint, body)
 	if err != nil {
 		return nil, err
 	}
+	if krc.bearerToken != "" {
+		req.Header.Set("Authorization", "Bearer "+krc.bearerToken)
+	}
 	return req, nil
 }
@@ -0,0 +1,107 @@
+package interceptor
+
+import (
+	"context"
+
+	"github.com/ray-project/kuberay/apiserver/pkg/auth"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	klog "k8s.io/klog/v2"
+)
+
+// AuthorizationHeader is the gRPC metadata key of the bearer token. The HTTP gateway forwards the
+// `Authorization` header of REST requests under the same key.
+const AuthorizationHeader = "authorization"
+
+// errUnauthenticated is returned for every authentication failure. The reason is only logged, so that clients
+// learn nothing about the configured authenticators.
+var errUnauthenticated = status.Error(codes.Unauthenticated, "Request is not authenticated. Please provide a valid bearer token.")
+
+// AuthInterceptor returns a UnaryServerInterceptor that authenticates the bearer token of every request
+// and checks it against the authorizer before calling the handler. The principal is stored in the
+// handler context, see auth.FromContext.
+func AuthInterceptor(authenticator auth.Authenticator, authorizer *auth.Authorizer) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		principal, err := authenticate(ctx, authenticator, info.FullMethod)
+		if err != nil {
+			return nil, err
+		}
+
+		if authorizer != nil {
+			if err := authorizer.Authorize(ctx, principal, req); err != nil {
+				klog.Warningf("%v denied for user %s: %v", info.FullMethod, principal.Name, err)
+				return nil, err
+			}
+		}
+
+		return handler(auth.NewContext(ctx, principal), req)
+	}
+}
+
+// AuthStreamInterceptor returns a StreamServerInterceptor that authenticates the bearer token of every stream
+// and checks every received request message against the authorizer before the handler sees it.
+func AuthStreamInterceptor(authenticator auth.Authenticator, authorizer *auth.Authorizer) grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		principal, err := authenticate(ss.Context(), authenticator, info.FullMethod)
+		if err != nil {
+			return err
+		}
+
+		return handler(srv, &authorizedServerStream{
+			ServerStream: ss,
+			ctx:          auth.NewContext(ss.Context(), principal),
+			principal:    principal,
+			authorizer:   authorizer,
+			method:       info.FullMethod,
+		})
+	}
+}
+
+// authorizedServerStream wraps a grpc.ServerStream to authorize the received request messages.
+type authorizedServerStream struct {
+	grpc.ServerStream
+	ctx        context.Context
+	principal  *auth.Principal
+	authorizer *auth.Authorizer
+	method     string
+}
+
+func (s *authorizedServerStream) Context() context.Context {
+	return s.ctx
+}
+
+func (s *authorizedServerStream) RecvMsg(m interface{}) error {
+	if err := s.ServerStream.RecvMsg(m); err != nil {
+		return err
+	}
+	if s.authorizer != nil {
+		if err := s.authorizer.Authorize(s.ctx, s.principal, m); err != nil {
+			klog.Warningf("%v denied for user %s: %v", s.method, s.principal.Name, err)
+			return err
+		}
+	}
+	return nil
+}
+
+func authenticate(ctx context.Context, authenticator auth.Authenticator, method string) (*auth.Principal, error) {
+	var header string
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if values := md.Get(AuthorizationHeader); len(values) > 0 {
+			header = values[0]
+		}
+	}
+
+	token, err := auth.BearerToken(header)
+	if err != nil {
+		klog.Warningf("%v rejected: %v", method, err)
+		return nil, errUnauthenticated
+	}
+	principal, err := authenticator.Authenticate(token)
+	if err != nil {
+		klog.Warningf("%v rejected, the bearer token is invalid: %v", method, err)
+		return nil, errUnauthenticated
+	}
+	return principal, nil
+}
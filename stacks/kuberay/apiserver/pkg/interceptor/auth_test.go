@@ -0,0 +1,91 @@
+package interceptor
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/ray-project/kuberay/apiserver/pkg/auth"
+	"github.com/ray-project/kuberay/apiserver/pkg/util"
+	api "github.com/ray-project/kuberay/proto/go_client"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestAuthInterceptor(t *testing.T) {
+	authenticator, err := auth.NewStaticTokenAuthenticator(strings.NewReader("token-bob,bob\n"))
+	require.NoError(t, err)
+	authInterceptor := AuthInterceptor(authenticator, auth.NewAuthorizer(&auth.Policy{
+		NamespaceOwners: map[string][]string{"team-a": {"alice"}},
+	}, nil))
+
+	info := &grpc.UnaryServerInfo{FullMethod: "/proto.ClusterService/ListCluster"}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		principal, ok := auth.FromContext(ctx)
+		require.True(t, ok)
+		return principal.Name, nil
+	}
+	withToken := func(header string) context.Context {
+		return metadata.NewIncomingContext(context.Background(), metadata.Pairs(AuthorizationHeader, header))
+	}
+
+	resp, err := authInterceptor(withToken("Bearer token-bob"), &api.ListClustersRequest{Namespace: "shared"}, info, handler)
+	require.NoError(t, err)
+	assert.Equal(t, "bob", resp)
+
+	_, err = authInterceptor(context.Background(), &api.ListClustersRequest{Namespace: "shared"}, info, handler)
+	assert.Equal(t, errUnauthenticated, err)
+
+	_, err = authInterceptor(withToken("Bearer token-eve"), &api.ListClustersRequest{Namespace: "shared"}, info, handler)
+	assert.Equal(t, errUnauthenticated, err)
+
+	_, err = authInterceptor(withToken("Bearer token-bob"), &api.ListClustersRequest{Namespace: "team-a"}, info, handler)
+	assert.True(t, util.IsUserErrorCodeMatch(err, codes.PermissionDenied), "unexpected error: %v", err)
+}
+
+type fakeServerStream struct {
+	grpc.ServerStream
+	ctx context.Context
+	req *api.TailJobLogRequest
+}
+
+func (s *fakeServerStream) Context() context.Context {
+	return s.ctx
+}
+
+func (s *fakeServerStream) RecvMsg(m interface{}) error {
+	proto.Merge(m.(proto.Message), s.req)
+	return nil
+}
+
+func TestAuthStreamInterceptor(t *testing.T) {
+	authenticator, err := auth.NewStaticTokenAuthenticator(strings.NewReader("token-bob,bob\n"))
+	require.NoError(t, err)
+	authInterceptor := AuthStreamInterceptor(authenticator, auth.NewAuthorizer(&auth.Policy{
+		NamespaceOwners: map[string][]string{"team-a": {"alice"}},
+	}, nil))
+
+	info := &grpc.StreamServerInfo{FullMethod: "/proto.RayJobSubmissionService/TailJobLog", IsServerStream: true}
+	handler := func(srv interface{}, stream grpc.ServerStream) error {
+		principal, ok := auth.FromContext(stream.Context())
+		require.True(t, ok)
+		assert.Equal(t, "bob", principal.Name)
+		return stream.RecvMsg(&api.TailJobLogRequest{})
+	}
+	newStream := func(header string, namespace string) *fakeServerStream {
+		return &fakeServerStream{
+			ctx: metadata.NewIncomingContext(context.Background(), metadata.Pairs(AuthorizationHeader, header)),
+			req: &api.TailJobLogRequest{Namespace: namespace, Clustername: "cluster", Submissionid: "id"},
+		}
+	}
+
+	err = authInterceptor(nil, newStream("Bearer token-eve", "team-b"), info, handler)
+	assert.Equal(t, errUnauthenticated, err)
+
+	err = authInterceptor(nil, newStream("Bearer token-bob", "team-a"), info, handler)
+	assert.True(t, util.IsUserErrorCodeMatch(err, codes.PermissionDenied), "unexpected error: %v", err)
+}
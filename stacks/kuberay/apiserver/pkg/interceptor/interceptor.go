@@ -2,8 +2,13 @@ package interceptor
 
 import (
 	"context"
+	"time"
 
+	"github.com/ray-project/kuberay/apiserver/pkg/auth"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
 	klog "k8s.io/klog/v2"
 )
 
@@ -19,3 +24,59 @@ func ApiServerInterceptor(ctx context.Context, req interface{}, info *grpc.Unary
 	klog.Infof("%v handler finished", info.FullMethod)
 	return
 }
+
+// ApiServerStreamInterceptor implements StreamServerInterceptor with the same wrapping logic as ApiServerInterceptor.
+func ApiServerStreamInterceptor(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	klog.Infof("%v handler starting", info.FullMethod)
+	err := handler(srv, ss)
+	if err != nil {
+		klog.Warning(err)
+	}
+	klog.Infof("%v handler finished", info.FullMethod)
+	return err
+}
+
+// AccessLogInterceptor writes a structured access log entry for every unary request. It must be chained after the
+// auth interceptors so that the entries name the authenticated user. Requests rejected by the auth interceptors are
+// logged by them.
+func AccessLogInterceptor(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	start := time.Now()
+	resp, err := handler(ctx, req)
+	logAccess(ctx, info.FullMethod, err, time.Since(start))
+	return resp, err
+}
+
+// AccessLogStreamInterceptor is the StreamServerInterceptor counterpart of AccessLogInterceptor.
+func AccessLogStreamInterceptor(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	start := time.Now()
+	err := handler(srv, ss)
+	logAccess(ss.Context(), info.FullMethod, err, time.Since(start))
+	return err
+}
+
+// logAccess writes a structured access log entry for a finished request.
+func logAccess(ctx context.Context, method string, err error, duration time.Duration) {
+	keysAndValues := []interface{}{
+		"method", method,
+		"code", status.Code(err).String(),
+		"duration", duration,
+	}
+	if principal, ok := auth.FromContext(ctx); ok {
+		keysAndValues = append(keysAndValues, "user", principal.Name)
+	}
+	if p, ok := peer.FromContext(ctx); ok {
+		keysAndValues = append(keysAndValues, "peer", p.Addr.String())
+	}
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		// Requests proxied by the HTTP gateway carry the original client information.
+		if values := md.Get("x-forwarded-for"); len(values) > 0 {
+			keysAndValues = append(keysAndValues, "forwardedFor", values[0])
+		}
+		if values := md.Get("grpcgateway-user-agent"); len(values) > 0 {
+			keysAndValues = append(keysAndValues, "userAgent", values[0])
+		} else if values := md.Get("user-agent"); len(values) > 0 {
+			keysAndValues = append(keysAndValues, "userAgent", values[0])
+		}
+	}
+	klog.InfoS("API request handled", keysAndValues...)
+}
@@ -23,28 +23,60 @@ const DefaultNamespace = "ray-system"
 type ResourceManagerInterface interface {
 	CreateCluster(ctx context.Context, apiCluster *api.Cluster) (*rayv1api.RayCluster, error)
 	GetCluster(ctx context.Context, clusterName string, namespace string) (*rayv1api.RayCluster, error)
-	ListClusters(ctx context.Context, namespace string) ([]*rayv1api.RayCluster, error)
-	ListAllClusters(ctx context.Context) ([]*rayv1api.RayCluster, error)
+	ListClusters(ctx context.Context, namespace string, options *ListOptions) ([]*rayv1api.RayCluster, *ListPage, error)
+	ListAllClusters(ctx context.Context, options *ListOptions) ([]*rayv1api.RayCluster, *ListPage, error)
+	UpdateCluster(ctx context.Context, apiCluster *api.Cluster, updatePaths []string) (*rayv1api.RayCluster, error)
+	SuspendCluster(ctx context.Context, clusterName string, namespace string) (*rayv1api.RayCluster, error)
+	ResumeCluster(ctx context.Context, clusterName string, namespace string) (*rayv1api.RayCluster, error)
 	DeleteCluster(ctx context.Context, clusterName string, namespace string) error
 	CreateComputeTemplate(ctx context.Context, runtime *api.ComputeTemplate) (*corev1.ConfigMap, error)
 	GetComputeTemplate(ctx context.Context, name string, namespace string) (*corev1.ConfigMap, error)
-	ListComputeTemplates(ctx context.Context, namespace string) ([]*corev1.ConfigMap, error)
+	ListComputeTemplates(ctx context.Context, namespace string, labelSelector string) ([]*corev1.ConfigMap, error)
 	DeleteComputeTemplate(ctx context.Context, name string, namespace string) error
+	CreateImageTemplate(ctx context.Context, imageTemplate *api.ImageTemplate) (*corev1.ConfigMap, error)
+	GetImageTemplate(ctx context.Context, name string, namespace string) (*corev1.ConfigMap, error)
+	ListImageTemplates(ctx context.Context, namespace string) ([]*corev1.ConfigMap, error)
+	DeleteImageTemplate(ctx context.Context, name string, namespace string) error
 	CreateJob(ctx context.Context, apiJob *api.RayJob) (*rayv1api.RayJob, error)
 	GetJob(ctx context.Context, jobName string, namespace string) (*rayv1api.RayJob, error)
-	ListJobs(ctx context.Context, namespace string) ([]*rayv1api.RayJob, error)
-	ListAllJobs(ctx context.Context) ([]*rayv1api.RayJob, error)
+	ListJobs(ctx context.Context, namespace string, options *ListOptions) ([]*rayv1api.RayJob, *ListPage, error)
+	ListAllJobs(ctx context.Context, options *ListOptions) ([]*rayv1api.RayJob, *ListPage, error)
 	DeleteJob(ctx context.Context, jobName string, namespace string) error
 	CreateService(ctx context.Context, apiService *api.RayService) (*rayv1api.RayService, error)
 	UpdateRayService(ctx context.Context, request *api.UpdateRayServiceRequest) (*rayv1api.RayService, error)
 	GetService(ctx context.Context, serviceName, namespace string) error
-	ListServices(ctx context.Context, namespace string) ([]*rayv1api.RayService, error)
-	ListAllServices(ctx context.Context) ([]*rayv1api.RayService, error)
+	ListServices(ctx context.Context, namespace string, options *ListOptions) ([]*rayv1api.RayService, *ListPage, error)
+	ListAllServices(ctx context.Context, options *ListOptions) ([]*rayv1api.RayService, *ListPage, error)
 	DeleteService(ctx context.Context, serviceName, namespace string) error
 	GetClusterEvents(ctx context.Context, clusterName string, namespace string) ([]corev1.Event, error)
 	GetServiceEvents(ctx context.Context, service rayv1api.RayService) ([]corev1.Event, error)
 }
 
+// ListOptions holds the pagination and filtering parameters of a list call.
+type ListOptions struct {
+	// The token returned by the previous page. Empty for the first page.
+	PageToken string
+	// The maximum number of items to return. Zero means no limit.
+	PageSize int32
+	// Only list the resources owned by this user.
+	User string
+	// Only list the clusters of this environment.
+	Environment string
+	// An additional Kubernetes label selector the resources have to match.
+	LabelSelector string
+	// Only list the clusters in this state. Checked after fetching the clusters, so the total size is
+	// not reported with it.
+	ClusterState string
+}
+
+// ListPage describes where a page of results sits in the full listing.
+type ListPage struct {
+	// The token to fetch the next page. Empty when this is the last page.
+	NextPageToken string
+	// The number of items in this page and all the pages after it. Zero when it is unknown.
+	TotalSize int32
+}
+
 type ResourceManager struct {
 	clientManager ClientManagerInterface
 }
@@ -81,6 +113,108 @@ func (r *ResourceManager) getKubernetesNamespaceClient() clientv1.NamespaceInter
 	return r.clientManager.KubernetesClient().NamespaceClient()
 }
 
+// Build a label selector from a set of required labels and an optional user provided selector.
+func buildLabelSelector(matchLabels map[string]string, labelSelector string) (string, error) {
+	selector := labels.SelectorFromSet(matchLabels)
+	if labelSelector == "" {
+		return selector.String(), nil
+	}
+	parsed, err := labels.Parse(labelSelector)
+	if err != nil {
+		return "", util.NewInvalidInputError("Label selector %s is invalid: %v", labelSelector, err)
+	}
+	requirements, _ := parsed.Requirements()
+	return selector.Add(requirements...).String(), nil
+}
+
+// Build the Kubernetes list options selecting the resources managed by the API server.
+// The page token is the Kubernetes continue token of the previous page.
+func buildManagedListOptions(options *ListOptions) (metav1.ListOptions, error) {
+	matchLabels := map[string]string{
+		util.KubernetesManagedByLabelKey: util.ComponentName,
+	}
+	if options == nil {
+		return metav1.ListOptions{LabelSelector: labels.Set(matchLabels).String()}, nil
+	}
+	if options.User != "" {
+		matchLabels[util.RayClusterUserLabelKey] = options.User
+	}
+	if options.Environment != "" {
+		matchLabels[util.RayClusterEnvironmentLabelKey] = options.Environment
+	}
+	labelSelector, err := buildLabelSelector(matchLabels, options.LabelSelector)
+	if err != nil {
+		return metav1.ListOptions{}, err
+	}
+	return metav1.ListOptions{
+		LabelSelector: labelSelector,
+		Continue:      options.PageToken,
+		Limit:         int64(options.PageSize),
+	}, nil
+}
+
+// Check whether a cluster matches the filters which can not be expressed as a label selector.
+func matchesClusterFilters(cluster *rayv1api.RayCluster, options *ListOptions) bool {
+	if options == nil || options.ClusterState == "" {
+		return true
+	}
+	return string(cluster.Status.State) == options.ClusterState
+}
+
+// List the clusters matching the options. The cluster state can not be selected by Kubernetes, so
+// when it is set further Kubernetes pages are fetched until the page is full or there are no more
+// clusters. The total size is unknown in that case and left unset.
+func listClusters(ctx context.Context, client rayv1.RayClusterInterface, options *ListOptions) ([]*rayv1api.RayCluster, *ListPage, error) {
+	listOptions, err := buildManagedListOptions(options)
+	if err != nil {
+		return nil, nil, err
+	}
+	if options == nil || options.ClusterState == "" {
+		rayClusterList, err := client.List(ctx, listOptions)
+		if err != nil {
+			return nil, nil, err
+		}
+		var result []*rayv1api.RayCluster
+		length := len(rayClusterList.Items)
+		for i := 0; i < length; i++ {
+			result = append(result, &rayClusterList.Items[i])
+		}
+		return result, buildListPage(rayClusterList.ListMeta, length), nil
+	}
+
+	var result []*rayv1api.RayCluster
+	for {
+		// Only ask for the missing clusters, so that the continue token does not skip any match.
+		if options.PageSize > 0 {
+			listOptions.Limit = int64(options.PageSize) - int64(len(result))
+		}
+		rayClusterList, err := client.List(ctx, listOptions)
+		if err != nil {
+			return nil, nil, err
+		}
+		for i := range rayClusterList.Items {
+			if matchesClusterFilters(&rayClusterList.Items[i], options) {
+				result = append(result, &rayClusterList.Items[i])
+			}
+		}
+		listOptions.Continue = rayClusterList.Continue
+		if listOptions.Continue == "" || (options.PageSize > 0 && len(result) >= int(options.PageSize)) {
+			return result, &ListPage{NextPageToken: listOptions.Continue}, nil
+		}
+	}
+}
+
+// Build the page information from the metadata of a Kubernetes list. Kubernetes does not count the
+// remaining items of a list with a label selector, which the managed lists always have, so the total
+// size is only known and set on the last page.
+func buildListPage(listMeta metav1.ListMeta, length int) *ListPage {
+	page := &ListPage{NextPageToken: listMeta.Continue}
+	if listMeta.Continue == "" {
+		page.TotalSize = int32(length)
+	}
+	return page
+}
+
 // clusters
 func (r *ResourceManager) CreateCluster(ctx context.Context, apiCluster *api.Cluster) (*rayv1api.RayCluster, error) {
 	// populate cluster map
@@ -88,9 +222,13 @@ func (r *ResourceManager) CreateCluster(ctx context.Context, apiCluster *api.Clu
 	if err != nil {
 		return nil, util.NewInternalServerError(err, "Failed to populate compute template for (%s/%s)", apiCluster.Namespace, apiCluster.Name)
 	}
+	imageTemplateDict, err := r.populateImageTemplate(ctx, apiCluster.ClusterSpec, apiCluster.Namespace)
+	if err != nil {
+		return nil, util.NewInternalServerError(err, "Failed to populate image template for (%s/%s)", apiCluster.Namespace, apiCluster.Name)
+	}
 
 	// convert *api.Cluster to rayv1api.RayCluster
-	rayCluster, err := util.NewRayCluster(apiCluster, computeTemplateDict)
+	rayCluster, err := util.NewRayCluster(apiCluster, computeTemplateDict, imageTemplateDict)
 	if err != nil {
 		return nil, util.NewInvalidInputErrorWithDetails(err, "Failed to create a Ray cluster")
 	}
@@ -108,21 +246,25 @@ func (r *ResourceManager) CreateCluster(ctx context.Context, apiCluster *api.Clu
 }
 
 // Compute template
+// The head group may be missing and worker groups may omit their compute template when
+// only a part of the cluster spec is provided, as in an update.
 func (r *ResourceManager) populateComputeTemplate(ctx context.Context, clusterSpec *api.ClusterSpec, nameSpace string) (map[string]*api.ComputeTemplate, error) {
 	dict := map[string]*api.ComputeTemplate{}
 	// populate head compute template
-	name := clusterSpec.HeadGroupSpec.ComputeTemplate
-	configMap, err := r.GetComputeTemplate(ctx, name, nameSpace)
-	if err != nil {
-		return nil, err
+	if clusterSpec.HeadGroupSpec != nil {
+		name := clusterSpec.HeadGroupSpec.ComputeTemplate
+		configMap, err := r.GetComputeTemplate(ctx, name, nameSpace)
+		if err != nil {
+			return nil, err
+		}
+		computeTemplate := model.FromKubeToAPIComputeTemplate(configMap)
+		dict[name] = computeTemplate
 	}
-	computeTemplate := model.FromKubeToAPIComputeTemplate(configMap)
-	dict[name] = computeTemplate
 
 	// populate worker compute template
 	for _, spec := range clusterSpec.WorkerGroupSpec {
 		name := spec.ComputeTemplate
-		if _, exist := dict[name]; !exist {
+		if _, exist := dict[name]; !exist && name != "" {
 			configMap, err := r.GetComputeTemplate(ctx, name, nameSpace)
 			if err != nil {
 				return nil, err
@@ -135,59 +277,122 @@ func (r *ResourceManager) populateComputeTemplate(ctx context.Context, clusterSp
 	return dict, nil
 }
 
+// Image template
+func (r *ResourceManager) populateImageTemplate(ctx context.Context, clusterSpec *api.ClusterSpec, nameSpace string) (map[string]*api.ImageTemplate, error) {
+	dict := map[string]*api.ImageTemplate{}
+	names := []string{}
+	if clusterSpec.HeadGroupSpec != nil {
+		names = append(names, clusterSpec.HeadGroupSpec.ImageTemplate)
+	}
+	for _, spec := range clusterSpec.WorkerGroupSpec {
+		names = append(names, spec.ImageTemplate)
+	}
+
+	for _, name := range names {
+		if _, exist := dict[name]; exist || name == "" {
+			continue
+		}
+		configMap, err := r.GetImageTemplate(ctx, name, nameSpace)
+		if err != nil {
+			return nil, err
+		}
+		dict[name] = model.FromKubeToAPIImageTemplate(configMap)
+	}
+
+	return dict, nil
+}
+
 func (r *ResourceManager) GetCluster(ctx context.Context, clusterName string, namespace string) (*rayv1api.RayCluster, error) {
 	client := r.getRayClusterClient(namespace)
 	return getClusterByName(ctx, client, clusterName)
 }
 
-func (r *ResourceManager) ListClusters(ctx context.Context, namespace string) ([]*rayv1api.RayCluster, error) {
-	labelSelector := metav1.LabelSelector{
-		MatchLabels: map[string]string{
-			util.KubernetesManagedByLabelKey: util.ComponentName,
-		},
-	}
-	rayClusterList, err := r.getRayClusterClient(namespace).List(ctx, metav1.ListOptions{
-		LabelSelector: labels.Set(labelSelector.MatchLabels).String(),
-	})
+func (r *ResourceManager) ListClusters(ctx context.Context, namespace string, options *ListOptions) ([]*rayv1api.RayCluster, *ListPage, error) {
+	result, page, err := listClusters(ctx, r.getRayClusterClient(namespace), options)
 	if err != nil {
-		return nil, util.Wrap(err, fmt.Sprintf("List RayCluster failed in %s", namespace))
+		return nil, nil, util.Wrap(err, fmt.Sprintf("List RayCluster failed in %s", namespace))
 	}
+	return result, page, nil
+}
 
-	var result []*rayv1api.RayCluster
-	length := len(rayClusterList.Items)
-	for i := 0; i < length; i++ {
-		result = append(result, &rayClusterList.Items[i])
+// List clusters across all namespaces. A single cluster scoped list is used so that
+// Kubernetes can paginate over the whole result.
+func (r *ResourceManager) ListAllClusters(ctx context.Context, options *ListOptions) ([]*rayv1api.RayCluster, *ListPage, error) {
+	result, page, err := listClusters(ctx, r.getRayClusterClient(metav1.NamespaceAll), options)
+	if err != nil {
+		return nil, nil, util.Wrap(err, "List RayCluster failed in all namespaces")
 	}
-
-	return result, nil
+	return result, page, nil
 }
 
-func (r *ResourceManager) ListAllClusters(ctx context.Context) ([]*rayv1api.RayCluster, error) {
-	namespaces, err := r.getKubernetesNamespaceClient().List(ctx, metav1.ListOptions{})
+// Update the parts of a cluster selected by the update mask paths. Compute and image templates are
+// only resolved for the node groups present in the given cluster spec.
+func (r *ResourceManager) UpdateCluster(ctx context.Context, apiCluster *api.Cluster, updatePaths []string) (*rayv1api.RayCluster, error) {
+	client := r.getRayClusterClient(apiCluster.Namespace)
+	oldCluster, err := getClusterByName(ctx, client, apiCluster.Name)
 	if err != nil {
-		return nil, util.Wrap(err, "Failed to fetch all Kubernetes namespaces")
+		return nil, util.Wrap(err, fmt.Sprintf("Update cluster fail, no cluster named: %s ", apiCluster.Name))
 	}
 
-	var result []*rayv1api.RayCluster
-	labelSelector := metav1.LabelSelector{
-		MatchLabels: map[string]string{
-			util.KubernetesManagedByLabelKey: util.ComponentName,
-		},
-	}
-	for _, namespace := range namespaces.Items {
-		rayClusterList, err := r.getRayClusterClient(namespace.Name).List(ctx, metav1.ListOptions{
-			LabelSelector: labels.Set(labelSelector.MatchLabels).String(),
-		})
+	computeTemplateDict := map[string]*api.ComputeTemplate{}
+	imageTemplateDict := map[string]*api.ImageTemplate{}
+	if apiCluster.ClusterSpec != nil {
+		// Keep the current values of the node group fields which are not sent by the client.
+		currentSpec := model.PopulateRayClusterSpec(*oldCluster.Spec.DeepCopy())
+		apiCluster.ClusterSpec = util.MergeClusterSpec(currentSpec, apiCluster.ClusterSpec, updatePaths)
+		computeTemplateDict, err = r.populateComputeTemplate(ctx, apiCluster.ClusterSpec, apiCluster.Namespace)
 		if err != nil {
-			return nil, util.Wrap(err, fmt.Sprintf("List RayCluster failed in %s", namespace.Name))
+			return nil, util.NewInternalServerError(err, "Failed to populate compute template for (%s/%s)", apiCluster.Namespace, apiCluster.Name)
 		}
-
-		length := len(rayClusterList.Items)
-		for i := 0; i < length; i++ {
-			result = append(result, &rayClusterList.Items[i])
+		imageTemplateDict, err = r.populateImageTemplate(ctx, apiCluster.ClusterSpec, apiCluster.Namespace)
+		if err != nil {
+			return nil, util.NewInternalServerError(err, "Failed to populate image template for (%s/%s)", apiCluster.Namespace, apiCluster.Name)
 		}
 	}
-	return result, nil
+
+	rayCluster := oldCluster.DeepCopy()
+	if err := util.UpdateRayCluster(rayCluster, apiCluster, updatePaths, computeTemplateDict, imageTemplateDict); err != nil {
+		return nil, util.NewInvalidInputErrorWithDetails(err, "Failed to update a Ray cluster")
+	}
+	if rayCluster.Annotations == nil {
+		rayCluster.Annotations = map[string]string{}
+	}
+	rayCluster.Annotations["ray.io/update-timestamp"] = r.clientManager.Time().Now().String()
+
+	newRayCluster, err := client.Update(ctx, rayCluster, metav1.UpdateOptions{})
+	if err != nil {
+		return nil, util.NewInternalServerError(err, "Failed to update cluster for (%s/%s)", rayCluster.Namespace, rayCluster.Name)
+	}
+	return newRayCluster, nil
+}
+
+// Suspend a cluster. The operator deletes all the pods of a suspended cluster.
+func (r *ResourceManager) SuspendCluster(ctx context.Context, clusterName string, namespace string) (*rayv1api.RayCluster, error) {
+	return r.setClusterSuspend(ctx, clusterName, namespace, true)
+}
+
+// Resume a suspended cluster. The operator creates the pods of the cluster again.
+func (r *ResourceManager) ResumeCluster(ctx context.Context, clusterName string, namespace string) (*rayv1api.RayCluster, error) {
+	return r.setClusterSuspend(ctx, clusterName, namespace, false)
+}
+
+func (r *ResourceManager) setClusterSuspend(ctx context.Context, clusterName string, namespace string, suspend bool) (*rayv1api.RayCluster, error) {
+	client := r.getRayClusterClient(namespace)
+	oldCluster, err := getClusterByName(ctx, client, clusterName)
+	if err != nil {
+		return nil, util.Wrap(err, "Get cluster failure")
+	}
+	if oldCluster.Spec.Suspend != nil && *oldCluster.Spec.Suspend == suspend {
+		return oldCluster, nil
+	}
+
+	rayCluster := oldCluster.DeepCopy()
+	rayCluster.Spec.Suspend = &suspend
+	newRayCluster, err := client.Update(ctx, rayCluster, metav1.UpdateOptions{})
+	if err != nil {
+		return nil, util.NewInternalServerError(err, "Failed to set suspend to %t for cluster (%s/%s)", suspend, namespace, clusterName)
+	}
+	return newRayCluster, nil
 }
 
 func (r *ResourceManager) DeleteCluster(ctx context.Context, clusterName string, namespace string) error {
@@ -208,6 +413,7 @@ func (r *ResourceManager) DeleteCluster(ctx context.Context, clusterName string,
 
 func (r *ResourceManager) CreateJob(ctx context.Context, apiJob *api.RayJob) (*rayv1api.RayJob, error) {
 	computeTemplateMap := make(map[string]*api.ComputeTemplate)
+	imageTemplateMap := make(map[string]*api.ImageTemplate)
 	var err error
 
 	// populate cluster map
@@ -216,10 +422,14 @@ func (r *ResourceManager) CreateJob(ctx context.Context, apiJob *api.RayJob) (*r
 		if err != nil {
 			return nil, util.NewInternalServerError(err, "Failed to populate compute template for (%s/%s)", apiJob.Namespace, apiJob.JobId)
 		}
+		imageTemplateMap, err = r.populateImageTemplate(ctx, apiJob.ClusterSpec, apiJob.Namespace)
+		if err != nil {
+			return nil, util.NewInternalServerError(err, "Failed to populate image template for (%s/%s)", apiJob.Namespace, apiJob.JobId)
+		}
 	}
 
 	// convert *api.Cluster to rayv1api.RayCluster
-	rayJob, err := util.NewRayJob(apiJob, computeTemplateMap)
+	rayJob, err := util.NewRayJob(apiJob, computeTemplateMap, imageTemplateMap)
 	if err != nil {
 		return nil, util.NewInvalidInputErrorWithDetails(err, "Failed to create a Ray Job")
 	}
@@ -237,17 +447,14 @@ func (r *ResourceManager) GetJob(ctx context.Context, jobName string, namespace
 	return getJobByName(ctx, client, jobName)
 }
 
-func (r *ResourceManager) ListJobs(ctx context.Context, namespace string) ([]*rayv1api.RayJob, error) {
-	labelSelector := metav1.LabelSelector{
-		MatchLabels: map[string]string{
-			util.KubernetesManagedByLabelKey: util.ComponentName,
-		},
+func (r *ResourceManager) ListJobs(ctx context.Context, namespace string, options *ListOptions) ([]*rayv1api.RayJob, *ListPage, error) {
+	listOptions, err := buildManagedListOptions(options)
+	if err != nil {
+		return nil, nil, err
 	}
-	rayJobList, err := r.getRayJobClient(namespace).List(ctx, metav1.ListOptions{
-		LabelSelector: labels.Set(labelSelector.MatchLabels).String(),
-	})
+	rayJobList, err := r.getRayJobClient(namespace).List(ctx, listOptions)
 	if err != nil {
-		return nil, util.Wrap(err, fmt.Sprintf("List RayCluster failed in %s", namespace))
+		return nil, nil, util.Wrap(err, fmt.Sprintf("List RayJob failed in %s", namespace))
 	}
 
 	var result []*rayv1api.RayJob
@@ -256,35 +463,28 @@ func (r *ResourceManager) ListJobs(ctx context.Context, namespace string) ([]*ra
 		result = append(result, &rayJobList.Items[i])
 	}
 
-	return result, nil
+	return result, buildListPage(rayJobList.ListMeta, length), nil
 }
 
-func (r *ResourceManager) ListAllJobs(ctx context.Context) ([]*rayv1api.RayJob, error) {
-	namespaces, err := r.getKubernetesNamespaceClient().List(ctx, metav1.ListOptions{})
+// List jobs across all namespaces. A single cluster scoped list is used so that
+// Kubernetes can paginate over the whole result.
+func (r *ResourceManager) ListAllJobs(ctx context.Context, options *ListOptions) ([]*rayv1api.RayJob, *ListPage, error) {
+	listOptions, err := buildManagedListOptions(options)
 	if err != nil {
-		return nil, util.Wrap(err, "Failed to fetch all Kubernetes namespaces")
+		return nil, nil, err
+	}
+	rayJobList, err := r.getRayJobClient(metav1.NamespaceAll).List(ctx, listOptions)
+	if err != nil {
+		return nil, nil, util.Wrap(err, "List RayJob failed in all namespaces")
 	}
 
 	var result []*rayv1api.RayJob
-	labelSelector := metav1.LabelSelector{
-		MatchLabels: map[string]string{
-			util.KubernetesManagedByLabelKey: util.ComponentName,
-		},
+	length := len(rayJobList.Items)
+	for i := 0; i < length; i++ {
+		result = append(result, &rayJobList.Items[i])
 	}
-	for _, namespace := range namespaces.Items {
-		rayJobList, err := r.getRayJobClient(namespace.Name).List(ctx, metav1.ListOptions{
-			LabelSelector: labels.Set(labelSelector.MatchLabels).String(),
-		})
-		if err != nil {
-			return nil, util.Wrap(err, fmt.Sprintf("List RayCluster failed in %s", namespace.Name))
-		}
 
-		length := len(rayJobList.Items)
-		for i := 0; i < length; i++ {
-			result = append(result, &rayJobList.Items[i])
-		}
-	}
-	return result, nil
+	return result, buildListPage(rayJobList.ListMeta, length), nil
 }
 
 func (r *ResourceManager) DeleteJob(ctx context.Context, jobName string, namespace string) error {
@@ -309,7 +509,11 @@ func (r *ResourceManager) CreateService(ctx context.Context, apiService *api.Ray
 	if err != nil {
 		return nil, util.NewInternalServerError(err, "Failed to populate compute template for (%s/%s)", apiService.Namespace, apiService.Name)
 	}
-	rayService, err := util.NewRayService(apiService, computeTemplateDict)
+	imageTemplateDict, err := r.populateImageTemplate(ctx, apiService.ClusterSpec, apiService.Namespace)
+	if err != nil {
+		return nil, util.NewInternalServerError(err, "Failed to populate image template for (%s/%s)", apiService.Namespace, apiService.Name)
+	}
+	rayService, err := util.NewRayService(apiService, computeTemplateDict, imageTemplateDict)
 	if err != nil {
 		return nil, util.NewInvalidInputErrorWithDetails(err, "Failed to create a Ray Service")
 	}
@@ -336,7 +540,11 @@ func (r *ResourceManager) UpdateRayService(ctx context.Context, apiService *api.
 	if err != nil {
 		return nil, util.NewInternalServerError(err, "Failed to populate compute template for (%s/%s)", apiService.Namespace, apiService.Name)
 	}
-	rayService, err := util.NewRayService(apiService, computeTemplateDict)
+	imageTemplateDict, err := r.populateImageTemplate(ctx, apiService.ClusterSpec, apiService.Namespace)
+	if err != nil {
+		return nil, util.NewInternalServerError(err, "Failed to populate image template for (%s/%s)", apiService.Namespace, apiService.Name)
+	}
+	rayService, err := util.NewRayService(apiService, computeTemplateDict, imageTemplateDict)
 	if err != nil {
 		return nil, err
 	}
@@ -354,42 +562,40 @@ func (r *ResourceManager) GetService(ctx context.Context, serviceName, namespace
 	return getServiceByName(ctx, client, serviceName)
 }
 
-func (r *ResourceManager) ListServices(ctx context.Context, namespace string) ([]*rayv1api.RayService, error) {
-	labelSelector := metav1.LabelSelector{
-		MatchLabels: map[string]string{
-			util.KubernetesManagedByLabelKey: util.ComponentName,
-		},
+func (r *ResourceManager) ListServices(ctx context.Context, namespace string, options *ListOptions) ([]*rayv1api.RayService, *ListPage, error) {
+	listOptions, err := buildManagedListOptions(options)
+	if err != nil {
+		return nil, nil, err
 	}
-	rayServiceList, err := r.getRayServiceClient(namespace).List(ctx, metav1.ListOptions{
-		LabelSelector: labels.Set(labelSelector.MatchLabels).String(),
-	})
+	rayServiceList, err := r.getRayServiceClient(namespace).List(ctx, listOptions)
 	if err != nil {
-		return nil, util.Wrap(err, fmt.Sprintf("List RayService failed in %s", namespace))
+		return nil, nil, util.Wrap(err, fmt.Sprintf("List RayService failed in %s", namespace))
 	}
 	rayServices := make([]*rayv1api.RayService, 0)
-	for _, service := range rayServiceList.Items {
-		rayServices = append(rayServices, &service)
+	for i := range rayServiceList.Items {
+		rayServices = append(rayServices, &rayServiceList.Items[i])
 	}
 
-	return rayServices, nil
+	return rayServices, buildListPage(rayServiceList.ListMeta, len(rayServices)), nil
 }
 
-func (r *ResourceManager) ListAllServices(ctx context.Context) ([]*rayv1api.RayService, error) {
-	rayServices := make([]*rayv1api.RayService, 0)
-
-	namespaces, err := r.getKubernetesNamespaceClient().List(ctx, metav1.ListOptions{})
+// List services across all namespaces. A single cluster scoped list is used so that
+// Kubernetes can paginate over the whole result.
+func (r *ResourceManager) ListAllServices(ctx context.Context, options *ListOptions) ([]*rayv1api.RayService, *ListPage, error) {
+	listOptions, err := buildManagedListOptions(options)
 	if err != nil {
-		return nil, util.Wrap(err, "Failed to fetch all Kubernetes namespaces")
+		return nil, nil, err
 	}
-
-	for _, namespace := range namespaces.Items {
-		servicesByNamespace, err := r.ListServices(ctx, namespace.Name)
-		if err != nil {
-			return nil, util.Wrap(err, "List All Rayservices failed")
-		}
-		rayServices = append(rayServices, servicesByNamespace...)
+	rayServiceList, err := r.getRayServiceClient(metav1.NamespaceAll).List(ctx, listOptions)
+	if err != nil {
+		return nil, nil, util.Wrap(err, "List All Rayservices failed")
 	}
-	return rayServices, nil
+	rayServices := make([]*rayv1api.RayService, 0)
+	for i := range rayServiceList.Items {
+		rayServices = append(rayServices, &rayServiceList.Items[i])
+	}
+
+	return rayServices, buildListPage(rayServiceList.ListMeta, len(rayServices)), nil
 }
 
 func (r *ResourceManager) DeleteService(ctx context.Context, serviceName, namespace string) error {
@@ -431,9 +637,13 @@ func (r *ResourceManager) GetComputeTemplate(ctx context.Context, name string, n
 	return getComputeTemplateByName(ctx, client, name)
 }
 
-func (r *ResourceManager) ListComputeTemplates(ctx context.Context, namespace string) ([]*corev1.ConfigMap, error) {
+func (r *ResourceManager) ListComputeTemplates(ctx context.Context, namespace string, labelSelector string) ([]*corev1.ConfigMap, error) {
+	selector, err := buildLabelSelector(map[string]string{"ray.io/config-type": "compute-template"}, labelSelector)
+	if err != nil {
+		return nil, err
+	}
 	client := r.getKubernetesConfigMapClient(namespace)
-	configMapList, err := client.List(ctx, metav1.ListOptions{LabelSelector: "ray.io/config-type=compute-template"})
+	configMapList, err := client.List(ctx, metav1.ListOptions{LabelSelector: selector})
 	if err != nil {
 		return nil, util.Wrap(err, "List compute templates failed")
 	}
@@ -447,7 +657,11 @@ func (r *ResourceManager) ListComputeTemplates(ctx context.Context, namespace st
 	return result, nil
 }
 
-func (r *ResourceManager) ListAllComputeTemplates(ctx context.Context) ([]*corev1.ConfigMap, error) {
+func (r *ResourceManager) ListAllComputeTemplates(ctx context.Context, labelSelector string) ([]*corev1.ConfigMap, error) {
+	selector, err := buildLabelSelector(map[string]string{"ray.io/config-type": "compute-template"}, labelSelector)
+	if err != nil {
+		return nil, err
+	}
 	namespaces, err := r.getKubernetesNamespaceClient().List(ctx, metav1.ListOptions{})
 	if err != nil {
 		return nil, util.Wrap(err, "Failed to fetch all Kubernetes namespaces")
@@ -456,7 +670,7 @@ func (r *ResourceManager) ListAllComputeTemplates(ctx context.Context) ([]*corev
 	var result []*corev1.ConfigMap
 	for _, namespace := range namespaces.Items {
 		client := r.getKubernetesConfigMapClient(namespace.Name)
-		configMapList, err := client.List(ctx, metav1.ListOptions{LabelSelector: "ray.io/config-type=compute-template"})
+		configMapList, err := client.List(ctx, metav1.ListOptions{LabelSelector: selector})
 		if err != nil {
 			return nil, util.Wrap(err, fmt.Sprintf("List compute templates failed in %s", namespace.Name))
 		}
@@ -484,6 +698,80 @@ func (r *ResourceManager) DeleteComputeTemplate(ctx context.Context, name string
 	return nil
 }
 
+// Image templates
+func (r *ResourceManager) CreateImageTemplate(ctx context.Context, imageTemplate *api.ImageTemplate) (*corev1.ConfigMap, error) {
+	_, err := r.GetImageTemplate(ctx, imageTemplate.Name, imageTemplate.Namespace)
+	if err == nil {
+		return nil, util.NewAlreadyExistError("Image template with name %s already exists in namespace %s", imageTemplate.Name, imageTemplate.Namespace)
+	}
+
+	configMap, err := util.NewImageTemplate(imageTemplate)
+	if err != nil {
+		return nil, util.NewInternalServerError(err, "Failed to convert image template (%s/%s)", imageTemplate.Namespace, imageTemplate.Name)
+	}
+
+	client := r.getKubernetesConfigMapClient(imageTemplate.Namespace)
+	newConfigMap, err := client.Create(ctx, configMap, metav1.CreateOptions{})
+	if err != nil {
+		return nil, util.NewInternalServerError(err, "Failed to create an image template for (%s/%s)", imageTemplate.Namespace, imageTemplate.Name)
+	}
+
+	return newConfigMap, nil
+}
+
+func (r *ResourceManager) GetImageTemplate(ctx context.Context, name string, namespace string) (*corev1.ConfigMap, error) {
+	client := r.getKubernetesConfigMapClient(namespace)
+	return getImageTemplateByName(ctx, client, name)
+}
+
+func (r *ResourceManager) ListImageTemplates(ctx context.Context, namespace string) ([]*corev1.ConfigMap, error) {
+	client := r.getKubernetesConfigMapClient(namespace)
+	configMapList, err := client.List(ctx, metav1.ListOptions{LabelSelector: "ray.io/config-type=image-template"})
+	if err != nil {
+		return nil, util.Wrap(err, "List image templates failed")
+	}
+
+	var result []*corev1.ConfigMap
+	length := len(configMapList.Items)
+	for i := 0; i < length; i++ {
+		result = append(result, &configMapList.Items[i])
+	}
+
+	return result, nil
+}
+
+func (r *ResourceManager) ListAllImageTemplates(ctx context.Context) ([]*corev1.ConfigMap, error) {
+	namespaces, err := r.getKubernetesNamespaceClient().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, util.Wrap(err, "Failed to fetch all Kubernetes namespaces")
+	}
+
+	var result []*corev1.ConfigMap
+	for _, namespace := range namespaces.Items {
+		imageTemplates, err := r.ListImageTemplates(ctx, namespace.Name)
+		if err != nil {
+			return nil, util.Wrap(err, fmt.Sprintf("List image templates failed in %s", namespace.Name))
+		}
+		result = append(result, imageTemplates...)
+	}
+	return result, nil
+}
+
+func (r *ResourceManager) DeleteImageTemplate(ctx context.Context, name string, namespace string) error {
+	client := r.getKubernetesConfigMapClient(namespace)
+
+	configMap, err := getImageTemplateByName(ctx, client, name)
+	if err != nil {
+		return util.Wrap(err, "Get image template failure")
+	}
+
+	if err := client.Delete(ctx, configMap.Name, metav1.DeleteOptions{}); err != nil {
+		return util.NewInternalServerError(err, "failed to delete image template %v.", name)
+	}
+
+	return nil
+}
+
 // getClusterByName returns the Kubernetes RayCluster object by given name and client
 func getClusterByName(ctx context.Context, client rayv1.RayClusterInterface, name string) (*rayv1api.RayCluster, error) {
 	cluster, err := client.Get(ctx, name, metav1.GetOptions{})
@@ -546,6 +834,24 @@ func getComputeTemplateByName(ctx context.Context, client clientv1.ConfigMapInte
 	return runtime, nil
 }
 
+// getImageTemplateByName returns the Kubernetes configmap object by given name and client.
+// Config maps that do not hold an image template are reported as not found.
+func getImageTemplateByName(ctx context.Context, client clientv1.ConfigMapInterface, name string) (*corev1.ConfigMap, error) {
+	configMap, err := client.Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return nil, util.NewNotFoundError(err, "Image template %s not found", name)
+		}
+
+		return nil, util.Wrap(err, "Get image template failed")
+	}
+	if configType := configMap.Labels["ray.io/config-type"]; configType != "image-template" {
+		return nil, util.NewNotFoundError(fmt.Errorf("config map %s is not an image template", name), "Image template %s not found", name)
+	}
+
+	return configMap, nil
+}
+
 func (r *ResourceManager) GetClusterEvents(ctx context.Context, clusterName string, namespace string) ([]corev1.Event, error) {
 	client := r.getEventsClient(namespace)
 	clusterClient := r.getRayClusterClient(namespace)
@@ -0,0 +1,142 @@
+package manager
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"testing"
+
+	rayv1api "github.com/ray-project/kuberay/ray-operator/apis/ray/v1"
+	rayv1 "github.com/ray-project/kuberay/ray-operator/pkg/client/clientset/versioned/typed/ray/v1"
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestBuildManagedListOptions(t *testing.T) {
+	tests := []struct {
+		name     string
+		options  *ListOptions
+		selector string
+		limit    int64
+		token    string
+		isError  bool
+	}{
+		{
+			name:     "no options",
+			options:  nil,
+			selector: "app.kubernetes.io/managed-by=kuberay-apiserver",
+		},
+		{
+			name:     "pagination",
+			options:  &ListOptions{PageToken: "token", PageSize: 10},
+			selector: "app.kubernetes.io/managed-by=kuberay-apiserver",
+			limit:    10,
+			token:    "token",
+		},
+		{
+			name:     "user and environment",
+			options:  &ListOptions{User: "alice", Environment: "DEV"},
+			selector: "app.kubernetes.io/managed-by=kuberay-apiserver,ray.io/environment=DEV,ray.io/user=alice",
+		},
+		{
+			name:     "label selector",
+			options:  &ListOptions{User: "alice", LabelSelector: "team in (ml),tier!=batch"},
+			selector: "app.kubernetes.io/managed-by=kuberay-apiserver,ray.io/user=alice,team in (ml),tier!=batch",
+		},
+		{
+			name:    "invalid label selector",
+			options: &ListOptions{LabelSelector: "team in ml"},
+			isError: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			listOptions, err := buildManagedListOptions(tt.options)
+			if tt.isError {
+				assert.NotNil(t, err)
+				return
+			}
+			assert.Nil(t, err)
+			assert.Equal(t, tt.selector, listOptions.LabelSelector)
+			assert.Equal(t, tt.limit, listOptions.Limit)
+			assert.Equal(t, tt.token, listOptions.Continue)
+		})
+	}
+}
+
+func TestMatchesClusterFilters(t *testing.T) {
+	cluster := &rayv1api.RayCluster{Status: rayv1api.RayClusterStatus{State: rayv1api.Ready}}
+	assert.True(t, matchesClusterFilters(cluster, nil))
+	assert.True(t, matchesClusterFilters(cluster, &ListOptions{}))
+	assert.True(t, matchesClusterFilters(cluster, &ListOptions{ClusterState: "ready"}))
+	assert.False(t, matchesClusterFilters(cluster, &ListOptions{ClusterState: "failed"}))
+}
+
+// fakeRayClusterClient pages over a fixed list of clusters like the Kubernetes API server does.
+type fakeRayClusterClient struct {
+	rayv1.RayClusterInterface
+	clusters []rayv1api.RayCluster
+	calls    int
+}
+
+func (c *fakeRayClusterClient) List(_ context.Context, opts metav1.ListOptions) (*rayv1api.RayClusterList, error) {
+	c.calls++
+	start := 0
+	if opts.Continue != "" {
+		start, _ = strconv.Atoi(opts.Continue)
+	}
+	end := len(c.clusters)
+	if opts.Limit > 0 && start+int(opts.Limit) < end {
+		end = start + int(opts.Limit)
+	}
+	list := &rayv1api.RayClusterList{Items: c.clusters[start:end]}
+	// RemainingItemCount is never set, as the API server does not set it for lists with a label selector.
+	if end < len(c.clusters) {
+		list.Continue = strconv.Itoa(end)
+	}
+	return list, nil
+}
+
+func TestListClustersWithClusterState(t *testing.T) {
+	states := []rayv1api.ClusterState{rayv1api.Ready, rayv1api.Suspended, rayv1api.Suspended, rayv1api.Ready, rayv1api.Suspended, rayv1api.Ready}
+	client := &fakeRayClusterClient{}
+	for i, state := range states {
+		client.clusters = append(client.clusters, rayv1api.RayCluster{
+			ObjectMeta: metav1.ObjectMeta{Name: fmt.Sprintf("cluster-%d", i)},
+			Status:     rayv1api.RayClusterStatus{State: state},
+		})
+	}
+	ctx := context.Background()
+
+	// Without a state the Kubernetes page is returned as is. The total size is only known on the last page.
+	clusters, page, err := listClusters(ctx, client, &ListOptions{PageSize: 4})
+	assert.Nil(t, err)
+	assert.Len(t, clusters, 4)
+	assert.Equal(t, int32(0), page.TotalSize)
+
+	clusters, page, err = listClusters(ctx, client, &ListOptions{PageSize: 4, PageToken: page.NextPageToken})
+	assert.Nil(t, err)
+	assert.Len(t, clusters, 2)
+	assert.Equal(t, int32(2), page.TotalSize)
+	assert.Empty(t, page.NextPageToken)
+
+	clusters, page, err = listClusters(ctx, client, nil)
+	assert.Nil(t, err)
+	assert.Len(t, clusters, 6)
+	assert.Equal(t, int32(6), page.TotalSize)
+
+	// The pages are full, no cluster is skipped and the total size is not set.
+	options := &ListOptions{PageSize: 2, ClusterState: "ready"}
+	clusters, page, err = listClusters(ctx, client, options)
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"cluster-0", "cluster-3"}, []string{clusters[0].Name, clusters[1].Name})
+	assert.Equal(t, int32(0), page.TotalSize)
+	assert.NotEmpty(t, page.NextPageToken)
+
+	options.PageToken = page.NextPageToken
+	clusters, page, err = listClusters(ctx, client, options)
+	assert.Nil(t, err)
+	assert.Len(t, clusters, 1)
+	assert.Equal(t, "cluster-5", clusters[0].Name)
+	assert.Empty(t, page.NextPageToken)
+}
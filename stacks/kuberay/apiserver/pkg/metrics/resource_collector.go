@@ -0,0 +1,109 @@
+package metrics
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/ray-project/kuberay/apiserver/pkg/model"
+	rayinformers "github.com/ray-project/kuberay/ray-operator/pkg/client/informers/externalversions"
+	raylisters "github.com/ray-project/kuberay/ray-operator/pkg/client/listers/ray/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	klog "k8s.io/klog/v2"
+)
+
+// unknownState is reported for resources whose status has not been populated by the operator yet.
+const unknownState = "unknown"
+
+var (
+	clustersDesc = prometheus.NewDesc(
+		"kuberay_apiserver_clusters",
+		"Number of clusters managed by the API server, by namespace and state",
+		[]string{"namespace", "state"}, nil,
+	)
+	jobsDesc = prometheus.NewDesc(
+		"kuberay_apiserver_jobs",
+		"Number of jobs managed by the API server, by namespace and deployment state",
+		[]string{"namespace", "state"}, nil,
+	)
+	servicesDesc = prometheus.NewDesc(
+		"kuberay_apiserver_services",
+		"Number of services managed by the API server, by namespace and state",
+		[]string{"namespace", "state"}, nil,
+	)
+)
+
+// ResourceCollector is a prometheus.Collector that reports the number of clusters, jobs and services
+// per namespace and state. The resources are read from the informer cache, so scrapes do not reach
+// the Kubernetes API server.
+type ResourceCollector struct {
+	clusters raylisters.RayClusterLister
+	jobs     raylisters.RayJobLister
+	services raylisters.RayServiceLister
+}
+
+// NewResourceCollector creates the RayCluster, RayJob and RayService informers of the factory. The factory
+// must be started, and its caches synced, before the collector reports accurate counts.
+func NewResourceCollector(factory rayinformers.SharedInformerFactory) *ResourceCollector {
+	informers := factory.Ray().V1()
+	return &ResourceCollector{
+		clusters: informers.RayClusters().Lister(),
+		jobs:     informers.RayJobs().Lister(),
+		services: informers.RayServices().Lister(),
+	}
+}
+
+func (c *ResourceCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- clustersDesc
+	ch <- jobsDesc
+	ch <- servicesDesc
+}
+
+func (c *ResourceCollector) Collect(ch chan<- prometheus.Metric) {
+	if clusters, err := c.clusters.List(labels.Everything()); err != nil {
+		klog.Warningf("Failed to list clusters for metrics: %v", err)
+	} else {
+		counts := stateCounts{}
+		for _, cluster := range clusters {
+			counts.add(cluster.Namespace, model.FromCrdToApiCluster(cluster, nil).ClusterState)
+		}
+		counts.collect(ch, clustersDesc)
+	}
+
+	if jobs, err := c.jobs.List(labels.Everything()); err != nil {
+		klog.Warningf("Failed to list jobs for metrics: %v", err)
+	} else {
+		counts := stateCounts{}
+		for _, job := range jobs {
+			counts.add(job.Namespace, string(job.Status.JobDeploymentStatus))
+		}
+		counts.collect(ch, jobsDesc)
+	}
+
+	if services, err := c.services.List(labels.Everything()); err != nil {
+		klog.Warningf("Failed to list services for metrics: %v", err)
+	} else {
+		counts := stateCounts{}
+		for _, service := range services {
+			counts.add(service.Namespace, string(service.Status.ServiceStatus))
+		}
+		counts.collect(ch, servicesDesc)
+	}
+}
+
+type stateKey struct {
+	namespace string
+	state     string
+}
+
+type stateCounts map[stateKey]int
+
+func (s stateCounts) add(namespace, state string) {
+	if state == "" {
+		state = unknownState
+	}
+	s[stateKey{namespace: namespace, state: state}]++
+}
+
+func (s stateCounts) collect(ch chan<- prometheus.Metric, desc *prometheus.Desc) {
+	for key, count := range s {
+		ch <- prometheus.MustNewConstMetric(desc, prometheus.GaugeValue, float64(count), key.namespace, key.state)
+	}
+}
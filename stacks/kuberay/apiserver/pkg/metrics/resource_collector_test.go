@@ -0,0 +1,57 @@
+package metrics
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	rayv1api "github.com/ray-project/kuberay/ray-operator/apis/ray/v1"
+	"github.com/ray-project/kuberay/ray-operator/pkg/client/clientset/versioned/fake"
+	rayinformers "github.com/ray-project/kuberay/ray-operator/pkg/client/informers/externalversions"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestResourceCollector(t *testing.T) {
+	suspend := true
+	newCluster := func(namespace, name string, state rayv1api.ClusterState) *rayv1api.RayCluster {
+		return &rayv1api.RayCluster{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace},
+			Status:     rayv1api.RayClusterStatus{State: state},
+		}
+	}
+	suspended := newCluster("team-b", "cluster", rayv1api.Ready)
+	suspended.Spec.Suspend = &suspend
+
+	client := fake.NewSimpleClientset(
+		newCluster("team-a", "cluster-1", rayv1api.Ready),
+		newCluster("team-a", "cluster-2", rayv1api.Ready),
+		newCluster("team-a", "cluster-3", ""),
+		suspended,
+		&rayv1api.RayJob{
+			ObjectMeta: metav1.ObjectMeta{Name: "job", Namespace: "team-a"},
+			Status:     rayv1api.RayJobStatus{JobDeploymentStatus: rayv1api.JobDeploymentStatusRunning},
+		},
+	)
+	factory := rayinformers.NewSharedInformerFactory(client, 0)
+	collector := NewResourceCollector(factory)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	factory.Start(ctx.Done())
+	for informer, synced := range factory.WaitForCacheSync(ctx.Done()) {
+		require.True(t, synced, "informer %v is not synced", informer)
+	}
+
+	expected := `
+# HELP kuberay_apiserver_clusters Number of clusters managed by the API server, by namespace and state
+# TYPE kuberay_apiserver_clusters gauge
+kuberay_apiserver_clusters{namespace="team-a",state="ready"} 2
+kuberay_apiserver_clusters{namespace="team-a",state="unknown"} 1
+kuberay_apiserver_clusters{namespace="team-b",state="suspended"} 1
+# HELP kuberay_apiserver_jobs Number of jobs managed by the API server, by namespace and deployment state
+# TYPE kuberay_apiserver_jobs gauge
+kuberay_apiserver_jobs{namespace="team-a",state="Running"} 1
+`
+	require.NoError(t, testutil.CollectAndCompare(collector, strings.NewReader(expected)))
+}
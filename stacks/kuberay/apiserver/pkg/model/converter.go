@@ -12,6 +12,7 @@ import (
 	api "github.com/ray-project/kuberay/proto/go_client"
 	rayv1api "github.com/ray-project/kuberay/ray-operator/apis/ray/v1"
 	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
 // Default annotations used by Ray nodes
@@ -25,6 +26,7 @@ func getNodeDefaultAnnotations() []string {
 		"cni.projectcalico.org/podIPs",
 		"cni.projectcalico.org/containerID",
 		"ray.io/compute-template",
+		"ray.io/image-template",
 		"k8s.v1.cni.cncf.io/network-status",
 		"k8s.v1.cni.cncf.io/networks-status",
 	}
@@ -105,6 +107,12 @@ func FromCrdToApiCluster(cluster *rayv1api.RayCluster, events []corev1.Event) *a
 		ClusterState: string(cluster.Status.State),
 	}
 
+	// Report a suspended cluster as soon as the suspension is requested, as the operator only
+	// updates the state once all the pods are gone.
+	if cluster.Spec.Suspend != nil && *cluster.Spec.Suspend {
+		pbCluster.ClusterState = string(rayv1api.Suspended)
+	}
+
 	if len(cluster.ObjectMeta.Annotations) > 0 {
 		pbCluster.Annotations = cluster.ObjectMeta.Annotations
 	}
@@ -132,6 +140,7 @@ func FromCrdToApiCluster(cluster *rayv1api.RayCluster, events []corev1.Event) *a
 	for name, port := range cluster.Status.Endpoints {
 		pbCluster.ServiceEndpoint[name] = port
 	}
+	pbCluster.Conditions = PopulateConditions(cluster.Status.Conditions)
 	return pbCluster
 }
 
@@ -221,6 +230,7 @@ func PopulateHeadNodeSpec(spec rayv1api.HeadGroupSpec) *api.HeadGroupSpec {
 		ServiceType:     string(spec.ServiceType),
 		Image:           spec.Template.Annotations[util.RayClusterImageAnnotationKey],
 		ComputeTemplate: spec.Template.Annotations[util.RayClusterComputeTemplateAnnotationKey],
+		ImageTemplate:   spec.Template.Annotations[util.RayClusterImageTemplateAnnotationKey],
 		Volumes:         PopulateVolumes(&spec.Template),
 	}
 
@@ -270,6 +280,7 @@ func PopulateWorkerNodeSpec(specs []rayv1api.WorkerGroupSpec) []*api.WorkerGroup
 			GroupName:       spec.GroupName,
 			Image:           spec.Template.Annotations[util.RayClusterImageAnnotationKey],
 			ComputeTemplate: spec.Template.Annotations[util.RayClusterComputeTemplateAnnotationKey],
+			ImageTemplate:   spec.Template.Annotations[util.RayClusterImageTemplateAnnotationKey],
 			Volumes:         PopulateVolumes(&spec.Template),
 		}
 
@@ -398,6 +409,41 @@ func FromKubeToAPIComputeTemplates(configMaps []*corev1.ConfigMap) []*api.Comput
 	return apiComputeTemplates
 }
 
+func FromKubeToAPIImageTemplate(configMap *corev1.ConfigMap) *api.ImageTemplate {
+	imageTemplate := &api.ImageTemplate{}
+	imageTemplate.Name = configMap.Name
+	imageTemplate.Namespace = configMap.Namespace
+	imageTemplate.BaseImage = configMap.Data["base_image"]
+	imageTemplate.CustomCommands = configMap.Data["custom_commands"]
+	imageTemplate.Image = configMap.Data["image"]
+	packages := map[string]*[]string{
+		"pip_packages":    &imageTemplate.PipPackages,
+		"conda_packages":  &imageTemplate.CondaPackages,
+		"system_packages": &imageTemplate.SystemPackages,
+	}
+	for key, value := range packages {
+		if val, ok := configMap.Data[key]; ok {
+			if err := json.Unmarshal([]byte(val), value); err != nil {
+				klog.Errorf("failed to unmarshall %s for image template %s, value %s, error %v", key, imageTemplate.Name, val, err)
+			}
+		}
+	}
+	if val, ok := configMap.Data["environment_variables"]; ok {
+		if err := json.Unmarshal([]byte(val), &imageTemplate.EnvironmentVariables); err != nil {
+			klog.Errorf("failed to unmarshall environment variables for image template %s, value %s, error %v", imageTemplate.Name, val, err)
+		}
+	}
+	return imageTemplate
+}
+
+func FromKubeToAPIImageTemplates(configMaps []*corev1.ConfigMap) []*api.ImageTemplate {
+	apiImageTemplates := make([]*api.ImageTemplate, 0)
+	for _, configMap := range configMaps {
+		apiImageTemplates = append(apiImageTemplates, FromKubeToAPIImageTemplate(configMap))
+	}
+	return apiImageTemplates
+}
+
 func FromCrdToApiJobs(jobs []*rayv1api.RayJob) []*api.RayJob {
 	apiJobs := make([]*api.RayJob, 0)
 	for _, job := range jobs {
@@ -427,6 +473,7 @@ func FromCrdToApiJob(job *rayv1api.RayJob) (pbJob *api.RayJob) {
 		JobStatus:                string(job.Status.JobStatus),
 		JobDeploymentStatus:      string(job.Status.JobDeploymentStatus),
 		Message:                  job.Status.Message,
+		Conditions:               PopulateConditions(job.Status.Conditions),
 	}
 
 	// Add optional params
@@ -516,6 +563,7 @@ func PoplulateRayServiceStatus(serviceName string, serviceStatus rayv1api.RaySer
 		RayClusterName:         serviceStatus.ActiveServiceStatus.RayClusterName,
 		RayClusterState:        string(serviceStatus.ActiveServiceStatus.RayClusterStatus.State),
 		ServeApplicationStatus: PopulateServeApplicationStatus(serviceStatus.ActiveServiceStatus.Applications),
+		Conditions:             PopulateConditions(serviceStatus.Conditions),
 	}
 	status.ServiceEndpoint = map[string]string{}
 	for name, port := range serviceStatus.ActiveServiceStatus.RayClusterStatus.Endpoints {
@@ -569,3 +617,18 @@ func PopulateRayServiceEvent(serviceName string, events []corev1.Event) []*api.R
 	}
 	return serviceEvents
 }
+
+func PopulateConditions(conditions []metav1.Condition) []*api.Condition {
+	pbConditions := make([]*api.Condition, 0, len(conditions))
+	for _, condition := range conditions {
+		pbConditions = append(pbConditions, &api.Condition{
+			Type:               condition.Type,
+			Status:             string(condition.Status),
+			Reason:             condition.Reason,
+			Message:            condition.Message,
+			LastTransitionTime: &timestamp.Timestamp{Seconds: condition.LastTransitionTime.Unix()},
+			ObservedGeneration: condition.ObservedGeneration,
+		})
+	}
+	return pbConditions
+}
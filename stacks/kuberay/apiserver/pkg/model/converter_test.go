@@ -132,6 +132,20 @@ var configMapWithoutTolerations = corev1.ConfigMap{
 	},
 }
 
+var imageTemplateConfigMap = corev1.ConfigMap{
+	ObjectMeta: metav1.ObjectMeta{
+		Name:      "my-image",
+		Namespace: "max",
+	},
+	Data: map[string]string{
+		"name":                  "my-image",
+		"namespace":             "max",
+		"base_image":            "rayproject/ray:2.4.0",
+		"pip_packages":          "[\"numpy==1.24.2\",\"pandas>=1.5,<2\"]",
+		"environment_variables": "{\"FOO\":\"bar\"}",
+	},
+}
+
 var configMapWithTolerations = corev1.ConfigMap{
 	Data: map[string]string{
 		"cpu":             "4",
@@ -556,6 +570,41 @@ func TestPopulateRayClusterSpec(t *testing.T) {
 	}
 }
 
+func TestPopulateSuspendedClusterState(t *testing.T) {
+	cluster := ClusterSpecTest.DeepCopy()
+	cluster.Status.State = rayv1api.Ready
+	assert.Equal(t, "ready", FromCrdToApiCluster(cluster, []corev1.Event{}).ClusterState)
+
+	// The cluster is reported as suspended before the operator has deleted its pods
+	cluster.Spec.Suspend = pointer.Bool(true)
+	assert.Equal(t, "suspended", FromCrdToApiCluster(cluster, []corev1.Event{}).ClusterState)
+}
+
+func TestPopulateConditions(t *testing.T) {
+	cluster := ClusterSpecTest.DeepCopy()
+	transitionTime := metav1.Unix(1700000000, 0)
+	cluster.Status.Conditions = []metav1.Condition{
+		{
+			Type:               string(rayv1api.HeadPodReady),
+			Status:             metav1.ConditionTrue,
+			Reason:             "HeadPodRunningAndReady",
+			Message:            "The head Pod is running and ready",
+			LastTransitionTime: transitionTime,
+			ObservedGeneration: 2,
+		},
+	}
+	conditions := FromCrdToApiCluster(cluster, []corev1.Event{}).Conditions
+	assert.Equal(t, 1, len(conditions))
+	assert.Equal(t, "HeadPodReady", conditions[0].Type)
+	assert.Equal(t, "True", conditions[0].Status)
+	assert.Equal(t, "HeadPodRunningAndReady", conditions[0].Reason)
+	assert.Equal(t, "The head Pod is running and ready", conditions[0].Message)
+	assert.Equal(t, transitionTime.Unix(), conditions[0].LastTransitionTime.Seconds)
+	assert.Equal(t, int64(2), conditions[0].ObservedGeneration)
+
+	assert.Equal(t, 0, len(FromCrdToApiJob(&JobNewClusterTest).Conditions))
+}
+
 func TestPopulateTemplate(t *testing.T) {
 	template := FromKubeToAPIComputeTemplate(&configMapWithoutTolerations)
 	if len(template.Tolerations) != 0 {
@@ -574,6 +623,16 @@ func TestPopulateTemplate(t *testing.T) {
 	}
 }
 
+func TestPopulateImageTemplate(t *testing.T) {
+	imageTemplate := FromKubeToAPIImageTemplate(&imageTemplateConfigMap)
+	assert.Equal(t, "my-image", imageTemplate.Name)
+	assert.Equal(t, "max", imageTemplate.Namespace)
+	assert.Equal(t, "rayproject/ray:2.4.0", imageTemplate.BaseImage)
+	assert.Equal(t, []string{"numpy==1.24.2", "pandas>=1.5,<2"}, imageTemplate.PipPackages)
+	assert.Equal(t, 0, len(imageTemplate.CondaPackages))
+	assert.Equal(t, map[string]string{"FOO": "bar"}, imageTemplate.EnvironmentVariables)
+}
+
 func tolerationToString(toleration *api.PodToleration) string {
 	return "Key: " + toleration.Key + " Operator: " + string(toleration.Operator) + " Effect: " + string(toleration.Effect)
 }
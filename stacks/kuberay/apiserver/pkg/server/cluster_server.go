@@ -2,6 +2,7 @@ package server
 
 import (
 	"context"
+	"strings"
 
 	"github.com/golang/protobuf/ptypes/empty"
 	"github.com/ray-project/kuberay/apiserver/pkg/manager"
@@ -75,7 +76,22 @@ func (s *ClusterServer) ListCluster(ctx context.Context, request *api.ListCluste
 		return nil, util.NewInvalidInputError("Namespace is empty. Please specify a valid value.")
 	}
 
-	clusters, err := s.resourceManager.ListClusters(ctx, request.Namespace)
+	if err := ValidatePageSize(request.PageSize); err != nil {
+		return nil, err
+	}
+
+	if err := ValidateEnvironment(request.Environment); err != nil {
+		return nil, err
+	}
+
+	clusters, page, err := s.resourceManager.ListClusters(ctx, request.Namespace, &manager.ListOptions{
+		PageToken:     request.PageToken,
+		PageSize:      request.PageSize,
+		User:          request.User,
+		Environment:   request.Environment,
+		LabelSelector: request.LabelSelector,
+		ClusterState:  request.ClusterState,
+	})
 	if err != nil {
 		return nil, util.Wrap(err, "List clusters failed.")
 	}
@@ -90,14 +106,31 @@ func (s *ClusterServer) ListCluster(ctx context.Context, request *api.ListCluste
 	}
 
 	return &api.ListClustersResponse{
-		Clusters: model.FromCrdToApiClusters(clusters, clusterEventMap),
+		Clusters:      model.FromCrdToApiClusters(clusters, clusterEventMap),
+		TotalSize:     page.TotalSize,
+		NextPageToken: page.NextPageToken,
 	}, nil
 }
 
-// Finds all Clusters in all namespaces.
-// TODO: Supports pagination and sorting on certain fields when we have DB support. request needs to be extended.
+// Finds all Clusters in all namespaces. Supports pagination.
+// TODO: Supports sorting on certain fields when we have DB support. request needs to be extended.
 func (s *ClusterServer) ListAllClusters(ctx context.Context, request *api.ListAllClustersRequest) (*api.ListAllClustersResponse, error) {
-	clusters, err := s.resourceManager.ListAllClusters(ctx)
+	if err := ValidatePageSize(request.PageSize); err != nil {
+		return nil, err
+	}
+
+	if err := ValidateEnvironment(request.Environment); err != nil {
+		return nil, err
+	}
+
+	clusters, page, err := s.resourceManager.ListAllClusters(ctx, &manager.ListOptions{
+		PageToken:     request.PageToken,
+		PageSize:      request.PageSize,
+		User:          request.User,
+		Environment:   request.Environment,
+		LabelSelector: request.LabelSelector,
+		ClusterState:  request.ClusterState,
+	})
 	if err != nil {
 		return nil, util.Wrap(err, "List clusters from all namespaces failed.")
 	}
@@ -112,10 +145,70 @@ func (s *ClusterServer) ListAllClusters(ctx context.Context, request *api.ListAl
 	}
 
 	return &api.ListAllClustersResponse{
-		Clusters: model.FromCrdToApiClusters(clusters, clusterEventMap),
+		Clusters:      model.FromCrdToApiClusters(clusters, clusterEventMap),
+		TotalSize:     page.TotalSize,
+		NextPageToken: page.NextPageToken,
 	}, nil
 }
 
+// Updates a Cluster in place. Only the fields listed in the update mask are changed.
+func (s *ClusterServer) UpdateCluster(ctx context.Context, request *api.UpdateClusterRequest) (*api.Cluster, error) {
+	if err := ValidateUpdateClusterRequest(request); err != nil {
+		return nil, util.Wrap(err, "Validate update cluster request failed.")
+	}
+
+	// use the name and namespace in the request to identify the cluster to be updated
+	request.Cluster.Name = request.Name
+	request.Cluster.Namespace = request.Namespace
+
+	cluster, err := s.resourceManager.UpdateCluster(ctx, request.Cluster, request.UpdateMask.GetPaths())
+	if err != nil {
+		return nil, util.Wrap(err, "Update Cluster failed.")
+	}
+	events, err := s.resourceManager.GetClusterEvents(ctx, cluster.Name, cluster.Namespace)
+	if err != nil {
+		klog.Warningf("Failed to get cluster's event, cluster: %s/%s, err: %v", cluster.Namespace, cluster.Name, err)
+	}
+
+	return model.FromCrdToApiCluster(cluster, events), nil
+}
+
+// Suspends a Cluster by deleting all its pods.
+func (s *ClusterServer) SuspendCluster(ctx context.Context, request *api.SuspendClusterRequest) (*api.Cluster, error) {
+	if err := validateClusterNameAndNamespace(request.Name, request.Namespace); err != nil {
+		return nil, err
+	}
+
+	cluster, err := s.resourceManager.SuspendCluster(ctx, request.Name, request.Namespace)
+	if err != nil {
+		return nil, util.Wrap(err, "Suspend Cluster failed.")
+	}
+	events, err := s.resourceManager.GetClusterEvents(ctx, cluster.Name, cluster.Namespace)
+	if err != nil {
+		klog.Warningf("Failed to get cluster's event, cluster: %s/%s, err: %v", cluster.Namespace, cluster.Name, err)
+	}
+
+	return model.FromCrdToApiCluster(cluster, events), nil
+}
+
+// Resumes a suspended Cluster.
+func (s *ClusterServer) ResumeCluster(ctx context.Context, request *api.ResumeClusterRequest) (*api.Cluster, error) {
+	if err := validateClusterNameAndNamespace(request.Name, request.Namespace); err != nil {
+		return nil, err
+	}
+
+	cluster, err := s.resourceManager.ResumeCluster(ctx, request.Name, request.Namespace)
+	if err != nil {
+		return nil, util.Wrap(err, "Resume Cluster failed.")
+	}
+	events, err := s.resourceManager.GetClusterEvents(ctx, cluster.Name, cluster.Namespace)
+	if err != nil {
+		klog.Warningf("Failed to get cluster's event, cluster: %s/%s, err: %v", cluster.Namespace, cluster.Name, err)
+	}
+
+	return model.FromCrdToApiCluster(cluster, events), nil
+}
+
 // Deletes an Cluster without deleting the Cluster's runs and jobs. To
 // avoid unexpected behaviors, delete an Cluster's runs and jobs before
 // deleting the Cluster.
@@ -160,6 +253,75 @@ func ValidateCreateClusterRequest(request *api.CreateClusterRequest) error {
 	return nil
 }
 
+func ValidateUpdateClusterRequest(request *api.UpdateClusterRequest) error {
+	if request.Name == "" {
+		return util.NewInvalidInputError("Cluster name is empty. Please specify a valid value.")
+	}
+
+	if request.Namespace == "" {
+		return util.NewInvalidInputError("Namespace is empty. Please specify a valid value.")
+	}
+
+	if request.Cluster == nil {
+		return util.NewInvalidInputError("Cluster is empty, please input a valid payload.")
+	}
+
+	if request.Cluster.Namespace != "" && request.Namespace != request.Cluster.Namespace {
+		return util.NewInvalidInputError("The namespace in the request is different from the namespace in the cluster definition.")
+	}
+
+	if request.Cluster.Name != "" && request.Name != request.Cluster.Name {
+		return util.NewInvalidInputError("The name in the request is different from the name in the cluster definition.")
+	}
+
+	if len(request.UpdateMask.GetPaths()) == 0 {
+		return util.NewInvalidInputError("Update mask is empty. Please specify the fields to be updated.")
+	}
+
+	for _, path := range request.UpdateMask.GetPaths() {
+		switch {
+		case path == "name" || path == "namespace":
+			continue
+		case path == util.ClusterHeadGroupSpecPath || strings.HasPrefix(path, util.ClusterHeadGroupSpecPath+"."):
+			if err := validateHeadGroupSpec(request.Cluster.ClusterSpec); err != nil {
+				return err
+			}
+		case path == util.ClusterWorkerGroupSpecPath:
+			if request.Cluster.ClusterSpec == nil {
+				return util.NewInvalidInputError("A ClusterSpec object is required. Please specify one.")
+			}
+			if err := validateWorkerGroupSpecs(request.Cluster.ClusterSpec.WorkerGroupSpec); err != nil {
+				return err
+			}
+		case path == util.ClusterWorkerGroupReplicasPath || path == util.ClusterWorkerGroupMinReplicasPath || path == util.ClusterWorkerGroupMaxReplicasPath:
+			if request.Cluster.ClusterSpec == nil {
+				return util.NewInvalidInputError("A ClusterSpec object is required. Please specify one.")
+			}
+			for index, spec := range request.Cluster.ClusterSpec.WorkerGroupSpec {
+				if len(spec.GroupName) == 0 {
+					return util.NewInvalidInputError("WorkerNodeSpec %d group name is empty. Please specify a valid value.", index)
+				}
+			}
+		default:
+			return util.NewInvalidInputError("Update mask path %s is not supported.", path)
+		}
+	}
+
+	return nil
+}
+
+func validateClusterNameAndNamespace(name string, namespace string) error {
+	if name == "" {
+		return util.NewInvalidInputError("Cluster name is empty. Please specify a valid value.")
+	}
+
+	if namespace == "" {
+		return util.NewInvalidInputError("Namespace is empty. Please specify a valid value.")
+	}
+
+	return nil
+}
+
 func NewClusterServer(resourceManager *manager.ResourceManager, options *ClusterServerOptions) *ClusterServer {
 	return &ClusterServer{resourceManager: resourceManager, options: options}
 }
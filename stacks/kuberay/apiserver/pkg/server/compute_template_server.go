@@ -61,7 +61,7 @@ func (s *ComputeTemplateServer) ListComputeTemplates(ctx context.Context, reques
 		return nil, util.NewInvalidInputError("Namespace is empty. Please specify a valid value.")
 	}
 
-	runtimes, err := s.resourceManager.ListComputeTemplates(ctx, request.Namespace)
+	runtimes, err := s.resourceManager.ListComputeTemplates(ctx, request.Namespace, request.LabelSelector)
 	if err != nil {
 		return nil, util.Wrap(err, fmt.Sprintf("List compute templates in namespace %s failed.", request.Namespace))
 	}
@@ -72,7 +72,7 @@ func (s *ComputeTemplateServer) ListComputeTemplates(ctx context.Context, reques
 }
 
 func (s *ComputeTemplateServer) ListAllComputeTemplates(ctx context.Context, request *api.ListAllComputeTemplatesRequest) (*api.ListAllComputeTemplatesResponse, error) {
-	runtimes, err := s.resourceManager.ListAllComputeTemplates(ctx)
+	runtimes, err := s.resourceManager.ListAllComputeTemplates(ctx, request.LabelSelector)
 	if err != nil {
 		return nil, util.Wrap(err, "List all compute templates from all namespaces failed.")
 	}
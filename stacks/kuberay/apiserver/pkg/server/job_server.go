@@ -68,25 +68,47 @@ func (s *RayJobServer) ListRayJobs(ctx context.Context, request *api.ListRayJobs
 		return nil, util.NewInvalidInputError("job namespace is empty. Please specify a valid value.")
 	}
 
-	jobs, err := s.resourceManager.ListJobs(ctx, request.Namespace)
+	if err := ValidatePageSize(request.PageSize); err != nil {
+		return nil, err
+	}
+
+	jobs, page, err := s.resourceManager.ListJobs(ctx, request.Namespace, &manager.ListOptions{
+		PageToken:     request.PageToken,
+		PageSize:      request.PageSize,
+		User:          request.User,
+		LabelSelector: request.LabelSelector,
+	})
 	if err != nil {
 		return nil, util.Wrap(err, "List jobs failed.")
 	}
 
 	return &api.ListRayJobsResponse{
-		Jobs: model.FromCrdToApiJobs(jobs),
+		Jobs:          model.FromCrdToApiJobs(jobs),
+		TotalSize:     page.TotalSize,
+		NextPageToken: page.NextPageToken,
 	}, nil
 }
 
 // Finds all Jobs in all namespaces.
 func (s *RayJobServer) ListAllRayJobs(ctx context.Context, request *api.ListAllRayJobsRequest) (*api.ListAllRayJobsResponse, error) {
-	jobs, err := s.resourceManager.ListAllJobs(ctx)
+	if err := ValidatePageSize(request.PageSize); err != nil {
+		return nil, err
+	}
+
+	jobs, page, err := s.resourceManager.ListAllJobs(ctx, &manager.ListOptions{
+		PageToken:     request.PageToken,
+		PageSize:      request.PageSize,
+		User:          request.User,
+		LabelSelector: request.LabelSelector,
+	})
 	if err != nil {
 		return nil, util.Wrap(err, "List jobs failed.")
 	}
 
 	return &api.ListAllRayJobsResponse{
-		Jobs: model.FromCrdToApiJobs(jobs),
+		Jobs:          model.FromCrdToApiJobs(jobs),
+		TotalSize:     page.TotalSize,
+		NextPageToken: page.NextPageToken,
 	}, nil
 }
 
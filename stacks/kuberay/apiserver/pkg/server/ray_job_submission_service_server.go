@@ -10,6 +10,7 @@ import (
 
 	"github.com/go-logr/logr"
 	"github.com/go-logr/zerologr"
+	"github.com/ray-project/kuberay/apiserver/pkg/util"
 	api "github.com/ray-project/kuberay/proto/go_client"
 	"github.com/ray-project/kuberay/ray-operator/controllers/ray/utils"
 	"github.com/rs/zerolog"
@@ -126,6 +127,20 @@ func (s *RayJobSubmissionServiceServer) GetJobLog(ctx context.Context, req *api.
 	return &api.GetJobLogReply{Log: *jlog}, nil
 }
 
+// Tail job log
+func (s *RayJobSubmissionServiceServer) TailJobLog(req *api.TailJobLogRequest, stream api.RayJobSubmissionService_TailJobLogServer) error {
+	s.log.Info("RayJobSubmissionService tail job log")
+	ctx := stream.Context()
+	clusterRequest := api.GetClusterRequest{Name: req.Clustername, Namespace: req.Namespace}
+	url, err := s.getRayClusterURL(ctx, &clusterRequest)
+	if err != nil {
+		return err
+	}
+	return util.TailJobLog(ctx, *url, req.Submissionid, func(chunk string) error {
+		return stream.Send(&api.TailJobLogReply{Log: chunk})
+	})
+}
+
 // List jobs
 func (s *RayJobSubmissionServiceServer) ListJobDetails(ctx context.Context, req *api.ListJobDetailsRequest) (*api.ListJobSubmissionInfo, error) {
 	s.log.Info("RayJobSubmissionService get jobs list")
@@ -87,7 +87,15 @@ func (s *RayServiceServer) ListRayServices(ctx context.Context, request *api.Lis
 	if request.Namespace == "" {
 		return nil, util.NewInvalidInputError("ray service namespace is empty. Please specify a valid value.")
 	}
-	services, err := s.resourceManager.ListServices(ctx, request.Namespace)
+	if err := ValidatePageSize(request.PageSize); err != nil {
+		return nil, err
+	}
+	services, page, err := s.resourceManager.ListServices(ctx, request.Namespace, &manager.ListOptions{
+		PageToken:     request.PageToken,
+		PageSize:      request.PageSize,
+		User:          request.User,
+		LabelSelector: request.LabelSelector,
+	})
 	if err != nil {
 		return nil, util.Wrap(err, "failed to list rayservice.")
 	}
@@ -101,12 +109,22 @@ func (s *RayServiceServer) ListRayServices(ctx context.Context, request *api.Lis
 		serviceEventMap[service.Name] = serviceEvents
 	}
 	return &api.ListRayServicesResponse{
-		Services: model.FromCrdToApiServices(services, serviceEventMap),
+		Services:      model.FromCrdToApiServices(services, serviceEventMap),
+		TotalSize:     page.TotalSize,
+		NextPageToken: page.NextPageToken,
 	}, nil
 }
 
 func (s *RayServiceServer) ListAllRayServices(ctx context.Context, request *api.ListAllRayServicesRequest) (*api.ListAllRayServicesResponse, error) {
-	services, err := s.resourceManager.ListAllServices(ctx)
+	if err := ValidatePageSize(request.PageSize); err != nil {
+		return nil, err
+	}
+	services, page, err := s.resourceManager.ListAllServices(ctx, &manager.ListOptions{
+		PageToken:     request.PageToken,
+		PageSize:      request.PageSize,
+		User:          request.User,
+		LabelSelector: request.LabelSelector,
+	})
 	if err != nil {
 		return nil, util.Wrap(err, "list all services failed.")
 	}
@@ -120,7 +138,9 @@ func (s *RayServiceServer) ListAllRayServices(ctx context.Context, request *api.
 		serviceEventMap[service.Name] = serviceEvents
 	}
 	return &api.ListAllRayServicesResponse{
-		Services: model.FromCrdToApiServices(services, serviceEventMap),
+		Services:      model.FromCrdToApiServices(services, serviceEventMap),
+		TotalSize:     page.TotalSize,
+		NextPageToken: page.NextPageToken,
 	}, nil
 }
 
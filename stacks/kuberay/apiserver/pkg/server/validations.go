@@ -5,9 +5,39 @@ import (
 	api "github.com/ray-project/kuberay/proto/go_client"
 )
 
+// ValidatePageSize validates that the page size of a list request is not negative.
+// A page size of zero means that all the results are returned in a single page.
+func ValidatePageSize(pageSize int32) error {
+	if pageSize < 0 {
+		return util.NewInvalidInputError("Page size is negative. Please specify a valid value.")
+	}
+	return nil
+}
+
+// ValidateEnvironment validates that the environment filter of a list request,
+// if set, is one of the cluster environments.
+func ValidateEnvironment(environment string) error {
+	if environment == "" {
+		return nil
+	}
+	if _, ok := api.Cluster_Environment_value[environment]; !ok {
+		return util.NewInvalidInputError("Environment %s is not valid. Please specify one of DEV, TESTING, STAGING or PRODUCTION.", environment)
+	}
+	return nil
+}
+
 // ValidateClusterSpec validates that the *api.ClusterSpec is not nil and
 // has all the required fields
 func ValidateClusterSpec(clusterSpec *api.ClusterSpec) error {
+	if err := validateHeadGroupSpec(clusterSpec); err != nil {
+		return err
+	}
+	return validateWorkerGroupSpecs(clusterSpec.WorkerGroupSpec)
+}
+
+// validateHeadGroupSpec validates that the head group of the cluster spec
+// is populated with all the required fields
+func validateHeadGroupSpec(clusterSpec *api.ClusterSpec) error {
 	if clusterSpec == nil {
 		return util.NewInvalidInputError("A ClusterSpec object is required. Please specify one.")
 	}
@@ -20,8 +50,13 @@ func ValidateClusterSpec(clusterSpec *api.ClusterSpec) error {
 	if len(clusterSpec.HeadGroupSpec.RayStartParams) == 0 {
 		return util.NewInvalidInputError("HeadGroupSpec RayStartParams is empty. Please specify values.")
 	}
+	return nil
+}
 
-	for index, spec := range clusterSpec.WorkerGroupSpec {
+// validateWorkerGroupSpecs validates that every worker group has all the
+// required fields
+func validateWorkerGroupSpecs(workerGroupSpecs []*api.WorkerGroupSpec) error {
+	for index, spec := range workerGroupSpecs {
 		if len(spec.GroupName) == 0 {
 			return util.NewInvalidInputError("WorkerNodeSpec %d group name is empty. Please specify a valid value.", index)
 		}
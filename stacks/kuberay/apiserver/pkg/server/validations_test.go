@@ -7,6 +7,7 @@ import (
 	"github.com/ray-project/kuberay/apiserver/pkg/util"
 	api "github.com/ray-project/kuberay/proto/go_client"
 	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/types/known/fieldmaskpb"
 )
 
 func TestValidateClusterSpec(t *testing.T) {
@@ -337,3 +338,162 @@ func TestValidateCreateServiceRequest(t *testing.T) {
 		})
 	}
 }
+
+func TestValidatePageSize(t *testing.T) {
+	tests := []struct {
+		name          string
+		pageSize      int32
+		expectedError error
+	}{
+		{
+			name:          "No page size",
+			pageSize:      0,
+			expectedError: nil,
+		},
+		{
+			name:          "A positive page size",
+			pageSize:      20,
+			expectedError: nil,
+		},
+		{
+			name:          "A negative page size",
+			pageSize:      -1,
+			expectedError: util.NewInvalidInputError("Page size is negative. Please specify a valid value."),
+		},
+	}
+	// Execute tests sequentially
+	for _, tc := range tests {
+		tc := tc // capture range variable
+		t.Run(tc.name, func(t *testing.T) {
+			actualError := server.ValidatePageSize(tc.pageSize)
+			if tc.expectedError == nil {
+				require.NoError(t, actualError, "No error expected.")
+			} else {
+				require.EqualError(t, actualError, tc.expectedError.Error(), "A matching error is expected")
+			}
+		})
+	}
+}
+
+func TestValidateEnvironment(t *testing.T) {
+	tests := []struct {
+		name          string
+		environment   string
+		expectedError error
+	}{
+		{
+			name:          "No environment",
+			environment:   "",
+			expectedError: nil,
+		},
+		{
+			name:          "A valid environment",
+			environment:   "PRODUCTION",
+			expectedError: nil,
+		},
+		{
+			name:          "An invalid environment",
+			environment:   "production",
+			expectedError: util.NewInvalidInputError("Environment production is not valid. Please specify one of DEV, TESTING, STAGING or PRODUCTION."),
+		},
+	}
+	// Execute tests sequentially
+	for _, tc := range tests {
+		tc := tc // capture range variable
+		t.Run(tc.name, func(t *testing.T) {
+			actualError := server.ValidateEnvironment(tc.environment)
+			if tc.expectedError == nil {
+				require.NoError(t, actualError, "No error expected.")
+			} else {
+				require.EqualError(t, actualError, tc.expectedError.Error(), "A matching error is expected")
+			}
+		})
+	}
+}
+
+func TestValidateUpdateClusterRequest(t *testing.T) {
+	tests := []struct {
+		name          string
+		request       *api.UpdateClusterRequest
+		expectedError error
+	}{
+		{
+			name: "A valid scaling request",
+			request: &api.UpdateClusterRequest{
+				Name:      "a-cluster",
+				Namespace: "a-namespace",
+				Cluster: &api.Cluster{
+					ClusterSpec: &api.ClusterSpec{
+						WorkerGroupSpec: []*api.WorkerGroupSpec{{GroupName: "group-1", Replicas: 2}},
+					},
+				},
+				UpdateMask: &fieldmaskpb.FieldMask{Paths: []string{"cluster_spec.worker_group_spec.replicas"}},
+			},
+			expectedError: nil,
+		},
+		{
+			name: "A request without update mask",
+			request: &api.UpdateClusterRequest{
+				Name:      "a-cluster",
+				Namespace: "a-namespace",
+				Cluster:   &api.Cluster{},
+			},
+			expectedError: util.NewInvalidInputError("Update mask is empty. Please specify the fields to be updated."),
+		},
+		{
+			name: "A request with a mismatched namespace",
+			request: &api.UpdateClusterRequest{
+				Name:      "a-cluster",
+				Namespace: "a-namespace",
+				Cluster:   &api.Cluster{Namespace: "another-namespace"},
+			},
+			expectedError: util.NewInvalidInputError("The namespace in the request is different from the namespace in the cluster definition."),
+		},
+		{
+			name: "A request with an unsupported path",
+			request: &api.UpdateClusterRequest{
+				Name:       "a-cluster",
+				Namespace:  "a-namespace",
+				Cluster:    &api.Cluster{User: "someone"},
+				UpdateMask: &fieldmaskpb.FieldMask{Paths: []string{"user"}},
+			},
+			expectedError: util.NewInvalidInputError("Update mask path user is not supported."),
+		},
+		{
+			name: "A head group update without head group",
+			request: &api.UpdateClusterRequest{
+				Name:       "a-cluster",
+				Namespace:  "a-namespace",
+				Cluster:    &api.Cluster{ClusterSpec: &api.ClusterSpec{}},
+				UpdateMask: &fieldmaskpb.FieldMask{Paths: []string{"cluster_spec.head_group_spec"}},
+			},
+			expectedError: util.NewInvalidInputError("Cluster Spec Object requires HeadGroupSpec to be populated. Please specify one."),
+		},
+		{
+			name: "A worker group update with an invalid worker group",
+			request: &api.UpdateClusterRequest{
+				Name:      "a-cluster",
+				Namespace: "a-namespace",
+				Cluster: &api.Cluster{
+					ClusterSpec: &api.ClusterSpec{
+						WorkerGroupSpec: []*api.WorkerGroupSpec{{GroupName: "group-1"}},
+					},
+				},
+				UpdateMask: &fieldmaskpb.FieldMask{Paths: []string{"cluster_spec.worker_group_spec"}},
+			},
+			expectedError: util.NewInvalidInputError("WorkerNodeSpec 0 compute template is empty. Please specify a valid value."),
+		},
+	}
+	// Execute tests sequentially
+	for _, tc := range tests {
+		tc := tc // capture range variable
+		t.Run(tc.name, func(t *testing.T) {
+			actualError := server.ValidateUpdateClusterRequest(tc.request)
+			if tc.expectedError == nil {
+				require.NoError(t, actualError, "No error expected.")
+			} else {
+				require.EqualError(t, actualError, tc.expectedError.Error(), "A matching error is expected")
+			}
+		})
+	}
+}
@@ -6,11 +6,15 @@ import (
 	"fmt"
 	"net"
 	"strconv"
+	"strings"
 
 	klog "k8s.io/klog/v2"
 
 	api "github.com/ray-project/kuberay/proto/go_client"
 	rayv1api "github.com/ray-project/kuberay/ray-operator/apis/ray/v1"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -21,8 +25,8 @@ type RayCluster struct {
 }
 
 // NewRayCluster creates a RayCluster.
-// func NewRayCluster(apiCluster *api.Cluster, clusterRuntime *api.ClusterRuntime, computeRuntime *api.ComputeRuntime) *RayCluster {
-func NewRayCluster(apiCluster *api.Cluster, computeTemplateMap map[string]*api.ComputeTemplate) (*RayCluster, error) {
+// Compute and image templates referenced by name in the cluster spec are resolved through the given maps.
+func NewRayCluster(apiCluster *api.Cluster, computeTemplateMap map[string]*api.ComputeTemplate, imageTemplateMap map[string]*api.ImageTemplate) (*RayCluster, error) {
 	// Check for "ray.io/enable-serve-service=true"
 	enableServeService := false
 	if enableServeServiceValue, exist := apiCluster.Annotations["ray.io/enable-serve-service"]; exist && enableServeServiceValue == "true" {
@@ -30,7 +34,7 @@ func NewRayCluster(apiCluster *api.Cluster, computeTemplateMap map[string]*api.C
 	}
 
 	// Build cluster spec
-	spec, err := buildRayClusterSpec(apiCluster.Version, apiCluster.Envs, apiCluster.ClusterSpec, computeTemplateMap, enableServeService)
+	spec, err := buildRayClusterSpec(apiCluster.Version, apiCluster.Envs, apiCluster.ClusterSpec, computeTemplateMap, imageTemplateMap, enableServeService)
 	if err != nil {
 		return nil, err
 	}
@@ -44,6 +48,9 @@ func NewRayCluster(apiCluster *api.Cluster, computeTemplateMap map[string]*api.C
 		},
 		Spec: *spec,
 	}
+	if err := setClusterEnvs(rayCluster, apiCluster.Envs); err != nil {
+		return nil, err
+	}
 
 	return &RayCluster{rayCluster}, nil
 }
@@ -62,62 +69,64 @@ func buildRayClusterLabels(cluster *api.Cluster) map[string]string {
 
 // Build cluster annotations
 func buildRayClusterAnnotations(cluster *api.Cluster) map[string]string {
-	if cluster.Annotations == nil {
-		return map[string]string{}
+	annotations := map[string]string{}
+	for k, v := range cluster.Annotations {
+		annotations[k] = v
+	}
+	return annotations
+}
+
+// setClusterEnvs records the cluster level environment variables in an annotation of the RayCluster. They are
+// merged into the containers of every node group, so they can not be told apart from the node group ones later.
+func setClusterEnvs(rayCluster *rayv1api.RayCluster, envs *api.EnvironmentVariables) error {
+	if envs == nil {
+		return nil
+	}
+	value, err := protojson.Marshal(envs)
+	if err != nil {
+		return fmt.Errorf("failed to marshal the environment variables of cluster %s: %w", rayCluster.Name, err)
+	}
+	if rayCluster.Annotations == nil {
+		rayCluster.Annotations = map[string]string{}
+	}
+	rayCluster.Annotations[RayClusterEnvsAnnotationKey] = string(value)
+	return nil
+}
+
+// getClusterEnvs returns the cluster level environment variables recorded by setClusterEnvs, or nil if the RayCluster
+// has none.
+func getClusterEnvs(rayCluster *rayv1api.RayCluster) (*api.EnvironmentVariables, error) {
+	value, ok := rayCluster.Annotations[RayClusterEnvsAnnotationKey]
+	if !ok {
+		return nil, nil
+	}
+	envs := &api.EnvironmentVariables{}
+	if err := protojson.Unmarshal([]byte(value), envs); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal the environment variables of cluster %s: %w", rayCluster.Name, err)
 	}
-	return cluster.Annotations
+	return envs, nil
 }
 
 // TODO(Basasuya & MissionToMars): The job spec depends on ClusterSpec which not all cluster-related configs are included,
 // such as `metadata` and `envs`. We just put `imageVersion` and `envs` in the arguments list, and should be refactored later.
-func buildRayClusterSpec(imageVersion string, envs *api.EnvironmentVariables, clusterSpec *api.ClusterSpec, computeTemplateMap map[string]*api.ComputeTemplate, enableServeService bool) (*rayv1api.RayClusterSpec, error) {
-	computeTemplate := computeTemplateMap[clusterSpec.HeadGroupSpec.ComputeTemplate]
-	headPodTemplate, err := buildHeadPodTemplate(imageVersion, envs, clusterSpec.HeadGroupSpec, computeTemplate, enableServeService)
+func buildRayClusterSpec(imageVersion string, envs *api.EnvironmentVariables, clusterSpec *api.ClusterSpec, computeTemplateMap map[string]*api.ComputeTemplate, imageTemplateMap map[string]*api.ImageTemplate, enableServeService bool) (*rayv1api.RayClusterSpec, error) {
+	headGroupSpec, err := buildHeadGroupSpec(imageVersion, envs, clusterSpec.HeadGroupSpec, computeTemplateMap, imageTemplateMap, enableServeService)
 	if err != nil {
 		return nil, err
 	}
 	rayClusterSpec := &rayv1api.RayClusterSpec{
-		RayVersion: imageVersion,
-		HeadGroupSpec: rayv1api.HeadGroupSpec{
-			ServiceType:    corev1.ServiceType(clusterSpec.HeadGroupSpec.ServiceType),
-			Template:       *headPodTemplate,
-			RayStartParams: clusterSpec.HeadGroupSpec.RayStartParams,
-		},
+		RayVersion:       imageVersion,
+		HeadGroupSpec:    *headGroupSpec,
 		WorkerGroupSpecs: []rayv1api.WorkerGroupSpec{},
 	}
 
-	// If enable ingress is specified, add it to the head node spec.
-	if clusterSpec.HeadGroupSpec.EnableIngress {
-		rayClusterSpec.HeadGroupSpec.EnableIngress = &clusterSpec.HeadGroupSpec.EnableIngress
-	}
-
 	// Build worker groups
 	for _, spec := range clusterSpec.WorkerGroupSpec {
-		computeTemplate = computeTemplateMap[spec.ComputeTemplate]
-		workerPodTemplate, err := buildWorkerPodTemplate(imageVersion, envs, spec, computeTemplate)
+		workerNodeSpec, err := buildWorkerGroupSpec(imageVersion, envs, spec, computeTemplateMap, imageTemplateMap)
 		if err != nil {
 			return nil, err
 		}
-
-		minReplicas := spec.Replicas
-		maxReplicas := spec.Replicas
-		if spec.MinReplicas != 0 {
-			minReplicas = spec.MinReplicas
-		}
-		if spec.MaxReplicas != 0 {
-			maxReplicas = spec.MaxReplicas
-		}
-
-		workerNodeSpec := rayv1api.WorkerGroupSpec{
-			GroupName:      spec.GroupName,
-			MinReplicas:    intPointer(minReplicas),
-			MaxReplicas:    intPointer(maxReplicas),
-			Replicas:       intPointer(spec.Replicas),
-			RayStartParams: spec.RayStartParams,
-			Template:       *workerPodTemplate,
-		}
-
-		rayClusterSpec.WorkerGroupSpecs = append(rayClusterSpec.WorkerGroupSpecs, workerNodeSpec)
+		rayClusterSpec.WorkerGroupSpecs = append(rayClusterSpec.WorkerGroupSpecs, *workerNodeSpec)
 	}
 
 	if clusterSpec.EnableInTreeAutoscaling {
@@ -135,20 +144,248 @@ func buildRayClusterSpec(imageVersion string, envs *api.EnvironmentVariables, cl
 	return rayClusterSpec, nil
 }
 
+// Build head group spec
+func buildHeadGroupSpec(imageVersion string, envs *api.EnvironmentVariables, spec *api.HeadGroupSpec, computeTemplateMap map[string]*api.ComputeTemplate, imageTemplateMap map[string]*api.ImageTemplate, enableServeService bool) (*rayv1api.HeadGroupSpec, error) {
+	computeTemplate := computeTemplateMap[spec.ComputeTemplate]
+	imageTemplate, err := lookupImageTemplate(imageTemplateMap, spec.ImageTemplate)
+	if err != nil {
+		return nil, err
+	}
+	headPodTemplate, err := buildHeadPodTemplate(imageVersion, envs, spec, computeTemplate, imageTemplate, enableServeService)
+	if err != nil {
+		return nil, err
+	}
+	headGroupSpec := &rayv1api.HeadGroupSpec{
+		ServiceType:    corev1.ServiceType(spec.ServiceType),
+		Template:       *headPodTemplate,
+		RayStartParams: spec.RayStartParams,
+	}
+
+	// If enable ingress is specified, add it to the head node spec.
+	if spec.EnableIngress {
+		headGroupSpec.EnableIngress = &spec.EnableIngress
+	}
+
+	return headGroupSpec, nil
+}
+
+// Build worker group spec
+func buildWorkerGroupSpec(imageVersion string, envs *api.EnvironmentVariables, spec *api.WorkerGroupSpec, computeTemplateMap map[string]*api.ComputeTemplate, imageTemplateMap map[string]*api.ImageTemplate) (*rayv1api.WorkerGroupSpec, error) {
+	computeTemplate := computeTemplateMap[spec.ComputeTemplate]
+	imageTemplate, err := lookupImageTemplate(imageTemplateMap, spec.ImageTemplate)
+	if err != nil {
+		return nil, err
+	}
+	workerPodTemplate, err := buildWorkerPodTemplate(imageVersion, envs, spec, computeTemplate, imageTemplate)
+	if err != nil {
+		return nil, err
+	}
+
+	minReplicas := spec.Replicas
+	maxReplicas := spec.Replicas
+	if spec.MinReplicas != 0 {
+		minReplicas = spec.MinReplicas
+	}
+	if spec.MaxReplicas != 0 {
+		maxReplicas = spec.MaxReplicas
+	}
+
+	return &rayv1api.WorkerGroupSpec{
+		GroupName:      spec.GroupName,
+		MinReplicas:    intPointer(minReplicas),
+		MaxReplicas:    intPointer(maxReplicas),
+		Replicas:       intPointer(spec.Replicas),
+		RayStartParams: spec.RayStartParams,
+		Template:       *workerPodTemplate,
+	}, nil
+}
+
+// Update mask paths supported by UpdateRayCluster
+const (
+	ClusterHeadGroupSpecPath          = "cluster_spec.head_group_spec"
+	ClusterWorkerGroupSpecPath        = "cluster_spec.worker_group_spec"
+	ClusterWorkerGroupReplicasPath    = "cluster_spec.worker_group_spec.replicas"
+	ClusterWorkerGroupMinReplicasPath = "cluster_spec.worker_group_spec.min_replicas"
+	ClusterWorkerGroupMaxReplicasPath = "cluster_spec.worker_group_spec.max_replicas"
+)
+
+// MergeClusterSpec returns a copy of the update in which the node groups selected by the update mask paths are
+// completed with the current values of the fields the update does not set, see UpdateRayCluster. Worker groups are
+// matched by group name, and worker groups which are not part of the update are kept as sent.
+func MergeClusterSpec(current *api.ClusterSpec, update *api.ClusterSpec, updatePaths []string) *api.ClusterSpec {
+	merged := proto.Clone(update).(*api.ClusterSpec)
+	for _, path := range updatePaths {
+		switch {
+		case path == ClusterHeadGroupSpecPath || strings.HasPrefix(path, ClusterHeadGroupSpecPath+"."):
+			if update.HeadGroupSpec != nil && current.HeadGroupSpec != nil {
+				headGroupSpec := proto.Clone(current.HeadGroupSpec)
+				overwriteSetFields(headGroupSpec, update.HeadGroupSpec)
+				merged.HeadGroupSpec = headGroupSpec.(*api.HeadGroupSpec)
+			}
+		case path == ClusterWorkerGroupSpecPath:
+			for i, spec := range update.WorkerGroupSpec {
+				for _, currentSpec := range current.WorkerGroupSpec {
+					if currentSpec.GroupName == spec.GroupName {
+						workerGroupSpec := proto.Clone(currentSpec)
+						overwriteSetFields(workerGroupSpec, spec)
+						merged.WorkerGroupSpec[i] = workerGroupSpec.(*api.WorkerGroupSpec)
+					}
+				}
+			}
+		}
+	}
+	return merged
+}
+
+// overwriteSetFields overwrites the fields of dst with the fields set in src. Unlike proto.Merge, maps and lists
+// of src replace the ones of dst instead of being merged.
+func overwriteSetFields(dst proto.Message, src proto.Message) {
+	dstMessage := dst.ProtoReflect()
+	src.ProtoReflect().Range(func(field protoreflect.FieldDescriptor, value protoreflect.Value) bool {
+		dstMessage.Set(field, value)
+		return true
+	})
+}
+
+// UpdateRayCluster applies the fields of the API cluster selected by the update mask paths to an existing RayCluster.
+// The head group and the list of worker groups are rebuilt from the given node groups, which are expected to be
+// completed with MergeClusterSpec, while the replica paths only rescale the existing worker groups matched by group
+// name. Paths under the head group spec rebuild the whole head group. The rebuilt node groups keep the cluster level
+// environment variables of the RayCluster unless the API cluster sends new ones. The replicas of every worker group
+// must be within its min and max replicas after the update.
+func UpdateRayCluster(rayCluster *rayv1api.RayCluster, apiCluster *api.Cluster, updatePaths []string, computeTemplateMap map[string]*api.ComputeTemplate, imageTemplateMap map[string]*api.ImageTemplate) error {
+	enableServeService := rayCluster.Annotations["ray.io/enable-serve-service"] == "true"
+	imageVersion := rayCluster.Spec.RayVersion
+	clusterSpec := apiCluster.ClusterSpec
+
+	envs := apiCluster.Envs
+	if envs == nil {
+		var err error
+		if envs, err = getClusterEnvs(rayCluster); err != nil {
+			return err
+		}
+	} else if err := setClusterEnvs(rayCluster, envs); err != nil {
+		return err
+	}
+
+	for _, path := range updatePaths {
+		switch {
+		case path == "name" || path == "namespace":
+			// These identify the cluster and can not be changed.
+			continue
+		case clusterSpec == nil:
+			return fmt.Errorf("cluster spec is required to update %s", path)
+		case path == ClusterHeadGroupSpecPath || strings.HasPrefix(path, ClusterHeadGroupSpecPath+"."):
+			if clusterSpec.HeadGroupSpec == nil {
+				return fmt.Errorf("head group spec is required to update %s", path)
+			}
+			headGroupSpec, err := buildHeadGroupSpec(imageVersion, envs, clusterSpec.HeadGroupSpec, computeTemplateMap, imageTemplateMap, enableServeService)
+			if err != nil {
+				return err
+			}
+			rayCluster.Spec.HeadGroupSpec = *headGroupSpec
+		case path == ClusterWorkerGroupSpecPath:
+			workerGroupSpecs := []rayv1api.WorkerGroupSpec{}
+			for _, spec := range clusterSpec.WorkerGroupSpec {
+				workerGroupSpec, err := buildWorkerGroupSpec(imageVersion, envs, spec, computeTemplateMap, imageTemplateMap)
+				if err != nil {
+					return err
+				}
+				workerGroupSpecs = append(workerGroupSpecs, *workerGroupSpec)
+			}
+			rayCluster.Spec.WorkerGroupSpecs = workerGroupSpecs
+		case path == ClusterWorkerGroupReplicasPath || path == ClusterWorkerGroupMinReplicasPath || path == ClusterWorkerGroupMaxReplicasPath:
+			for _, spec := range clusterSpec.WorkerGroupSpec {
+				workerGroupSpec := findWorkerGroupSpec(rayCluster, spec.GroupName)
+				if workerGroupSpec == nil {
+					return fmt.Errorf("worker group %s is not found", spec.GroupName)
+				}
+				switch path {
+				case ClusterWorkerGroupReplicasPath:
+					workerGroupSpec.Replicas = intPointer(spec.Replicas)
+				case ClusterWorkerGroupMinReplicasPath:
+					workerGroupSpec.MinReplicas = intPointer(spec.MinReplicas)
+				case ClusterWorkerGroupMaxReplicasPath:
+					workerGroupSpec.MaxReplicas = intPointer(spec.MaxReplicas)
+				}
+			}
+		default:
+			return fmt.Errorf("update mask path %s is not supported", path)
+		}
+	}
+
+	for _, spec := range rayCluster.Spec.WorkerGroupSpecs {
+		if spec.Replicas == nil || spec.MinReplicas == nil || spec.MaxReplicas == nil {
+			continue
+		}
+		if *spec.MinReplicas > *spec.Replicas || *spec.Replicas > *spec.MaxReplicas {
+			return fmt.Errorf("worker group %s must satisfy min replicas (%d) <= replicas (%d) <= max replicas (%d)",
+				spec.GroupName, *spec.MinReplicas, *spec.Replicas, *spec.MaxReplicas)
+		}
+	}
+	return nil
+}
+
+// Find a worker group of the RayCluster by name
+func findWorkerGroupSpec(rayCluster *rayv1api.RayCluster, groupName string) *rayv1api.WorkerGroupSpec {
+	for i := range rayCluster.Spec.WorkerGroupSpecs {
+		if rayCluster.Spec.WorkerGroupSpecs[i].GroupName == groupName {
+			return &rayCluster.Spec.WorkerGroupSpecs[i]
+		}
+	}
+	return nil
+}
+
+// Look up an image template by name. An empty name means that no image template is used.
+func lookupImageTemplate(imageTemplateMap map[string]*api.ImageTemplate, name string) (*api.ImageTemplate, error) {
+	if len(name) == 0 {
+		return nil, nil
+	}
+	imageTemplate, ok := imageTemplateMap[name]
+	if !ok || imageTemplate == nil {
+		return nil, fmt.Errorf("image template %s is not found", name)
+	}
+	return imageTemplate, nil
+}
+
 // Annotations common to both head and worker nodes
-func buildNodeGroupAnnotations(computeTemplate *api.ComputeTemplate, image string) map[string]string {
+func buildNodeGroupAnnotations(computeTemplate *api.ComputeTemplate, imageTemplate *api.ImageTemplate, image string) map[string]string {
 	annotations := map[string]string{}
 	annotations[RayClusterComputeTemplateAnnotationKey] = computeTemplate.Name
 	annotations[RayClusterImageAnnotationKey] = image
+	if imageTemplate != nil {
+		annotations[RayClusterImageTemplateAnnotationKey] = imageTemplate.Name
+	}
 	return annotations
 }
 
-// Build head node template
-func buildHeadPodTemplate(imageVersion string, envs *api.EnvironmentVariables, spec *api.HeadGroupSpec, computeRuntime *api.ComputeTemplate, enableServeService bool) (*corev1.PodTemplateSpec, error) {
-	image := constructRayImage(RayClusterDefaultImageRepository, imageVersion)
-	if len(spec.Image) != 0 {
-		image = spec.Image
+// Build the container image for a node group. The image template, if any, wins over the group image,
+// which in turn wins over the default image for the Ray version.
+func buildNodeGroupImage(imageVersion string, image string, imageTemplate *api.ImageTemplate) string {
+	if imageTemplate != nil {
+		if len(imageTemplate.Image) != 0 {
+			return imageTemplate.Image
+		}
+		return imageTemplate.BaseImage
+	}
+	if len(image) != 0 {
+		return image
+	}
+	return constructRayImage(RayClusterDefaultImageRepository, imageVersion)
+}
+
+// Convert the environment variables of an image template
+func convertImageTemplateEnvironment(imageTemplate *api.ImageTemplate) []corev1.EnvVar {
+	converted := []corev1.EnvVar{}
+	if imageTemplate == nil {
+		return converted
 	}
+	return convertEnvironmentVariables(&api.EnvironmentVariables{Values: imageTemplate.EnvironmentVariables})
+}
+
+// Build head node template
+func buildHeadPodTemplate(imageVersion string, envs *api.EnvironmentVariables, spec *api.HeadGroupSpec, computeRuntime *api.ComputeTemplate, imageTemplate *api.ImageTemplate, enableServeService bool) (*corev1.PodTemplateSpec, error) {
+	image := buildNodeGroupImage(imageVersion, spec.Image, imageTemplate)
 
 	// calculate resources
 	cpu := fmt.Sprint(computeRuntime.GetCpu())
@@ -163,7 +400,7 @@ func buildHeadPodTemplate(imageVersion string, envs *api.EnvironmentVariables, s
 
 	podTemplateSpec := corev1.PodTemplateSpec{
 		ObjectMeta: metav1.ObjectMeta{
-			Annotations: buildNodeGroupAnnotations(computeRuntime, spec.Image),
+			Annotations: buildNodeGroupAnnotations(computeRuntime, imageTemplate, spec.Image),
 			Labels:      map[string]string{},
 		},
 		Spec: corev1.PodSpec{
@@ -231,6 +468,12 @@ func buildHeadPodTemplate(imageVersion string, envs *api.EnvironmentVariables, s
 			container.Resources.Requests[corev1.ResourceName(accelerator)] = resource.MustParse(fmt.Sprint(gpu))
 			container.Resources.Limits[corev1.ResourceName(accelerator)] = resource.MustParse(fmt.Sprint(gpu))
 		}
+		// Add image template environments
+		templateEnv := convertImageTemplateEnvironment(imageTemplate)
+		if len(templateEnv) > 0 {
+			container.Env = append(container.Env, templateEnv...)
+		}
+
 		globalEnv := convertEnvironmentVariables(envs)
 		if len(globalEnv) > 0 {
 			container.Env = append(container.Env, globalEnv...)
@@ -384,13 +627,10 @@ func constructRayImage(containerImage string, version string) string {
 }
 
 // Build worker pod template
-func buildWorkerPodTemplate(imageVersion string, envs *api.EnvironmentVariables, spec *api.WorkerGroupSpec, computeRuntime *api.ComputeTemplate) (*corev1.PodTemplateSpec, error) {
+func buildWorkerPodTemplate(imageVersion string, envs *api.EnvironmentVariables, spec *api.WorkerGroupSpec, computeRuntime *api.ComputeTemplate, imageTemplate *api.ImageTemplate) (*corev1.PodTemplateSpec, error) {
 	// If user doesn't provide the image, let's use the default image instead.
 	// TODO: verify the versions in the range
-	image := constructRayImage(RayClusterDefaultImageRepository, imageVersion)
-	if len(spec.Image) != 0 {
-		image = spec.Image
-	}
+	image := buildNodeGroupImage(imageVersion, spec.Image, imageTemplate)
 
 	// calculate resources
 	cpu := fmt.Sprint(computeRuntime.GetCpu())
@@ -405,7 +645,7 @@ func buildWorkerPodTemplate(imageVersion string, envs *api.EnvironmentVariables,
 
 	podTemplateSpec := corev1.PodTemplateSpec{
 		ObjectMeta: metav1.ObjectMeta{
-			Annotations: buildNodeGroupAnnotations(computeRuntime, spec.Image),
+			Annotations: buildNodeGroupAnnotations(computeRuntime, imageTemplate, spec.Image),
 			Labels:      map[string]string{},
 		},
 		Spec: corev1.PodSpec{
@@ -522,6 +762,12 @@ func buildWorkerPodTemplate(imageVersion string, envs *api.EnvironmentVariables,
 			container.Resources.Limits[corev1.ResourceName(accelerator)] = resource.MustParse(fmt.Sprint(gpu))
 		}
 
+		// Add image template environments
+		templateEnv := convertImageTemplateEnvironment(imageTemplate)
+		if len(templateEnv) > 0 {
+			container.Env = append(container.Env, templateEnv...)
+		}
+
 		globalEnv := convertEnvironmentVariables(envs)
 		if len(globalEnv) > 0 {
 			container.Env = append(container.Env, globalEnv...)
@@ -817,6 +1063,55 @@ func NewComputeTemplate(runtime *api.ComputeTemplate) (*corev1.ConfigMap, error)
 	return config, nil
 }
 
+// Build image template
+func NewImageTemplate(imageTemplate *api.ImageTemplate) (*corev1.ConfigMap, error) {
+	// Create data map
+	dmap := map[string]string{
+		"name":            imageTemplate.Name,
+		"namespace":       imageTemplate.Namespace,
+		"base_image":      imageTemplate.BaseImage,
+		"custom_commands": imageTemplate.CustomCommands,
+		"image":           imageTemplate.Image,
+	}
+	// Add package lists and environment variables if defined
+	packages := map[string][]string{
+		"pip_packages":    imageTemplate.PipPackages,
+		"conda_packages":  imageTemplate.CondaPackages,
+		"system_packages": imageTemplate.SystemPackages,
+	}
+	for key, value := range packages {
+		if len(value) == 0 {
+			continue
+		}
+		p, err := json.Marshal(value)
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal %s for image template %s: %w", key, imageTemplate.Name, err)
+		}
+		dmap[key] = string(p)
+	}
+	if len(imageTemplate.EnvironmentVariables) > 0 {
+		e, err := json.Marshal(imageTemplate.EnvironmentVariables)
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal environment variables for image template %s: %w", imageTemplate.Name, err)
+		}
+		dmap["environment_variables"] = string(e)
+	}
+
+	config := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      imageTemplate.Name,
+			Namespace: imageTemplate.Namespace,
+			Labels: map[string]string{
+				"ray.io/config-type":    "image-template",
+				"ray.io/image-template": imageTemplate.Name,
+			},
+		},
+		Data: dmap,
+	}
+
+	return config, nil
+}
+
 // GetNodeHostIP returns the provided node's IP, based on the priority:
 // 1. NodeInternalIP
 // 2. NodeExternalIP
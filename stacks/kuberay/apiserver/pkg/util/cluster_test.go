@@ -1,12 +1,14 @@
 package util
 
 import (
+	"encoding/json"
 	"reflect"
 	"sort"
 	"testing"
 
 	api "github.com/ray-project/kuberay/proto/go_client"
 	"github.com/stretchr/testify/assert"
+	"google.golang.org/protobuf/proto"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -508,7 +510,7 @@ func TestBuildVolumeMounts(t *testing.T) {
 }
 
 func TestBuildHeadPodTemplate(t *testing.T) {
-	podSpec, err := buildHeadPodTemplate("2.4", &api.EnvironmentVariables{}, &headGroup, &template, false)
+	podSpec, err := buildHeadPodTemplate("2.4", &api.EnvironmentVariables{}, &headGroup, &template, nil, false)
 	assert.Nil(t, err)
 
 	if podSpec.Spec.ServiceAccountName != "account" {
@@ -549,7 +551,7 @@ func TestBuildHeadPodTemplate(t *testing.T) {
 		t.Errorf("failed to convert labels, got %v, expected %v", podSpec.Labels, expectedLabels)
 	}
 
-	podSpec, err = buildHeadPodTemplate("2.4", &api.EnvironmentVariables{}, &headGroup, &template, true)
+	podSpec, err = buildHeadPodTemplate("2.4", &api.EnvironmentVariables{}, &headGroup, &template, nil, true)
 	assert.Nil(t, err)
 	if len(podSpec.Spec.Containers[0].Ports) != 6 {
 		t.Errorf("failed build ports")
@@ -569,7 +571,7 @@ func TestConvertAutoscalerOptions(t *testing.T) {
 }
 
 func TestBuildRayCluster(t *testing.T) {
-	cluster, err := NewRayCluster(&rayCluster, map[string]*api.ComputeTemplate{"foo": &template})
+	cluster, err := NewRayCluster(&rayCluster, map[string]*api.ComputeTemplate{"foo": &template}, nil)
 	assert.Nil(t, err)
 	if len(cluster.ObjectMeta.Annotations) != 1 {
 		t.Errorf("failed to propagate annotations")
@@ -578,14 +580,14 @@ func TestBuildRayCluster(t *testing.T) {
 		t.Errorf("failed to propagate create Ingress")
 	}
 	assert.Equal(t, cluster.Spec.EnableInTreeAutoscaling, (*bool)(nil))
-	cluster, err = NewRayCluster(&rayClusterAutoScaler, map[string]*api.ComputeTemplate{"foo": &template})
+	cluster, err = NewRayCluster(&rayClusterAutoScaler, map[string]*api.ComputeTemplate{"foo": &template}, nil)
 	assert.Nil(t, err)
 	assert.Equal(t, *cluster.Spec.EnableInTreeAutoscaling, true)
 	assert.NotEqual(t, cluster.Spec.AutoscalerOptions, nil)
 }
 
 func TestBuilWorkerPodTemplate(t *testing.T) {
-	podSpec, err := buildWorkerPodTemplate("2.4", &api.EnvironmentVariables{}, &workerGroup, &template)
+	podSpec, err := buildWorkerPodTemplate("2.4", &api.EnvironmentVariables{}, &workerGroup, &template, nil)
 	assert.Nil(t, err)
 
 	if podSpec.Spec.ServiceAccountName != "account" {
@@ -624,3 +626,218 @@ func containsEnv(envs []corev1.EnvVar, key string, val string) bool {
 func tolerationToString(toleration *corev1.Toleration) string {
 	return "Key: " + toleration.Key + " Operator: " + string(toleration.Operator) + " Effect: " + string(toleration.Effect)
 }
+
+var testImageTemplate = api.ImageTemplate{
+	Name:        "my-image",
+	Namespace:   "foo",
+	BaseImage:   "rayproject/ray:2.4.0",
+	PipPackages: []string{"numpy==1.24.2", "pandas>=1.5,<2"},
+	EnvironmentVariables: map[string]string{
+		"IMAGE_TEMPLATE_ENV": "value",
+	},
+}
+
+func TestBuildNodeGroupImage(t *testing.T) {
+	tests := []struct {
+		name          string
+		image         string
+		imageTemplate *api.ImageTemplate
+		expect        string
+	}{
+		{
+			"default image",
+			"",
+			nil,
+			"rayproject/ray:2.4",
+		},
+		{
+			"group image",
+			"bar",
+			nil,
+			"bar",
+		},
+		{
+			"image template base image",
+			"bar",
+			&api.ImageTemplate{Name: "t", BaseImage: "base"},
+			"base",
+		},
+		{
+			"image template built image",
+			"bar",
+			&api.ImageTemplate{Name: "t", BaseImage: "base", Image: "built"},
+			"built",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := buildNodeGroupImage("2.4", tt.image, tt.imageTemplate)
+			if got != tt.expect {
+				t.Errorf("failed for %s ..., got %v, expected %v", tt.name, got, tt.expect)
+			}
+		})
+	}
+}
+
+func TestBuildRayClusterWithImageTemplate(t *testing.T) {
+	head := proto.Clone(&headGroup).(*api.HeadGroupSpec)
+	head.ImageTemplate = testImageTemplate.Name
+	worker := proto.Clone(&workerGroup).(*api.WorkerGroupSpec)
+	worker.ImageTemplate = testImageTemplate.Name
+	cluster := api.Cluster{
+		Name:      "test_cluster",
+		Namespace: "foo",
+		ClusterSpec: &api.ClusterSpec{
+			HeadGroupSpec:   head,
+			WorkerGroupSpec: []*api.WorkerGroupSpec{worker},
+		},
+	}
+
+	rayCluster, err := NewRayCluster(&cluster, map[string]*api.ComputeTemplate{"foo": &template},
+		map[string]*api.ImageTemplate{testImageTemplate.Name: &testImageTemplate})
+	assert.Nil(t, err)
+
+	headTemplate := rayCluster.Spec.HeadGroupSpec.Template
+	assert.Equal(t, testImageTemplate.BaseImage, headTemplate.Spec.Containers[0].Image)
+	assert.Equal(t, testImageTemplate.Name, headTemplate.Annotations[RayClusterImageTemplateAnnotationKey])
+	assert.True(t, containsEnv(headTemplate.Spec.Containers[0].Env, "IMAGE_TEMPLATE_ENV", "value"))
+
+	workerTemplate := rayCluster.Spec.WorkerGroupSpecs[0].Template
+	assert.Equal(t, testImageTemplate.BaseImage, workerTemplate.Spec.Containers[0].Image)
+	assert.Equal(t, testImageTemplate.Name, workerTemplate.Annotations[RayClusterImageTemplateAnnotationKey])
+	assert.True(t, containsEnv(workerTemplate.Spec.Containers[0].Env, "IMAGE_TEMPLATE_ENV", "value"))
+
+	// Referencing an image template that does not exist should fail
+	_, err = NewRayCluster(&cluster, map[string]*api.ComputeTemplate{"foo": &template}, nil)
+	assert.NotNil(t, err)
+}
+
+func TestNewImageTemplate(t *testing.T) {
+	configMap, err := NewImageTemplate(&testImageTemplate)
+	assert.Nil(t, err)
+	assert.Equal(t, "image-template", configMap.Labels["ray.io/config-type"])
+	assert.Equal(t, testImageTemplate.Name, configMap.Labels["ray.io/image-template"])
+	assert.Equal(t, testImageTemplate.BaseImage, configMap.Data["base_image"])
+	var pipPackages []string
+	assert.Nil(t, json.Unmarshal([]byte(configMap.Data["pip_packages"]), &pipPackages))
+	assert.Equal(t, testImageTemplate.PipPackages, pipPackages)
+	assert.Equal(t, `{"IMAGE_TEMPLATE_ENV":"value"}`, configMap.Data["environment_variables"])
+	_, exists := configMap.Data["conda_packages"]
+	assert.False(t, exists)
+}
+
+func TestUpdateRayCluster(t *testing.T) {
+	cluster, err := NewRayCluster(&rayCluster, map[string]*api.ComputeTemplate{"foo": &template}, nil)
+	assert.Nil(t, err)
+
+	// Scale the existing worker group
+	scaled := cluster.DeepCopy()
+	update := &api.Cluster{
+		ClusterSpec: &api.ClusterSpec{
+			WorkerGroupSpec: []*api.WorkerGroupSpec{
+				{GroupName: "wg", Replicas: 2, MinReplicas: 1, MaxReplicas: 10},
+			},
+		},
+	}
+	err = UpdateRayCluster(scaled, update, []string{ClusterWorkerGroupReplicasPath, ClusterWorkerGroupMinReplicasPath, ClusterWorkerGroupMaxReplicasPath}, nil, nil)
+	assert.Nil(t, err)
+	assert.Equal(t, int32(2), *scaled.Spec.WorkerGroupSpecs[0].Replicas)
+	assert.Equal(t, int32(1), *scaled.Spec.WorkerGroupSpecs[0].MinReplicas)
+	assert.Equal(t, int32(10), *scaled.Spec.WorkerGroupSpecs[0].MaxReplicas)
+	assert.Equal(t, cluster.Spec.WorkerGroupSpecs[0].Template, scaled.Spec.WorkerGroupSpecs[0].Template)
+
+	// Replicas out of the min and max replicas should fail
+	err = UpdateRayCluster(cluster.DeepCopy(), update, []string{ClusterWorkerGroupReplicasPath}, nil, nil)
+	assert.ErrorContains(t, err, "min replicas (5) <= replicas (2) <= max replicas (5)")
+
+	// Scaling a worker group which does not exist should fail
+	update.ClusterSpec.WorkerGroupSpec[0].GroupName = "unknown"
+	err = UpdateRayCluster(cluster.DeepCopy(), update, []string{ClusterWorkerGroupReplicasPath}, nil, nil)
+	assert.NotNil(t, err)
+
+	// Replace the worker groups
+	replaced := cluster.DeepCopy()
+	newWorkerGroup := proto.Clone(&workerGroup).(*api.WorkerGroupSpec)
+	newWorkerGroup.GroupName = "wg2"
+	update = &api.Cluster{
+		ClusterSpec: &api.ClusterSpec{
+			WorkerGroupSpec: []*api.WorkerGroupSpec{&workerGroup, newWorkerGroup},
+		},
+	}
+	err = UpdateRayCluster(replaced, update, []string{ClusterWorkerGroupSpecPath}, map[string]*api.ComputeTemplate{"foo": &template}, nil)
+	assert.Nil(t, err)
+	assert.Equal(t, 2, len(replaced.Spec.WorkerGroupSpecs))
+	assert.Equal(t, "wg2", replaced.Spec.WorkerGroupSpecs[1].GroupName)
+	assert.Equal(t, cluster.Spec.HeadGroupSpec, replaced.Spec.HeadGroupSpec)
+
+	// Unsupported paths are rejected
+	err = UpdateRayCluster(cluster.DeepCopy(), update, []string{"user"}, nil, nil)
+	assert.NotNil(t, err)
+}
+
+func TestMergeClusterSpec(t *testing.T) {
+	current := proto.Clone(rayCluster.ClusterSpec).(*api.ClusterSpec)
+	update := &api.ClusterSpec{
+		HeadGroupSpec: &api.HeadGroupSpec{ServiceType: "NodePort"},
+		WorkerGroupSpec: []*api.WorkerGroupSpec{
+			{GroupName: "wg", Image: "baz", RayStartParams: map[string]string{"num-cpus": "1"}},
+			{GroupName: "wg2", ComputeTemplate: "foo"},
+		},
+	}
+
+	// Only the node groups selected by the paths are merged
+	merged := MergeClusterSpec(current, update, []string{ClusterWorkerGroupSpecPath})
+	assert.True(t, proto.Equal(update.HeadGroupSpec, merged.HeadGroupSpec))
+
+	merged = MergeClusterSpec(current, update, []string{ClusterHeadGroupSpecPath, ClusterWorkerGroupSpecPath})
+	expectedHead := proto.Clone(&headGroup).(*api.HeadGroupSpec)
+	expectedHead.ServiceType = "NodePort"
+	assert.True(t, proto.Equal(expectedHead, merged.HeadGroupSpec), "unexpected head group: %v", merged.HeadGroupSpec)
+
+	// Set fields replace the current ones, maps included, while unset fields keep their current value
+	expectedWorker := proto.Clone(&workerGroup).(*api.WorkerGroupSpec)
+	expectedWorker.Image = "baz"
+	expectedWorker.RayStartParams = map[string]string{"num-cpus": "1"}
+	assert.Equal(t, 2, len(merged.WorkerGroupSpec))
+	assert.True(t, proto.Equal(expectedWorker, merged.WorkerGroupSpec[0]), "unexpected worker group: %v", merged.WorkerGroupSpec[0])
+	assert.True(t, proto.Equal(update.WorkerGroupSpec[1], merged.WorkerGroupSpec[1]))
+
+	// The inputs are not modified
+	assert.Equal(t, "bar", current.WorkerGroupSpec[0].Image)
+	assert.Equal(t, "", update.WorkerGroupSpec[0].ComputeTemplate)
+}
+
+func TestUpdateRayClusterKeepsClusterEnvs(t *testing.T) {
+	apiCluster := proto.Clone(&rayCluster).(*api.Cluster)
+	apiCluster.Envs = &api.EnvironmentVariables{Values: map[string]string{"CLUSTER_ENV": "value"}}
+	cluster, err := NewRayCluster(apiCluster, map[string]*api.ComputeTemplate{"foo": &template}, nil)
+	assert.Nil(t, err)
+	envs, err := getClusterEnvs(cluster.RayCluster)
+	assert.Nil(t, err)
+	assert.True(t, proto.Equal(apiCluster.Envs, envs))
+	assert.Empty(t, apiCluster.Annotations[RayClusterEnvsAnnotationKey])
+
+	// Rebuilding the node groups without envs keeps the cluster level environment variables
+	updated := cluster.DeepCopy()
+	update := &api.Cluster{
+		ClusterSpec: &api.ClusterSpec{
+			HeadGroupSpec:   &headGroup,
+			WorkerGroupSpec: []*api.WorkerGroupSpec{&workerGroup},
+		},
+	}
+	err = UpdateRayCluster(updated, update, []string{ClusterHeadGroupSpecPath, ClusterWorkerGroupSpecPath}, map[string]*api.ComputeTemplate{"foo": &template}, nil)
+	assert.Nil(t, err)
+	assert.True(t, containsEnv(updated.Spec.HeadGroupSpec.Template.Spec.Containers[0].Env, "CLUSTER_ENV", "value"))
+	assert.True(t, containsEnv(updated.Spec.WorkerGroupSpecs[0].Template.Spec.Containers[0].Env, "CLUSTER_ENV", "value"))
+
+	// Sent envs replace the cluster level environment variables
+	updated = cluster.DeepCopy()
+	update.Envs = &api.EnvironmentVariables{Values: map[string]string{"NEW_CLUSTER_ENV": "value"}}
+	err = UpdateRayCluster(updated, update, []string{ClusterWorkerGroupSpecPath}, map[string]*api.ComputeTemplate{"foo": &template}, nil)
+	assert.Nil(t, err)
+	assert.True(t, containsEnv(updated.Spec.WorkerGroupSpecs[0].Template.Spec.Containers[0].Env, "NEW_CLUSTER_ENV", "value"))
+	assert.False(t, containsEnv(updated.Spec.WorkerGroupSpecs[0].Template.Spec.Containers[0].Env, "CLUSTER_ENV", "value"))
+	envs, err = getClusterEnvs(updated)
+	assert.Nil(t, err)
+	assert.True(t, proto.Equal(update.Envs, envs))
+}
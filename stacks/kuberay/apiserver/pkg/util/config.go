@@ -17,9 +17,12 @@ const (
 	KubernetesManagedByLabelKey       = "app.kubernetes.io/managed-by"
 
 	// Annotation keys
+	// Cluster level
+	RayClusterEnvsAnnotationKey = "ray.io/cluster-envs"
 	// Role level
 	RayClusterComputeTemplateAnnotationKey = "ray.io/compute-template"
 	RayClusterImageAnnotationKey           = "ray.io/compute-image"
+	RayClusterImageTemplateAnnotationKey   = "ray.io/image-template"
 
 	RayClusterDefaultImageRepository = "rayproject/ray"
 )
@@ -15,7 +15,7 @@ type RayJob struct {
 }
 
 // NewRayJob creates a RayJob.
-func NewRayJob(apiJob *api.RayJob, computeTemplateMap map[string]*api.ComputeTemplate) (*RayJob, error) {
+func NewRayJob(apiJob *api.RayJob, computeTemplateMap map[string]*api.ComputeTemplate, imageTemplateMap map[string]*api.ImageTemplate) (*RayJob, error) {
 	rayJob := &rayv1api.RayJob{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:        apiJob.Name,
@@ -35,7 +35,7 @@ func NewRayJob(apiJob *api.RayJob, computeTemplateMap map[string]*api.ComputeTem
 		},
 	}
 	if apiJob.ClusterSpec != nil {
-		clusterSpec, err := buildRayClusterSpec(apiJob.Version, nil, apiJob.ClusterSpec, computeTemplateMap, false)
+		clusterSpec, err := buildRayClusterSpec(apiJob.Version, nil, apiJob.ClusterSpec, computeTemplateMap, imageTemplateMap, false)
 		if err != nil {
 			return nil, err
 		}
@@ -0,0 +1,59 @@
+package util
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"github.com/gorilla/websocket"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// TailJobLog follows the log of a job through the `/api/jobs/{id}/logs/tail` websocket of the dashboard at
+// dashboardAddress (host:port) and calls handler with every chunk it receives. It returns nil once the dashboard
+// closes the websocket after the job finished, or the first error of the connection or the handler. Cancel ctx to
+// stop following the log. The job name is escaped into a single path segment, so it can not reach other endpoints of
+// the dashboard.
+func TailJobLog(ctx context.Context, dashboardAddress string, jobName string, handler func(chunk string) error) error {
+	if jobName == "" || jobName == "." || jobName == ".." {
+		return NewInvalidInputError("Invalid job submission id %q", jobName)
+	}
+	tailURL := "ws://" + dashboardAddress + "/api/jobs/" + url.PathEscape(jobName) + "/logs/tail"
+	conn, resp, err := websocket.DefaultDialer.DialContext(ctx, tailURL, nil)
+	if err != nil {
+		if resp != nil && resp.StatusCode == http.StatusNotFound {
+			return apierrors.NewNotFound(schema.GroupResource{Group: "RayJob", Resource: "JobSubmission"}, jobName)
+		}
+		return fmt.Errorf("TailJobLog fail: %w", err)
+	}
+	defer conn.Close()
+
+	// Unblock ReadMessage when the caller is gone.
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		select {
+		case <-ctx.Done():
+			conn.Close()
+		case <-done:
+		}
+	}()
+
+	for {
+		_, message, err := conn.ReadMessage()
+		if err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			if websocket.IsCloseError(err, websocket.CloseNormalClosure) {
+				return nil
+			}
+			return fmt.Errorf("TailJobLog fail: %w", err)
+		}
+		if err := handler(string(message)); err != nil {
+			return err
+		}
+	}
+}
@@ -0,0 +1,62 @@
+package util
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+)
+
+func TestTailJobLog(t *testing.T) {
+	upgrader := websocket.Upgrader{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		// Every request must stay on the tail endpoint of a single job.
+		segments := strings.Split(strings.TrimPrefix(req.URL.EscapedPath(), "/api/jobs/"), "/")
+		assert.Equal(t, []string{"logs", "tail"}, segments[1:], "unexpected path %s", req.URL.EscapedPath())
+		assert.Empty(t, req.URL.RawQuery)
+		if req.URL.EscapedPath() != "/api/jobs/tail-job-1/logs/tail" {
+			http.NotFound(w, req)
+			return
+		}
+		conn, err := upgrader.Upgrade(w, req, nil)
+		if !assert.NoError(t, err) {
+			return
+		}
+		defer conn.Close()
+		for _, chunk := range []string{"line 1\n", "line 2\nline 3\n"} {
+			assert.NoError(t, conn.WriteMessage(websocket.TextMessage, []byte(chunk)))
+		}
+		message := websocket.FormatCloseMessage(websocket.CloseNormalClosure, "")
+		assert.NoError(t, conn.WriteMessage(websocket.CloseMessage, message))
+	}))
+	defer server.Close()
+	address := strings.TrimPrefix(server.URL, "http://")
+
+	var chunks []string
+	err := TailJobLog(context.Background(), address, "tail-job-1", func(chunk string) error {
+		chunks = append(chunks, chunk)
+		return nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"line 1\n", "line 2\nline 3\n"}, chunks)
+
+	err = TailJobLog(context.Background(), address, "missing-job", func(chunk string) error { return nil })
+	assert.True(t, apierrors.IsNotFound(err), "unexpected error: %v", err)
+
+	// Path characters stay in the job name and do not reach other endpoints of the dashboard.
+	for _, jobName := range []string{"../tail-job-1", "tail-job-1/logs/tail?", "a/../../tail-job-1"} {
+		err = TailJobLog(context.Background(), address, jobName, func(chunk string) error { return nil })
+		assert.True(t, apierrors.IsNotFound(err), "unexpected error for %q: %v", jobName, err)
+	}
+	for _, jobName := range []string{"", ".", ".."} {
+		err = TailJobLog(context.Background(), address, jobName, func(chunk string) error { return nil })
+		assert.True(t, IsUserErrorCodeMatch(err, codes.InvalidArgument), "unexpected error for %q: %v", jobName, err)
+	}
+}
@@ -75,7 +75,7 @@ var apiJobExistingClusterSubmitterBadParams = &api.RayJob{
 
 func TestBuildRayJob(t *testing.T) {
 	// Test request with cluster creation
-	job, err := NewRayJob(apiJobNewCluster, map[string]*api.ComputeTemplate{"foo": &template})
+	job, err := NewRayJob(apiJobNewCluster, map[string]*api.ComputeTemplate{"foo": &template}, nil)
 	assert.Nil(t, err)
 	assert.Equal(t, "test", job.ObjectMeta.Name)
 	assert.Equal(t, "test", job.ObjectMeta.Namespace)
@@ -88,7 +88,7 @@ func TestBuildRayJob(t *testing.T) {
 	assert.NotNil(t, job.Spec.RayClusterSpec)
 
 	// Test request without cluster creation
-	job, err = NewRayJob(apiJobExistingCluster, map[string]*api.ComputeTemplate{"foo": &template})
+	job, err = NewRayJob(apiJobExistingCluster, map[string]*api.ComputeTemplate{"foo": &template}, nil)
 	assert.Nil(t, err)
 	assert.Equal(t, "test", job.ObjectMeta.Name)
 	assert.Equal(t, "test", job.ObjectMeta.Namespace)
@@ -100,7 +100,7 @@ func TestBuildRayJob(t *testing.T) {
 	assert.Nil(t, job.Spec.SubmitterPodTemplate)
 
 	// Test request without cluster creation with submitter
-	job, err = NewRayJob(apiJobExistingClusterSubmitter, map[string]*api.ComputeTemplate{"foo": &template})
+	job, err = NewRayJob(apiJobExistingClusterSubmitter, map[string]*api.ComputeTemplate{"foo": &template}, nil)
 	assert.Nil(t, err)
 	assert.Equal(t, "test", job.ObjectMeta.Name)
 	assert.Equal(t, "test", job.ObjectMeta.Namespace)
@@ -119,6 +119,6 @@ func TestBuildRayJob(t *testing.T) {
 	assert.Equal(t, "150Mi", job.Spec.SubmitterPodTemplate.Spec.Containers[0].Resources.Requests.Memory().String())
 
 	// Test request without cluster creation with submitter bad parameters
-	_, err = NewRayJob(apiJobExistingClusterSubmitterBadParams, map[string]*api.ComputeTemplate{"foo": &template})
+	_, err = NewRayJob(apiJobExistingClusterSubmitterBadParams, map[string]*api.ComputeTemplate{"foo": &template}, nil)
 	assert.NotNil(t, err)
 }
@@ -16,9 +16,9 @@ func (s *RayService) Get() *rayv1api.RayService {
 	return s.RayService
 }
 
-func NewRayService(apiService *api.RayService, computeTemplateMap map[string]*api.ComputeTemplate) (*RayService, error) {
+func NewRayService(apiService *api.RayService, computeTemplateMap map[string]*api.ComputeTemplate, imageTemplateMap map[string]*api.ImageTemplate) (*RayService, error) {
 	// Build the spec
-	spec, err := buildRayServiceSpec(apiService, computeTemplateMap)
+	spec, err := buildRayServiceSpec(apiService, computeTemplateMap, imageTemplateMap)
 	if err != nil {
 		return nil, err
 	}
@@ -49,7 +49,7 @@ func buildRayServiceAnnotations(apiService *api.RayService) map[string]string {
 	return annotations
 }
 
-func buildRayServiceSpec(apiService *api.RayService, computeTemplateMap map[string]*api.ComputeTemplate) (*rayv1api.RayServiceSpec, error) {
+func buildRayServiceSpec(apiService *api.RayService, computeTemplateMap map[string]*api.ComputeTemplate, imageTemplateMap map[string]*api.ImageTemplate) (*rayv1api.RayServiceSpec, error) {
 	// Ensure that at least one and only one serve config (V1 or V2) defined
 	if apiService.ServeConfig_V2 == "" {
 		// Serve configuration is not defined
@@ -57,7 +57,7 @@ func buildRayServiceSpec(apiService *api.RayService, computeTemplateMap map[stri
 	}
 
 	// generate Ray cluster spec and buid cluster
-	newRayClusterSpec, err := buildRayClusterSpec(apiService.Version, nil, apiService.ClusterSpec, computeTemplateMap, true)
+	newRayClusterSpec, err := buildRayClusterSpec(apiService.Version, nil, apiService.ClusterSpec, computeTemplateMap, imageTemplateMap, true)
 	if err != nil {
 		return nil, err
 	}
@@ -0,0 +1,32 @@
+package util
+
+import (
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+)
+
+// EventStreamContentType is the MIME type of server-sent events.
+const EventStreamContentType = "text/event-stream"
+
+// EventStreamMarshaler wraps a JSON runtime.Marshaler to frame every message of a streaming response as a
+// server-sent event, so that REST clients can follow streaming endpoints with an EventSource.
+// It is selected by the gateway for requests with an `Accept: text/event-stream` header.
+type EventStreamMarshaler struct {
+	runtime.Marshaler
+}
+
+func (m *EventStreamMarshaler) ContentType(_ interface{}) string {
+	return EventStreamContentType
+}
+
+func (m *EventStreamMarshaler) Marshal(v interface{}) ([]byte, error) {
+	data, err := m.Marshaler.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	return append([]byte("data: "), data...), nil
+}
+
+// Delimiter terminates every event, see runtime.Delimited.
+func (m *EventStreamMarshaler) Delimiter() []byte {
+	return []byte("\n\n")
+}
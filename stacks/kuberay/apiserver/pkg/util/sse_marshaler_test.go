@@ -0,0 +1,20 @@
+package util
+
+import (
+	"testing"
+
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+	api "github.com/ray-project/kuberay/proto/go_client"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEventStreamMarshaler(t *testing.T) {
+	marshaler := &EventStreamMarshaler{Marshaler: &runtime.JSONPb{}}
+
+	data, err := marshaler.Marshal(map[string]interface{}{"result": &api.TailJobLogReply{Log: "line 1\nline 2\n"}})
+	require.NoError(t, err)
+	assert.Equal(t, `data: {"result":{"log":"line 1\nline 2\n"}}`, string(data))
+	assert.Equal(t, "\n\n", string(marshaler.Delimiter()))
+	assert.Equal(t, EventStreamContentType, marshaler.ContentType(nil))
+}
@@ -532,7 +532,7 @@ func TestGetAllClusters(t *testing.T) {
 		tCtx.DeleteConfigMap(t, confiMapName)
 	})
 
-	response, actualRpcStatus, err := tCtx.GetRayApiServerClient().ListAllClusters()
+	response, actualRpcStatus, err := tCtx.GetRayApiServerClient().ListAllClusters(&api.ListAllClustersRequest{})
 	require.NoError(t, err, "No error expected")
 	require.Nil(t, actualRpcStatus, "No RPC status expected")
 	require.NotNil(t, response, "A response is expected")
@@ -11,6 +11,7 @@ import (
 	"github.com/ray-project/kuberay/cli/pkg/cmd/cluster"
 	"github.com/ray-project/kuberay/cli/pkg/cmd/config"
 	"github.com/ray-project/kuberay/cli/pkg/cmd/info"
+	"github.com/ray-project/kuberay/cli/pkg/cmd/job"
 	"github.com/ray-project/kuberay/cli/pkg/cmd/template"
 	"github.com/ray-project/kuberay/cli/pkg/cmd/version"
 	"github.com/ray-project/kuberay/cli/pkg/cmdutil"
@@ -58,6 +59,7 @@ func init() {
 	rootCmd.AddCommand(version.NewCmdVersion())
 	rootCmd.AddCommand(cluster.NewCmdCluster())
 	rootCmd.AddCommand(template.NewCmdTemplate())
+	rootCmd.AddCommand(job.NewCmdJob())
 	rootCmd.AddCommand(config.NewCmdConfig())
 }
 
@@ -86,6 +88,7 @@ func initConfig() {
 	}
 
 	viper.AutomaticEnv() // read in environment variables that match
+	cobra.CheckErr(viper.BindEnv("token", cmdutil.TokenEnv))
 
 	if err := viper.ReadInConfig(); err != nil {
 		klog.Fatal(err)
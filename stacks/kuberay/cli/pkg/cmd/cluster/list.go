@@ -13,7 +13,10 @@ import (
 )
 
 type ListOptions struct {
-	namespace string
+	namespace     string
+	user          string
+	environment   string
+	labelSelector string
 }
 
 func newCmdList() *cobra.Command {
@@ -30,6 +33,12 @@ func newCmdList() *cobra.Command {
 
 	cmd.Flags().StringVarP(&opts.namespace, "namespace", "n", "",
 		"kubernetes namespace where the cluster is provisioned")
+	cmd.Flags().StringVarP(&opts.user, "user", "u", "",
+		"only list the clusters owned by this user")
+	cmd.Flags().StringVarP(&opts.environment, "environment", "", "",
+		"only list the clusters of this environment, one of DEV, TESTING, STAGING or PRODUCTION")
+	cmd.Flags().StringVarP(&opts.labelSelector, "selector", "l", "",
+		"only list the clusters matching this label selector")
 
 	return cmd
 }
@@ -49,14 +58,21 @@ func listCluster(opts ListOptions) error {
 
 	var clusters []*go_client.Cluster
 	if len(opts.namespace) == 0 {
-		r, err := client.ListAllClusters(ctx, &go_client.ListAllClustersRequest{})
+		r, err := client.ListAllClusters(ctx, &go_client.ListAllClustersRequest{
+			User:          opts.user,
+			Environment:   opts.environment,
+			LabelSelector: opts.labelSelector,
+		})
 		if err != nil {
 			log.Fatalf("could not list all clusters %v", err)
 		}
 		clusters = r.GetClusters()
 	} else {
 		r, err := client.ListCluster(ctx, &go_client.ListClustersRequest{
-			Namespace: opts.namespace,
+			Namespace:     opts.namespace,
+			User:          opts.user,
+			Environment:   opts.environment,
+			LabelSelector: opts.labelSelector,
 		})
 		if err != nil {
 			log.Fatalf("could not list clusters %v", err)
@@ -0,0 +1,20 @@
+package job
+
+import (
+	"github.com/spf13/cobra"
+)
+
+func NewCmdJob() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "job <command>",
+		Short: "Manage ray jobs submitted to a cluster",
+		Long:  ``,
+		Annotations: map[string]string{
+			"IsCore": "true",
+		},
+	}
+
+	cmd.AddCommand(newCmdLogs())
+
+	return cmd
+}
@@ -0,0 +1,103 @@
+package job
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"os/signal"
+	"time"
+
+	"github.com/ray-project/kuberay/cli/pkg/cmdutil"
+	"github.com/ray-project/kuberay/proto/go_client"
+	"github.com/spf13/cobra"
+	"k8s.io/klog/v2"
+)
+
+type LogsOptions struct {
+	namespace string
+	cluster   string
+	follow    bool
+}
+
+func newCmdLogs() *cobra.Command {
+	opts := LogsOptions{}
+
+	cmd := &cobra.Command{
+		Use:   "logs <submission id>",
+		Short: "Print the log of a job submitted to a ray cluster",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return printJobLogs(args[0], opts)
+		},
+	}
+
+	cmd.Flags().StringVarP(&opts.namespace, "namespace", "n", "",
+		"kubernetes namespace where the cluster is provisioned")
+	cmd.Flags().StringVarP(&opts.cluster, "cluster", "c", "",
+		"name of the cluster the job was submitted to")
+	cmd.Flags().BoolVarP(&opts.follow, "follow", "f", false,
+		"follow the log until the job finishes")
+	for _, flag := range []string{"namespace", "cluster"} {
+		if err := cmd.MarkFlagRequired(flag); err != nil {
+			klog.Warning(err)
+		}
+	}
+
+	return cmd
+}
+
+func printJobLogs(submissionID string, opts LogsOptions) error {
+	// Get gRPC connection
+	conn, err := cmdutil.GetGrpcConn()
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	// build gRPC client
+	client := go_client.NewRayJobSubmissionServiceClient(conn)
+
+	if !opts.follow {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+
+		r, err := client.GetJobLog(ctx, &go_client.GetJobLogRequest{
+			Namespace:    opts.namespace,
+			Clustername:  opts.cluster,
+			Submissionid: submissionID,
+		})
+		if err != nil {
+			log.Fatalf("could not get log of job %v: %v", submissionID, err)
+		}
+		fmt.Print(r.Log)
+		return nil
+	}
+
+	// Follow the log until the job finishes or the user interrupts.
+	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer cancel()
+
+	stream, err := client.TailJobLog(ctx, &go_client.TailJobLogRequest{
+		Namespace:    opts.namespace,
+		Clustername:  opts.cluster,
+		Submissionid: submissionID,
+	})
+	if err != nil {
+		log.Fatalf("could not follow log of job %v: %v", submissionID, err)
+	}
+	for {
+		r, err := stream.Recv()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			log.Fatalf("could not follow log of job %v: %v", submissionID, err)
+		}
+		fmt.Print(r.Log)
+	}
+}
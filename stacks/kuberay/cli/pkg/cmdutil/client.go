@@ -13,10 +13,26 @@ func GetGrpcConn() (*grpc.ClientConn, error) {
 	address := GetVal("endpoint")
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
-	conn, err := grpc.DialContext(ctx, address, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	opts := []grpc.DialOption{grpc.WithTransportCredentials(insecure.NewCredentials())}
+	if token := GetVal("token"); token != "" {
+		opts = append(opts, grpc.WithPerRPCCredentials(bearerToken(token)))
+	}
+	conn, err := grpc.DialContext(ctx, address, opts...)
 	if err != nil {
 		log.Fatalf("can not connect: %v", err)
 	}
 
 	return conn, err
 }
+
+// bearerToken sends the token in the `authorization` metadata of every call, unary and streaming.
+type bearerToken string
+
+func (t bearerToken) GetRequestMetadata(_ context.Context, _ ...string) (map[string]string, error) {
+	return map[string]string{"authorization": "Bearer " + string(t)}, nil
+}
+
+// RequireTransportSecurity returns false because the CLI connects to the API server without TLS.
+func (t bearerToken) RequireTransportSecurity() bool {
+	return false
+}
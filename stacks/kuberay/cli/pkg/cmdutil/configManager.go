@@ -8,7 +8,7 @@ import (
 	"github.com/spf13/viper"
 )
 
-var supportedKeys = map[string]bool{"endpoint": true}
+var supportedKeys = map[string]bool{"endpoint": true, "token": true}
 
 func validateKey(key string) {
 	_, ok := supportedKeys[key]
@@ -3,4 +3,6 @@ package cmdutil
 const (
 	DefaultRpcAddress = "127.0.0.1"
 	DefaultRpcPort    = "8887"
+	// TokenEnv is the environment variable that overrides the `token` config key.
+	TokenEnv = "KUBERAY_TOKEN"
 )
@@ -27,6 +27,14 @@ type ClusterServiceClient interface {
 	ListCluster(ctx context.Context, in *ListClustersRequest, opts ...grpc.CallOption) (*ListClustersResponse, error)
 	// Finds all Clusters in all namespaces. Supports pagination, and sorting on certain fields.
 	ListAllClusters(ctx context.Context, in *ListAllClustersRequest, opts ...grpc.CallOption) (*ListAllClustersResponse, error)
+	// Updates a Cluster in place. Only the fields listed in the update mask
+	// are changed, the rest of the cluster is left untouched.
+	UpdateCluster(ctx context.Context, in *UpdateClusterRequest, opts ...grpc.CallOption) (*Cluster, error)
+	// Suspends a Cluster. All the pods of the cluster are deleted while the
+	// cluster itself is kept, so that it can be resumed later.
+	SuspendCluster(ctx context.Context, in *SuspendClusterRequest, opts ...grpc.CallOption) (*Cluster, error)
+	// Resumes a suspended Cluster. The pods of the cluster are created again.
+	ResumeCluster(ctx context.Context, in *ResumeClusterRequest, opts ...grpc.CallOption) (*Cluster, error)
 	// Deletes an cluster without deleting the cluster's runs and jobs. To
 	// avoid unexpected behaviors, delete an cluster's runs and jobs before
 	// deleting the cluster.
@@ -77,6 +85,33 @@ func (c *clusterServiceClient) ListAllClusters(ctx context.Context, in *ListAllC
 	return out, nil
 }
 
+func (c *clusterServiceClient) UpdateCluster(ctx context.Context, in *UpdateClusterRequest, opts ...grpc.CallOption) (*Cluster, error) {
+	out := new(Cluster)
+	err := c.cc.Invoke(ctx, "/proto.ClusterService/UpdateCluster", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *clusterServiceClient) SuspendCluster(ctx context.Context, in *SuspendClusterRequest, opts ...grpc.CallOption) (*Cluster, error) {
+	out := new(Cluster)
+	err := c.cc.Invoke(ctx, "/proto.ClusterService/SuspendCluster", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *clusterServiceClient) ResumeCluster(ctx context.Context, in *ResumeClusterRequest, opts ...grpc.CallOption) (*Cluster, error) {
+	out := new(Cluster)
+	err := c.cc.Invoke(ctx, "/proto.ClusterService/ResumeCluster", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *clusterServiceClient) DeleteCluster(ctx context.Context, in *DeleteClusterRequest, opts ...grpc.CallOption) (*emptypb.Empty, error) {
 	out := new(emptypb.Empty)
 	err := c.cc.Invoke(ctx, "/proto.ClusterService/DeleteCluster", in, out, opts...)
@@ -98,6 +133,14 @@ type ClusterServiceServer interface {
 	ListCluster(context.Context, *ListClustersRequest) (*ListClustersResponse, error)
 	// Finds all Clusters in all namespaces. Supports pagination, and sorting on certain fields.
 	ListAllClusters(context.Context, *ListAllClustersRequest) (*ListAllClustersResponse, error)
+	// Updates a Cluster in place. Only the fields listed in the update mask
+	// are changed, the rest of the cluster is left untouched.
+	UpdateCluster(context.Context, *UpdateClusterRequest) (*Cluster, error)
+	// Suspends a Cluster. All the pods of the cluster are deleted while the
+	// cluster itself is kept, so that it can be resumed later.
+	SuspendCluster(context.Context, *SuspendClusterRequest) (*Cluster, error)
+	// Resumes a suspended Cluster. The pods of the cluster are created again.
+	ResumeCluster(context.Context, *ResumeClusterRequest) (*Cluster, error)
 	// Deletes an cluster without deleting the cluster's runs and jobs. To
 	// avoid unexpected behaviors, delete an cluster's runs and jobs before
 	// deleting the cluster.
@@ -121,6 +164,15 @@ func (UnimplementedClusterServiceServer) ListCluster(context.Context, *ListClust
 func (UnimplementedClusterServiceServer) ListAllClusters(context.Context, *ListAllClustersRequest) (*ListAllClustersResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method ListAllClusters not implemented")
 }
+func (UnimplementedClusterServiceServer) UpdateCluster(context.Context, *UpdateClusterRequest) (*Cluster, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdateCluster not implemented")
+}
+func (UnimplementedClusterServiceServer) SuspendCluster(context.Context, *SuspendClusterRequest) (*Cluster, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SuspendCluster not implemented")
+}
+func (UnimplementedClusterServiceServer) ResumeCluster(context.Context, *ResumeClusterRequest) (*Cluster, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ResumeCluster not implemented")
+}
 func (UnimplementedClusterServiceServer) DeleteCluster(context.Context, *DeleteClusterRequest) (*emptypb.Empty, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method DeleteCluster not implemented")
 }
@@ -209,6 +261,60 @@ func _ClusterService_ListAllClusters_Handler(srv interface{}, ctx context.Contex
 	return interceptor(ctx, in, info, handler)
 }
 
+func _ClusterService_UpdateCluster_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdateClusterRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ClusterServiceServer).UpdateCluster(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/proto.ClusterService/UpdateCluster",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ClusterServiceServer).UpdateCluster(ctx, req.(*UpdateClusterRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ClusterService_SuspendCluster_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SuspendClusterRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ClusterServiceServer).SuspendCluster(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/proto.ClusterService/SuspendCluster",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ClusterServiceServer).SuspendCluster(ctx, req.(*SuspendClusterRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ClusterService_ResumeCluster_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ResumeClusterRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ClusterServiceServer).ResumeCluster(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/proto.ClusterService/ResumeCluster",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ClusterServiceServer).ResumeCluster(ctx, req.(*ResumeClusterRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _ClusterService_DeleteCluster_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(DeleteClusterRequest)
 	if err := dec(in); err != nil {
@@ -250,6 +356,18 @@ var ClusterService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "ListAllClusters",
 			Handler:    _ClusterService_ListAllClusters_Handler,
 		},
+		{
+			MethodName: "UpdateCluster",
+			Handler:    _ClusterService_UpdateCluster_Handler,
+		},
+		{
+			MethodName: "SuspendCluster",
+			Handler:    _ClusterService_SuspendCluster_Handler,
+		},
+		{
+			MethodName: "ResumeCluster",
+			Handler:    _ClusterService_ResumeCluster_Handler,
+		},
 		{
 			MethodName: "DeleteCluster",
 			Handler:    _ClusterService_DeleteCluster_Handler,
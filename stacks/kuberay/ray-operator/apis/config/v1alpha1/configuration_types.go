@@ -32,7 +32,9 @@ type Configuration struct {
 	// If empty, all namespaces will be watched.
 	WatchNamespace string `json:"watchNamespace,omitempty"`
 
-	// ForcedClusterUpgrade enables force upgrading clusters.
+	// ForcedClusterUpgrade enables force upgrading clusters. RayClusters which do not set
+	// spec.upgradeStrategy use the Recreate strategy if it is true, and OnDelete otherwise.
+	// Deprecated: Use the ForcedClusterUpgrade feature gate instead.
 	ForcedClusterUpgrade bool `json:"forcedClusterUpgrade,omitempty"`
 
 	// LogFile is a path to a local file for synchronizing logs.
@@ -40,8 +42,14 @@ type Configuration struct {
 
 	// EnableBatchScheduler enables the batch scheduler. Currently this is supported
 	// by Volcano to support gang scheduling.
+	// Deprecated: Use the BatchScheduler feature gate instead.
 	EnableBatchScheduler bool `json:"enableBatchScheduler,omitempty"`
 
+	// FeatureGates enables or disables features of the operator by name, e.g.
+	// `RandomPodDelete: true`. A custom resource can override them for itself with
+	// the `ray.io/feature-gates` annotation, except for BatchScheduler.
+	FeatureGates map[string]bool `json:"featureGates,omitempty"`
+
 	// HeadSidecarContainers includes specification for a sidecar container
 	// to inject into every Head pod.
 	HeadSidecarContainers []corev1.Container `json:"headSidecarContainers,omitempty"`
@@ -49,4 +57,99 @@ type Configuration struct {
 	// WorkerSidecarContainers includes specification for a sidecar container
 	// to inject into every Worker pod.
 	WorkerSidecarContainers []corev1.Container `json:"workerSidecarContainers,omitempty"`
+
+	// PodDefaults are merged into the head and worker Pods of every RayCluster.
+	// Values set in the Pod templates of a RayCluster take precedence.
+	PodDefaults *PodDefaults `json:"podDefaults,omitempty"`
+
+	// Requeue configures how often the reconcilers requeue custom resources.
+	Requeue *RequeueConfiguration `json:"requeue,omitempty"`
+}
+
+// RequeueConfiguration configures how often the reconcilers requeue custom resources.
+// Unset durations use the defaults.
+type RequeueConfiguration struct {
+	// RayClusterInterval is the interval for requeueing a RayCluster which is being
+	// reconciled. Defaults to 2s.
+	RayClusterInterval *metav1.Duration `json:"rayClusterInterval,omitempty"`
+
+	// RayClusterSteadyStateInterval is the interval for unconditionally requeueing a
+	// RayCluster. Defaults to the RAYCLUSTER_DEFAULT_REQUEUE_SECONDS_ENV environment
+	// variable, or 5m.
+	RayClusterSteadyStateInterval *metav1.Duration `json:"rayClusterSteadyStateInterval,omitempty"`
+
+	// RayServiceInterval is the interval for requeueing a RayService whose Ray Serve
+	// applications are not ready or which has a pending RayCluster. Defaults to 2s.
+	RayServiceInterval *metav1.Duration `json:"rayServiceInterval,omitempty"`
+
+	// RayServiceSteadyStateInterval is the interval for checking the Ray Serve
+	// applications of a healthy RayService without a pending RayCluster. Defaults to 30s.
+	RayServiceSteadyStateInterval *metav1.Duration `json:"rayServiceSteadyStateInterval,omitempty"`
+
+	// RayJobInterval is the interval for requeueing a RayJob whose Ray job is not
+	// running. Defaults to 3s.
+	RayJobInterval *metav1.Duration `json:"rayJobInterval,omitempty"`
+
+	// RayJobSteadyStateInterval is the interval for checking the status of a running
+	// Ray job. Defaults to 10s.
+	RayJobSteadyStateInterval *metav1.Duration `json:"rayJobSteadyStateInterval,omitempty"`
+
+	// RayClusterDeletionDelay is how long a RayService keeps a RayCluster after it
+	// stops serving traffic. Defaults to 60s.
+	RayClusterDeletionDelay *metav1.Duration `json:"rayClusterDeletionDelay,omitempty"`
+
+	// ErrorBackoffBase is the requeue delay after a custom resource fails to be
+	// reconciled. The delay doubles with every consecutive failure of the custom
+	// resource up to ErrorBackoffMax. Defaults to 1s.
+	ErrorBackoffBase *metav1.Duration `json:"errorBackoffBase,omitempty"`
+
+	// ErrorBackoffMax is the maximum requeue delay of a custom resource which keeps
+	// failing to be reconciled. Defaults to 5m.
+	ErrorBackoffMax *metav1.Duration `json:"errorBackoffMax,omitempty"`
+}
+
+// PodDefaults defines the defaults merged into the Pods of RayClusters.
+type PodDefaults struct {
+	// Head is merged into every head Pod.
+	Head *RayPodDefaults `json:"head,omitempty"`
+
+	// Worker is merged into every worker Pod.
+	Worker *RayPodDefaults `json:"worker,omitempty"`
+
+	// Namespaces are merged into the Pods of RayClusters in specific namespaces.
+	// They take precedence over Head and Worker.
+	Namespaces []NamespacePodDefaults `json:"namespaces,omitempty"`
+}
+
+// NamespacePodDefaults defines the defaults merged into the Pods of RayClusters in a namespace.
+type NamespacePodDefaults struct {
+	// Namespace is the namespace of the RayClusters.
+	Namespace string `json:"namespace"`
+
+	// Head is merged into every head Pod in the namespace.
+	Head *RayPodDefaults `json:"head,omitempty"`
+
+	// Worker is merged into every worker Pod in the namespace.
+	Worker *RayPodDefaults `json:"worker,omitempty"`
+}
+
+// RayPodDefaults defines the defaults merged into the Pods of a Ray node type.
+type RayPodDefaults struct {
+	// Template is merged into the Pod template with strategic merge patch semantics,
+	// e.g. labels and nodeSelector are merged by key, volumes and containers are
+	// merged by name, and lists without a merge key, such as tolerations, are only
+	// used if the Pod template does not set them.
+	Template *corev1.PodTemplateSpec `json:"template,omitempty"`
+
+	// Env is added to the Ray container. Variables with the same name in the
+	// Ray container take precedence.
+	Env []corev1.EnvVar `json:"env,omitempty"`
+
+	// VolumeMounts are added to the Ray container. Volume mounts with the same
+	// mount path in the Ray container take precedence.
+	VolumeMounts []corev1.VolumeMount `json:"volumeMounts,omitempty"`
+
+	// ImageRegistry replaces the registry of every container image in the Pod,
+	// e.g. `rayproject/ray:2.9.0` becomes `<imageRegistry>/rayproject/ray:2.9.0`.
+	ImageRegistry string `json:"imageRegistry,omitempty"`
 }
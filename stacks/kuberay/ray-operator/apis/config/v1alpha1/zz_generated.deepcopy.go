@@ -6,6 +6,7 @@ package v1alpha1
 
 import (
 	"k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 )
 
@@ -18,6 +19,13 @@ func (in *Configuration) DeepCopyInto(out *Configuration) {
 		*out = new(bool)
 		**out = **in
 	}
+	if in.FeatureGates != nil {
+		in, out := &in.FeatureGates, &out.FeatureGates
+		*out = make(map[string]bool, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
 	if in.HeadSidecarContainers != nil {
 		in, out := &in.HeadSidecarContainers, &out.HeadSidecarContainers
 		*out = make([]v1.Container, len(*in))
@@ -32,6 +40,16 @@ func (in *Configuration) DeepCopyInto(out *Configuration) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.PodDefaults != nil {
+		in, out := &in.PodDefaults, &out.PodDefaults
+		*out = new(PodDefaults)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Requeue != nil {
+		in, out := &in.Requeue, &out.Requeue
+		*out = new(RequeueConfiguration)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Configuration.
@@ -51,3 +69,154 @@ func (in *Configuration) DeepCopyObject() runtime.Object {
 	}
 	return nil
 }
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NamespacePodDefaults) DeepCopyInto(out *NamespacePodDefaults) {
+	*out = *in
+	if in.Head != nil {
+		in, out := &in.Head, &out.Head
+		*out = new(RayPodDefaults)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Worker != nil {
+		in, out := &in.Worker, &out.Worker
+		*out = new(RayPodDefaults)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NamespacePodDefaults.
+func (in *NamespacePodDefaults) DeepCopy() *NamespacePodDefaults {
+	if in == nil {
+		return nil
+	}
+	out := new(NamespacePodDefaults)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PodDefaults) DeepCopyInto(out *PodDefaults) {
+	*out = *in
+	if in.Head != nil {
+		in, out := &in.Head, &out.Head
+		*out = new(RayPodDefaults)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Worker != nil {
+		in, out := &in.Worker, &out.Worker
+		*out = new(RayPodDefaults)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Namespaces != nil {
+		in, out := &in.Namespaces, &out.Namespaces
+		*out = make([]NamespacePodDefaults, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PodDefaults.
+func (in *PodDefaults) DeepCopy() *PodDefaults {
+	if in == nil {
+		return nil
+	}
+	out := new(PodDefaults)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RayPodDefaults) DeepCopyInto(out *RayPodDefaults) {
+	*out = *in
+	if in.Template != nil {
+		in, out := &in.Template, &out.Template
+		*out = new(v1.PodTemplateSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Env != nil {
+		in, out := &in.Env, &out.Env
+		*out = make([]v1.EnvVar, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.VolumeMounts != nil {
+		in, out := &in.VolumeMounts, &out.VolumeMounts
+		*out = make([]v1.VolumeMount, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RayPodDefaults.
+func (in *RayPodDefaults) DeepCopy() *RayPodDefaults {
+	if in == nil {
+		return nil
+	}
+	out := new(RayPodDefaults)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RequeueConfiguration) DeepCopyInto(out *RequeueConfiguration) {
+	*out = *in
+	if in.RayClusterInterval != nil {
+		in, out := &in.RayClusterInterval, &out.RayClusterInterval
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.RayClusterSteadyStateInterval != nil {
+		in, out := &in.RayClusterSteadyStateInterval, &out.RayClusterSteadyStateInterval
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.RayServiceInterval != nil {
+		in, out := &in.RayServiceInterval, &out.RayServiceInterval
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.RayServiceSteadyStateInterval != nil {
+		in, out := &in.RayServiceSteadyStateInterval, &out.RayServiceSteadyStateInterval
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.RayJobInterval != nil {
+		in, out := &in.RayJobInterval, &out.RayJobInterval
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.RayJobSteadyStateInterval != nil {
+		in, out := &in.RayJobSteadyStateInterval, &out.RayJobSteadyStateInterval
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.RayClusterDeletionDelay != nil {
+		in, out := &in.RayClusterDeletionDelay, &out.RayClusterDeletionDelay
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.ErrorBackoffBase != nil {
+		in, out := &in.ErrorBackoffBase, &out.ErrorBackoffBase
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.ErrorBackoffMax != nil {
+		in, out := &in.ErrorBackoffMax, &out.ErrorBackoffMax
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RequeueConfiguration.
+func (in *RequeueConfiguration) DeepCopy() *RequeueConfiguration {
+	if in == nil {
+		return nil
+	}
+	out := new(RequeueConfiguration)
+	in.DeepCopyInto(out)
+	return out
+}
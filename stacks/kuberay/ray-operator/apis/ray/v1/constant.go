@@ -0,0 +1,27 @@
+package v1
+
+// The following constants are used by both the webhooks and the controllers.
+const (
+	// In KubeRay, the Ray container must be the first application container in a head or worker Pod.
+	RayContainerIndex = 0
+
+	// Ray GCS FT related annotations
+	RayFTEnabledAnnotationKey         = "ray.io/ft-enabled"
+	RayExternalStorageNSAnnotationKey = "ray.io/external-storage-namespace"
+
+	// Use as default port
+	DefaultClientPort = 10001
+	// For Ray >= 1.11.0, "DefaultRedisPort" actually refers to the GCS server port.
+	// However, the role of this port is unchanged in Ray APIs like ray.init and ray start.
+	// This is the port used by Ray workers and drivers inside the Ray cluster to connect to the Ray head.
+	DefaultRedisPort     = 6379
+	DefaultDashboardPort = 8265
+	DefaultMetricsPort   = 8080
+	DefaultServingPort   = 8000
+
+	ClientPortName    = "client"
+	RedisPortName     = "redis"
+	DashboardPortName = "dashboard"
+	MetricsPortName   = "metrics"
+	ServingPortName   = "serve"
+)
@@ -4,6 +4,7 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
 )
 
 // EDIT THIS FILE!  THIS IS SCAFFOLDING FOR YOU TO OWN!
@@ -27,6 +28,100 @@ type RayClusterSpec struct {
 	// Suspend indicates whether a RayCluster should be suspended.
 	// A suspended RayCluster will have head pods and worker pods deleted.
 	Suspend *bool `json:"suspend,omitempty"`
+	// UpgradeStrategy defines how the KubeRay operator replaces Pods whose head or worker group template has changed.
+	// +optional
+	UpgradeStrategy *RayClusterUpgradeStrategy `json:"upgradeStrategy,omitempty"`
+	// FailedPodRetention keeps failed head and worker Pods for debugging instead of deleting them immediately.
+	// +optional
+	FailedPodRetention *FailedPodRetentionPolicy `json:"failedPodRetention,omitempty"`
+	// FailureBudget limits how many Pods of a group can fail before the RayCluster is marked as Failed.
+	// +optional
+	FailureBudget *RayClusterFailureBudget `json:"failureBudget,omitempty"`
+	// AutoSuspend configures when the KubeRay operator suspends and resumes the RayCluster automatically.
+	// +optional
+	AutoSuspend *RayClusterAutoSuspend `json:"autoSuspend,omitempty"`
+}
+
+// RayClusterAutoSuspend defines when the KubeRay operator sets the `suspend` field of a RayCluster automatically.
+// The schedules are evaluated when they are due, so users can still suspend or resume the RayCluster manually
+// in between.
+type RayClusterAutoSuspend struct {
+	// IdleTTLSeconds is the number of seconds the RayCluster can have no running Ray jobs and no running Ray tasks,
+	// according to the Ray dashboard, before it is suspended.
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	IdleTTLSeconds *int32 `json:"idleTTLSeconds,omitempty"`
+	// SuspendSchedule is a cron expression in the standard 5-field format. The RayCluster is suspended at each
+	// scheduled time.
+	// +optional
+	SuspendSchedule string `json:"suspendSchedule,omitempty"`
+	// ResumeSchedule is a cron expression in the standard 5-field format. A suspended RayCluster is resumed at
+	// each scheduled time.
+	// +optional
+	ResumeSchedule string `json:"resumeSchedule,omitempty"`
+	// TimeZone is the IANA name of the time zone of the schedules, such as `America/Los_Angeles`. Defaults to UTC.
+	// +optional
+	TimeZone string `json:"timeZone,omitempty"`
+}
+
+// RayClusterFailureBudget defines how many Pod failures of a head or worker group are tolerated. After each failure,
+// the KubeRay operator waits exponentially longer before it creates new Pods for the group. When a group exceeds the
+// budget, the KubeRay operator stops creating Pods for the group and marks the RayCluster as Failed until the
+// group's template or rayStartParams change. The failures are kept when the RayCluster is suspended and resumed.
+type RayClusterFailureBudget struct {
+	// MaxFailures is the number of Pod failures of a group tolerated within WindowSeconds. Defaults to 5.
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	MaxFailures *int32 `json:"maxFailures,omitempty"`
+	// WindowSeconds is the length of the sliding window in which Pod failures are counted. Defaults to 600.
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	WindowSeconds *int32 `json:"windowSeconds,omitempty"`
+}
+
+// FailedPodRetentionPolicy defines how many Pods in the `Failed` or `Succeeded` phase are retained and for how long.
+// A retained Pod no longer carries the `ray.io/cluster` label, so it is not part of the RayCluster anymore and a
+// replacement Pod is created. It is labeled with `ray.io/retained-from-cluster` instead.
+type FailedPodRetentionPolicy struct {
+	// MaxPodsPerGroup is the maximum number of failed Pods retained for the head group and for each worker group.
+	// The oldest retained Pods are deleted when more Pods fail. Defaults to 1.
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	MaxPodsPerGroup *int32 `json:"maxPodsPerGroup,omitempty"`
+	// TTLSeconds is the number of seconds a failed Pod is retained. If not set, failed Pods are retained until
+	// MaxPodsPerGroup is exceeded or the RayCluster is deleted.
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	TTLSeconds *int32 `json:"ttlSeconds,omitempty"`
+}
+
+// RayClusterUpgradeType is the strategy used to replace outdated Pods of a RayCluster.
+// +kubebuilder:validation:Enum=Recreate;RollingUpdate;OnDelete
+type RayClusterUpgradeType string
+
+const (
+	// RecreateUpgrade deletes all the outdated Pods at once, and new Pods are created from the current templates
+	// in the following reconciliation.
+	RecreateUpgrade RayClusterUpgradeType = "Recreate"
+	// RollingUpdateUpgrade replaces the outdated worker Pods of each worker group in batches bounded by the
+	// worker group's rollingUpdate settings, and waits for the new Pods to be ready between batches. Terminating
+	// Pods count as unavailable.
+	RollingUpdateUpgrade RayClusterUpgradeType = "RollingUpdate"
+	// OnDeleteUpgrade never deletes outdated Pods. New Pods are created from the current templates only after
+	// the outdated Pods are deleted by users or by the Ray autoscaler.
+	OnDeleteUpgrade RayClusterUpgradeType = "OnDelete"
+)
+
+// RayClusterUpgradeStrategy defines how the KubeRay operator replaces outdated Pods of a RayCluster.
+// The head Pod is only replaced when the head group template or its rayStartParams change.
+// While a worker group has outdated Pods, changes of its replicas are applied by the upgrade instead of the regular
+// scaling: a rolling update creates and deletes Pods towards the new replicas, and excess updated Pods are deleted
+// once no outdated Pods are left.
+type RayClusterUpgradeStrategy struct {
+	// Type is the upgrade strategy. If not set, the KubeRay operator uses Recreate when the
+	// `ForcedClusterUpgrade` feature gate is enabled and OnDelete otherwise.
+	// +optional
+	Type RayClusterUpgradeType `json:"type,omitempty"`
 }
 
 // HeadGroupSpec are the spec for the head pod
@@ -57,6 +152,7 @@ type WorkerGroupSpec struct {
 	// +kubebuilder:default:=2147483647
 	MaxReplicas *int32 `json:"maxReplicas"`
 	// NumOfHosts denotes the number of hosts to create per replica. The default value is 1.
+	// The Pods of a replica are created together and are deleted together when any one of them fails or is missing.
 	// +kubebuilder:default:=1
 	NumOfHosts int32 `json:"numOfHosts,omitempty"`
 	// RayStartParams are the params of the start command: address, object-store-memory, ...
@@ -65,11 +161,29 @@ type WorkerGroupSpec struct {
 	Template corev1.PodTemplateSpec `json:"template"`
 	// ScaleStrategy defines which pods to remove
 	ScaleStrategy ScaleStrategy `json:"scaleStrategy,omitempty"`
+	// RollingUpdate configures how the Pods of this worker group are replaced when the RayCluster's upgradeStrategy is RollingUpdate.
+	// +optional
+	RollingUpdate *WorkerGroupRollingUpdate `json:"rollingUpdate,omitempty"`
+}
+
+// WorkerGroupRollingUpdate bounds the number of worker Pods replaced at a time during a rolling update.
+// For multi-host worker groups, the values are counted in replicas instead of Pods.
+type WorkerGroupRollingUpdate struct {
+	// MaxUnavailable is the maximum number of desired worker Pods that can be unavailable during the update.
+	// Value can be an absolute number or a percentage of the desired replicas, rounded down. Defaults to 1.
+	// +optional
+	MaxUnavailable *intstr.IntOrString `json:"maxUnavailable,omitempty"`
+	// MaxSurge is the maximum number of worker Pods that can be created above the desired replicas during the update.
+	// Value can be an absolute number or a percentage of the desired replicas, rounded up. Defaults to 0.
+	// It is ignored for multi-host worker groups. MaxUnavailable and MaxSurge cannot both be 0.
+	// +optional
+	MaxSurge *intstr.IntOrString `json:"maxSurge,omitempty"`
 }
 
 // ScaleStrategy to remove workers
 type ScaleStrategy struct {
-	// WorkersToDelete workers to be deleted
+	// WorkersToDelete workers to be deleted. The Pods are deleted even if they are annotated with
+	// `ray.io/do-not-evict: "true"`, which only applies when the KubeRay operator picks the Pods to delete on scale-down.
 	WorkersToDelete []string `json:"workersToDelete,omitempty"`
 }
 
@@ -116,6 +230,25 @@ const (
 	Suspended ClusterState = "suspended"
 )
 
+// RayClusterConditionType is the type of a condition in RayClusterStatus.Conditions.
+type RayClusterConditionType string
+
+const (
+	// HeadPodReady indicates whether the head Pod of the RayCluster is running and ready.
+	HeadPodReady RayClusterConditionType = "HeadPodReady"
+	// AllWorkersReady indicates whether all the desired worker Pods of the RayCluster are running and ready.
+	AllWorkersReady RayClusterConditionType = "AllWorkersReady"
+	// RayClusterProvisioned indicates whether all the Pods of the RayCluster have been ready at least once.
+	// Once it is true, it only becomes false again when the RayCluster is suspended.
+	RayClusterProvisioned RayClusterConditionType = "Provisioned"
+	// RayClusterReplicaFailure is added when the KubeRay operator fails to create or delete Pods of the RayCluster,
+	// and removed once the Pods are reconciled successfully.
+	RayClusterReplicaFailure RayClusterConditionType = "ReplicaFailure"
+	// RayClusterUpgradeInProgress indicates whether some worker Pods were created from outdated worker group templates
+	// and are being replaced according to the upgradeStrategy. It is not set when the upgradeStrategy is OnDelete.
+	RayClusterUpgradeInProgress RayClusterConditionType = "UpgradeInProgress"
+)
+
 // RayClusterStatus defines the observed state of RayCluster
 type RayClusterStatus struct {
 	// INSERT ADDITIONAL STATUS FIELD - define observed state of cluster
@@ -126,6 +259,9 @@ type RayClusterStatus struct {
 	AvailableWorkerReplicas int32 `json:"availableWorkerReplicas,omitempty"`
 	// DesiredWorkerReplicas indicates overall desired replicas claimed by the user at the cluster level.
 	DesiredWorkerReplicas int32 `json:"desiredWorkerReplicas,omitempty"`
+	// UpdatedWorkerReplicas indicates how many worker replicas were created from the current worker group templates.
+	// +optional
+	UpdatedWorkerReplicas int32 `json:"updatedWorkerReplicas,omitempty"`
 	// MinWorkerReplicas indicates sum of minimum replicas of each node group.
 	MinWorkerReplicas int32 `json:"minWorkerReplicas,omitempty"`
 	// MaxWorkerReplicas indicates sum of maximum replicas of each node group.
@@ -151,6 +287,62 @@ type RayClusterStatus struct {
 	// RayCluster's generation, which is updated on mutation by the API Server.
 	// +optional
 	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+	// Conditions represent the latest available observations of the RayCluster's state.
+	// +optional
+	// +listType=map
+	// +listMapKey=type
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+	// GroupFailures records the recent Pod failures of each group when the RayCluster has a failure budget.
+	// +optional
+	GroupFailures []RayClusterGroupFailures `json:"groupFailures,omitempty"`
+	// LastActivityTime is the last time the Ray dashboard reported running Ray jobs or Ray tasks. It is only set
+	// when autoSuspend.idleTTLSeconds is set and the RayCluster is not suspended.
+	// +optional
+	LastActivityTime *metav1.Time `json:"lastActivityTime,omitempty"`
+	// LastScheduleTime is the last time the autoSuspend schedules were evaluated.
+	// +optional
+	LastScheduleTime *metav1.Time `json:"lastScheduleTime,omitempty"`
+	// NextScheduledAction is the next suspension or resumption of the RayCluster planned by autoSuspend.
+	// +optional
+	NextScheduledAction *RayClusterScheduledAction `json:"nextScheduledAction,omitempty"`
+}
+
+// RayClusterScheduledActionType is an action taken by autoSuspend.
+type RayClusterScheduledActionType string
+
+const (
+	SuspendRayCluster RayClusterScheduledActionType = "Suspend"
+	ResumeRayCluster  RayClusterScheduledActionType = "Resume"
+)
+
+// RayClusterScheduledAction is a suspension or resumption of a RayCluster planned by autoSuspend.
+type RayClusterScheduledAction struct {
+	// Action is either Suspend or Resume.
+	Action RayClusterScheduledActionType `json:"action"`
+	// Time is when the action is planned.
+	Time metav1.Time `json:"time"`
+	// Reason is either IdleTTL or Schedule.
+	Reason string `json:"reason"`
+}
+
+// RayClusterGroupFailures records the recent Pod failures of a head or worker group.
+type RayClusterGroupFailures struct {
+	// GroupName is the name of the worker group, or `headgroup` for the head group.
+	GroupName string `json:"groupName"`
+	// FailureTimes are the times of the Pod failures within the failure budget window.
+	// +optional
+	FailureTimes []metav1.Time `json:"failureTimes,omitempty"`
+	// LastTerminationMessage describes how the Ray container of the last failed Pod terminated.
+	// +optional
+	LastTerminationMessage string `json:"lastTerminationMessage,omitempty"`
+	// BudgetExceeded indicates that the group exceeded the failure budget and no Pods are created for it
+	// until the group's template or rayStartParams change.
+	// +optional
+	BudgetExceeded bool `json:"budgetExceeded,omitempty"`
+	// TemplateHash is the hash of the group's template and rayStartParams when the failures were recorded. The
+	// failures are discarded when they change.
+	// +optional
+	TemplateHash string `json:"templateHash,omitempty"`
 }
 
 // HeadInfo gives info about head
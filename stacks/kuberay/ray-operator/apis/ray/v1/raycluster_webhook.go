@@ -1,11 +1,20 @@
 package v1
 
 import (
+	"fmt"
 	"regexp"
+	"strings"
+	"time"
 
+	"github.com/robfig/cron/v3"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	apivalidation "k8s.io/apimachinery/pkg/api/validation"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/intstr"
 	"k8s.io/apimachinery/pkg/util/validation/field"
 	ctrl "sigs.k8s.io/controller-runtime"
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
@@ -19,6 +28,18 @@ var (
 	nameRegex, _  = regexp.Compile("^[a-z]([-a-z0-9]*[a-z0-9])?$")
 )
 
+// rayRedisAddressEnvVar is the environment variable that points the Ray head to the external Redis in GCS FT.
+const rayRedisAddressEnvVar = "RAY_REDIS_ADDRESS"
+
+// defaultHeadPorts are the ports that the KubeRay operator exposes for the Ray head by default.
+var defaultHeadPorts = map[string]int32{
+	ClientPortName:    DefaultClientPort,
+	RedisPortName:     DefaultRedisPort,
+	DashboardPortName: DefaultDashboardPort,
+	MetricsPortName:   DefaultMetricsPort,
+	ServingPortName:   DefaultServingPort,
+}
+
 func (r *RayCluster) SetupWebhookWithManager(mgr ctrl.Manager) error {
 	return ctrl.NewWebhookManagedBy(mgr).
 		For(r).
@@ -33,13 +54,28 @@ var _ webhook.Validator = &RayCluster{}
 // ValidateCreate implements webhook.Validator so a webhook will be registered for the type
 func (r *RayCluster) ValidateCreate() (admission.Warnings, error) {
 	rayclusterlog.Info("validate create", "name", r.Name)
-	return nil, r.validateRayCluster()
+	_, err := r.validateRayCluster(nil)
+	return r.warnings(), err
 }
 
 // ValidateUpdate implements webhook.Validator so a webhook will be registered for the type
 func (r *RayCluster) ValidateUpdate(old runtime.Object) (admission.Warnings, error) {
 	rayclusterlog.Info("validate update", "name", r.Name)
-	return nil, r.validateRayCluster()
+	// Do not block the removal of finalizers from a RayCluster that is being deleted.
+	if r.DeletionTimestamp != nil {
+		return nil, nil
+	}
+	oldRayCluster, ok := old.(*RayCluster)
+	if !ok {
+		return nil, apierrors.NewBadRequest(fmt.Sprintf("expected a RayCluster but got a %T", old))
+	}
+	// Updates of the metadata or the status, e.g. the ones of the KubeRay operator, are only checked for changes
+	// of immutable annotations.
+	if equality.Semantic.DeepEqual(oldRayCluster.Spec, r.Spec) {
+		return nil, newRayClusterInvalidError(r.Name, r.validateImmutableFields(oldRayCluster))
+	}
+	warnings, err := r.validateRayCluster(oldRayCluster)
+	return append(r.warnings(), warnings...), err
 }
 
 // ValidateDelete implements webhook.Validator so a webhook will be registered for the type
@@ -48,42 +84,376 @@ func (r *RayCluster) ValidateDelete() (admission.Warnings, error) {
 	return nil, nil
 }
 
-func (r *RayCluster) validateRayCluster() error {
+// validateRayCluster validates the RayCluster. `old` is the RayCluster before the update, or nil on creation.
+// On update, the violations that the old RayCluster already had are returned as warnings instead of errors, so that
+// RayClusters created before a validation rule was added can still be updated.
+func (r *RayCluster) validateRayCluster(old *RayCluster) (admission.Warnings, error) {
+	allErrs := r.validateFields()
+	if old == nil {
+		return nil, newRayClusterInvalidError(r.Name, allErrs)
+	}
+
+	var warnings admission.Warnings
+	oldErrs := old.validateFields()
+	newErrs := field.ErrorList{}
+	for _, err := range allErrs {
+		if containsFieldError(oldErrs, err) {
+			warnings = append(warnings, fmt.Sprintf("%s; the RayCluster is accepted because it was already invalid before the update", err.Error()))
+			continue
+		}
+		newErrs = append(newErrs, err)
+	}
+	newErrs = append(newErrs, r.validateImmutableFields(old)...)
+	return warnings, newRayClusterInvalidError(r.Name, newErrs)
+}
+
+// validateFields returns the violations of the RayCluster that do not depend on its previous state.
+func (r *RayCluster) validateFields() field.ErrorList {
 	var allErrs field.ErrorList
 
 	if err := r.validateName(); err != nil {
 		allErrs = append(allErrs, err)
 	}
 
-	if err := r.validateWorkerGroups(); err != nil {
+	allErrs = append(allErrs, validateRayClusterSpec(field.NewPath("spec"), &r.Spec)...)
+
+	if err := r.validateGCSFaultTolerance(); err != nil {
 		allErrs = append(allErrs, err)
 	}
 
+	return allErrs
+}
+
+// newRayClusterInvalidError returns an Invalid error for the violations, or nil if there are none.
+func newRayClusterInvalidError(name string, allErrs field.ErrorList) error {
 	if len(allErrs) == 0 {
 		return nil
 	}
-
 	return apierrors.NewInvalid(
 		schema.GroupKind{Group: "ray.io", Kind: "RayCluster"},
-		r.Name, allErrs)
+		name, allErrs)
+}
+
+// containsFieldError returns whether the list has an error of the same type for the same field. The values are not
+// compared, since they may differ between the old and the new object.
+func containsFieldError(allErrs field.ErrorList, err *field.Error) bool {
+	for _, e := range allErrs {
+		if e.Type == err.Type && e.Field == err.Field {
+			return true
+		}
+	}
+	return false
 }
 
 func (r *RayCluster) validateName() *field.Error {
-	if !nameRegex.MatchString(r.Name) {
-		return field.Invalid(field.NewPath("metadata").Child("name"), r.Name, "name must consist of lower case alphanumeric characters or '-', start with an alphabetic character, and end with an alphanumeric character (e.g. 'my-name',  or 'abc-123', regex used for validation is '[a-z]([-a-z0-9]*[a-z0-9])?')")
+	return validateResourceName(r.Name)
+}
+
+// validateResourceName checks that the name of a Ray custom resource can be used to derive the names of its Pods and Services.
+func validateResourceName(name string) *field.Error {
+	if !nameRegex.MatchString(name) {
+		return field.Invalid(field.NewPath("metadata").Child("name"), name, "name must consist of lower case alphanumeric characters or '-', start with an alphabetic character, and end with an alphanumeric character (e.g. 'my-name',  or 'abc-123', regex used for validation is '[a-z]([-a-z0-9]*[a-z0-9])?')")
 	}
 	return nil
 }
 
-func (r *RayCluster) validateWorkerGroups() *field.Error {
+// validateGCSFaultTolerance checks that the Redis address is set when GCS fault tolerance is enabled.
+func (r *RayCluster) validateGCSFaultTolerance() *field.Error {
+	if r.Annotations[RayFTEnabledAnnotationKey] != "true" || len(r.Spec.HeadGroupSpec.Template.Spec.Containers) == 0 {
+		return nil
+	}
+	if hasEnvVar(r.Spec.HeadGroupSpec.Template.Spec.Containers[RayContainerIndex], rayRedisAddressEnvVar) {
+		return nil
+	}
+	envPath := field.NewPath("spec").Child("headGroupSpec").Child("template").Child("spec").Child("containers").Index(RayContainerIndex).Child("env")
+	return field.Required(envPath, fmt.Sprintf("%s must be set in the Ray head container when the %s annotation is \"true\"", rayRedisAddressEnvVar, RayFTEnabledAnnotationKey))
+}
+
+// validateImmutableFields rejects changes that the KubeRay operator does not apply to an existing RayCluster.
+func (r *RayCluster) validateImmutableFields(old *RayCluster) field.ErrorList {
+	var allErrs field.ErrorList
+
+	annotationsPath := field.NewPath("metadata").Child("annotations")
+	for _, key := range []string{RayFTEnabledAnnotationKey, RayExternalStorageNSAnnotationKey} {
+		allErrs = append(allErrs, apivalidation.ValidateImmutableField(r.Annotations[key], old.Annotations[key], annotationsPath.Key(key))...)
+	}
+
+	// The head service is only created once, so changes to its spec are never applied.
+	headGroupSpecPath := field.NewPath("spec").Child("headGroupSpec")
+	allErrs = append(allErrs, apivalidation.ValidateImmutableField(r.Spec.HeadGroupSpec.ServiceType, old.Spec.HeadGroupSpec.ServiceType, headGroupSpecPath.Child("serviceType"))...)
+	allErrs = append(allErrs, apivalidation.ValidateImmutableField(r.Spec.HeadGroupSpec.HeadService, old.Spec.HeadGroupSpec.HeadService, headGroupSpecPath.Child("headService"))...)
+
+	return allErrs
+}
+
+// warnings returns admission warnings for configurations that are legal but likely to cause problems.
+func (r *RayCluster) warnings() admission.Warnings {
+	warnings := rayClusterSpecWarnings(field.NewPath("spec"), &r.Spec)
+	if r.Annotations[RayFTEnabledAnnotationKey] != "true" && len(r.Spec.HeadGroupSpec.Template.Spec.Containers) != 0 &&
+		hasEnvVar(r.Spec.HeadGroupSpec.Template.Spec.Containers[RayContainerIndex], rayRedisAddressEnvVar) {
+		warnings = append(warnings, fmt.Sprintf("%s is set in the Ray head container, but GCS fault tolerance is only enabled when the %s annotation is \"true\"",
+			rayRedisAddressEnvVar, RayFTEnabledAnnotationKey))
+	}
+	return warnings
+}
+
+// validateRayClusterSpec validates a RayClusterSpec. It is shared by the webhooks of the custom resources that
+// embed a RayClusterSpec.
+func validateRayClusterSpec(path *field.Path, spec *RayClusterSpec) field.ErrorList {
+	var allErrs field.ErrorList
+
+	headGroupSpecPath := path.Child("headGroupSpec")
+	headTemplate := spec.HeadGroupSpec.Template
+	if err := validateRayContainer(headGroupSpecPath.Child("template"), headTemplate); err != nil {
+		allErrs = append(allErrs, err)
+	} else {
+		allErrs = append(allErrs, validateRayStartParams(headGroupSpecPath.Child("rayStartParams"), spec.HeadGroupSpec.RayStartParams, headTemplate.Spec.Containers[RayContainerIndex])...)
+		allErrs = append(allErrs, validateHeadPorts(headGroupSpecPath.Child("template").Child("spec").Child("containers").Index(RayContainerIndex).Child("ports"), headTemplate.Spec.Containers[RayContainerIndex])...)
+	}
+
+	allErrs = append(allErrs, validateWorkerGroupSpecs(path.Child("workerGroupSpecs"), spec.WorkerGroupSpecs)...)
+	allErrs = append(allErrs, validateAutoSuspend(path.Child("autoSuspend"), spec.AutoSuspend)...)
+
+	return allErrs
+}
+
+// validateAutoSuspend checks that the schedules are valid cron expressions and that the time zone exists.
+func validateAutoSuspend(path *field.Path, autoSuspend *RayClusterAutoSuspend) field.ErrorList {
+	if autoSuspend == nil {
+		return nil
+	}
+	var allErrs field.ErrorList
+
+	schedules := []struct {
+		name     string
+		schedule string
+	}{
+		{"suspendSchedule", autoSuspend.SuspendSchedule},
+		{"resumeSchedule", autoSuspend.ResumeSchedule},
+	}
+	for _, s := range schedules {
+		if s.schedule == "" {
+			continue
+		}
+		if _, err := cron.ParseStandard(s.schedule); err != nil {
+			allErrs = append(allErrs, field.Invalid(path.Child(s.name), s.schedule, err.Error()))
+		}
+	}
+	if autoSuspend.TimeZone != "" {
+		if _, err := time.LoadLocation(autoSuspend.TimeZone); err != nil {
+			allErrs = append(allErrs, field.Invalid(path.Child("timeZone"), autoSuspend.TimeZone, err.Error()))
+		}
+	}
+
+	return allErrs
+}
+
+func validateWorkerGroupSpecs(path *field.Path, workerGroupSpecs []WorkerGroupSpec) field.ErrorList {
+	var allErrs field.ErrorList
 	workerGroupNames := make(map[string]bool)
 
-	for i, workerGroup := range r.Spec.WorkerGroupSpecs {
+	for i, workerGroup := range workerGroupSpecs {
+		workerGroupPath := path.Index(i)
 		if _, ok := workerGroupNames[workerGroup.GroupName]; ok {
-			return field.Invalid(field.NewPath("spec").Child("workerGroupSpecs").Index(i), workerGroup, "worker group names must be unique")
+			allErrs = append(allErrs, field.Invalid(workerGroupPath, workerGroup, "worker group names must be unique"))
 		}
 		workerGroupNames[workerGroup.GroupName] = true
+
+		if workerGroup.MinReplicas != nil && workerGroup.MaxReplicas != nil && *workerGroup.MinReplicas > *workerGroup.MaxReplicas {
+			allErrs = append(allErrs, field.Invalid(workerGroupPath.Child("minReplicas"), *workerGroup.MinReplicas,
+				fmt.Sprintf("minReplicas must be less than or equal to maxReplicas (%d)", *workerGroup.MaxReplicas)))
+		}
+
+		if err := validateRayContainer(workerGroupPath.Child("template"), workerGroup.Template); err != nil {
+			allErrs = append(allErrs, err)
+		} else {
+			allErrs = append(allErrs, validateRayStartParams(workerGroupPath.Child("rayStartParams"), workerGroup.RayStartParams, workerGroup.Template.Spec.Containers[RayContainerIndex])...)
+		}
+
+		allErrs = append(allErrs, validateRollingUpdate(workerGroupPath.Child("rollingUpdate"), workerGroup.RollingUpdate)...)
+	}
+
+	return allErrs
+}
+
+// validateRollingUpdate checks that maxUnavailable and maxSurge are non-negative integers or percentages, and that they
+// are not both 0. Percentages are resolved against 100 replicas, so only "0%" resolves to 0.
+func validateRollingUpdate(path *field.Path, rollingUpdate *WorkerGroupRollingUpdate) field.ErrorList {
+	if rollingUpdate == nil {
+		return nil
+	}
+	var allErrs field.ErrorList
+
+	resolve := func(fieldPath *field.Path, value *intstr.IntOrString, defaultValue int) int {
+		if value == nil {
+			return defaultValue
+		}
+		resolved, err := intstr.GetScaledValueFromIntOrPercent(value, 100, false)
+		if err != nil {
+			allErrs = append(allErrs, field.Invalid(fieldPath, value.String(), "must be an integer or a percentage"))
+			return defaultValue
+		}
+		if resolved < 0 {
+			allErrs = append(allErrs, field.Invalid(fieldPath, value.String(), "must be greater than or equal to 0"))
+		}
+		return resolved
+	}
+	maxUnavailable := resolve(path.Child("maxUnavailable"), rollingUpdate.MaxUnavailable, 1)
+	maxSurge := resolve(path.Child("maxSurge"), rollingUpdate.MaxSurge, 0)
+	if maxUnavailable == 0 && maxSurge == 0 {
+		allErrs = append(allErrs, field.Invalid(path.Child("maxUnavailable"), rollingUpdate.MaxUnavailable.String(),
+			"maxUnavailable and maxSurge cannot both be 0"))
 	}
 
+	return allErrs
+}
+
+// validateRayContainer checks that the Pod template has a Ray container at `RayContainerIndex`.
+func validateRayContainer(templatePath *field.Path, template corev1.PodTemplateSpec) *field.Error {
+	if len(template.Spec.Containers) <= RayContainerIndex {
+		return field.Required(templatePath.Child("spec").Child("containers"),
+			fmt.Sprintf("the Ray container must be the container at index %d of the Pod template", RayContainerIndex))
+	}
 	return nil
 }
+
+// validateRayStartParams checks that the resources in rayStartParams do not exceed the resource limits of the Ray container.
+func validateRayStartParams(path *field.Path, rayStartParams map[string]string, rayContainer corev1.Container) field.ErrorList {
+	var allErrs field.ErrorList
+
+	limits := rayContainer.Resources.Limits
+	gpuLimit := resource.Quantity{}
+	for resourceName, quantity := range limits {
+		if strings.HasSuffix(string(resourceName), "gpu") {
+			gpuLimit = quantity
+			break
+		}
+	}
+	resourceParams := []struct {
+		param string
+		limit resource.Quantity
+	}{
+		{"num-cpus", limits[corev1.ResourceCPU]},
+		{"memory", limits[corev1.ResourceMemory]},
+		{"num-gpus", gpuLimit},
+	}
+
+	for _, resourceParam := range resourceParams {
+		value, ok := rayStartParams[resourceParam.param]
+		if !ok {
+			continue
+		}
+		quantity, err := resource.ParseQuantity(value)
+		if err != nil {
+			allErrs = append(allErrs, field.Invalid(path.Key(resourceParam.param), value, "must be a number"))
+			continue
+		}
+		if !resourceParam.limit.IsZero() && quantity.Cmp(resourceParam.limit) > 0 {
+			allErrs = append(allErrs, field.Invalid(path.Key(resourceParam.param), value,
+				fmt.Sprintf("exceeds the resource limit (%s) of the Ray container", resourceParam.limit.String())))
+		}
+	}
+
+	return allErrs
+}
+
+// validateHeadPorts checks that the ports of the Ray head container are unique and do not clash with the default
+// head ports of the KubeRay operator. A default port name must not be used for another default port number, and
+// the default metrics port must not be taken by another port, because the KubeRay operator appends a "metrics"
+// port to the Ray container if none is defined.
+func validateHeadPorts(path *field.Path, rayContainer corev1.Container) field.ErrorList {
+	var allErrs field.ErrorList
+
+	defaultPortNames := make(map[int32]string, len(defaultHeadPorts))
+	for name, port := range defaultHeadPorts {
+		defaultPortNames[port] = name
+	}
+
+	hasMetricsPort := false
+	for _, port := range rayContainer.Ports {
+		if port.Name == MetricsPortName {
+			hasMetricsPort = true
+		}
+	}
+
+	portNames := make(map[string]bool)
+	containerPorts := make(map[int32]bool)
+	for i, port := range rayContainer.Ports {
+		portPath := path.Index(i)
+		if port.Name != "" {
+			if portNames[port.Name] {
+				allErrs = append(allErrs, field.Duplicate(portPath.Child("name"), port.Name))
+			}
+			portNames[port.Name] = true
+		}
+		if containerPorts[port.ContainerPort] {
+			allErrs = append(allErrs, field.Duplicate(portPath.Child("containerPort"), port.ContainerPort))
+		}
+		containerPorts[port.ContainerPort] = true
+
+		defaultName, isDefaultPort := defaultPortNames[port.ContainerPort]
+		if !isDefaultPort || defaultName == port.Name {
+			continue
+		}
+		if _, isDefaultName := defaultHeadPorts[port.Name]; isDefaultName || (defaultName == MetricsPortName && !hasMetricsPort) {
+			allErrs = append(allErrs, field.Invalid(portPath.Child("containerPort"), port.ContainerPort,
+				fmt.Sprintf("port %d is reserved for the default %q port of the Ray head", port.ContainerPort, defaultName)))
+		}
+	}
+
+	return allErrs
+}
+
+// rayClusterSpecWarnings returns admission warnings for a RayClusterSpec that is legal but likely to cause problems.
+func rayClusterSpecWarnings(path *field.Path, spec *RayClusterSpec) admission.Warnings {
+	var warnings admission.Warnings
+
+	if len(spec.HeadGroupSpec.Template.Spec.Containers) > RayContainerIndex &&
+		spec.HeadGroupSpec.Template.Spec.Containers[RayContainerIndex].Resources.Limits.Memory().IsZero() {
+		warnings = append(warnings, fmt.Sprintf("%s: the Ray container has no memory limit, so Ray may use more memory than the node can provide",
+			path.Child("headGroupSpec").Child("template").String()))
+	}
+	for i, workerGroup := range spec.WorkerGroupSpecs {
+		workerGroupPath := path.Child("workerGroupSpecs").Index(i)
+		if len(workerGroup.Template.Spec.Containers) > RayContainerIndex &&
+			workerGroup.Template.Spec.Containers[RayContainerIndex].Resources.Limits.Memory().IsZero() {
+			warnings = append(warnings, fmt.Sprintf("%s: the Ray container has no memory limit, so Ray may use more memory than the node can provide",
+				workerGroupPath.Child("template").String()))
+		}
+		if workerGroup.Replicas != nil && workerGroup.MinReplicas != nil && workerGroup.MaxReplicas != nil &&
+			*workerGroup.MinReplicas <= *workerGroup.MaxReplicas &&
+			(*workerGroup.Replicas < *workerGroup.MinReplicas || *workerGroup.Replicas > *workerGroup.MaxReplicas) {
+			warnings = append(warnings, fmt.Sprintf("%s: replicas (%d) is outside of [minReplicas, maxReplicas] ([%d, %d]) and will be clamped",
+				workerGroupPath.Child("replicas").String(), *workerGroup.Replicas, *workerGroup.MinReplicas, *workerGroup.MaxReplicas))
+		}
+	}
+
+	return warnings
+}
+
+func hasEnvVar(container corev1.Container, name string) bool {
+	for _, env := range container.Env {
+		if env.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// defaultWorkerGroupReplicas sets `Replicas` of each worker group to a value within [MinReplicas, MaxReplicas].
+// It follows the same rules as the KubeRay operator uses to compute the desired number of worker Pods.
+func defaultWorkerGroupReplicas(rayClusterSpec *RayClusterSpec) {
+	for i := range rayClusterSpec.WorkerGroupSpecs {
+		workerGroup := &rayClusterSpec.WorkerGroupSpecs[i]
+		if workerGroup.MinReplicas == nil || workerGroup.MaxReplicas == nil || *workerGroup.MinReplicas > *workerGroup.MaxReplicas {
+			continue
+		}
+		if workerGroup.Replicas == nil || *workerGroup.Replicas < *workerGroup.MinReplicas {
+			replicas := *workerGroup.MinReplicas
+			workerGroup.Replicas = &replicas
+		} else if *workerGroup.Replicas > *workerGroup.MaxReplicas {
+			replicas := *workerGroup.MaxReplicas
+			workerGroup.Replicas = &replicas
+		}
+	}
+}
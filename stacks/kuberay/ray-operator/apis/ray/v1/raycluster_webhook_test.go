@@ -0,0 +1,275 @@
+package v1
+
+import (
+	"strings"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/utils/pointer"
+)
+
+func newWebhookTestRayCluster() *RayCluster {
+	rayContainer := func(name string) corev1.Container {
+		return corev1.Container{
+			Name:  name,
+			Image: "rayproject/ray:2.9.0",
+			Resources: corev1.ResourceRequirements{
+				Limits: corev1.ResourceList{
+					corev1.ResourceCPU:    resource.MustParse("2"),
+					corev1.ResourceMemory: resource.MustParse("4Gi"),
+				},
+			},
+		}
+	}
+	headContainer := rayContainer("ray-head")
+	headContainer.Ports = []corev1.ContainerPort{
+		{Name: "gcs-server", ContainerPort: 6379},
+		{Name: "dashboard", ContainerPort: 8265},
+		{Name: "client", ContainerPort: 10001},
+	}
+	return &RayCluster{
+		ObjectMeta: metav1.ObjectMeta{Name: "raycluster-sample"},
+		Spec: RayClusterSpec{
+			HeadGroupSpec: HeadGroupSpec{
+				RayStartParams: map[string]string{"num-cpus": "0"},
+				Template: corev1.PodTemplateSpec{
+					Spec: corev1.PodSpec{Containers: []corev1.Container{headContainer}},
+				},
+			},
+			WorkerGroupSpecs: []WorkerGroupSpec{
+				{
+					GroupName:      "small-group",
+					Replicas:       pointer.Int32(1),
+					MinReplicas:    pointer.Int32(1),
+					MaxReplicas:    pointer.Int32(5),
+					RayStartParams: map[string]string{},
+					Template: corev1.PodTemplateSpec{
+						Spec: corev1.PodSpec{Containers: []corev1.Container{rayContainer("ray-worker")}},
+					},
+				},
+			},
+		},
+	}
+}
+
+func TestValidateRayCluster(t *testing.T) {
+	tests := map[string]struct {
+		mutate      func(rayCluster *RayCluster)
+		expectedErr string
+	}{
+		"valid RayCluster": {
+			mutate: func(rayCluster *RayCluster) {},
+		},
+		"minReplicas greater than maxReplicas": {
+			mutate: func(rayCluster *RayCluster) {
+				rayCluster.Spec.WorkerGroupSpecs[0].MinReplicas = pointer.Int32(6)
+			},
+			expectedErr: "spec.workerGroupSpecs[0].minReplicas",
+		},
+		"head Pod template without the Ray container": {
+			mutate: func(rayCluster *RayCluster) {
+				rayCluster.Spec.HeadGroupSpec.Template.Spec.Containers = nil
+			},
+			expectedErr: "spec.headGroupSpec.template.spec.containers",
+		},
+		"worker Pod template without the Ray container": {
+			mutate: func(rayCluster *RayCluster) {
+				rayCluster.Spec.WorkerGroupSpecs[0].Template.Spec.Containers = nil
+			},
+			expectedErr: "spec.workerGroupSpecs[0].template.spec.containers",
+		},
+		"num-cpus exceeds the CPU limit": {
+			mutate: func(rayCluster *RayCluster) {
+				rayCluster.Spec.WorkerGroupSpecs[0].RayStartParams["num-cpus"] = "4"
+			},
+			expectedErr: "spec.workerGroupSpecs[0].rayStartParams[num-cpus]",
+		},
+		"memory exceeds the memory limit": {
+			mutate: func(rayCluster *RayCluster) {
+				rayCluster.Spec.HeadGroupSpec.RayStartParams["memory"] = "8000000000"
+			},
+			expectedErr: "spec.headGroupSpec.rayStartParams[memory]",
+		},
+		"num-gpus is not a number": {
+			mutate: func(rayCluster *RayCluster) {
+				rayCluster.Spec.HeadGroupSpec.RayStartParams["num-gpus"] = "one"
+			},
+			expectedErr: "must be a number",
+		},
+		"GCS fault tolerance without RAY_REDIS_ADDRESS": {
+			mutate: func(rayCluster *RayCluster) {
+				rayCluster.Annotations = map[string]string{"ray.io/ft-enabled": "true"}
+			},
+			expectedErr: "RAY_REDIS_ADDRESS must be set",
+		},
+		"GCS fault tolerance with RAY_REDIS_ADDRESS": {
+			mutate: func(rayCluster *RayCluster) {
+				rayCluster.Annotations = map[string]string{"ray.io/ft-enabled": "true"}
+				rayCluster.Spec.HeadGroupSpec.Template.Spec.Containers[0].Env = []corev1.EnvVar{{Name: "RAY_REDIS_ADDRESS", Value: "redis:6379"}}
+			},
+		},
+		"default port name used for another default port": {
+			mutate: func(rayCluster *RayCluster) {
+				rayCluster.Spec.HeadGroupSpec.Template.Spec.Containers[0].Ports[2].ContainerPort = 8000
+			},
+			expectedErr: "reserved for the default \"serve\" port",
+		},
+		"default metrics port taken by another port": {
+			mutate: func(rayCluster *RayCluster) {
+				ports := &rayCluster.Spec.HeadGroupSpec.Template.Spec.Containers[0].Ports
+				*ports = append(*ports, corev1.ContainerPort{Name: "http", ContainerPort: 8080})
+			},
+			expectedErr: "reserved for the default \"metrics\" port",
+		},
+		"maxUnavailable and maxSurge are both 0": {
+			mutate: func(rayCluster *RayCluster) {
+				zero := intstr.FromInt(0)
+				rayCluster.Spec.WorkerGroupSpecs[0].RollingUpdate = &WorkerGroupRollingUpdate{MaxUnavailable: &zero, MaxSurge: &zero}
+			},
+			expectedErr: "maxUnavailable and maxSurge cannot both be 0",
+		},
+		"invalid maxSurge": {
+			mutate: func(rayCluster *RayCluster) {
+				maxSurge := intstr.FromString("one")
+				rayCluster.Spec.WorkerGroupSpecs[0].RollingUpdate = &WorkerGroupRollingUpdate{MaxSurge: &maxSurge}
+			},
+			expectedErr: "spec.workerGroupSpecs[0].rollingUpdate.maxSurge",
+		},
+		"valid rolling update": {
+			mutate: func(rayCluster *RayCluster) {
+				maxUnavailable := intstr.FromString("0%")
+				maxSurge := intstr.FromString("25%")
+				rayCluster.Spec.WorkerGroupSpecs[0].RollingUpdate = &WorkerGroupRollingUpdate{MaxUnavailable: &maxUnavailable, MaxSurge: &maxSurge}
+			},
+		},
+		"invalid autoSuspend schedule": {
+			mutate: func(rayCluster *RayCluster) {
+				rayCluster.Spec.AutoSuspend = &RayClusterAutoSuspend{SuspendSchedule: "0 20 * *"}
+			},
+			expectedErr: "spec.autoSuspend.suspendSchedule",
+		},
+		"invalid autoSuspend time zone": {
+			mutate: func(rayCluster *RayCluster) {
+				rayCluster.Spec.AutoSuspend = &RayClusterAutoSuspend{ResumeSchedule: "0 8 * * 1-5", TimeZone: "Mars/Olympus_Mons"}
+			},
+			expectedErr: "spec.autoSuspend.timeZone",
+		},
+		"valid autoSuspend": {
+			mutate: func(rayCluster *RayCluster) {
+				rayCluster.Spec.AutoSuspend = &RayClusterAutoSuspend{SuspendSchedule: "0 20 * * 1-5", ResumeSchedule: "0 8 * * 1-5", TimeZone: "Europe/Berlin"}
+			},
+		},
+		"duplicated head port names": {
+			mutate: func(rayCluster *RayCluster) {
+				ports := &rayCluster.Spec.HeadGroupSpec.Template.Spec.Containers[0].Ports
+				*ports = append(*ports, corev1.ContainerPort{Name: "client", ContainerPort: 10002})
+			},
+			expectedErr: "Duplicate value",
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			rayCluster := newWebhookTestRayCluster()
+			tc.mutate(rayCluster)
+			_, err := rayCluster.validateRayCluster(nil)
+			if tc.expectedErr == "" {
+				if err != nil {
+					t.Fatalf("expected no error, got %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tc.expectedErr) {
+				t.Fatalf("expected error containing %q, got %v", tc.expectedErr, err)
+			}
+		})
+	}
+}
+
+func TestValidateRayClusterUpdate(t *testing.T) {
+	oldRayCluster := newWebhookTestRayCluster()
+
+	// Scaling a worker group is allowed.
+	newRayCluster := oldRayCluster.DeepCopy()
+	newRayCluster.Spec.WorkerGroupSpecs[0].Replicas = pointer.Int32(3)
+	if _, err := newRayCluster.ValidateUpdate(oldRayCluster); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	newRayCluster = oldRayCluster.DeepCopy()
+	newRayCluster.Spec.HeadGroupSpec.ServiceType = corev1.ServiceTypeNodePort
+	if _, err := newRayCluster.ValidateUpdate(oldRayCluster); err == nil || !strings.Contains(err.Error(), "spec.headGroupSpec.serviceType") {
+		t.Fatalf("expected an error for the immutable serviceType, got %v", err)
+	}
+
+	newRayCluster = oldRayCluster.DeepCopy()
+	newRayCluster.Annotations = map[string]string{"ray.io/ft-enabled": "false"}
+	if _, err := newRayCluster.ValidateUpdate(oldRayCluster); err == nil || !strings.Contains(err.Error(), "metadata.annotations[ray.io/ft-enabled]") {
+		t.Fatalf("expected an error for the immutable ray.io/ft-enabled annotation, got %v", err)
+	}
+
+	// Immutable fields can change while the RayCluster is being deleted.
+	now := metav1.Now()
+	newRayCluster.DeletionTimestamp = &now
+	if _, err := newRayCluster.ValidateUpdate(oldRayCluster); err != nil {
+		t.Fatalf("expected no error for a RayCluster being deleted, got %v", err)
+	}
+}
+
+func TestValidateRayClusterUpdateOfInvalidRayCluster(t *testing.T) {
+	// The RayCluster was created before the num-cpus rule was added.
+	oldRayCluster := newWebhookTestRayCluster()
+	oldRayCluster.Spec.WorkerGroupSpecs[0].RayStartParams["num-cpus"] = "4"
+
+	// Updates that do not change the spec, e.g. of labels or the status, are not validated.
+	newRayCluster := oldRayCluster.DeepCopy()
+	newRayCluster.Labels = map[string]string{"team": "ml"}
+	if warnings, err := newRayCluster.ValidateUpdate(oldRayCluster); err != nil || len(warnings) != 0 {
+		t.Fatalf("expected no warnings and no error, got %v and %v", warnings, err)
+	}
+
+	// Violations that the RayCluster already had are returned as warnings, so that the KubeRay operator can still
+	// suspend it.
+	newRayCluster = oldRayCluster.DeepCopy()
+	newRayCluster.Spec.Suspend = pointer.Bool(true)
+	warnings, err := newRayCluster.ValidateUpdate(oldRayCluster)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if len(warnings) != 1 || !strings.Contains(warnings[0], "spec.workerGroupSpecs[0].rayStartParams[num-cpus]") {
+		t.Fatalf("expected a warning for spec.workerGroupSpecs[0].rayStartParams[num-cpus], got %v", warnings)
+	}
+
+	// New violations are still rejected.
+	newRayCluster.Spec.HeadGroupSpec.RayStartParams["num-gpus"] = "one"
+	if _, err := newRayCluster.ValidateUpdate(oldRayCluster); err == nil || !strings.Contains(err.Error(), "spec.headGroupSpec.rayStartParams[num-gpus]") {
+		t.Fatalf("expected an error for spec.headGroupSpec.rayStartParams[num-gpus], got %v", err)
+	}
+}
+
+func TestRayClusterWarnings(t *testing.T) {
+	rayCluster := newWebhookTestRayCluster()
+	if warnings, _ := rayCluster.ValidateCreate(); len(warnings) != 0 {
+		t.Fatalf("expected no warnings, got %v", warnings)
+	}
+
+	rayCluster.Spec.WorkerGroupSpecs[0].Template.Spec.Containers[0].Resources.Limits = nil
+	rayCluster.Spec.WorkerGroupSpecs[0].Replicas = pointer.Int32(10)
+	rayCluster.Spec.HeadGroupSpec.Template.Spec.Containers[0].Env = []corev1.EnvVar{{Name: "RAY_REDIS_ADDRESS", Value: "redis:6379"}}
+	warnings, err := rayCluster.ValidateCreate()
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	expectedWarnings := []string{"no memory limit", "will be clamped", "GCS fault tolerance is only enabled"}
+	if len(warnings) != len(expectedWarnings) {
+		t.Fatalf("expected %d warnings, got %v", len(expectedWarnings), warnings)
+	}
+	for i, expected := range expectedWarnings {
+		if !strings.Contains(warnings[i], expected) {
+			t.Errorf("expected warning %d to contain %q, got %q", i, expected, warnings[i])
+		}
+	}
+}
@@ -1,8 +1,11 @@
 package v1
 
 import (
+	"fmt"
+
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/yaml"
 )
 
 // EDIT THIS FILE!  THIS IS SCAFFOLDING FOR YOU TO OWN!
@@ -30,6 +33,17 @@ func IsJobTerminal(status JobStatus) bool {
 	return ok
 }
 
+// UnmarshalRuntimeEnvYAML parses RuntimeEnvYAML into the runtime environment that is submitted to the Ray dashboard.
+// It is shared by the webhook and the controller so that both accept the same input. Note that it only checks
+// the validity of the YAML string, not its adherence to the runtime environment schema.
+func UnmarshalRuntimeEnvYAML(runtimeEnvYAML string) (map[string]interface{}, error) {
+	var runtimeEnv map[string]interface{}
+	if err := yaml.Unmarshal([]byte(runtimeEnvYAML), &runtimeEnv); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal RuntimeEnvYAML: %v: %v", runtimeEnvYAML, err)
+	}
+	return runtimeEnv, nil
+}
+
 // JobDeploymentStatus indicates RayJob status including RayCluster lifecycle management and Job submission
 type JobDeploymentStatus string
 
@@ -40,6 +54,29 @@ const (
 	JobDeploymentStatusComplete     JobDeploymentStatus = "Complete"
 	JobDeploymentStatusSuspending   JobDeploymentStatus = "Suspending"
 	JobDeploymentStatusSuspended    JobDeploymentStatus = "Suspended"
+	JobDeploymentStatusRetrying     JobDeploymentStatus = "Retrying"
+	JobDeploymentStatusFailed       JobDeploymentStatus = "Failed"
+)
+
+// RayJobConditionType is the type of a condition in RayJobStatus.Conditions.
+type RayJobConditionType string
+
+const (
+	// JobSubmitted indicates whether the Ray job of the current attempt has been accepted by the RayCluster.
+	JobSubmitted RayJobConditionType = "JobSubmitted"
+	// RayJobComplete indicates whether the JobDeploymentStatus of the RayJob is `Complete`.
+	RayJobComplete RayJobConditionType = "Complete"
+	// RayJobFailed indicates whether the JobDeploymentStatus of the RayJob is `Failed`.
+	RayJobFailed RayJobConditionType = "Failed"
+)
+
+// JobFailedReason indicates the reason the RayJob changes its JobDeploymentStatus to 'Failed'
+type JobFailedReason string
+
+const (
+	SubmissionFailed JobFailedReason = "SubmissionFailed"
+	DeadlineExceeded JobFailedReason = "DeadlineExceeded"
+	AppFailed        JobFailedReason = "AppFailed"
 )
 
 type JobSubmissionMode string
@@ -49,6 +86,14 @@ const (
 	HTTPMode   JobSubmissionMode = "HTTPMode"   // Submit job via HTTP request
 )
 
+// RayJobRetryPolicy specifies which RayCluster a failed Ray job is re-submitted to.
+type RayJobRetryPolicy string
+
+const (
+	RetryOnSameCluster RayJobRetryPolicy = "SameCluster" // Re-submit the Ray job to the existing RayCluster
+	RetryOnNewCluster  RayJobRetryPolicy = "NewCluster"  // Delete the RayCluster and re-submit the Ray job to a new one
+)
+
 // RayJobSpec defines the desired state of RayJob
 type RayJobSpec struct {
 	// INSERT ADDITIONAL SPEC FIELDS - desired state of cluster
@@ -91,6 +136,28 @@ type RayJobSpec struct {
 	// EntrypointResources specifies the custom resources and quantities to reserve for the
 	// entrypoint command.
 	EntrypointResources string `json:"entrypointResources,omitempty"`
+	// BackoffLimit is the number of times a failed Ray job is re-submitted before the RayJob is marked as `Failed`.
+	// A Ray job is considered failed if it reaches the FAILED status or, in K8sJobMode, if the submitter
+	// Kubernetes Job fails. Retries are delayed by an exponential backoff starting at 10 seconds and capped
+	// at 6 minutes. Defaults to 0, which disables retries. A failed Ray job marks the RayJob as `Failed`
+	// rather than `Complete` even without retries.
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	BackoffLimit *int32 `json:"backoffLimit,omitempty"`
+	// RetryPolicy specifies how a failed Ray job is re-submitted.
+	// In "SameCluster", the Ray job is re-submitted to the existing RayCluster.
+	// In "NewCluster", the RayCluster is deleted and the Ray job is re-submitted to a new RayCluster.
+	// "NewCluster" is not supported in the ClusterSelector mode.
+	// +kubebuilder:validation:Enum=SameCluster;NewCluster
+	// +optional
+	RetryPolicy RayJobRetryPolicy `json:"retryPolicy,omitempty"`
+	// ActiveDeadlineSeconds is the duration in seconds that the RayJob may be active, counted from its
+	// `status.startTime` and including the creation of its RayCluster and all retries. Once the deadline is
+	// exceeded, the KubeRay operator stops the Ray job and marks the RayJob as `Failed` with the reason
+	// `DeadlineExceeded`, without retries.
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	ActiveDeadlineSeconds *int32 `json:"activeDeadlineSeconds,omitempty"`
 }
 
 // RayJobStatus defines the observed state of RayJob
@@ -103,19 +170,30 @@ type RayJobStatus struct {
 	JobStatus           JobStatus           `json:"jobStatus,omitempty"`
 	JobDeploymentStatus JobDeploymentStatus `json:"jobDeploymentStatus,omitempty"`
 	Message             string              `json:"message,omitempty"`
-	// Represents time when the job was acknowledged by the Ray cluster.
-	// It is not guaranteed to be set in happens-before order across separate operations.
+	// StartTime is the time when the KubeRay operator started to initialize the RayJob. It is kept when
+	// the Ray job is retried, and reset when the RayJob is suspended.
 	// It is represented in RFC3339 form
 	StartTime *metav1.Time `json:"startTime,omitempty"`
-	// EndTime is the time when JobDeploymentStatus transitioned to 'Complete' status.
+	// EndTime is the time when JobDeploymentStatus transitioned to 'Complete', 'Failed' or 'Retrying' status.
 	// This occurs when the Ray job reaches a terminal state (SUCCEEDED, FAILED, STOPPED)
-	// or the submitter Job has failed.
+	// or the submitter Job has failed. It is reset when a failed Ray job is re-submitted.
 	EndTime          *metav1.Time     `json:"endTime,omitempty"`
 	RayClusterStatus RayClusterStatus `json:"rayClusterStatus,omitempty"`
 	// observedGeneration is the most recent generation observed for this RayJob. It corresponds to the
 	// RayJob's generation, which is updated on mutation by the API Server.
 	// +optional
 	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+	// Failed is the number of failed attempts to run the Ray job.
+	// +optional
+	Failed int32 `json:"failed,omitempty"`
+	// Reason is the reason the JobDeploymentStatus transitioned to 'Failed'.
+	// +optional
+	Reason JobFailedReason `json:"reason,omitempty"`
+	// Conditions represent the latest available observations of the RayJob's state.
+	// +optional
+	// +listType=map
+	// +listMapKey=type
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
 }
 
 // +kubebuilder:object:root=true
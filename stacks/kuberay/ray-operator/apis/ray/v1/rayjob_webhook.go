@@ -0,0 +1,149 @@
+package v1
+
+import (
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	ctrl "sigs.k8s.io/controller-runtime"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+// log is for logging in this package.
+var rayjoblog = logf.Log.WithName("rayjob-resource")
+
+func (r *RayJob) SetupWebhookWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewWebhookManagedBy(mgr).
+		For(r).
+		Complete()
+}
+
+//+kubebuilder:webhook:path=/mutate-ray-io-v1-rayjob,mutating=true,failurePolicy=fail,sideEffects=None,groups=ray.io,resources=rayjobs,verbs=create;update,versions=v1,name=mrayjob.kb.io,admissionReviewVersions=v1
+
+var _ webhook.Defaulter = &RayJob{}
+
+// Default implements webhook.Defaulter so a webhook will be registered for the type
+func (r *RayJob) Default() {
+	rayjoblog.Info("default", "name", r.Name)
+
+	if r.Spec.SubmissionMode == "" {
+		r.Spec.SubmissionMode = K8sJobMode
+	}
+	if r.Spec.RayClusterSpec != nil {
+		defaultWorkerGroupReplicas(r.Spec.RayClusterSpec)
+	}
+}
+
+//+kubebuilder:webhook:path=/validate-ray-io-v1-rayjob,mutating=false,failurePolicy=fail,sideEffects=None,groups=ray.io,resources=rayjobs,verbs=create;update,versions=v1,name=vrayjob.kb.io,admissionReviewVersions=v1
+
+var _ webhook.Validator = &RayJob{}
+
+// ValidateCreate implements webhook.Validator so a webhook will be registered for the type
+func (r *RayJob) ValidateCreate() (admission.Warnings, error) {
+	rayjoblog.Info("validate create", "name", r.Name)
+	return r.warnings(), r.validateRayJob()
+}
+
+// ValidateUpdate implements webhook.Validator so a webhook will be registered for the type
+func (r *RayJob) ValidateUpdate(old runtime.Object) (admission.Warnings, error) {
+	rayjoblog.Info("validate update", "name", r.Name)
+	oldRayJob, ok := old.(*RayJob)
+	if !ok {
+		return nil, apierrors.NewBadRequest(fmt.Sprintf("expected a RayJob but got a %T", old))
+	}
+	// Do not block the removal of finalizers from a RayJob that is being deleted, nor updates of the metadata or
+	// the status of a RayJob whose spec was accepted before.
+	if r.DeletionTimestamp != nil || equality.Semantic.DeepEqual(oldRayJob.Spec, r.Spec) {
+		return nil, nil
+	}
+	return r.warnings(), r.validateRayJob()
+}
+
+// ValidateDelete implements webhook.Validator so a webhook will be registered for the type
+func (r *RayJob) ValidateDelete() (admission.Warnings, error) {
+	rayjoblog.Info("validate delete", "name", r.Name)
+	return nil, nil
+}
+
+// warnings returns admission warnings for configurations that are legal but likely to cause problems.
+func (r *RayJob) warnings() admission.Warnings {
+	if r.Spec.RayClusterSpec == nil {
+		return nil
+	}
+	return rayClusterSpecWarnings(field.NewPath("spec").Child("rayClusterSpec"), r.Spec.RayClusterSpec)
+}
+
+func (r *RayJob) validateRayJob() error {
+	var allErrs field.ErrorList
+
+	if err := validateResourceName(r.Name); err != nil {
+		allErrs = append(allErrs, err)
+	}
+
+	allErrs = append(allErrs, r.validateRayJobSpec()...)
+
+	if len(allErrs) == 0 {
+		return nil
+	}
+
+	return apierrors.NewInvalid(
+		schema.GroupKind{Group: "ray.io", Kind: "RayJob"},
+		r.Name, allErrs)
+}
+
+func (r *RayJob) validateRayJobSpec() field.ErrorList {
+	var allErrs field.ErrorList
+	specPath := field.NewPath("spec")
+
+	if r.Spec.Entrypoint == "" && r.Spec.SubmitterPodTemplate == nil {
+		allErrs = append(allErrs, field.Required(specPath.Child("entrypoint"), "entrypoint must be set unless submitterPodTemplate is specified"))
+	}
+
+	switch {
+	case r.Spec.RayClusterSpec == nil && len(r.Spec.ClusterSelector) == 0:
+		allErrs = append(allErrs, field.Required(specPath.Child("rayClusterSpec"), "one of rayClusterSpec or clusterSelector must be set"))
+	case r.Spec.RayClusterSpec != nil && len(r.Spec.ClusterSelector) != 0:
+		allErrs = append(allErrs, field.Forbidden(specPath.Child("clusterSelector"), "rayClusterSpec and clusterSelector are mutually exclusive"))
+	case r.Spec.RayClusterSpec != nil:
+		allErrs = append(allErrs, validateRayClusterSpec(specPath.Child("rayClusterSpec"), r.Spec.RayClusterSpec)...)
+	}
+
+	switch r.Spec.SubmissionMode {
+	case "", K8sJobMode:
+	case HTTPMode:
+		if r.Spec.SubmitterPodTemplate != nil {
+			allErrs = append(allErrs, field.Forbidden(specPath.Child("submitterPodTemplate"), fmt.Sprintf("submitterPodTemplate is not supported in %s", HTTPMode)))
+		}
+	default:
+		allErrs = append(allErrs, field.NotSupported(specPath.Child("submissionMode"), r.Spec.SubmissionMode, []string{string(K8sJobMode), string(HTTPMode)}))
+	}
+
+	// KubeRay has some limitations for the suspend operation. See `validateRayJobSpec` in the RayJob controller.
+	if r.Spec.Suspend && !r.Spec.ShutdownAfterJobFinishes {
+		allErrs = append(allErrs, field.Forbidden(specPath.Child("suspend"), "a RayJob with shutdownAfterJobFinishes set to false is not allowed to be suspended"))
+	}
+	if r.Spec.Suspend && len(r.Spec.ClusterSelector) != 0 {
+		allErrs = append(allErrs, field.Forbidden(specPath.Child("suspend"), "the ClusterSelector mode doesn't support the suspend operation"))
+	}
+
+	if r.Spec.BackoffLimit != nil && *r.Spec.BackoffLimit < 0 {
+		allErrs = append(allErrs, field.Invalid(specPath.Child("backoffLimit"), *r.Spec.BackoffLimit, "backoffLimit must be a non-negative integer"))
+	}
+	if r.Spec.ActiveDeadlineSeconds != nil && *r.Spec.ActiveDeadlineSeconds <= 0 {
+		allErrs = append(allErrs, field.Invalid(specPath.Child("activeDeadlineSeconds"), *r.Spec.ActiveDeadlineSeconds, "activeDeadlineSeconds must be a positive integer"))
+	}
+	if r.Spec.RetryPolicy == RetryOnNewCluster && len(r.Spec.ClusterSelector) != 0 {
+		allErrs = append(allErrs, field.Forbidden(specPath.Child("retryPolicy"), fmt.Sprintf("the ClusterSelector mode doesn't support the %s retry policy", RetryOnNewCluster)))
+	}
+
+	if _, err := UnmarshalRuntimeEnvYAML(r.Spec.RuntimeEnvYAML); err != nil {
+		allErrs = append(allErrs, field.Invalid(specPath.Child("runtimeEnvYAML"), r.Spec.RuntimeEnvYAML, err.Error()))
+	}
+
+	return allErrs
+}
@@ -0,0 +1,168 @@
+package v1
+
+import (
+	"strings"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/pointer"
+)
+
+func TestRayJobDefault(t *testing.T) {
+	rayJob := &RayJob{
+		Spec: RayJobSpec{
+			RayClusterSpec: &RayClusterSpec{
+				WorkerGroupSpecs: []WorkerGroupSpec{
+					{GroupName: "below-min", Replicas: pointer.Int32(0), MinReplicas: pointer.Int32(1), MaxReplicas: pointer.Int32(5)},
+					{GroupName: "above-max", Replicas: pointer.Int32(10), MinReplicas: pointer.Int32(1), MaxReplicas: pointer.Int32(5)},
+					{GroupName: "unset", MinReplicas: pointer.Int32(2), MaxReplicas: pointer.Int32(5)},
+					{GroupName: "in-range", Replicas: pointer.Int32(3), MinReplicas: pointer.Int32(1), MaxReplicas: pointer.Int32(5)},
+				},
+			},
+		},
+	}
+	rayJob.Default()
+
+	if rayJob.Spec.SubmissionMode != K8sJobMode {
+		t.Errorf("expected submissionMode %s, got %s", K8sJobMode, rayJob.Spec.SubmissionMode)
+	}
+	for i, expected := range []int32{1, 5, 2, 3} {
+		workerGroup := rayJob.Spec.RayClusterSpec.WorkerGroupSpecs[i]
+		if *workerGroup.Replicas != expected {
+			t.Errorf("worker group %s: expected %d replicas, got %d", workerGroup.GroupName, expected, *workerGroup.Replicas)
+		}
+	}
+
+	rayJob.Spec.SubmissionMode = HTTPMode
+	rayJob.Default()
+	if rayJob.Spec.SubmissionMode != HTTPMode {
+		t.Errorf("expected submissionMode %s to be kept, got %s", HTTPMode, rayJob.Spec.SubmissionMode)
+	}
+}
+
+func TestValidateRayJob(t *testing.T) {
+	validRayJob := func() *RayJob {
+		return &RayJob{
+			ObjectMeta: metav1.ObjectMeta{Name: "rayjob-sample"},
+			Spec: RayJobSpec{
+				Entrypoint: "python /home/ray/samples/sample_code.py",
+				RayClusterSpec: &RayClusterSpec{
+					HeadGroupSpec: HeadGroupSpec{
+						Template: corev1.PodTemplateSpec{
+							Spec: corev1.PodSpec{
+								Containers: []corev1.Container{{Name: "ray-head", Image: "rayproject/ray:2.9.0"}},
+							},
+						},
+					},
+				},
+			},
+		}
+	}
+
+	tests := map[string]struct {
+		mutate      func(rayJob *RayJob)
+		expectedErr string
+	}{
+		"valid RayJob": {
+			mutate: func(rayJob *RayJob) {},
+		},
+		"invalid name": {
+			mutate:      func(rayJob *RayJob) { rayJob.Name = "invalid.name" },
+			expectedErr: "metadata.name",
+		},
+		"missing entrypoint": {
+			mutate:      func(rayJob *RayJob) { rayJob.Spec.Entrypoint = "" },
+			expectedErr: "entrypoint must be set",
+		},
+		"missing entrypoint with submitterPodTemplate": {
+			mutate: func(rayJob *RayJob) {
+				rayJob.Spec.Entrypoint = ""
+				rayJob.Spec.SubmitterPodTemplate = &corev1.PodTemplateSpec{}
+			},
+		},
+		"neither rayClusterSpec nor clusterSelector": {
+			mutate:      func(rayJob *RayJob) { rayJob.Spec.RayClusterSpec = nil },
+			expectedErr: "one of rayClusterSpec or clusterSelector must be set",
+		},
+		"both rayClusterSpec and clusterSelector": {
+			mutate: func(rayJob *RayJob) {
+				rayJob.Spec.ClusterSelector = map[string]string{"ray.io/cluster": "raycluster-sample"}
+			},
+			expectedErr: "mutually exclusive",
+		},
+		"duplicated worker group names": {
+			mutate: func(rayJob *RayJob) {
+				rayJob.Spec.RayClusterSpec.WorkerGroupSpecs = []WorkerGroupSpec{{GroupName: "group1"}, {GroupName: "group1"}}
+			},
+			expectedErr: "worker group names must be unique",
+		},
+		"unsupported submissionMode": {
+			mutate:      func(rayJob *RayJob) { rayJob.Spec.SubmissionMode = "InvalidMode" },
+			expectedErr: "spec.submissionMode",
+		},
+		"submitterPodTemplate in HTTPMode": {
+			mutate: func(rayJob *RayJob) {
+				rayJob.Spec.SubmissionMode = HTTPMode
+				rayJob.Spec.SubmitterPodTemplate = &corev1.PodTemplateSpec{}
+			},
+			expectedErr: "submitterPodTemplate is not supported in HTTPMode",
+		},
+		"suspend without shutdownAfterJobFinishes": {
+			mutate:      func(rayJob *RayJob) { rayJob.Spec.Suspend = true },
+			expectedErr: "not allowed to be suspended",
+		},
+		"NewCluster retry policy with clusterSelector": {
+			mutate: func(rayJob *RayJob) {
+				rayJob.Spec.RayClusterSpec = nil
+				rayJob.Spec.ClusterSelector = map[string]string{"ray.io/cluster": "raycluster-sample"}
+				rayJob.Spec.RetryPolicy = RetryOnNewCluster
+			},
+			expectedErr: "spec.retryPolicy",
+		},
+		"invalid runtimeEnvYAML": {
+			mutate:      func(rayJob *RayJob) { rayJob.Spec.RuntimeEnvYAML = "pip: [" },
+			expectedErr: "failed to unmarshal RuntimeEnvYAML",
+		},
+		// The controller rejects a runtimeEnvYAML that is not a mapping when it submits the job.
+		"runtimeEnvYAML is not a mapping": {
+			mutate:      func(rayJob *RayJob) { rayJob.Spec.RuntimeEnvYAML = "invalid_yaml_str" },
+			expectedErr: "spec.runtimeEnvYAML",
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			rayJob := validRayJob()
+			tc.mutate(rayJob)
+			err := rayJob.validateRayJob()
+			if tc.expectedErr == "" {
+				if err != nil {
+					t.Fatalf("expected no error, got %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tc.expectedErr) {
+				t.Fatalf("expected error containing %q, got %v", tc.expectedErr, err)
+			}
+		})
+	}
+}
+
+func TestValidateRayJobUpdate(t *testing.T) {
+	// A RayJob created before a validation rule was added must still accept updates that do not change its spec.
+	oldRayJob := &RayJob{
+		ObjectMeta: metav1.ObjectMeta{Name: "rayjob-sample"},
+		Spec:       RayJobSpec{Entrypoint: "python /home/ray/samples/sample_code.py"},
+	}
+	newRayJob := oldRayJob.DeepCopy()
+	newRayJob.Labels = map[string]string{"team": "ml"}
+	if _, err := newRayJob.ValidateUpdate(oldRayJob); err != nil {
+		t.Errorf("expected no error for an update that does not change the spec, got %v", err)
+	}
+
+	newRayJob.Spec.Entrypoint = "python /home/ray/samples/other_code.py"
+	if _, err := newRayJob.ValidateUpdate(oldRayJob); err == nil || !strings.Contains(err.Error(), "spec.rayClusterSpec") {
+		t.Errorf("expected an error for spec.rayClusterSpec, got %v", err)
+	}
+}
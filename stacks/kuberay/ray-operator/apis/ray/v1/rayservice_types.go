@@ -20,6 +20,18 @@ const (
 	FailedToUpdateService            ServiceStatus = "FailedToUpdateService"
 )
 
+// RayServiceConditionType is the type of a condition in RayServiceStatuses.Conditions.
+type RayServiceConditionType string
+
+const (
+	// RayServiceReady indicates whether the RayService is serving traffic, i.e. its ServiceStatus is `Running`.
+	RayServiceReady RayServiceConditionType = "Ready"
+	// ServeAppsHealthy indicates whether all the Serve applications on the active RayCluster are `RUNNING`.
+	ServeAppsHealthy RayServiceConditionType = "ServeAppsHealthy"
+	// UpgradeInProgress indicates whether a pending RayCluster is being prepared to replace the active RayCluster.
+	UpgradeInProgress RayServiceConditionType = "UpgradeInProgress"
+)
+
 // These statuses should match Ray Serve's application statuses
 // See `enum ApplicationStatus` in https://sourcegraph.com/github.com/ray-project/ray/-/blob/src/ray/protobuf/serve.proto for more details.
 var ApplicationStatusEnum = struct {
@@ -77,6 +89,11 @@ type RayServiceStatuses struct {
 	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
 	// LastUpdateTime represents the timestamp when the RayService status was last updated.
 	LastUpdateTime *metav1.Time `json:"lastUpdateTime,omitempty"`
+	// Conditions represent the latest available observations of the RayService's state.
+	// +optional
+	// +listType=map
+	// +listMapKey=type
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
 }
 
 type RayServiceStatus struct {
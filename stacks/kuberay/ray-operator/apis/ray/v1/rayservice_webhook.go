@@ -0,0 +1,105 @@
+package v1
+
+import (
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/apimachinery/pkg/util/yaml"
+	ctrl "sigs.k8s.io/controller-runtime"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+// log is for logging in this package.
+var rayservicelog = logf.Log.WithName("rayservice-resource")
+
+func (r *RayService) SetupWebhookWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewWebhookManagedBy(mgr).
+		For(r).
+		Complete()
+}
+
+//+kubebuilder:webhook:path=/mutate-ray-io-v1-rayservice,mutating=true,failurePolicy=fail,sideEffects=None,groups=ray.io,resources=rayservices,verbs=create;update,versions=v1,name=mrayservice.kb.io,admissionReviewVersions=v1
+
+var _ webhook.Defaulter = &RayService{}
+
+// Default implements webhook.Defaulter so a webhook will be registered for the type
+func (r *RayService) Default() {
+	rayservicelog.Info("default", "name", r.Name)
+
+	defaultWorkerGroupReplicas(&r.Spec.RayClusterSpec)
+}
+
+//+kubebuilder:webhook:path=/validate-ray-io-v1-rayservice,mutating=false,failurePolicy=fail,sideEffects=None,groups=ray.io,resources=rayservices,verbs=create;update,versions=v1,name=vrayservice.kb.io,admissionReviewVersions=v1
+
+var _ webhook.Validator = &RayService{}
+
+// ValidateCreate implements webhook.Validator so a webhook will be registered for the type
+func (r *RayService) ValidateCreate() (admission.Warnings, error) {
+	rayservicelog.Info("validate create", "name", r.Name)
+	return r.warnings(), r.validateRayService()
+}
+
+// ValidateUpdate implements webhook.Validator so a webhook will be registered for the type
+func (r *RayService) ValidateUpdate(old runtime.Object) (admission.Warnings, error) {
+	rayservicelog.Info("validate update", "name", r.Name)
+	oldRayService, ok := old.(*RayService)
+	if !ok {
+		return nil, apierrors.NewBadRequest(fmt.Sprintf("expected a RayService but got a %T", old))
+	}
+	// Do not block the removal of finalizers from a RayService that is being deleted, nor updates of the metadata or
+	// the status of a RayService whose spec was accepted before.
+	if r.DeletionTimestamp != nil || equality.Semantic.DeepEqual(oldRayService.Spec, r.Spec) {
+		return nil, nil
+	}
+	return r.warnings(), r.validateRayService()
+}
+
+// ValidateDelete implements webhook.Validator so a webhook will be registered for the type
+func (r *RayService) ValidateDelete() (admission.Warnings, error) {
+	rayservicelog.Info("validate delete", "name", r.Name)
+	return nil, nil
+}
+
+// warnings returns admission warnings for configurations that are legal but likely to cause problems.
+func (r *RayService) warnings() admission.Warnings {
+	return rayClusterSpecWarnings(field.NewPath("spec").Child("rayClusterConfig"), &r.Spec.RayClusterSpec)
+}
+
+func (r *RayService) validateRayService() error {
+	var allErrs field.ErrorList
+
+	if err := validateResourceName(r.Name); err != nil {
+		allErrs = append(allErrs, err)
+	}
+
+	allErrs = append(allErrs, r.validateRayServiceSpec()...)
+
+	if len(allErrs) == 0 {
+		return nil
+	}
+
+	return apierrors.NewInvalid(
+		schema.GroupKind{Group: "ray.io", Kind: "RayService"},
+		r.Name, allErrs)
+}
+
+func (r *RayService) validateRayServiceSpec() field.ErrorList {
+	var allErrs field.ErrorList
+	specPath := field.NewPath("spec")
+
+	// The RayService controller unmarshals serveConfigV2 in the same way before sending it to the Ray dashboard.
+	serveConfig := map[string]interface{}{}
+	if err := yaml.Unmarshal([]byte(r.Spec.ServeConfigV2), &serveConfig); err != nil {
+		allErrs = append(allErrs, field.Invalid(specPath.Child("serveConfigV2"), r.Spec.ServeConfigV2, fmt.Sprintf("failed to unmarshal serveConfigV2: %v", err)))
+	}
+
+	allErrs = append(allErrs, validateRayClusterSpec(specPath.Child("rayClusterConfig"), &r.Spec.RayClusterSpec)...)
+
+	return allErrs
+}
@@ -0,0 +1,116 @@
+package v1
+
+import (
+	"strings"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/pointer"
+)
+
+func TestRayServiceDefault(t *testing.T) {
+	rayService := &RayService{
+		Spec: RayServiceSpec{
+			RayClusterSpec: RayClusterSpec{
+				WorkerGroupSpecs: []WorkerGroupSpec{
+					{GroupName: "above-max", Replicas: pointer.Int32(10), MinReplicas: pointer.Int32(1), MaxReplicas: pointer.Int32(5)},
+					// minReplicas > maxReplicas is left to the KubeRay operator.
+					{GroupName: "invalid-range", Replicas: pointer.Int32(3), MinReplicas: pointer.Int32(5), MaxReplicas: pointer.Int32(1)},
+				},
+			},
+		},
+	}
+	rayService.Default()
+
+	for i, expected := range []int32{5, 3} {
+		workerGroup := rayService.Spec.RayClusterSpec.WorkerGroupSpecs[i]
+		if *workerGroup.Replicas != expected {
+			t.Errorf("worker group %s: expected %d replicas, got %d", workerGroup.GroupName, expected, *workerGroup.Replicas)
+		}
+	}
+}
+
+func TestValidateRayService(t *testing.T) {
+	validRayService := func() *RayService {
+		return &RayService{
+			ObjectMeta: metav1.ObjectMeta{Name: "rayservice-sample"},
+			Spec: RayServiceSpec{
+				ServeConfigV2: "applications:\n  - name: math_app\n    import_path: conditional_dag.serve_dag\n",
+				RayClusterSpec: RayClusterSpec{
+					HeadGroupSpec: HeadGroupSpec{
+						Template: corev1.PodTemplateSpec{
+							Spec: corev1.PodSpec{
+								Containers: []corev1.Container{{Name: "ray-head", Image: "rayproject/ray:2.9.0"}},
+							},
+						},
+					},
+				},
+			},
+		}
+	}
+
+	tests := map[string]struct {
+		mutate      func(rayService *RayService)
+		expectedErr string
+	}{
+		"valid RayService": {
+			mutate: func(rayService *RayService) {},
+		},
+		"invalid name": {
+			mutate:      func(rayService *RayService) { rayService.Name = "invalid.name" },
+			expectedErr: "metadata.name",
+		},
+		"invalid serveConfigV2": {
+			mutate:      func(rayService *RayService) { rayService.Spec.ServeConfigV2 = "applications: [" },
+			expectedErr: "failed to unmarshal serveConfigV2",
+		},
+		"head Pod template without containers": {
+			mutate: func(rayService *RayService) {
+				rayService.Spec.RayClusterSpec.HeadGroupSpec.Template.Spec.Containers = nil
+			},
+			expectedErr: "spec.rayClusterConfig.headGroupSpec.template.spec.containers",
+		},
+		"duplicated worker group names": {
+			mutate: func(rayService *RayService) {
+				rayService.Spec.RayClusterSpec.WorkerGroupSpecs = []WorkerGroupSpec{{GroupName: "group1"}, {GroupName: "group1"}}
+			},
+			expectedErr: "worker group names must be unique",
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			rayService := validRayService()
+			tc.mutate(rayService)
+			err := rayService.validateRayService()
+			if tc.expectedErr == "" {
+				if err != nil {
+					t.Fatalf("expected no error, got %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tc.expectedErr) {
+				t.Fatalf("expected error containing %q, got %v", tc.expectedErr, err)
+			}
+		})
+	}
+}
+
+func TestValidateRayServiceUpdate(t *testing.T) {
+	// A RayService created before a validation rule was added must still accept updates that do not change its spec.
+	oldRayService := &RayService{
+		ObjectMeta: metav1.ObjectMeta{Name: "rayservice-sample"},
+		Spec:       RayServiceSpec{ServeConfigV2: "applications: ["},
+	}
+	newRayService := oldRayService.DeepCopy()
+	newRayService.Labels = map[string]string{"team": "ml"}
+	if _, err := newRayService.ValidateUpdate(oldRayService); err != nil {
+		t.Errorf("expected no error for an update that does not change the spec, got %v", err)
+	}
+
+	newRayService.Spec.ServeConfigV2 = "applications: [[]"
+	if _, err := newRayService.ValidateUpdate(oldRayService); err == nil || !strings.Contains(err.Error(), "spec.serveConfigV2") {
+		t.Errorf("expected an error for spec.serveConfigV2, got %v", err)
+	}
+}
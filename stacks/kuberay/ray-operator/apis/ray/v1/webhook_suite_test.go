@@ -98,6 +98,12 @@ var _ = BeforeSuite(func() {
 	err = (&RayCluster{}).SetupWebhookWithManager(mgr)
 	Expect(err).NotTo(HaveOccurred())
 
+	err = (&RayJob{}).SetupWebhookWithManager(mgr)
+	Expect(err).NotTo(HaveOccurred())
+
+	err = (&RayService{}).SetupWebhookWithManager(mgr)
+	Expect(err).NotTo(HaveOccurred())
+
 	//+kubebuilder:scaffold:webhook
 
 	go func() {
@@ -202,6 +208,88 @@ var _ = Describe("RayCluster validating webhook", func() {
 	})
 })
 
+var _ = Describe("RayJob webhooks", func() {
+	Context("when both rayClusterSpec and clusterSelector are set", func() {
+		It("should return error", func() {
+			rayJob := RayJob{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace: "default",
+					Name:      fmt.Sprintf("test-rayjob-%d", rand.IntnRange(1000, 9000)),
+				},
+				Spec: RayJobSpec{
+					Entrypoint:      "python test.py",
+					ClusterSelector: map[string]string{"ray.io/cluster": "raycluster-sample"},
+					RayClusterSpec: &RayClusterSpec{
+						HeadGroupSpec: HeadGroupSpec{
+							RayStartParams: map[string]string{},
+							Template: corev1.PodTemplateSpec{
+								Spec: corev1.PodSpec{
+									Containers: []corev1.Container{},
+								},
+							},
+						},
+					},
+				},
+			}
+
+			err := k8sClient.Create(context.TODO(), &rayJob)
+			Expect(err).To(HaveOccurred())
+
+			Expect(err.Error()).To(ContainSubstring("rayClusterSpec and clusterSelector are mutually exclusive"))
+		})
+	})
+
+	Context("when submissionMode is not set", func() {
+		It("should default to K8sJobMode", func() {
+			rayJob := RayJob{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace: "default",
+					Name:      fmt.Sprintf("test-rayjob-%d", rand.IntnRange(1000, 9000)),
+				},
+				Spec: RayJobSpec{
+					Entrypoint:      "python test.py",
+					ClusterSelector: map[string]string{"ray.io/cluster": "raycluster-sample"},
+				},
+			}
+
+			err := k8sClient.Create(context.TODO(), &rayJob)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(rayJob.Spec.SubmissionMode).To(Equal(K8sJobMode))
+		})
+	})
+})
+
+var _ = Describe("RayService validating webhook", func() {
+	Context("when serveConfigV2 is not a valid YAML string", func() {
+		It("should return error", func() {
+			rayService := RayService{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace: "default",
+					Name:      fmt.Sprintf("test-rayservice-%d", rand.IntnRange(1000, 9000)),
+				},
+				Spec: RayServiceSpec{
+					ServeConfigV2: "applications: [",
+					RayClusterSpec: RayClusterSpec{
+						HeadGroupSpec: HeadGroupSpec{
+							RayStartParams: map[string]string{},
+							Template: corev1.PodTemplateSpec{
+								Spec: corev1.PodSpec{
+									Containers: []corev1.Container{{Name: "ray-head", Image: "rayproject/ray:2.9.0"}},
+								},
+							},
+						},
+					},
+				},
+			}
+
+			err := k8sClient.Create(context.TODO(), &rayService)
+			Expect(err).To(HaveOccurred())
+
+			Expect(err.Error()).To(ContainSubstring("failed to unmarshal serveConfigV2"))
+		})
+	})
+})
+
 var _ = AfterSuite(func() {
 	cancel()
 	By("tearing down the test environment")
@@ -6,7 +6,9 @@ package v1
 
 import (
 	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/intstr"
 )
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
@@ -101,6 +103,31 @@ func (in *AutoscalerOptions) DeepCopy() *AutoscalerOptions {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FailedPodRetentionPolicy) DeepCopyInto(out *FailedPodRetentionPolicy) {
+	*out = *in
+	if in.MaxPodsPerGroup != nil {
+		in, out := &in.MaxPodsPerGroup, &out.MaxPodsPerGroup
+		*out = new(int32)
+		**out = **in
+	}
+	if in.TTLSeconds != nil {
+		in, out := &in.TTLSeconds, &out.TTLSeconds
+		*out = new(int32)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FailedPodRetentionPolicy.
+func (in *FailedPodRetentionPolicy) DeepCopy() *FailedPodRetentionPolicy {
+	if in == nil {
+		return nil
+	}
+	out := new(FailedPodRetentionPolicy)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *HeadGroupSpec) DeepCopyInto(out *HeadGroupSpec) {
 	*out = *in
@@ -176,6 +203,73 @@ func (in *RayCluster) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RayClusterAutoSuspend) DeepCopyInto(out *RayClusterAutoSuspend) {
+	*out = *in
+	if in.IdleTTLSeconds != nil {
+		in, out := &in.IdleTTLSeconds, &out.IdleTTLSeconds
+		*out = new(int32)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RayClusterAutoSuspend.
+func (in *RayClusterAutoSuspend) DeepCopy() *RayClusterAutoSuspend {
+	if in == nil {
+		return nil
+	}
+	out := new(RayClusterAutoSuspend)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RayClusterFailureBudget) DeepCopyInto(out *RayClusterFailureBudget) {
+	*out = *in
+	if in.MaxFailures != nil {
+		in, out := &in.MaxFailures, &out.MaxFailures
+		*out = new(int32)
+		**out = **in
+	}
+	if in.WindowSeconds != nil {
+		in, out := &in.WindowSeconds, &out.WindowSeconds
+		*out = new(int32)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RayClusterFailureBudget.
+func (in *RayClusterFailureBudget) DeepCopy() *RayClusterFailureBudget {
+	if in == nil {
+		return nil
+	}
+	out := new(RayClusterFailureBudget)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RayClusterGroupFailures) DeepCopyInto(out *RayClusterGroupFailures) {
+	*out = *in
+	if in.FailureTimes != nil {
+		in, out := &in.FailureTimes, &out.FailureTimes
+		*out = make([]metav1.Time, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RayClusterGroupFailures.
+func (in *RayClusterGroupFailures) DeepCopy() *RayClusterGroupFailures {
+	if in == nil {
+		return nil
+	}
+	out := new(RayClusterGroupFailures)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *RayClusterList) DeepCopyInto(out *RayClusterList) {
 	*out = *in
@@ -208,6 +302,22 @@ func (in *RayClusterList) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RayClusterScheduledAction) DeepCopyInto(out *RayClusterScheduledAction) {
+	*out = *in
+	in.Time.DeepCopyInto(&out.Time)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RayClusterScheduledAction.
+func (in *RayClusterScheduledAction) DeepCopy() *RayClusterScheduledAction {
+	if in == nil {
+		return nil
+	}
+	out := new(RayClusterScheduledAction)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *RayClusterSpec) DeepCopyInto(out *RayClusterSpec) {
 	*out = *in
@@ -241,6 +351,26 @@ func (in *RayClusterSpec) DeepCopyInto(out *RayClusterSpec) {
 		*out = new(bool)
 		**out = **in
 	}
+	if in.UpgradeStrategy != nil {
+		in, out := &in.UpgradeStrategy, &out.UpgradeStrategy
+		*out = new(RayClusterUpgradeStrategy)
+		**out = **in
+	}
+	if in.FailedPodRetention != nil {
+		in, out := &in.FailedPodRetention, &out.FailedPodRetention
+		*out = new(FailedPodRetentionPolicy)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.FailureBudget != nil {
+		in, out := &in.FailureBudget, &out.FailureBudget
+		*out = new(RayClusterFailureBudget)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.AutoSuspend != nil {
+		in, out := &in.AutoSuspend, &out.AutoSuspend
+		*out = new(RayClusterAutoSuspend)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RayClusterSpec.
@@ -272,6 +402,33 @@ func (in *RayClusterStatus) DeepCopyInto(out *RayClusterStatus) {
 		}
 	}
 	out.Head = in.Head
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]metav1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.GroupFailures != nil {
+		in, out := &in.GroupFailures, &out.GroupFailures
+		*out = make([]RayClusterGroupFailures, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.LastActivityTime != nil {
+		in, out := &in.LastActivityTime, &out.LastActivityTime
+		*out = (*in).DeepCopy()
+	}
+	if in.LastScheduleTime != nil {
+		in, out := &in.LastScheduleTime, &out.LastScheduleTime
+		*out = (*in).DeepCopy()
+	}
+	if in.NextScheduledAction != nil {
+		in, out := &in.NextScheduledAction, &out.NextScheduledAction
+		*out = new(RayClusterScheduledAction)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RayClusterStatus.
@@ -284,6 +441,21 @@ func (in *RayClusterStatus) DeepCopy() *RayClusterStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RayClusterUpgradeStrategy) DeepCopyInto(out *RayClusterUpgradeStrategy) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RayClusterUpgradeStrategy.
+func (in *RayClusterUpgradeStrategy) DeepCopy() *RayClusterUpgradeStrategy {
+	if in == nil {
+		return nil
+	}
+	out := new(RayClusterUpgradeStrategy)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *RayJob) DeepCopyInto(out *RayJob) {
 	*out = *in
@@ -370,6 +542,16 @@ func (in *RayJobSpec) DeepCopyInto(out *RayJobSpec) {
 		*out = new(corev1.PodTemplateSpec)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.BackoffLimit != nil {
+		in, out := &in.BackoffLimit, &out.BackoffLimit
+		*out = new(int32)
+		**out = **in
+	}
+	if in.ActiveDeadlineSeconds != nil {
+		in, out := &in.ActiveDeadlineSeconds, &out.ActiveDeadlineSeconds
+		*out = new(int32)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RayJobSpec.
@@ -394,6 +576,13 @@ func (in *RayJobStatus) DeepCopyInto(out *RayJobStatus) {
 		*out = (*in).DeepCopy()
 	}
 	in.RayClusterStatus.DeepCopyInto(&out.RayClusterStatus)
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]metav1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RayJobStatus.
@@ -528,6 +717,13 @@ func (in *RayServiceStatuses) DeepCopyInto(out *RayServiceStatuses) {
 		in, out := &in.LastUpdateTime, &out.LastUpdateTime
 		*out = (*in).DeepCopy()
 	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]metav1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RayServiceStatuses.
@@ -579,6 +775,31 @@ func (in *ServeDeploymentStatus) DeepCopy() *ServeDeploymentStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *WorkerGroupRollingUpdate) DeepCopyInto(out *WorkerGroupRollingUpdate) {
+	*out = *in
+	if in.MaxUnavailable != nil {
+		in, out := &in.MaxUnavailable, &out.MaxUnavailable
+		*out = new(intstr.IntOrString)
+		**out = **in
+	}
+	if in.MaxSurge != nil {
+		in, out := &in.MaxSurge, &out.MaxSurge
+		*out = new(intstr.IntOrString)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new WorkerGroupRollingUpdate.
+func (in *WorkerGroupRollingUpdate) DeepCopy() *WorkerGroupRollingUpdate {
+	if in == nil {
+		return nil
+	}
+	out := new(WorkerGroupRollingUpdate)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *WorkerGroupSpec) DeepCopyInto(out *WorkerGroupSpec) {
 	*out = *in
@@ -606,6 +827,11 @@ func (in *WorkerGroupSpec) DeepCopyInto(out *WorkerGroupSpec) {
 	}
 	in.Template.DeepCopyInto(&out.Template)
 	in.ScaleStrategy.DeepCopyInto(&out.ScaleStrategy)
+	if in.RollingUpdate != nil {
+		in, out := &in.RollingUpdate, &out.RollingUpdate
+		*out = new(WorkerGroupRollingUpdate)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new WorkerGroupSpec.
@@ -0,0 +1,277 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/fsnotify/fsnotify"
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/pointer"
+
+	configapi "github.com/ray-project/kuberay/ray-operator/apis/config/v1alpha1"
+	"github.com/ray-project/kuberay/ray-operator/pkg/features"
+)
+
+// configWatcher watches the operator Configuration file and applies the reloadable fields of a changed Configuration
+// without restarting the operator. Changes to the other fields are reported as a warning, because they only take
+// effect after a restart.
+//
+// The directory of the file is watched rather than the file itself, because a mounted ConfigMap is updated by
+// atomically replacing a symlink in the directory.
+type configWatcher struct {
+	path   string
+	scheme *runtime.Scheme
+	log    logr.Logger
+	// apply applies the reloadable fields of a valid Configuration.
+	apply func(config configapi.Configuration) error
+
+	recorder record.EventRecorder
+	// eventObject is the object the events are recorded for, usually the operator Pod. Events are not recorded if it is nil.
+	eventObject runtime.Object
+
+	// config is the running Configuration and data is the content of the file it was last checked against.
+	config configapi.Configuration
+	data   []byte
+}
+
+// NeedLeaderElection implements manager.LeaderElectionRunnable, so that every replica of the operator reloads the Configuration.
+func (w *configWatcher) NeedLeaderElection() bool {
+	return false
+}
+
+// Start implements manager.Runnable.
+func (w *configWatcher) Start(ctx context.Context) error {
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return err
+	}
+	defer watcher.Close()
+	if err := watcher.Add(filepath.Dir(w.path)); err != nil {
+		return err
+	}
+	w.log.Info("Watching the config file", "path", w.path)
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case event, ok := <-watcher.Events:
+			if !ok {
+				return nil
+			}
+			if event.Has(fsnotify.Chmod) {
+				continue
+			}
+			w.reload()
+		case err, ok := <-watcher.Errors:
+			if !ok {
+				return nil
+			}
+			w.log.Error(err, "Failed to watch the config file", "path", w.path)
+		}
+	}
+}
+
+// reload reads the config file and applies it if its content changed.
+func (w *configWatcher) reload() {
+	data, err := os.ReadFile(w.path)
+	if err != nil {
+		// The file can be missing for a moment while a ConfigMap is updated.
+		w.log.Info("Failed to read the config file", "path", w.path, "error", err.Error())
+		return
+	}
+	if bytes.Equal(data, w.data) {
+		return
+	}
+	w.data = data
+
+	config, err := decodeConfig(data, w.scheme)
+	if err == nil {
+		err = validateConfig(config)
+	}
+	if err != nil {
+		w.log.Error(err, "Ignoring the invalid config file", "path", w.path)
+		w.warn("InvalidConfiguration", "Ignoring the invalid config file %s: %v", w.path, err)
+		return
+	}
+
+	if changed := nonReloadableConfigChanges(w.config, config); len(changed) > 0 {
+		w.log.Info("The config file changed fields which require an operator restart", "path", w.path, "fields", changed)
+		w.warn("ConfigurationRestartRequired", "Restart the operator to apply the changes of the fields %s of the config file %s",
+			strings.Join(changed, ", "), w.path)
+	}
+	// Only the reloadable fields are applied, and the other fields keep their running values until a restart.
+	reloaded := withReloadableConfig(w.config, config)
+	if err := w.apply(reloaded); err != nil {
+		w.log.Error(err, "Failed to apply the config file", "path", w.path)
+		w.warn("InvalidConfiguration", "Failed to apply the config file %s: %v", w.path, err)
+		return
+	}
+	w.config = reloaded
+	w.log.Info("Reloaded the config file", "path", w.path)
+}
+
+func (w *configWatcher) warn(reason string, messageFmt string, args ...interface{}) {
+	if w.eventObject != nil {
+		w.recorder.Eventf(w.eventObject, corev1.EventTypeWarning, reason, messageFmt, args...)
+	}
+}
+
+// operatorPod returns the Pod of the operator, which is set with the downward API, or nil if it is unknown.
+func operatorPod() *corev1.Pod {
+	name, namespace := os.Getenv("POD_NAME"), os.Getenv("POD_NAMESPACE")
+	if name == "" || namespace == "" {
+		return nil
+	}
+	pod := &corev1.Pod{}
+	pod.Name = name
+	pod.Namespace = namespace
+	return pod
+}
+
+// validateConfig returns an error if a Configuration cannot be applied.
+func validateConfig(config configapi.Configuration) error {
+	if config.ReconcileConcurrency < 0 {
+		return fmt.Errorf("reconcileConcurrency must not be negative, got %d", config.ReconcileConcurrency)
+	}
+	if _, err := newFeatureGates(config); err != nil {
+		return err
+	}
+	if err := validateSidecarContainers("headSidecarContainers", config.HeadSidecarContainers); err != nil {
+		return err
+	}
+	if err := validateSidecarContainers("workerSidecarContainers", config.WorkerSidecarContainers); err != nil {
+		return err
+	}
+	if config.Requeue != nil {
+		if err := validateRequeueConfiguration(*config.Requeue); err != nil {
+			return err
+		}
+	}
+	if config.PodDefaults != nil {
+		for i, namespaceDefaults := range config.PodDefaults.Namespaces {
+			if namespaceDefaults.Namespace == "" {
+				return fmt.Errorf("podDefaults.namespaces[%d].namespace must not be empty", i)
+			}
+		}
+	}
+	return nil
+}
+
+func validateSidecarContainers(field string, containers []corev1.Container) error {
+	names := sets.New[string]()
+	for i, container := range containers {
+		if container.Name == "" {
+			return fmt.Errorf("%s[%d].name must not be empty", field, i)
+		}
+		if names.Has(container.Name) {
+			return fmt.Errorf("%s[%d].name %q is duplicated", field, i, container.Name)
+		}
+		names.Insert(container.Name)
+	}
+	return nil
+}
+
+func validateRequeueConfiguration(requeue configapi.RequeueConfiguration) error {
+	durations := map[string]*metav1.Duration{
+		"rayClusterInterval":            requeue.RayClusterInterval,
+		"rayClusterSteadyStateInterval": requeue.RayClusterSteadyStateInterval,
+		"rayServiceInterval":            requeue.RayServiceInterval,
+		"rayServiceSteadyStateInterval": requeue.RayServiceSteadyStateInterval,
+		"rayJobInterval":                requeue.RayJobInterval,
+		"rayJobSteadyStateInterval":     requeue.RayJobSteadyStateInterval,
+		"rayClusterDeletionDelay":       requeue.RayClusterDeletionDelay,
+		"errorBackoffBase":              requeue.ErrorBackoffBase,
+		"errorBackoffMax":               requeue.ErrorBackoffMax,
+	}
+	for field, duration := range durations {
+		if duration != nil && duration.Duration <= 0 {
+			return fmt.Errorf("requeue.%s must be positive, got %s", field, duration.Duration)
+		}
+	}
+	if requeue.ErrorBackoffBase != nil && requeue.ErrorBackoffMax != nil && requeue.ErrorBackoffMax.Duration < requeue.ErrorBackoffBase.Duration {
+		return fmt.Errorf("requeue.errorBackoffMax (%s) must not be less than requeue.errorBackoffBase (%s)",
+			requeue.ErrorBackoffMax.Duration, requeue.ErrorBackoffBase.Duration)
+	}
+	return nil
+}
+
+// nonReloadableConfigChanges returns the fields of the Configuration which changed but only take effect after a restart.
+func nonReloadableConfigChanges(oldConfig configapi.Configuration, newConfig configapi.Configuration) []string {
+	var changed []string
+	if oldConfig.MetricsAddr != newConfig.MetricsAddr {
+		changed = append(changed, "metricsAddr")
+	}
+	if oldConfig.ProbeAddr != newConfig.ProbeAddr {
+		changed = append(changed, "probeAddr")
+	}
+	if pointer.BoolDeref(oldConfig.EnableLeaderElection, configapi.DefaultEnableLeaderElection) !=
+		pointer.BoolDeref(newConfig.EnableLeaderElection, configapi.DefaultEnableLeaderElection) {
+		changed = append(changed, "enableLeaderElection")
+	}
+	if oldConfig.LeaderElectionNamespace != newConfig.LeaderElectionNamespace {
+		changed = append(changed, "leaderElectionNamespace")
+	}
+	if oldConfig.ReconcileConcurrency != newConfig.ReconcileConcurrency {
+		changed = append(changed, "reconcileConcurrency")
+	}
+	if oldConfig.WatchNamespace != newConfig.WatchNamespace {
+		changed = append(changed, "watchNamespace")
+	}
+	if oldConfig.LogFile != newConfig.LogFile {
+		changed = append(changed, "logFile")
+	}
+	// The error backoff is part of the rate limiters of the controllers, which are created when the operator starts.
+	oldRequeue, newRequeue := requeueConfiguration(oldConfig), requeueConfiguration(newConfig)
+	if durationValue(oldRequeue.ErrorBackoffBase) != durationValue(newRequeue.ErrorBackoffBase) {
+		changed = append(changed, "requeue.errorBackoffBase")
+	}
+	if durationValue(oldRequeue.ErrorBackoffMax) != durationValue(newRequeue.ErrorBackoffMax) {
+		changed = append(changed, "requeue.errorBackoffMax")
+	}
+	// The RayCluster controller only watches PodGroups if the BatchScheduler feature gate is enabled when it starts.
+	if isBatchSchedulerEnabled(oldConfig) != isBatchSchedulerEnabled(newConfig) {
+		changed = append(changed, fmt.Sprintf("featureGates.%s", features.BatchScheduler))
+	}
+	return changed
+}
+
+// isBatchSchedulerEnabled reports whether a Configuration enables the BatchScheduler feature gate, which has no
+// deprecated environment variable.
+func isBatchSchedulerEnabled(config configapi.Configuration) bool {
+	if enabled, ok := config.FeatureGates[string(features.BatchScheduler)]; ok {
+		return enabled
+	}
+	return config.EnableBatchScheduler || (*features.FeatureGates)(nil).Enabled(features.BatchScheduler)
+}
+
+// withReloadableConfig returns the running Configuration with the reloadable fields of the new Configuration.
+func withReloadableConfig(running configapi.Configuration, newConfig configapi.Configuration) configapi.Configuration {
+	running.HeadSidecarContainers = newConfig.HeadSidecarContainers
+	running.WorkerSidecarContainers = newConfig.WorkerSidecarContainers
+	running.PodDefaults = newConfig.PodDefaults
+	running.ForcedClusterUpgrade = newConfig.ForcedClusterUpgrade
+	// Keep the running BatchScheduler feature gate, which is not reloadable. The deprecated EnableBatchScheduler
+	// field is not reloaded either.
+	batchSchedulerEnabled := isBatchSchedulerEnabled(running)
+	running.FeatureGates = make(map[string]bool, len(newConfig.FeatureGates)+1)
+	for name, enabled := range newConfig.FeatureGates {
+		running.FeatureGates[name] = enabled
+	}
+	running.FeatureGates[string(features.BatchScheduler)] = batchSchedulerEnabled
+	// Keep the running error backoff, which is not reloadable.
+	runningRequeue, requeue := requeueConfiguration(running), requeueConfiguration(newConfig)
+	requeue.ErrorBackoffBase = runningRequeue.ErrorBackoffBase
+	requeue.ErrorBackoffMax = runningRequeue.ErrorBackoffMax
+	running.Requeue = &requeue
+	return running
+}
@@ -0,0 +1,327 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/pointer"
+
+	configapi "github.com/ray-project/kuberay/ray-operator/apis/config/v1alpha1"
+)
+
+const testConfigData = `apiVersion: config.ray.io/v1alpha1
+kind: Configuration
+headSidecarContainers:
+- name: fluentbit
+  image: fluent/fluent-bit:1.9.6
+`
+
+func newTestConfigWatcher(t *testing.T, configData string) (*configWatcher, *record.FakeRecorder, *[]configapi.Configuration) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	if err := os.WriteFile(path, []byte(configData), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	config, err := decodeConfig([]byte(configData), scheme)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pod := &corev1.Pod{}
+	pod.Name = "kuberay-operator"
+	pod.Namespace = "default"
+	recorder := record.NewFakeRecorder(10)
+	applied := &[]configapi.Configuration{}
+	watcher := &configWatcher{
+		path:   path,
+		scheme: scheme,
+		log:    logr.Discard(),
+		apply: func(config configapi.Configuration) error {
+			*applied = append(*applied, config)
+			return nil
+		},
+		recorder:    recorder,
+		eventObject: pod,
+		config:      config,
+		data:        []byte(configData),
+	}
+	return watcher, recorder, applied
+}
+
+func writeTestConfig(t *testing.T, watcher *configWatcher, configData string) {
+	if err := os.WriteFile(watcher.path, []byte(configData), 0o600); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func expectEvent(t *testing.T, recorder *record.FakeRecorder, contains ...string) {
+	select {
+	case event := <-recorder.Events:
+		for _, s := range contains {
+			if !strings.Contains(event, s) {
+				t.Errorf("expected event %q to contain %q", event, s)
+			}
+		}
+	default:
+		t.Errorf("expected an event containing %v", contains)
+	}
+}
+
+func expectNoEvent(t *testing.T, recorder *record.FakeRecorder) {
+	select {
+	case event := <-recorder.Events:
+		t.Errorf("unexpected event %q", event)
+	default:
+	}
+}
+
+func TestConfigWatcherReload(t *testing.T) {
+	watcher, recorder, applied := newTestConfigWatcher(t, testConfigData)
+
+	// The content of the file did not change.
+	watcher.reload()
+	if len(*applied) != 0 {
+		t.Fatalf("expected no reload, got %d", len(*applied))
+	}
+
+	// Reloadable fields are applied without an event.
+	writeTestConfig(t, watcher, testConfigData+`featureGates:
+  RandomPodDelete: true
+workerSidecarContainers:
+- name: fluentbit
+  image: fluent/fluent-bit:1.9.6
+`)
+	watcher.reload()
+	if len(*applied) != 1 {
+		t.Fatalf("expected 1 reload, got %d", len(*applied))
+	}
+	if len((*applied)[0].WorkerSidecarContainers) != 1 || !(*applied)[0].FeatureGates["RandomPodDelete"] {
+		t.Errorf("unexpected applied config %v", (*applied)[0])
+	}
+	if len(watcher.config.WorkerSidecarContainers) != 1 {
+		t.Errorf("expected the running config to be updated, got %v", watcher.config)
+	}
+	expectNoEvent(t, recorder)
+
+	// Non-reloadable fields are reported, and the reloadable fields are still applied.
+	writeTestConfig(t, watcher, testConfigData+`reconcileConcurrency: 10
+podDefaults:
+  worker:
+    imageRegistry: registry.example.com
+`)
+	watcher.reload()
+	if len(*applied) != 2 || (*applied)[1].PodDefaults == nil {
+		t.Fatalf("expected the pod defaults to be applied, got %v", *applied)
+	}
+	if watcher.config.ReconcileConcurrency != 1 {
+		t.Errorf("expected the running reconcileConcurrency to be unchanged, got %d", watcher.config.ReconcileConcurrency)
+	}
+	expectEvent(t, recorder, corev1.EventTypeWarning, "ConfigurationRestartRequired", "reconcileConcurrency")
+
+	// The BatchScheduler feature gate is not reloadable, so it keeps its running value, and the restart is still
+	// required after the following reloads.
+	writeTestConfig(t, watcher, testConfigData+`featureGates:
+  BatchScheduler: true
+`)
+	watcher.reload()
+	if len(*applied) != 3 || isBatchSchedulerEnabled((*applied)[2]) || isBatchSchedulerEnabled(watcher.config) {
+		t.Fatalf("expected the BatchScheduler feature gate to stay disabled, got %v", *applied)
+	}
+	expectEvent(t, recorder, corev1.EventTypeWarning, "ConfigurationRestartRequired", "featureGates.BatchScheduler")
+	writeTestConfig(t, watcher, testConfigData+`enableBatchScheduler: true
+featureGates:
+  RandomPodDelete: true
+`)
+	watcher.reload()
+	if len(*applied) != 4 || isBatchSchedulerEnabled((*applied)[3]) || !(*applied)[3].FeatureGates["RandomPodDelete"] {
+		t.Fatalf("expected only RandomPodDelete to be reloaded, got %v", *applied)
+	}
+	expectEvent(t, recorder, corev1.EventTypeWarning, "ConfigurationRestartRequired", "featureGates.BatchScheduler")
+
+	// Invalid configs are not applied.
+	writeTestConfig(t, watcher, testConfigData+`featureGates:
+  UnknownFeature: true
+`)
+	watcher.reload()
+	if len(*applied) != 4 {
+		t.Fatalf("expected the invalid config not to be applied, got %d reloads", len(*applied))
+	}
+	expectEvent(t, recorder, corev1.EventTypeWarning, "InvalidConfiguration", "UnknownFeature")
+
+	// A missing file is ignored.
+	if err := os.Remove(watcher.path); err != nil {
+		t.Fatal(err)
+	}
+	watcher.reload()
+	if len(*applied) != 4 {
+		t.Fatalf("expected a missing file to be ignored, got %d reloads", len(*applied))
+	}
+	expectNoEvent(t, recorder)
+}
+
+func TestConfigWatcherStart(t *testing.T) {
+	watcher, _, _ := newTestConfigWatcher(t, testConfigData)
+	reloaded := make(chan configapi.Configuration, 10)
+	watcher.apply = func(config configapi.Configuration) error {
+		reloaded <- config
+		return nil
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	done := make(chan error)
+	go func() {
+		done <- watcher.Start(ctx)
+	}()
+
+	// Wait for the watcher to start watching, then update the file as kubelet updates a mounted ConfigMap,
+	// by renaming a new file over it.
+	deadline := time.After(10 * time.Second)
+	newConfigData := testConfigData + "featureGates:\n  RandomPodDelete: true\n"
+	tmp := filepath.Join(filepath.Dir(watcher.path), "config.yaml.tmp")
+	ticker := time.NewTicker(100 * time.Millisecond)
+	defer ticker.Stop()
+	for i, reloadedConfig := 0, (configapi.Configuration{}); !reloadedConfig.FeatureGates["RandomPodDelete"]; {
+		select {
+		case reloadedConfig = <-reloaded:
+		case <-ticker.C:
+			// Change the content on every write, in case the watcher read the file before it started watching.
+			i++
+			if err := os.WriteFile(tmp, []byte(fmt.Sprintf("%s# %d\n", newConfigData, i)), 0o600); err != nil {
+				t.Fatal(err)
+			}
+			if err := os.Rename(tmp, watcher.path); err != nil {
+				t.Fatal(err)
+			}
+		case <-deadline:
+			t.Fatal("timed out waiting for the config file to be reloaded")
+		}
+	}
+
+	cancel()
+	if err := <-done; err != nil {
+		t.Errorf("expected no error, got %v", err)
+	}
+}
+
+func Test_validateConfig(t *testing.T) {
+	testcases := []struct {
+		name        string
+		config      configapi.Configuration
+		errContains string
+	}{
+		{
+			name:   "valid config",
+			config: configapi.Configuration{HeadSidecarContainers: []corev1.Container{{Name: "a"}, {Name: "b"}}},
+		},
+		{
+			name:        "negative reconcile concurrency",
+			config:      configapi.Configuration{ReconcileConcurrency: -1},
+			errContains: "reconcileConcurrency",
+		},
+		{
+			name:        "unknown feature gate",
+			config:      configapi.Configuration{FeatureGates: map[string]bool{"UnknownFeature": true}},
+			errContains: "unknown feature gate",
+		},
+		{
+			name:        "sidecar container without name",
+			config:      configapi.Configuration{WorkerSidecarContainers: []corev1.Container{{Image: "busybox"}}},
+			errContains: "workerSidecarContainers[0].name",
+		},
+		{
+			name:        "duplicated sidecar container names",
+			config:      configapi.Configuration{HeadSidecarContainers: []corev1.Container{{Name: "a"}, {Name: "a"}}},
+			errContains: "duplicated",
+		},
+		{
+			name: "negative requeue interval",
+			config: configapi.Configuration{Requeue: &configapi.RequeueConfiguration{
+				RayJobInterval: &metav1.Duration{Duration: -time.Second},
+			}},
+			errContains: "requeue.rayJobInterval",
+		},
+		{
+			name: "error backoff max less than base",
+			config: configapi.Configuration{Requeue: &configapi.RequeueConfiguration{
+				ErrorBackoffBase: &metav1.Duration{Duration: time.Minute},
+				ErrorBackoffMax:  &metav1.Duration{Duration: time.Second},
+			}},
+			errContains: "requeue.errorBackoffMax",
+		},
+		{
+			name: "pod defaults without namespace",
+			config: configapi.Configuration{PodDefaults: &configapi.PodDefaults{
+				Namespaces: []configapi.NamespacePodDefaults{{Namespace: ""}},
+			}},
+			errContains: "podDefaults.namespaces[0].namespace",
+		},
+	}
+
+	for _, testcase := range testcases {
+		t.Run(testcase.name, func(t *testing.T) {
+			err := validateConfig(testcase.config)
+			if testcase.errContains == "" {
+				if err != nil {
+					t.Errorf("expected no error, got %v", err)
+				}
+			} else if err == nil || !strings.Contains(err.Error(), testcase.errContains) {
+				t.Errorf("expected error containing %q, got %v", testcase.errContains, err)
+			}
+		})
+	}
+}
+
+func Test_nonReloadableConfigChanges(t *testing.T) {
+	oldConfig := configapi.Configuration{
+		MetricsAddr:          ":8080",
+		EnableLeaderElection: pointer.Bool(true),
+		ReconcileConcurrency: 1,
+	}
+	newConfig := *oldConfig.DeepCopy()
+	newConfig.HeadSidecarContainers = []corev1.Container{{Name: "a"}}
+	newConfig.FeatureGates = map[string]bool{"RandomPodDelete": true}
+	if changed := nonReloadableConfigChanges(oldConfig, newConfig); len(changed) != 0 {
+		t.Errorf("expected no changes, got %v", changed)
+	}
+
+	newConfig.MetricsAddr = ":9090"
+	newConfig.EnableLeaderElection = pointer.Bool(false)
+	newConfig.WatchNamespace = "default"
+	newConfig.EnableBatchScheduler = true
+	expected := []string{"metricsAddr", "enableLeaderElection", "watchNamespace", "featureGates.BatchScheduler"}
+	if changed := nonReloadableConfigChanges(oldConfig, newConfig); !reflect.DeepEqual(changed, expected) {
+		t.Errorf("expected %v, got %v", expected, changed)
+	}
+
+	// The requeue intervals are reloadable, but the error backoff is not.
+	newConfig = *oldConfig.DeepCopy()
+	newConfig.Requeue = &configapi.RequeueConfiguration{
+		RayServiceSteadyStateInterval: &metav1.Duration{Duration: time.Minute},
+		ErrorBackoffMax:               &metav1.Duration{Duration: time.Minute},
+	}
+	expected = []string{"requeue.errorBackoffMax"}
+	if changed := nonReloadableConfigChanges(oldConfig, newConfig); !reflect.DeepEqual(changed, expected) {
+		t.Errorf("expected %v, got %v", expected, changed)
+	}
+	running := withReloadableConfig(oldConfig, newConfig)
+	if running.Requeue.RayServiceSteadyStateInterval == nil || running.Requeue.ErrorBackoffMax != nil {
+		t.Errorf("expected only the requeue intervals to be reloaded, got %v", running.Requeue)
+	}
+
+	// The BatchScheduler feature gate takes precedence over the deprecated field.
+	newConfig = *oldConfig.DeepCopy()
+	newConfig.EnableBatchScheduler = true
+	newConfig.FeatureGates = map[string]bool{"BatchScheduler": false}
+	if changed := nonReloadableConfigChanges(oldConfig, newConfig); len(changed) != 0 {
+		t.Errorf("expected no changes, got %v", changed)
+	}
+}
@@ -25,6 +25,23 @@ type BatchScheduler interface {
 	AddMetadataToPod(app *rayv1.RayCluster, groupName string, pod *corev1.Pod)
 }
 
+// AdmissionBatchScheduler is implemented by the batch schedulers which admit a RayCluster or a RayJob as a whole
+// before any of its Pods is created, for example Kueue. They hold back a RayCluster or a RayJob which is not admitted
+// yet by setting its `suspend` field.
+type AdmissionBatchScheduler interface {
+	BatchScheduler
+
+	// AdmitRayCluster submits the RayCluster to the batch scheduler, and sets its labels and its `suspend` field
+	// according to the admission. The caller updates the RayCluster if they changed.
+	// It returns whether the RayCluster is admitted.
+	AdmitRayCluster(ctx context.Context, app *rayv1.RayCluster) (bool, error)
+
+	// AdmitRayJob submits the RayJob, which is admitted together with the RayCluster it creates, to the batch scheduler,
+	// and sets its labels and its `suspend` field according to the admission. The caller updates the RayJob if they
+	// changed. It returns whether the RayJob is admitted.
+	AdmitRayJob(ctx context.Context, rayJob *rayv1.RayJob) (bool, error)
+}
+
 // BatchSchedulerFactory handles initial setup of the scheduler plugin by registering the
 // necessary callbacks with the operator, and the creation of the BatchScheduler itself.
 type BatchSchedulerFactory interface {
@@ -36,7 +53,8 @@ type BatchSchedulerFactory interface {
 
 	// ConfigureReconciler configures the RayCluster Reconciler in the process of being built by
 	// adding watches for its scheduler-specific custom resource types, and any other needed setup.
-	ConfigureReconciler(b *builder.Builder) *builder.Builder
+	// The config is the one of the manager, which can be used to check whether those types are installed.
+	ConfigureReconciler(b *builder.Builder, config *rest.Config) (*builder.Builder, error)
 }
 
 type DefaultBatchScheduler struct{}
@@ -65,6 +83,6 @@ func (df *DefaultBatchSchedulerFactory) New(config *rest.Config) (BatchScheduler
 func (df *DefaultBatchSchedulerFactory) AddToScheme(scheme *runtime.Scheme) {
 }
 
-func (df *DefaultBatchSchedulerFactory) ConfigureReconciler(b *builder.Builder) *builder.Builder {
-	return b
+func (df *DefaultBatchSchedulerFactory) ConfigureReconciler(b *builder.Builder, config *rest.Config) (*builder.Builder, error) {
+	return b, nil
 }
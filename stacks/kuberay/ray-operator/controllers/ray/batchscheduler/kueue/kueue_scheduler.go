@@ -0,0 +1,531 @@
+package kueue
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	apiextensionsclient "k8s.io/apiextensions-apiserver/pkg/client/clientset/clientset"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/client-go/rest"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	rayv1 "github.com/ray-project/kuberay/ray-operator/apis/ray/v1"
+	schedulerinterface "github.com/ray-project/kuberay/ray-operator/controllers/ray/batchscheduler/interface"
+	"github.com/ray-project/kuberay/ray-operator/controllers/ray/utils"
+)
+
+const (
+	WorkloadCRDName = "workloads.kueue.x-k8s.io"
+	// QueueNameLabelKey is the label of a RayCluster or a RayJob with the name of the Kueue LocalQueue it is submitted to.
+	QueueNameLabelKey = "kueue.x-k8s.io/queue-name"
+	// SuspendedByKueueAnnotationKey marks a RayCluster or a RayJob which was suspended because its Workload is not
+	// admitted. Only the RayClusters and RayJobs with this annotation are resumed once their Workload is admitted.
+	SuspendedByKueueAnnotationKey = "ray.io/suspended-by-kueue"
+
+	// The name of the PodSet of the head Pod. The PodSets of the worker groups are named after the groups.
+	headPodSetName = "head"
+	// The name of the single container of a PodSet template, which requests the resources of a Pod.
+	podSetContainerName = "ray"
+	// The maximum number of PodSets of a Workload allowed by Kueue.
+	maxPodSets = 8
+
+	// The Workload conditions set by Kueue, and the Finished condition set by the owner of a Workload.
+	workloadQuotaReserved = "QuotaReserved"
+	workloadAdmitted      = "Admitted"
+	workloadEvicted       = "Evicted"
+	workloadFinished      = "Finished"
+)
+
+// WorkloadGroupVersionKind is the kind of the Kueue Workloads. Workloads are handled as unstructured objects, so the
+// operator does not depend on the Kueue API module.
+var WorkloadGroupVersionKind = schema.GroupVersionKind{Group: "kueue.x-k8s.io", Version: "v1beta1", Kind: "Workload"}
+
+// KueueBatchScheduler submits a RayCluster or a RayJob to Kueue as a single Workload with a PodSet for the head Pod and
+// one for each worker group. The RayCluster or the RayJob is suspended until Kueue admits the Workload, and suspended again
+// if Kueue evicts it. A RayCluster or a RayJob suspended by the user stays suspended, and its Workload is deactivated
+// so that Kueue releases its quota.
+type KueueBatchScheduler struct {
+	client client.Client
+	log    logr.Logger
+}
+
+type KueueBatchSchedulerFactory struct{}
+
+func GetPluginName() string {
+	return "kueue"
+}
+
+func (k *KueueBatchScheduler) Name() string {
+	return GetPluginName()
+}
+
+// DoBatchSchedulingOnSubmission does nothing, because the RayCluster was submitted by AdmitRayCluster before.
+func (k *KueueBatchScheduler) DoBatchSchedulingOnSubmission(ctx context.Context, app *rayv1.RayCluster) error {
+	return nil
+}
+
+// AddMetadataToPod does not add the queue label to the Pods, because Kueue would admit the Pods again if its Pod
+// integration is enabled. The Pods are scheduled by the default scheduler once the Workload is admitted.
+func (k *KueueBatchScheduler) AddMetadataToPod(app *rayv1.RayCluster, groupName string, pod *corev1.Pod) {
+}
+
+func (k *KueueBatchScheduler) AdmitRayCluster(ctx context.Context, app *rayv1.RayCluster) (bool, error) {
+	// The RayCluster of a RayJob is admitted with the RayJob, which is only resumed after it was admitted.
+	if app.Labels[utils.RayOriginatedFromCRDLabelKey] == utils.RayOriginatedFromCRDLabelValue(utils.RayJobCRD) {
+		return true, nil
+	}
+	if app.Spec.AutoSuspend != nil {
+		return false, fmt.Errorf("the RayCluster %s/%s cannot use autoSuspend with the %s batch scheduler, which suspends and resumes it", app.Namespace, app.Name, k.Name())
+	}
+
+	if err := validateQueueName(app, "RayCluster"); err != nil {
+		return false, err
+	}
+	podSets, err := getPodSets(ctx, &app.Spec)
+	if err != nil {
+		return false, err
+	}
+	suspend := app.Spec.Suspend != nil && *app.Spec.Suspend
+	active := !isSuspendedByUser(&app.ObjectMeta, suspend)
+	workload, err := k.syncWorkload(ctx, app, rayv1.SchemeGroupVersion.WithKind("RayCluster"), podSets, active)
+	if err != nil {
+		return false, err
+	}
+	suspended := app.Status.State == rayv1.Suspended
+	admitted, err := k.reconcileAdmission(ctx, workload, suspended)
+	if err != nil {
+		return false, err
+	}
+	if newSuspend := reconcileSuspend(&app.ObjectMeta, suspend, admitted); newSuspend != suspend {
+		app.Spec.Suspend = &newSuspend
+	}
+	return admitted, nil
+}
+
+func (k *KueueBatchScheduler) AdmitRayJob(ctx context.Context, rayJob *rayv1.RayJob) (bool, error) {
+	// A RayJob which uses an existing RayCluster does not create Pods to admit.
+	if len(rayJob.Spec.ClusterSelector) != 0 {
+		return true, nil
+	}
+	if rayJob.Spec.RayClusterSpec == nil {
+		return false, fmt.Errorf("the RayJob %s/%s has no rayClusterSpec", rayJob.Namespace, rayJob.Name)
+	}
+	if !rayJob.Spec.ShutdownAfterJobFinishes {
+		return false, fmt.Errorf("the RayJob %s/%s must set shutdownAfterJobFinishes to be admitted by the %s batch scheduler, "+
+			"because it is suspended until it is admitted", rayJob.Namespace, rayJob.Name, k.Name())
+	}
+
+	if err := validateQueueName(rayJob, "RayJob"); err != nil {
+		return false, err
+	}
+	podSets, err := getPodSets(ctx, rayJob.Spec.RayClusterSpec)
+	if err != nil {
+		return false, err
+	}
+	active := !isSuspendedByUser(&rayJob.ObjectMeta, rayJob.Spec.Suspend)
+	workload, err := k.syncWorkload(ctx, rayJob, rayv1.SchemeGroupVersion.WithKind("RayJob"), podSets, active)
+	if err != nil {
+		return false, err
+	}
+
+	// The quota of a finished RayJob is released, and the RayJob is not suspended anymore.
+	switch rayJob.Status.JobDeploymentStatus {
+	case rayv1.JobDeploymentStatusComplete, rayv1.JobDeploymentStatusFailed:
+		return true, k.finishWorkload(ctx, workload, fmt.Sprintf("The RayJob is %s", rayJob.Status.JobDeploymentStatus))
+	}
+
+	suspended := rayJob.Status.JobDeploymentStatus == rayv1.JobDeploymentStatusSuspended
+	admitted, err := k.reconcileAdmission(ctx, workload, suspended)
+	if err != nil {
+		return false, err
+	}
+	rayJob.Spec.Suspend = reconcileSuspend(&rayJob.ObjectMeta, rayJob.Spec.Suspend, admitted)
+	return admitted, nil
+}
+
+// isSuspendedByUser returns whether a RayCluster or a RayJob is suspended, but not because its Workload is not admitted.
+func isSuspendedByUser(object *metav1.ObjectMeta, suspend bool) bool {
+	_, suspendedByKueue := object.Annotations[SuspendedByKueueAnnotationKey]
+	return suspend && !suspendedByKueue
+}
+
+// reconcileSuspend returns whether a RayCluster or a RayJob must be suspended. It is suspended while its Workload is
+// not admitted, and only resumed after the admission if it was suspended for the Workload, so that a suspension by the
+// user is kept.
+func reconcileSuspend(object *metav1.ObjectMeta, suspend bool, admitted bool) bool {
+	_, suspendedByKueue := object.Annotations[SuspendedByKueueAnnotationKey]
+	if !admitted {
+		if !suspend {
+			metav1.SetMetaDataAnnotation(object, SuspendedByKueueAnnotationKey, "true")
+		}
+		return true
+	}
+	if suspendedByKueue {
+		delete(object.Annotations, SuspendedByKueueAnnotationKey)
+		return false
+	}
+	return suspend
+}
+
+// validateQueueName returns an error if a RayCluster or a RayJob has no LocalQueue. It is not submitted to a default
+// LocalQueue, which may belong to another team or not exist at all.
+func validateQueueName(owner client.Object, kind string) error {
+	if owner.GetLabels()[QueueNameLabelKey] == "" {
+		return fmt.Errorf("the %s %s/%s must set the %s label to the LocalQueue it is submitted to by the %s batch scheduler",
+			kind, owner.GetNamespace(), owner.GetName(), QueueNameLabelKey, GetPluginName())
+	}
+	return nil
+}
+
+// podSet is a PodSet of a Workload, which requests the resources of count Pods with the same template.
+type podSet struct {
+	name     string
+	count    int32
+	template corev1.PodTemplateSpec
+}
+
+// getPodSets returns the PodSets of a RayCluster: one for the head Pod and one for each worker group with the desired
+// number of Pods of the group. A worker group without Pods has no PodSet, because it does not need quota.
+func getPodSets(ctx context.Context, spec *rayv1.RayClusterSpec) ([]podSet, error) {
+	podSets := []podSet{{name: headPodSetName, count: 1, template: newPodSetTemplate(spec.HeadGroupSpec.Template)}}
+	for _, workerGroup := range spec.WorkerGroupSpecs {
+		count := utils.GetWorkerGroupDesiredReplicas(ctx, workerGroup) * utils.GetWorkerGroupNumOfHosts(workerGroup)
+		if count == 0 {
+			continue
+		}
+		if workerGroup.GroupName == headPodSetName {
+			return nil, fmt.Errorf("the worker group %q cannot be submitted to Kueue, because its name is used by the PodSet of the head Pod", workerGroup.GroupName)
+		}
+		if errs := validation.IsDNS1123Label(workerGroup.GroupName); len(errs) > 0 {
+			return nil, fmt.Errorf("the worker group %q cannot be submitted to Kueue, because it is not a valid PodSet name: %s",
+				workerGroup.GroupName, strings.Join(errs, ", "))
+		}
+		podSets = append(podSets, podSet{name: workerGroup.GroupName, count: count, template: newPodSetTemplate(workerGroup.Template)})
+	}
+	if len(podSets) > maxPodSets {
+		return nil, fmt.Errorf("a Kueue Workload can have at most %d PodSets, one for the head Pod and one for each worker group with Pods, but got %d",
+			maxPodSets, len(podSets))
+	}
+	return podSets, nil
+}
+
+// newPodSetTemplate returns the template of a PodSet, which keeps the scheduling constraints of the Pod template that
+// Kueue uses to assign the resource flavors, and a single container which requests the resources of a Pod.
+func newPodSetTemplate(template corev1.PodTemplateSpec) corev1.PodTemplateSpec {
+	return corev1.PodTemplateSpec{
+		Spec: corev1.PodSpec{
+			NodeSelector: template.Spec.NodeSelector,
+			Affinity:     template.Spec.Affinity,
+			Tolerations:  template.Spec.Tolerations,
+			Containers: []corev1.Container{
+				{
+					Name:      podSetContainerName,
+					Resources: corev1.ResourceRequirements{Requests: utils.CalculatePodResource(template.Spec)},
+				},
+			},
+		},
+	}
+}
+
+// GetWorkloadName returns the name of the Workload of a RayCluster or a RayJob.
+func GetWorkloadName(kind string, name string) string {
+	return fmt.Sprintf("%s-%s", strings.ToLower(kind), name)
+}
+
+// syncWorkload creates the Workload of the owner, or updates whether it is active, and its queue and PodSets if it is
+// not admitted yet. Kueue does not allow updating the PodSets of an admitted Workload.
+func (k *KueueBatchScheduler) syncWorkload(ctx context.Context, owner client.Object, ownerKind schema.GroupVersionKind, podSets []podSet, active bool) (*unstructured.Unstructured, error) {
+	desired, err := createWorkload(owner, ownerKind, podSets, active)
+	if err != nil {
+		return nil, err
+	}
+	workload := &unstructured.Unstructured{}
+	workload.SetGroupVersionKind(WorkloadGroupVersionKind)
+	if err := k.client.Get(ctx, client.ObjectKeyFromObject(desired), workload); err != nil {
+		if !errors.IsNotFound(err) {
+			return nil, err
+		}
+		if err := k.client.Create(ctx, desired); err != nil {
+			k.log.Error(err, "Workload CREATE error!", "workload", desired.GetName())
+			return nil, err
+		}
+		k.log.Info("Created Workload", "workload", desired.GetName(), "queue", owner.GetLabels()[QueueNameLabelKey])
+		return desired, nil
+	}
+
+	update := false
+	// Kueue evicts an inactive Workload, which releases its quota once the owner is suspended.
+	if isWorkloadActive(workload) != active {
+		if err := unstructured.SetNestedField(workload.Object, active, "spec", "active"); err != nil {
+			return nil, err
+		}
+		update = true
+	}
+	if !isConditionTrue(workload, workloadQuotaReserved) && !isConditionTrue(workload, workloadAdmitted) {
+		queueName, _, _ := unstructured.NestedString(workload.Object, "spec", "queueName")
+		existing, err := getWorkloadPodSets(workload)
+		if err != nil {
+			return nil, err
+		}
+		if queueName != owner.GetLabels()[QueueNameLabelKey] || !equalPodSets(existing, podSets) {
+			workload.Object["spec"] = desired.Object["spec"]
+			update = true
+		}
+	}
+	if update {
+		if err := k.client.Update(ctx, workload); err != nil {
+			k.log.Error(err, "Workload UPDATE error!", "workload", workload.GetName())
+			return nil, err
+		}
+	}
+	return workload, nil
+}
+
+func createWorkload(owner client.Object, ownerKind schema.GroupVersionKind, podSets []podSet, active bool) (*unstructured.Unstructured, error) {
+	podSetObjects := make([]interface{}, 0, len(podSets))
+	for i := range podSets {
+		templateObject, err := runtime.DefaultUnstructuredConverter.ToUnstructured(&podSets[i].template)
+		if err != nil {
+			return nil, err
+		}
+		// The empty metadata of the template is converted with a null creationTimestamp, which the Workload CRD rejects.
+		delete(templateObject, "metadata")
+		podSetObjects = append(podSetObjects, map[string]interface{}{
+			"name":     podSets[i].name,
+			"count":    int64(podSets[i].count),
+			"template": templateObject,
+		})
+	}
+
+	workload := &unstructured.Unstructured{}
+	workload.SetGroupVersionKind(WorkloadGroupVersionKind)
+	workload.SetNamespace(owner.GetNamespace())
+	workload.SetName(GetWorkloadName(ownerKind.Kind, owner.GetName()))
+	workload.SetLabels(map[string]string{
+		utils.RayOriginatedFromCRNameLabelKey: owner.GetName(),
+		utils.RayOriginatedFromCRDLabelKey:    utils.RayOriginatedFromCRDLabelValue(utils.CRDType(ownerKind.Kind)),
+	})
+	workload.SetOwnerReferences([]metav1.OwnerReference{*metav1.NewControllerRef(owner, ownerKind)})
+	workload.Object["spec"] = map[string]interface{}{
+		"queueName": owner.GetLabels()[QueueNameLabelKey],
+		"active":    active,
+		"podSets":   podSetObjects,
+	}
+	return workload, nil
+}
+
+// isWorkloadActive returns whether Kueue may admit a Workload. A Workload is active unless `spec.active` is false.
+func isWorkloadActive(workload *unstructured.Unstructured) bool {
+	active, found, err := unstructured.NestedBool(workload.Object, "spec", "active")
+	return err != nil || !found || active
+}
+
+// getWorkloadPodSets returns the PodSets of a Workload.
+func getWorkloadPodSets(workload *unstructured.Unstructured) ([]podSet, error) {
+	podSetObjects, _, err := unstructured.NestedSlice(workload.Object, "spec", "podSets")
+	if err != nil {
+		return nil, err
+	}
+	podSets := make([]podSet, 0, len(podSetObjects))
+	for _, podSetObject := range podSetObjects {
+		object, ok := podSetObject.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		name, _, _ := unstructured.NestedString(object, "name")
+		count, _, _ := unstructured.NestedInt64(object, "count")
+		templateObject, _, err := unstructured.NestedMap(object, "template")
+		if err != nil {
+			return nil, err
+		}
+		template := corev1.PodTemplateSpec{}
+		if err := runtime.DefaultUnstructuredConverter.FromUnstructured(templateObject, &template); err != nil {
+			return nil, err
+		}
+		podSets = append(podSets, podSet{name: name, count: int32(count), template: template})
+	}
+	return podSets, nil
+}
+
+// equalPodSets returns whether two lists of PodSets have the same names, counts, and templates.
+func equalPodSets(a []podSet, b []podSet) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i].name != b[i].name || a[i].count != b[i].count || !equality.Semantic.DeepEqual(a[i].template, b[i].template) {
+			return false
+		}
+	}
+	return true
+}
+
+// reconcileAdmission returns whether the Workload is admitted. After Kueue evicts a Workload, its owner releases the
+// quota reservation once the owner is suspended, so that Kueue can admit the Workload again.
+func (k *KueueBatchScheduler) reconcileAdmission(ctx context.Context, workload *unstructured.Unstructured, suspended bool) (bool, error) {
+	if !isConditionTrue(workload, workloadEvicted) {
+		return isConditionTrue(workload, workloadAdmitted), nil
+	}
+	if !suspended || !isConditionTrue(workload, workloadQuotaReserved) {
+		return false, nil
+	}
+
+	conditions, err := getConditions(workload)
+	if err != nil {
+		return false, err
+	}
+	evicted := meta.FindStatusCondition(conditions, workloadEvicted)
+	for _, conditionType := range []string{workloadQuotaReserved, workloadAdmitted} {
+		meta.SetStatusCondition(&conditions, metav1.Condition{
+			Type:    conditionType,
+			Status:  metav1.ConditionFalse,
+			Reason:  "Pending",
+			Message: evicted.Message,
+		})
+	}
+	unstructured.RemoveNestedField(workload.Object, "status", "admission")
+	if err := setConditions(workload, conditions); err != nil {
+		return false, err
+	}
+	if err := k.client.Status().Update(ctx, workload); err != nil {
+		k.log.Error(err, "Workload status UPDATE error!", "workload", workload.GetName())
+		return false, err
+	}
+	k.log.Info("Released the quota reservation of the evicted Workload", "workload", workload.GetName())
+	return false, nil
+}
+
+// finishWorkload marks a Workload as finished, so that Kueue releases its quota.
+func (k *KueueBatchScheduler) finishWorkload(ctx context.Context, workload *unstructured.Unstructured, message string) error {
+	if isConditionTrue(workload, workloadFinished) {
+		return nil
+	}
+	conditions, err := getConditions(workload)
+	if err != nil {
+		return err
+	}
+	meta.SetStatusCondition(&conditions, metav1.Condition{
+		Type:    workloadFinished,
+		Status:  metav1.ConditionTrue,
+		Reason:  "JobFinished",
+		Message: message,
+	})
+	if err := setConditions(workload, conditions); err != nil {
+		return err
+	}
+	if err := k.client.Status().Update(ctx, workload); err != nil {
+		k.log.Error(err, "Workload status UPDATE error!", "workload", workload.GetName())
+		return err
+	}
+	return nil
+}
+
+func getConditions(workload *unstructured.Unstructured) ([]metav1.Condition, error) {
+	conditionObjects, _, err := unstructured.NestedSlice(workload.Object, "status", "conditions")
+	if err != nil {
+		return nil, err
+	}
+	conditions := make([]metav1.Condition, 0, len(conditionObjects))
+	for _, conditionObject := range conditionObjects {
+		object, ok := conditionObject.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		condition := metav1.Condition{}
+		if err := runtime.DefaultUnstructuredConverter.FromUnstructured(object, &condition); err != nil {
+			return nil, err
+		}
+		conditions = append(conditions, condition)
+	}
+	return conditions, nil
+}
+
+func setConditions(workload *unstructured.Unstructured, conditions []metav1.Condition) error {
+	conditionObjects := make([]interface{}, 0, len(conditions))
+	for i := range conditions {
+		object, err := runtime.DefaultUnstructuredConverter.ToUnstructured(&conditions[i])
+		if err != nil {
+			return err
+		}
+		conditionObjects = append(conditionObjects, object)
+	}
+	return unstructured.SetNestedSlice(workload.Object, conditionObjects, "status", "conditions")
+}
+
+func isConditionTrue(workload *unstructured.Unstructured, conditionType string) bool {
+	conditions, err := getConditions(workload)
+	return err == nil && meta.IsStatusConditionTrue(conditions, conditionType)
+}
+
+func (kf *KueueBatchSchedulerFactory) New(config *rest.Config) (schedulerinterface.BatchScheduler, error) {
+	kueueClient, err := client.New(config, client.Options{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize kueue client with error %v", err)
+	}
+
+	extClient, err := apiextensionsclient.NewForConfig(config)
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize k8s extension client with error %v", err)
+	}
+
+	if _, err := extClient.ApiextensionsV1().CustomResourceDefinitions().Get(
+		context.TODO(),
+		WorkloadCRDName,
+		metav1.GetOptions{},
+	); err != nil {
+		return nil, fmt.Errorf("workload CRD is required to exist in current cluster. error: %s", err)
+	}
+	return &KueueBatchScheduler{
+		client: kueueClient,
+		log:    logf.Log.WithName("kueue"),
+	}, nil
+}
+
+func (kf *KueueBatchSchedulerFactory) AddToScheme(scheme *runtime.Scheme) {
+}
+
+// ConfigureReconciler watches the Workloads owned by the RayClusters, so that a RayCluster is reconciled as soon as
+// Kueue admits or evicts its Workload. The Workloads of the RayJobs are owned by the RayJobs, which are checked again
+// after the requeue interval of the RayJob controller. Nothing is watched if the Workload CRD is not installed, so that
+// the operator starts in clusters which only use other scheduler plugins.
+func (kf *KueueBatchSchedulerFactory) ConfigureReconciler(b *builder.Builder, config *rest.Config) (*builder.Builder, error) {
+	extClient, err := apiextensionsclient.NewForConfig(config)
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize k8s extension client with error %v", err)
+	}
+	installed, err := isWorkloadCRDInstalled(context.TODO(), extClient)
+	if err != nil {
+		return nil, err
+	}
+	if !installed {
+		logf.Log.WithName("kueue").Info("The Workload CRD is not installed, Workloads are not watched", "crd", WorkloadCRDName)
+		return b, nil
+	}
+	workload := &unstructured.Unstructured{}
+	workload.SetGroupVersionKind(WorkloadGroupVersionKind)
+	return b.Owns(workload), nil
+}
+
+// isWorkloadCRDInstalled returns whether the Workload CRD of Kueue exists in the cluster.
+func isWorkloadCRDInstalled(ctx context.Context, extClient apiextensionsclient.Interface) (bool, error) {
+	_, err := extClient.ApiextensionsV1().CustomResourceDefinitions().Get(ctx, WorkloadCRDName, metav1.GetOptions{})
+	if errors.IsNotFound(err) {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to get the %s CRD: %w", WorkloadCRDName, err)
+	}
+	return true, nil
+}
@@ -0,0 +1,363 @@
+package kueue
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/go-logr/logr"
+	rayv1 "github.com/ray-project/kuberay/ray-operator/apis/ray/v1"
+	"github.com/ray-project/kuberay/ray-operator/controllers/ray/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	apiextensionsfake "k8s.io/apiextensions-apiserver/pkg/client/clientset/clientset/fake"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/pointer"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func newTestRayClusterSpec() rayv1.RayClusterSpec {
+	resources := corev1.ResourceRequirements{
+		Requests: corev1.ResourceList{
+			corev1.ResourceCPU:    resource.MustParse("1"),
+			corev1.ResourceMemory: resource.MustParse("1Gi"),
+		},
+	}
+	return rayv1.RayClusterSpec{
+		HeadGroupSpec: rayv1.HeadGroupSpec{
+			Template: corev1.PodTemplateSpec{
+				Spec: corev1.PodSpec{Containers: []corev1.Container{{Name: "ray-head", Resources: resources}}},
+			},
+		},
+		WorkerGroupSpecs: []rayv1.WorkerGroupSpec{
+			{
+				GroupName: "worker",
+				Template: corev1.PodTemplateSpec{
+					Spec: corev1.PodSpec{Containers: []corev1.Container{{Name: "ray-worker", Resources: resources}}},
+				},
+				Replicas:    pointer.Int32(2),
+				MinReplicas: pointer.Int32(1),
+				MaxReplicas: pointer.Int32(4),
+			},
+		},
+	}
+}
+
+func newTestRayJob() *rayv1.RayJob {
+	rayClusterSpec := newTestRayClusterSpec()
+	return &rayv1.RayJob{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "rayjob-sample",
+			Namespace: "default",
+			Labels:    map[string]string{utils.RaySchedulerName: GetPluginName(), QueueNameLabelKey: "team-a"},
+		},
+		Spec: rayv1.RayJobSpec{
+			ShutdownAfterJobFinishes: true,
+			RayClusterSpec:           &rayClusterSpec,
+		},
+	}
+}
+
+func newTestScheduler() *KueueBatchScheduler {
+	workload := &unstructured.Unstructured{}
+	workload.SetGroupVersionKind(WorkloadGroupVersionKind)
+	return &KueueBatchScheduler{
+		client: fake.NewClientBuilder().WithStatusSubresource(workload).Build(),
+		log:    logr.Discard(),
+	}
+}
+
+func getWorkload(t *testing.T, k *KueueBatchScheduler, name string) *unstructured.Unstructured {
+	workload := &unstructured.Unstructured{}
+	workload.SetGroupVersionKind(WorkloadGroupVersionKind)
+	require.NoError(t, k.client.Get(context.Background(), types.NamespacedName{Namespace: "default", Name: name}, workload))
+	return workload
+}
+
+// setWorkloadConditions sets the conditions of a Workload like Kueue does.
+func setWorkloadConditions(t *testing.T, k *KueueBatchScheduler, workload *unstructured.Unstructured, conditions ...metav1.Condition) {
+	existing, err := getConditions(workload)
+	require.NoError(t, err)
+	for _, condition := range conditions {
+		meta.SetStatusCondition(&existing, condition)
+	}
+	require.NoError(t, setConditions(workload, existing))
+	require.NoError(t, unstructured.SetNestedField(workload.Object, "cluster-queue", "status", "admission", "clusterQueue"))
+	require.NoError(t, k.client.Status().Update(context.Background(), workload))
+}
+
+func TestAdmitRayJob(t *testing.T) {
+	ctx := context.Background()
+	k := newTestScheduler()
+	rayJob := newTestRayJob()
+	workloadName := GetWorkloadName("RayJob", rayJob.Name)
+
+	// The RayJob is suspended until it is admitted.
+	admitted, err := k.AdmitRayJob(ctx, rayJob)
+	require.NoError(t, err)
+	assert.False(t, admitted)
+	assert.True(t, rayJob.Spec.Suspend)
+	assert.Contains(t, rayJob.Annotations, SuspendedByKueueAnnotationKey)
+
+	workload := getWorkload(t, k, workloadName)
+	queueName, _, _ := unstructured.NestedString(workload.Object, "spec", "queueName")
+	assert.Equal(t, "team-a", queueName)
+	assert.Equal(t, rayJob.Name, workload.GetOwnerReferences()[0].Name)
+	assert.Equal(t, "RayJob", workload.GetOwnerReferences()[0].Kind)
+	// A PodSet for the head Pod and one for the desired, not the min, replicas of the worker group.
+	podSets, err := getWorkloadPodSets(workload)
+	require.NoError(t, err)
+	require.Len(t, podSets, 2)
+	assert.Equal(t, headPodSetName, podSets[0].name)
+	assert.Equal(t, int32(1), podSets[0].count)
+	assert.Equal(t, "worker", podSets[1].name)
+	assert.Equal(t, int32(2), podSets[1].count)
+	requests := podSets[1].template.Spec.Containers[0].Resources.Requests
+	assert.Equal(t, "1", requests.Cpu().String())
+	assert.Equal(t, "1Gi", requests.Memory().String())
+
+	// The RayJob is resumed once Kueue admits it.
+	setWorkloadConditions(t, k, workload,
+		metav1.Condition{Type: workloadQuotaReserved, Status: metav1.ConditionTrue, Reason: "QuotaReserved"},
+		metav1.Condition{Type: workloadAdmitted, Status: metav1.ConditionTrue, Reason: "Admitted"})
+	admitted, err = k.AdmitRayJob(ctx, rayJob)
+	require.NoError(t, err)
+	assert.True(t, admitted)
+	assert.False(t, rayJob.Spec.Suspend)
+	assert.NotContains(t, rayJob.Annotations, SuspendedByKueueAnnotationKey)
+
+	// The RayJob is suspended when Kueue evicts it, and the quota reservation is released once it is suspended.
+	workload = getWorkload(t, k, workloadName)
+	setWorkloadConditions(t, k, workload,
+		metav1.Condition{Type: workloadEvicted, Status: metav1.ConditionTrue, Reason: "Preempted", Message: "Preempted"})
+	rayJob.Status.JobDeploymentStatus = rayv1.JobDeploymentStatusRunning
+	admitted, err = k.AdmitRayJob(ctx, rayJob)
+	require.NoError(t, err)
+	assert.False(t, admitted)
+	assert.True(t, rayJob.Spec.Suspend)
+	assert.True(t, isConditionTrue(getWorkload(t, k, workloadName), workloadQuotaReserved))
+
+	rayJob.Status.JobDeploymentStatus = rayv1.JobDeploymentStatusSuspended
+	admitted, err = k.AdmitRayJob(ctx, rayJob)
+	require.NoError(t, err)
+	assert.False(t, admitted)
+	workload = getWorkload(t, k, workloadName)
+	assert.False(t, isConditionTrue(workload, workloadQuotaReserved))
+	assert.False(t, isConditionTrue(workload, workloadAdmitted))
+	_, found, _ := unstructured.NestedMap(workload.Object, "status", "admission")
+	assert.False(t, found)
+
+	// The Workload of a finished RayJob is marked as finished.
+	rayJob.Status.JobDeploymentStatus = rayv1.JobDeploymentStatusComplete
+	admitted, err = k.AdmitRayJob(ctx, rayJob)
+	require.NoError(t, err)
+	assert.True(t, admitted)
+	assert.True(t, isConditionTrue(getWorkload(t, k, workloadName), workloadFinished))
+}
+
+func TestAdmitRayJobUpdatesPendingWorkload(t *testing.T) {
+	ctx := context.Background()
+	k := newTestScheduler()
+	rayJob := newTestRayJob()
+	_, err := k.AdmitRayJob(ctx, rayJob)
+	require.NoError(t, err)
+
+	rayJob.Labels[QueueNameLabelKey] = "team-b"
+	rayJob.Spec.RayClusterSpec.WorkerGroupSpecs[0].Replicas = pointer.Int32(3)
+	_, err = k.AdmitRayJob(ctx, rayJob)
+	require.NoError(t, err)
+
+	workload := getWorkload(t, k, GetWorkloadName("RayJob", rayJob.Name))
+	queueName, _, _ := unstructured.NestedString(workload.Object, "spec", "queueName")
+	assert.Equal(t, "team-b", queueName)
+	podSets, err := getWorkloadPodSets(workload)
+	require.NoError(t, err)
+	require.Len(t, podSets, 2)
+	assert.Equal(t, int32(3), podSets[1].count)
+}
+
+func TestGetPodSets(t *testing.T) {
+	ctx := context.Background()
+	spec := newTestRayClusterSpec()
+	spec.WorkerGroupSpecs[0].NumOfHosts = 2
+	spec.WorkerGroupSpecs[0].Template.Spec.NodeSelector = map[string]string{"cloud.google.com/gke-tpu-topology": "2x2"}
+	spec.WorkerGroupSpecs[0].Template.Spec.Containers[0].Resources.Limits = corev1.ResourceList{
+		"google.com/tpu": resource.MustParse("4"),
+	}
+	spec.WorkerGroupSpecs = append(spec.WorkerGroupSpecs, rayv1.WorkerGroupSpec{
+		GroupName:   "idle",
+		Replicas:    pointer.Int32(0),
+		MinReplicas: pointer.Int32(0),
+		MaxReplicas: pointer.Int32(4),
+	})
+
+	// Each Pod of a multi-host replica is counted, the limits are requested if there are no requests, and a worker
+	// group without Pods has no PodSet.
+	podSets, err := getPodSets(ctx, &spec)
+	require.NoError(t, err)
+	require.Len(t, podSets, 2)
+	assert.Equal(t, int32(4), podSets[1].count)
+	assert.Equal(t, spec.WorkerGroupSpecs[0].Template.Spec.NodeSelector, podSets[1].template.Spec.NodeSelector)
+	requests := podSets[1].template.Spec.Containers[0].Resources.Requests
+	assert.Equal(t, "4", requests.Name("google.com/tpu", resource.DecimalSI).String())
+	assert.NotContains(t, spec.WorkerGroupSpecs[0].Template.Spec.Containers[0].Resources.Requests, corev1.ResourceName("google.com/tpu"))
+
+	// The PodSets are named after the worker groups.
+	spec.WorkerGroupSpecs[0].GroupName = headPodSetName
+	_, err = getPodSets(ctx, &spec)
+	assert.ErrorContains(t, err, "used by the PodSet of the head Pod")
+	spec.WorkerGroupSpecs[0].GroupName = "Small_Group"
+	_, err = getPodSets(ctx, &spec)
+	assert.ErrorContains(t, err, "not a valid PodSet name")
+
+	spec = newTestRayClusterSpec()
+	for i := 0; i < maxPodSets; i++ {
+		group := *spec.WorkerGroupSpecs[0].DeepCopy()
+		group.GroupName = fmt.Sprintf("worker-%d", i)
+		spec.WorkerGroupSpecs = append(spec.WorkerGroupSpecs, group)
+	}
+	_, err = getPodSets(ctx, &spec)
+	assert.ErrorContains(t, err, "at most 8 PodSets")
+}
+
+func TestAdmitRayJobSuspendedByUser(t *testing.T) {
+	ctx := context.Background()
+	k := newTestScheduler()
+	rayJob := newTestRayJob()
+	workloadName := GetWorkloadName("RayJob", rayJob.Name)
+
+	_, err := k.AdmitRayJob(ctx, rayJob)
+	require.NoError(t, err)
+	setWorkloadConditions(t, k, getWorkload(t, k, workloadName),
+		metav1.Condition{Type: workloadQuotaReserved, Status: metav1.ConditionTrue, Reason: "QuotaReserved"},
+		metav1.Condition{Type: workloadAdmitted, Status: metav1.ConditionTrue, Reason: "Admitted"})
+	_, err = k.AdmitRayJob(ctx, rayJob)
+	require.NoError(t, err)
+	require.False(t, rayJob.Spec.Suspend)
+
+	// Admitted and user-suspended stays suspended, and the Workload is deactivated to release the quota.
+	rayJob.Spec.Suspend = true
+	admitted, err := k.AdmitRayJob(ctx, rayJob)
+	require.NoError(t, err)
+	assert.True(t, admitted)
+	assert.True(t, rayJob.Spec.Suspend)
+	assert.NotContains(t, rayJob.Annotations, SuspendedByKueueAnnotationKey)
+	assert.False(t, isWorkloadActive(getWorkload(t, k, workloadName)))
+
+	// The Workload is activated again once the user resumes the RayJob.
+	rayJob.Spec.Suspend = false
+	_, err = k.AdmitRayJob(ctx, rayJob)
+	require.NoError(t, err)
+	assert.True(t, isWorkloadActive(getWorkload(t, k, workloadName)))
+}
+
+func TestAdmitRayJobValidation(t *testing.T) {
+	ctx := context.Background()
+	k := newTestScheduler()
+
+	rayJob := newTestRayJob()
+	rayJob.Spec.ShutdownAfterJobFinishes = false
+	_, err := k.AdmitRayJob(ctx, rayJob)
+	assert.ErrorContains(t, err, "shutdownAfterJobFinishes")
+
+	// A RayJob without a queue is not submitted to a default LocalQueue.
+	rayJob = newTestRayJob()
+	delete(rayJob.Labels, QueueNameLabelKey)
+	_, err = k.AdmitRayJob(ctx, rayJob)
+	assert.ErrorContains(t, err, QueueNameLabelKey)
+	assert.False(t, rayJob.Spec.Suspend)
+
+	// A RayJob which uses an existing RayCluster is not submitted.
+	rayJob = newTestRayJob()
+	rayJob.Spec.RayClusterSpec = nil
+	rayJob.Spec.ClusterSelector = map[string]string{"ray.io/cluster": "raycluster-sample"}
+	admitted, err := k.AdmitRayJob(ctx, rayJob)
+	require.NoError(t, err)
+	assert.True(t, admitted)
+	assert.False(t, rayJob.Spec.Suspend)
+}
+
+func TestAdmitRayCluster(t *testing.T) {
+	ctx := context.Background()
+	k := newTestScheduler()
+	cluster := &rayv1.RayCluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "raycluster-sample",
+			Namespace: "default",
+			Labels:    map[string]string{QueueNameLabelKey: "team-a"},
+		},
+		Spec: newTestRayClusterSpec(),
+	}
+
+	admitted, err := k.AdmitRayCluster(ctx, cluster)
+	require.NoError(t, err)
+	assert.False(t, admitted)
+	assert.True(t, *cluster.Spec.Suspend)
+	workload := getWorkload(t, k, GetWorkloadName("RayCluster", cluster.Name))
+	queueName, _, _ := unstructured.NestedString(workload.Object, "spec", "queueName")
+	assert.Equal(t, "team-a", queueName)
+
+	setWorkloadConditions(t, k, workload,
+		metav1.Condition{Type: workloadQuotaReserved, Status: metav1.ConditionTrue, Reason: "QuotaReserved"},
+		metav1.Condition{Type: workloadAdmitted, Status: metav1.ConditionTrue, Reason: "Admitted"})
+	admitted, err = k.AdmitRayCluster(ctx, cluster)
+	require.NoError(t, err)
+	assert.True(t, admitted)
+	assert.False(t, *cluster.Spec.Suspend)
+
+	// A RayCluster suspended by the user, e.g. with the SuspendCluster API, is not resumed.
+	cluster.Spec.Suspend = pointer.Bool(true)
+	admitted, err = k.AdmitRayCluster(ctx, cluster)
+	require.NoError(t, err)
+	assert.True(t, admitted)
+	assert.True(t, *cluster.Spec.Suspend)
+	assert.False(t, isWorkloadActive(getWorkload(t, k, GetWorkloadName("RayCluster", cluster.Name))))
+	cluster.Spec.Suspend = pointer.Bool(false)
+
+	// The RayCluster of a RayJob is admitted with the RayJob.
+	jobCluster := &rayv1.RayCluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "rayjob-sample-raycluster",
+			Namespace: "default",
+			Labels:    map[string]string{utils.RayOriginatedFromCRDLabelKey: utils.RayOriginatedFromCRDLabelValue(utils.RayJobCRD)},
+		},
+		Spec: newTestRayClusterSpec(),
+	}
+	admitted, err = k.AdmitRayCluster(ctx, jobCluster)
+	require.NoError(t, err)
+	assert.True(t, admitted)
+	assert.Nil(t, jobCluster.Spec.Suspend)
+
+	// A RayCluster without a queue is not submitted to a default LocalQueue.
+	noQueueCluster := &rayv1.RayCluster{
+		ObjectMeta: metav1.ObjectMeta{Name: "raycluster-no-queue", Namespace: "default"},
+		Spec:       newTestRayClusterSpec(),
+	}
+	_, err = k.AdmitRayCluster(ctx, noQueueCluster)
+	assert.ErrorContains(t, err, QueueNameLabelKey)
+	assert.Nil(t, noQueueCluster.Spec.Suspend)
+
+	// `autoSuspend` conflicts with the suspension by Kueue.
+	cluster.Spec.AutoSuspend = &rayv1.RayClusterAutoSuspend{IdleTTLSeconds: pointer.Int32(60)}
+	_, err = k.AdmitRayCluster(ctx, cluster)
+	assert.ErrorContains(t, err, "autoSuspend")
+}
+
+func TestIsWorkloadCRDInstalled(t *testing.T) {
+	ctx := context.Background()
+
+	installed, err := isWorkloadCRDInstalled(ctx, apiextensionsfake.NewSimpleClientset())
+	require.NoError(t, err)
+	assert.False(t, installed)
+
+	crd := &apiextensionsv1.CustomResourceDefinition{ObjectMeta: metav1.ObjectMeta{Name: WorkloadCRDName}}
+	installed, err = isWorkloadCRDInstalled(ctx, apiextensionsfake.NewSimpleClientset(crd))
+	require.NoError(t, err)
+	assert.True(t, installed)
+}
@@ -10,6 +10,7 @@ import (
 
 	rayv1 "github.com/ray-project/kuberay/ray-operator/apis/ray/v1"
 	schedulerinterface "github.com/ray-project/kuberay/ray-operator/controllers/ray/batchscheduler/interface"
+	"github.com/ray-project/kuberay/ray-operator/controllers/ray/batchscheduler/kueue"
 	"github.com/ray-project/kuberay/ray-operator/controllers/ray/batchscheduler/volcano"
 	"github.com/ray-project/kuberay/ray-operator/controllers/ray/utils"
 )
@@ -17,6 +18,7 @@ import (
 var schedulerContainers = map[string]schedulerinterface.BatchSchedulerFactory{
 	schedulerinterface.GetDefaultPluginName(): &schedulerinterface.DefaultBatchSchedulerFactory{},
 	volcano.GetPluginName():                   &volcano.VolcanoBatchSchedulerFactory{},
+	kueue.GetPluginName():                     &kueue.KueueBatchSchedulerFactory{},
 }
 
 func GetRegisteredNames() []string {
@@ -27,11 +29,14 @@ func GetRegisteredNames() []string {
 	return pluginNames
 }
 
-func ConfigureReconciler(b *builder.Builder) *builder.Builder {
-	for _, factory := range schedulerContainers {
-		b = factory.ConfigureReconciler(b)
+func ConfigureReconciler(b *builder.Builder, config *rest.Config) (*builder.Builder, error) {
+	for name, factory := range schedulerContainers {
+		var err error
+		if b, err = factory.ConfigureReconciler(b, config); err != nil {
+			return nil, fmt.Errorf("failed to configure the reconciler for scheduler plugin %s: %w", name, err)
+		}
 	}
-	return b
+	return b, nil
 }
 
 func AddToScheme(scheme *runtime.Scheme) {
@@ -63,6 +68,15 @@ func (batch *SchedulerManager) GetSchedulerForCluster(app *rayv1.RayCluster) (sc
 	return &schedulerinterface.DefaultBatchScheduler{}, nil
 }
 
+func (batch *SchedulerManager) GetSchedulerForRayJob(rayJob *rayv1.RayJob) (schedulerinterface.BatchScheduler, error) {
+	if schedulerName, ok := rayJob.ObjectMeta.Labels[utils.RaySchedulerName]; ok {
+		return batch.GetScheduler(schedulerName)
+	}
+
+	// no scheduler provided
+	return &schedulerinterface.DefaultBatchScheduler{}, nil
+}
+
 func (batch *SchedulerManager) GetScheduler(schedulerName string) (schedulerinterface.BatchScheduler, error) {
 	factory, registered := schedulerContainers[schedulerName]
 	if !registered {
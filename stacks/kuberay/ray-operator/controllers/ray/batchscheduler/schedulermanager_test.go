@@ -0,0 +1,26 @@
+package batchscheduler
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/client-go/rest"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+)
+
+func TestConfigureReconcilerWithoutWorkloadCRD(t *testing.T) {
+	// An API server without any CRD, like a cluster which only runs Volcano and does not install Kueue.
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusNotFound)
+		_, _ = w.Write([]byte(`{"kind":"Status","apiVersion":"v1","status":"Failure","reason":"NotFound","code":404}`))
+	}))
+	defer server.Close()
+
+	b, err := ConfigureReconciler(builder.ControllerManagedBy(nil), &rest.Config{Host: server.URL})
+	require.NoError(t, err)
+	assert.NotNil(t, b)
+}
@@ -50,10 +50,10 @@ func (v *VolcanoBatchScheduler) DoBatchSchedulingOnSubmission(ctx context.Contex
 	var minMember int32
 	var totalResource corev1.ResourceList
 	if app.Spec.EnableInTreeAutoscaling == nil || !*app.Spec.EnableInTreeAutoscaling {
-		minMember = utils.CalculateDesiredReplicas(ctx, app) + 1
+		minMember = utils.CalculateDesiredWorkerPods(ctx, app) + 1
 		totalResource = utils.CalculateDesiredResources(app)
 	} else {
-		minMember = utils.CalculateMinReplicas(app) + 1
+		minMember = utils.CalculateMinWorkerPods(app) + 1
 		totalResource = utils.CalculateMinResources(app)
 	}
 
@@ -182,6 +182,6 @@ func (vf *VolcanoBatchSchedulerFactory) AddToScheme(scheme *runtime.Scheme) {
 	utilruntime.Must(v1beta1.AddToScheme(scheme))
 }
 
-func (vf *VolcanoBatchSchedulerFactory) ConfigureReconciler(b *builder.Builder) *builder.Builder {
-	return b.Owns(&v1beta1.PodGroup{})
+func (vf *VolcanoBatchSchedulerFactory) ConfigureReconciler(b *builder.Builder, config *rest.Config) (*builder.Builder, error) {
+	return b.Owns(&v1beta1.PodGroup{}), nil
 }
@@ -77,7 +77,7 @@ func TestCreatePodGroup(t *testing.T) {
 		},
 	}
 
-	minMember := utils.CalculateDesiredReplicas(context.Background(), &cluster) + 1
+	minMember := utils.CalculateDesiredWorkerPods(context.Background(), &cluster) + 1
 	totalResource := utils.CalculateDesiredResources(&cluster)
 	pg := createPodGroup(&cluster, getAppPodGroupName(&cluster), minMember, totalResource)
 
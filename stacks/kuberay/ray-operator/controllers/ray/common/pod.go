@@ -4,11 +4,11 @@ import (
 	"bytes"
 	"context"
 	"fmt"
-	"os"
 	"strconv"
 	"strings"
 
 	"github.com/ray-project/kuberay/ray-operator/controllers/ray/utils"
+	"github.com/ray-project/kuberay/ray-operator/pkg/features"
 
 	rayv1 "github.com/ray-project/kuberay/ray-operator/apis/ray/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
@@ -103,7 +103,7 @@ func DefaultHeadPodTemplate(ctx context.Context, instance rayv1.RayCluster, head
 	if podTemplate.Labels == nil {
 		podTemplate.Labels = make(map[string]string)
 	}
-	podTemplate.Labels = labelPod(rayv1.HeadNode, instance.Name, "headgroup", instance.Spec.HeadGroupSpec.Template.ObjectMeta.Labels)
+	podTemplate.Labels = labelPod(rayv1.HeadNode, instance.Name, utils.RayHeadGroupName, headSpec.Template.ObjectMeta.Labels)
 	headSpec.RayStartParams = setMissingRayStartParams(ctx, headSpec.RayStartParams, rayv1.HeadNode, headPort, "", instance.Annotations)
 
 	initTemplateAnnotations(instance, &podTemplate)
@@ -138,20 +138,6 @@ func DefaultHeadPodTemplate(ctx context.Context, instance rayv1.RayCluster, head
 	return podTemplate
 }
 
-func getEnableInitContainerInjection() bool {
-	if s := os.Getenv(EnableInitContainerInjectionEnvKey); strings.ToLower(s) == "false" {
-		return false
-	}
-	return true
-}
-
-func getEnableProbesInjection() bool {
-	if s := os.Getenv(utils.ENABLE_PROBES_INJECTION); strings.ToLower(s) == "false" {
-		return false
-	}
-	return true
-}
-
 // DefaultWorkerPodTemplate sets the config values
 func DefaultWorkerPodTemplate(ctx context.Context, instance rayv1.RayCluster, workerSpec rayv1.WorkerGroupSpec, podName string, fqdnRayIP string, headPort string) corev1.PodTemplateSpec {
 	log := ctrl.LoggerFrom(ctx)
@@ -163,8 +149,8 @@ func DefaultWorkerPodTemplate(ctx context.Context, instance rayv1.RayCluster, wo
 	}
 
 	// The Ray worker should only start once the GCS server is ready.
-	// only inject init container only when ENABLE_INIT_CONTAINER_INJECTION is true
-	enableInitContainerInjection := getEnableInitContainerInjection()
+	// only inject init container only when the InitContainerInjection feature gate is enabled
+	enableInitContainerInjection := features.Enabled(ctx, features.InitContainerInjection)
 
 	if enableInitContainerInjection {
 		// Do not modify `deepCopyRayContainer` anywhere.
@@ -373,8 +359,8 @@ func BuildPod(ctx context.Context, podTemplateSpec corev1.PodTemplateSpec, rayNo
 	setContainerEnvVars(&pod, rayNodeType, rayStartParams, fqdnRayIP, headPort, rayStartCmd, creatorCRDType)
 
 	// Inject probes into the Ray containers if the user has not explicitly disabled them.
-	// The feature flag `ENABLE_PROBES_INJECTION` will be removed if this feature is stable enough.
-	enableProbesInjection := getEnableProbesInjection()
+	// The feature gate `ProbesInjection` will be removed if this feature is stable enough.
+	enableProbesInjection := features.Enabled(ctx, features.ProbesInjection)
 	log.Info("Probes injection feature flag", "enabled", enableProbesInjection)
 	if enableProbesInjection {
 		// Configure the readiness and liveness probes for the Ray container. These probes
@@ -386,6 +372,24 @@ func BuildPod(ctx context.Context, podTemplateSpec corev1.PodTemplateSpec, rayNo
 	return pod
 }
 
+// SetMultiHostPodIdentity assigns a worker Pod of a multi-host worker group to a replica. All the Pods of a replica
+// share the same replica index, and the host index is the rank of the Pod within its replica.
+func SetMultiHostPodIdentity(pod *corev1.Pod, numOfHosts int32, replicaIndex int32, hostIndex int32) {
+	if pod.Labels == nil {
+		pod.Labels = make(map[string]string)
+	}
+	pod.Labels[utils.RayWorkerReplicaIndexLabelKey] = utils.FormatInt32(replicaIndex)
+	pod.Labels[utils.RayHostIndexLabelKey] = utils.FormatInt32(hostIndex)
+
+	// These environment variables are managed by KubeRay and should not be set by the user.
+	container := &pod.Spec.Containers[utils.RayContainerIndex]
+	container.Env = append(container.Env,
+		corev1.EnvVar{Name: utils.RAY_WORKER_REPLICA_INDEX, Value: utils.FormatInt32(replicaIndex)},
+		corev1.EnvVar{Name: utils.RAY_HOST_INDEX, Value: utils.FormatInt32(hostIndex)},
+		corev1.EnvVar{Name: utils.RAY_NUM_HOSTS, Value: utils.FormatInt32(numOfHosts)},
+	)
+}
+
 // BuildAutoscalerContainer builds a Ray autoscaler container which can be appended to the head pod.
 func BuildAutoscalerContainer(autoscalerImage string) corev1.Container {
 	container := corev1.Container{
@@ -0,0 +1,171 @@
+package common
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/strategicpatch"
+
+	configapi "github.com/ray-project/kuberay/ray-operator/apis/config/v1alpha1"
+	rayv1 "github.com/ray-project/kuberay/ray-operator/apis/ray/v1"
+	"github.com/ray-project/kuberay/ray-operator/controllers/ray/utils"
+)
+
+// ApplyPodDefaults merges the operator-wide Pod defaults of a Ray node type into the Pod template of a RayCluster in
+// the given namespace. The defaults of the namespace are merged on top of the defaults of the node type, and the Pod
+// template is merged on top of both, so values set in the Pod template always take precedence.
+func ApplyPodDefaults(podTemplate corev1.PodTemplateSpec, podDefaults *configapi.PodDefaults, nodeType rayv1.RayNodeType, namespace string) (corev1.PodTemplateSpec, error) {
+	layers := getRayPodDefaults(podDefaults, nodeType, namespace)
+	if len(layers) == 0 {
+		return podTemplate, nil
+	}
+
+	// Merge the layers from the highest precedence to the lowest, so that each merge keeps the values merged so far.
+	merged := *podTemplate.DeepCopy()
+	for i := len(layers) - 1; i >= 0; i-- {
+		if layers[i].Template == nil {
+			continue
+		}
+		var err error
+		if merged, err = mergePodTemplate(*layers[i].Template, merged); err != nil {
+			return podTemplate, err
+		}
+	}
+	// Keep the containers of the Pod template first, because the Ray container is expected at `utils.RayContainerIndex`.
+	merged.Spec.Containers = orderContainers(merged.Spec.Containers, podTemplate.Spec.Containers)
+	merged.Spec.InitContainers = orderContainers(merged.Spec.InitContainers, podTemplate.Spec.InitContainers)
+
+	imageRegistry := ""
+	for i := len(layers) - 1; i >= 0; i-- {
+		if len(merged.Spec.Containers) > utils.RayContainerIndex {
+			rayContainer := &merged.Spec.Containers[utils.RayContainerIndex]
+			rayContainer.Env = addMissingEnvVars(rayContainer.Env, layers[i].Env)
+			rayContainer.VolumeMounts = addMissingVolumeMounts(rayContainer.VolumeMounts, layers[i].VolumeMounts)
+		}
+		if imageRegistry == "" {
+			imageRegistry = layers[i].ImageRegistry
+		}
+	}
+	if imageRegistry != "" {
+		for i := range merged.Spec.InitContainers {
+			merged.Spec.InitContainers[i].Image = replaceImageRegistry(merged.Spec.InitContainers[i].Image, imageRegistry)
+		}
+		for i := range merged.Spec.Containers {
+			merged.Spec.Containers[i].Image = replaceImageRegistry(merged.Spec.Containers[i].Image, imageRegistry)
+		}
+	}
+	return merged, nil
+}
+
+// getRayPodDefaults returns the Pod defaults that apply to a Ray node type in a namespace, from the lowest precedence to the highest.
+func getRayPodDefaults(podDefaults *configapi.PodDefaults, nodeType rayv1.RayNodeType, namespace string) []*configapi.RayPodDefaults {
+	if podDefaults == nil {
+		return nil
+	}
+	selectNodeType := func(head *configapi.RayPodDefaults, worker *configapi.RayPodDefaults) *configapi.RayPodDefaults {
+		if nodeType == rayv1.HeadNode {
+			return head
+		}
+		return worker
+	}
+
+	var layers []*configapi.RayPodDefaults
+	if layer := selectNodeType(podDefaults.Head, podDefaults.Worker); layer != nil {
+		layers = append(layers, layer)
+	}
+	for _, namespaceDefaults := range podDefaults.Namespaces {
+		if namespaceDefaults.Namespace != namespace {
+			continue
+		}
+		if layer := selectNodeType(namespaceDefaults.Head, namespaceDefaults.Worker); layer != nil {
+			layers = append(layers, layer)
+		}
+	}
+	return layers
+}
+
+// mergePodTemplate merges the Pod template `override` into `base` with strategic merge patch semantics.
+func mergePodTemplate(base corev1.PodTemplateSpec, override corev1.PodTemplateSpec) (corev1.PodTemplateSpec, error) {
+	merged := corev1.PodTemplateSpec{}
+	baseJSON, err := json.Marshal(base)
+	if err != nil {
+		return merged, err
+	}
+	overrideJSON, err := json.Marshal(override)
+	if err != nil {
+		return merged, err
+	}
+	mergedJSON, err := strategicpatch.StrategicMergePatch(baseJSON, overrideJSON, corev1.PodTemplateSpec{})
+	if err != nil {
+		return merged, fmt.Errorf("failed to merge the Pod defaults: %w", err)
+	}
+	err = json.Unmarshal(mergedJSON, &merged)
+	return merged, err
+}
+
+// orderContainers moves the containers named in `first` to the front of `containers`, in the order of `first`.
+func orderContainers(containers []corev1.Container, first []corev1.Container) []corev1.Container {
+	ordered := make([]corev1.Container, 0, len(containers))
+	isFirst := make(map[string]bool, len(first))
+	for _, container := range first {
+		isFirst[container.Name] = true
+		for _, c := range containers {
+			if c.Name == container.Name {
+				ordered = append(ordered, c)
+				break
+			}
+		}
+	}
+	for _, c := range containers {
+		if !isFirst[c.Name] {
+			ordered = append(ordered, c)
+		}
+	}
+	if len(ordered) == 0 {
+		return containers
+	}
+	return ordered
+}
+
+func addMissingEnvVars(envVars []corev1.EnvVar, defaults []corev1.EnvVar) []corev1.EnvVar {
+	for _, defaultEnvVar := range defaults {
+		if !utils.EnvVarExists(defaultEnvVar.Name, envVars) {
+			envVars = append(envVars, defaultEnvVar)
+		}
+	}
+	return envVars
+}
+
+func addMissingVolumeMounts(volumeMounts []corev1.VolumeMount, defaults []corev1.VolumeMount) []corev1.VolumeMount {
+	for _, defaultVolumeMount := range defaults {
+		exists := false
+		for _, volumeMount := range volumeMounts {
+			if volumeMount.MountPath == defaultVolumeMount.MountPath {
+				exists = true
+				break
+			}
+		}
+		if !exists {
+			volumeMounts = append(volumeMounts, defaultVolumeMount)
+		}
+	}
+	return volumeMounts
+}
+
+// replaceImageRegistry replaces the registry of a container image with `registry`. Following the Docker reference
+// format, the first component of the image is a registry if it contains a "." or a ":", or is "localhost".
+func replaceImageRegistry(image string, registry string) string {
+	registry = strings.TrimSuffix(registry, "/")
+	if image == "" || strings.HasPrefix(image, registry+"/") {
+		return image
+	}
+	repository := image
+	if i := strings.Index(image, "/"); i >= 0 {
+		if domain := image[:i]; strings.ContainsAny(domain, ".:") || domain == "localhost" {
+			repository = image[i+1:]
+		}
+	}
+	return registry + "/" + repository
+}
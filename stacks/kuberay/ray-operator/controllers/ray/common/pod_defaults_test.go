@@ -0,0 +1,105 @@
+package common
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	configapi "github.com/ray-project/kuberay/ray-operator/apis/config/v1alpha1"
+	rayv1 "github.com/ray-project/kuberay/ray-operator/apis/ray/v1"
+)
+
+func TestApplyPodDefaults(t *testing.T) {
+	podTemplate := corev1.PodTemplateSpec{
+		ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{"team": "user"}},
+		Spec: corev1.PodSpec{
+			NodeSelector: map[string]string{"disk": "ssd"},
+			Containers: []corev1.Container{
+				{
+					Name:  "ray-worker",
+					Image: "rayproject/ray:2.9.0",
+					Env:   []corev1.EnvVar{{Name: "LOG_LEVEL", Value: "debug"}},
+				},
+			},
+		},
+	}
+	podDefaults := &configapi.PodDefaults{
+		Head: &configapi.RayPodDefaults{
+			Env: []corev1.EnvVar{{Name: "HEAD_ONLY", Value: "true"}},
+		},
+		Worker: &configapi.RayPodDefaults{
+			Template: &corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{"team": "platform", "cost-center": "ml"}},
+				Spec: corev1.PodSpec{
+					NodeSelector: map[string]string{"pool": "ray", "disk": "hdd"},
+					Tolerations:  []corev1.Toleration{{Key: "ray", Operator: corev1.TolerationOpExists}},
+					Volumes:      []corev1.Volume{{Name: "cache"}},
+					Containers:   []corev1.Container{{Name: "log-agent", Image: "docker.io/fluent/fluent-bit:1.9.6"}},
+				},
+			},
+			Env:           []corev1.EnvVar{{Name: "LOG_LEVEL", Value: "info"}, {Name: "PIP_INDEX_URL", Value: "https://pypi.example.com"}},
+			VolumeMounts:  []corev1.VolumeMount{{Name: "cache", MountPath: "/cache"}},
+			ImageRegistry: "registry.example.com",
+		},
+		Namespaces: []configapi.NamespacePodDefaults{
+			{
+				Namespace: "team-a",
+				Worker: &configapi.RayPodDefaults{
+					Template: &corev1.PodTemplateSpec{
+						Spec: corev1.PodSpec{NodeSelector: map[string]string{"pool": "team-a"}},
+					},
+					ImageRegistry: "registry.team-a.example.com",
+				},
+			},
+		},
+	}
+
+	// The Pod template wins over the defaults, and the Ray container stays the first container.
+	merged, err := ApplyPodDefaults(podTemplate, podDefaults, rayv1.WorkerNode, "default")
+	assert.Nil(t, err)
+	assert.Equal(t, map[string]string{"team": "user", "cost-center": "ml"}, merged.Labels)
+	assert.Equal(t, map[string]string{"pool": "ray", "disk": "ssd"}, merged.Spec.NodeSelector)
+	assert.Equal(t, 1, len(merged.Spec.Tolerations))
+	assert.Equal(t, 1, len(merged.Spec.Volumes))
+	assert.Equal(t, 2, len(merged.Spec.Containers))
+	rayContainer := merged.Spec.Containers[0]
+	assert.Equal(t, "ray-worker", rayContainer.Name)
+	assert.Equal(t, "registry.example.com/rayproject/ray:2.9.0", rayContainer.Image)
+	assert.Equal(t, []corev1.EnvVar{{Name: "LOG_LEVEL", Value: "debug"}, {Name: "PIP_INDEX_URL", Value: "https://pypi.example.com"}}, rayContainer.Env)
+	assert.Equal(t, []corev1.VolumeMount{{Name: "cache", MountPath: "/cache"}}, rayContainer.VolumeMounts)
+	assert.Equal(t, "registry.example.com/fluent/fluent-bit:1.9.6", merged.Spec.Containers[1].Image)
+
+	// The defaults of the namespace win over the defaults of the node type.
+	merged, err = ApplyPodDefaults(podTemplate, podDefaults, rayv1.WorkerNode, "team-a")
+	assert.Nil(t, err)
+	assert.Equal(t, map[string]string{"pool": "team-a", "disk": "ssd"}, merged.Spec.NodeSelector)
+	assert.Equal(t, "registry.team-a.example.com/rayproject/ray:2.9.0", merged.Spec.Containers[0].Image)
+
+	// The defaults of the head do not apply to workers, and the Pod template is not modified.
+	merged, err = ApplyPodDefaults(podTemplate, podDefaults, rayv1.HeadNode, "default")
+	assert.Nil(t, err)
+	assert.Equal(t, podTemplate.Spec.NodeSelector, merged.Spec.NodeSelector)
+	assert.Equal(t, []corev1.EnvVar{{Name: "LOG_LEVEL", Value: "debug"}, {Name: "HEAD_ONLY", Value: "true"}}, merged.Spec.Containers[0].Env)
+	assert.Equal(t, []corev1.EnvVar{{Name: "LOG_LEVEL", Value: "debug"}}, podTemplate.Spec.Containers[0].Env)
+
+	merged, err = ApplyPodDefaults(podTemplate, nil, rayv1.HeadNode, "default")
+	assert.Nil(t, err)
+	assert.Equal(t, podTemplate, merged)
+}
+
+func TestReplaceImageRegistry(t *testing.T) {
+	tests := map[string]string{
+		"rayproject/ray:2.9.0":                    "mirror.example.com/rayproject/ray:2.9.0",
+		"ubuntu":                                  "mirror.example.com/ubuntu",
+		"docker.io/rayproject/ray:2.9.0":          "mirror.example.com/rayproject/ray:2.9.0",
+		"localhost:5000/ray:latest":               "mirror.example.com/ray:latest",
+		"localhost/ray":                           "mirror.example.com/ray",
+		"mirror.example.com/rayproject/ray":       "mirror.example.com/rayproject/ray",
+		"us-docker.pkg.dev/project/ray/ray:2.9.0": "mirror.example.com/project/ray/ray:2.9.0",
+	}
+	for image, expected := range tests {
+		assert.Equal(t, expected, replaceImageRegistry(image, "mirror.example.com/"), image)
+	}
+}
@@ -3,13 +3,13 @@ package common
 import (
 	"context"
 	"fmt"
-	"os"
 	"reflect"
 	"sort"
 	"strings"
 	"testing"
 
 	"github.com/ray-project/kuberay/ray-operator/controllers/ray/utils"
+	"github.com/ray-project/kuberay/ray-operator/pkg/features"
 	"github.com/stretchr/testify/assert"
 
 	corev1 "k8s.io/api/core/v1"
@@ -867,6 +867,30 @@ func TestDefaultWorkerPodTemplateWithName(t *testing.T) {
 	assert.Equal(t, worker, expectedWorker)
 }
 
+func TestSetMultiHostPodIdentity(t *testing.T) {
+	ctx := context.Background()
+
+	cluster := instance.DeepCopy()
+	fqdnRayIP := utils.GenerateFQDNServiceName(ctx, *cluster, cluster.Namespace)
+	worker := cluster.Spec.WorkerGroupSpecs[0]
+	podName := cluster.Name + utils.DashSymbol + string(rayv1.WorkerNode) + utils.DashSymbol + worker.GroupName + utils.DashSymbol
+	podTemplateSpec := DefaultWorkerPodTemplate(ctx, *cluster, *worker.DeepCopy(), podName, fqdnRayIP, "6379")
+	pod := BuildPod(ctx, podTemplateSpec, rayv1.WorkerNode, worker.RayStartParams, "6379", nil, utils.RayClusterCRD, fqdnRayIP)
+
+	SetMultiHostPodIdentity(&pod, 4, 2, 3)
+	assert.Equal(t, "2", pod.Labels[utils.RayWorkerReplicaIndexLabelKey])
+	assert.Equal(t, "3", pod.Labels[utils.RayHostIndexLabelKey])
+	for name, expected := range map[string]string{
+		utils.RAY_WORKER_REPLICA_INDEX: "2",
+		utils.RAY_HOST_INDEX:           "3",
+		utils.RAY_NUM_HOSTS:            "4",
+	} {
+		envVar, ok := utils.EnvVarByName(name, pod.Spec.Containers[utils.RayContainerIndex].Env)
+		assert.True(t, ok, "%s should be set", name)
+		assert.Equal(t, expected, envVar.Value)
+	}
+}
+
 func containerPortExists(ports []corev1.ContainerPort, name string, containerPort int32) error {
 	for _, port := range ports {
 		if port.Name == name {
@@ -1133,55 +1157,45 @@ func TestSetMissingRayStartParamsDashboardHost(t *testing.T) {
 	assert.Equal(t, "localhost", rayStartParams["dashboard-host"], fmt.Sprintf("Expected `%v` but got `%v`", "localhost", rayStartParams["dashboard-host"]))
 }
 
-func TestGetCustomWorkerInitImage(t *testing.T) {
-	// cleanup
-	defer os.Unsetenv(EnableInitContainerInjectionEnvKey)
-
-	// not set the env
-	b := getEnableInitContainerInjection()
-	assert.True(t, b)
-	// set the env with "true"
-	os.Setenv(EnableInitContainerInjectionEnvKey, "true")
-	b = getEnableInitContainerInjection()
-	assert.True(t, b)
-	// set the env with "True"
-	os.Setenv(EnableInitContainerInjectionEnvKey, "True")
-	b = getEnableInitContainerInjection()
-	assert.True(t, b)
-	// set the env with "false"
-	os.Setenv(EnableInitContainerInjectionEnvKey, "false")
-	b = getEnableInitContainerInjection()
-	assert.False(t, b)
-	// set the env with "False"
-	os.Setenv(EnableInitContainerInjectionEnvKey, "False")
-	b = getEnableInitContainerInjection()
-	assert.False(t, b)
+func TestDefaultWorkerPodTemplate_InitContainerInjection(t *testing.T) {
+	cluster := instance.DeepCopy()
+	worker := cluster.Spec.WorkerGroupSpecs[0]
+	podName := cluster.Name + utils.DashSymbol + string(rayv1.WorkerNode) + utils.DashSymbol + worker.GroupName + utils.DashSymbol + utils.FormatInt32(0)
+	fqdnRayIP := utils.GenerateFQDNServiceName(context.Background(), *cluster, cluster.Namespace)
+
+	// The init container is injected by default.
+	podTemplateSpec := DefaultWorkerPodTemplate(context.Background(), *cluster, worker, podName, fqdnRayIP, "6379")
+	assert.Equal(t, 1, len(podTemplateSpec.Spec.InitContainers))
+
+	// The init container is not injected if the InitContainerInjection feature gate is disabled.
+	featureGates, err := features.NewFeatureGates(map[string]bool{string(features.InitContainerInjection): false})
+	assert.Nil(t, err)
+	ctx := features.IntoContext(context.Background(), featureGates)
+	cluster = instance.DeepCopy()
+	worker = cluster.Spec.WorkerGroupSpecs[0]
+	podTemplateSpec = DefaultWorkerPodTemplate(ctx, *cluster, worker, podName, fqdnRayIP, "6379")
+	assert.Equal(t, 0, len(podTemplateSpec.Spec.InitContainers))
 }
 
-func TestGetEnableProbesInjection(t *testing.T) {
-	// cleanup
-	defer os.Unsetenv(utils.ENABLE_PROBES_INJECTION)
-
-	// not set the env
-	os.Unsetenv(utils.ENABLE_PROBES_INJECTION)
-	b := getEnableProbesInjection()
-	assert.True(t, b)
-	// set the env with "true"
-	os.Setenv(utils.ENABLE_PROBES_INJECTION, "true")
-	b = getEnableProbesInjection()
-	assert.True(t, b)
-	// set the env with "True"
-	os.Setenv(utils.ENABLE_PROBES_INJECTION, "True")
-	b = getEnableProbesInjection()
-	assert.True(t, b)
-	// set the env with "false"
-	os.Setenv(utils.ENABLE_PROBES_INJECTION, "false")
-	b = getEnableProbesInjection()
-	assert.False(t, b)
-	// set the env with "False"
-	os.Setenv(utils.ENABLE_PROBES_INJECTION, "False")
-	b = getEnableProbesInjection()
-	assert.False(t, b)
+func TestBuildPod_ProbesInjection(t *testing.T) {
+	cluster := instance.DeepCopy()
+	podName := strings.ToLower(cluster.Name + utils.DashSymbol + string(rayv1.HeadNode) + utils.DashSymbol + utils.FormatInt32(0))
+
+	// The probes are injected by default.
+	podTemplateSpec := DefaultHeadPodTemplate(context.Background(), *cluster, cluster.Spec.HeadGroupSpec, podName, "6379")
+	pod := BuildPod(context.Background(), podTemplateSpec, rayv1.HeadNode, cluster.Spec.HeadGroupSpec.RayStartParams, "6379", nil, utils.GetCRDType(""), "")
+	assert.NotNil(t, pod.Spec.Containers[utils.RayContainerIndex].LivenessProbe)
+	assert.NotNil(t, pod.Spec.Containers[utils.RayContainerIndex].ReadinessProbe)
+
+	// The probes are not injected if the ProbesInjection feature gate is disabled.
+	featureGates, err := features.NewFeatureGates(map[string]bool{string(features.ProbesInjection): false})
+	assert.Nil(t, err)
+	ctx := features.IntoContext(context.Background(), featureGates)
+	cluster = instance.DeepCopy()
+	podTemplateSpec = DefaultHeadPodTemplate(ctx, *cluster, cluster.Spec.HeadGroupSpec, podName, "6379")
+	pod = BuildPod(ctx, podTemplateSpec, rayv1.HeadNode, cluster.Spec.HeadGroupSpec.RayStartParams, "6379", nil, utils.GetCRDType(""), "")
+	assert.Nil(t, pod.Spec.Containers[utils.RayContainerIndex].LivenessProbe)
+	assert.Nil(t, pod.Spec.Containers[utils.RayContainerIndex].ReadinessProbe)
 }
 
 func TestInitLivenessAndReadinessProbe(t *testing.T) {
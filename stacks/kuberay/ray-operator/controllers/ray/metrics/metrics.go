@@ -0,0 +1,342 @@
+package metrics
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"k8s.io/apimachinery/pkg/types"
+	ctrlmetrics "sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+// unknownLabelValue is the label value of a state which is not set yet.
+const unknownLabelValue = "unknown"
+
+// Define all the prometheus metrics of the operator. They are served by the metrics endpoint of controller-runtime.
+var (
+	clustersCreatedCount = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "ray_operator_clusters_created_total",
+			Help: "Counts number of clusters created",
+		},
+		[]string{"namespace"},
+	)
+	clustersDeletedCount = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "ray_operator_clusters_deleted_total",
+			Help: "Counts number of clusters deleted",
+		},
+		[]string{"namespace"},
+	)
+	clustersSuccessfulCount = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "ray_operator_clusters_successful_total",
+			Help: "Counts number of clusters successful",
+		},
+		[]string{"namespace"},
+	)
+	clustersFailedCount = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "ray_operator_clusters_failed_total",
+			Help: "Counts number of clusters failed",
+		},
+		[]string{"namespace"},
+	)
+
+	clusters = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "ray_operator_clusters",
+			Help: "Number of RayClusters by state",
+		},
+		[]string{"namespace", "state"},
+	)
+	clusterReadyDuration = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name:    "ray_operator_cluster_ready_duration_seconds",
+			Help:    "Time from the creation of a RayCluster until all of its Pods are running and ready",
+			Buckets: prometheus.ExponentialBuckets(5, 2, 12),
+		},
+		[]string{"namespace"},
+	)
+	podsCreatedCount = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "ray_operator_pods_created_total",
+			Help: "Counts number of Ray Pods created",
+		},
+		[]string{"namespace", "group"},
+	)
+	podsDeletedCount = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "ray_operator_pods_deleted_total",
+			Help: "Counts number of Ray Pods deleted",
+		},
+		[]string{"namespace", "group"},
+	)
+
+	jobs = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "ray_operator_jobs",
+			Help: "Number of RayJobs by deployment status",
+		},
+		[]string{"namespace", "deployment_status"},
+	)
+	jobDuration = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name:    "ray_operator_job_duration_seconds",
+			Help:    "Time from the start of a RayJob until it completes or fails",
+			Buckets: prometheus.ExponentialBuckets(10, 2, 14),
+		},
+		[]string{"namespace", "deployment_status", "job_status"},
+	)
+
+	services = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "ray_operator_services",
+			Help: "Number of RayServices by status",
+		},
+		[]string{"namespace", "status"},
+	)
+	serviceUpgradeDuration = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name:    "ray_operator_service_upgrade_duration_seconds",
+			Help:    "Time from the creation of the pending RayCluster of a RayService until it is promoted to the active RayCluster",
+			Buckets: prometheus.ExponentialBuckets(10, 2, 12),
+		},
+		[]string{"namespace"},
+	)
+	serveApplications = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "ray_operator_serve_applications",
+			Help: "Number of Ray Serve applications of the active RayCluster of a RayService by status",
+		},
+		[]string{"namespace", "name", "status"},
+	)
+
+	dashboardRequestDuration = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name:    "ray_operator_dashboard_request_duration_seconds",
+			Help:    "Latency of the requests to the Ray dashboard",
+			Buckets: prometheus.DefBuckets,
+		},
+		[]string{"endpoint", "method"},
+	)
+	dashboardRequestErrors = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "ray_operator_dashboard_request_errors_total",
+			Help: "Counts number of failed requests to the Ray dashboard. The code is empty if no response was received",
+		},
+		[]string{"endpoint", "method", "code"},
+	)
+)
+
+func init() {
+	// Register custom metrics with the global prometheus registry
+	ctrlmetrics.Registry.MustRegister(clustersCreatedCount,
+		clustersDeletedCount,
+		clustersSuccessfulCount,
+		clustersFailedCount,
+		clusters,
+		clusterReadyDuration,
+		podsCreatedCount,
+		podsDeletedCount,
+		jobs,
+		jobDuration,
+		services,
+		serviceUpgradeDuration,
+		serveApplications,
+		dashboardRequestDuration,
+		dashboardRequestErrors)
+}
+
+func CreatedClustersCounterInc(namespace string) {
+	clustersCreatedCount.WithLabelValues(namespace).Inc()
+}
+
+// DeletedClustersCounterInc is called by DeleteCluster, because the deletion of a RayCluster is only observed when
+// the RayCluster controller cannot find it anymore.
+func DeletedClustersCounterInc(namespace string) {
+	clustersDeletedCount.WithLabelValues(namespace).Inc()
+}
+
+func SuccessfulClustersCounterInc(namespace string) {
+	clustersSuccessfulCount.WithLabelValues(namespace).Inc()
+}
+
+func FailedClustersCounterInc(namespace string) {
+	clustersFailedCount.WithLabelValues(namespace).Inc()
+}
+
+func CreatedPodsCounterInc(namespace string, group string) {
+	podsCreatedCount.WithLabelValues(namespace, group).Inc()
+}
+
+func DeletedPodsCounterInc(namespace string, group string) {
+	podsDeletedCount.WithLabelValues(namespace, group).Inc()
+}
+
+// stateGauge keeps track of the state of every custom resource of a kind, so that a gauge counts the custom resources
+// by state in each namespace.
+type stateGauge struct {
+	mu     sync.Mutex
+	gauge  *prometheus.GaugeVec
+	states map[types.NamespacedName]string
+}
+
+func newStateGauge(gauge *prometheus.GaugeVec) *stateGauge {
+	return &stateGauge{gauge: gauge, states: map[types.NamespacedName]string{}}
+}
+
+// set records the state of a custom resource and reports whether it was tracked before.
+func (g *stateGauge) set(key types.NamespacedName, state string) bool {
+	if state == "" {
+		state = unknownLabelValue
+	}
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	oldState, tracked := g.states[key]
+	if tracked && oldState == state {
+		return true
+	}
+	if tracked {
+		g.gauge.WithLabelValues(key.Namespace, oldState).Dec()
+	}
+	g.gauge.WithLabelValues(key.Namespace, state).Inc()
+	g.states[key] = state
+	return tracked
+}
+
+// delete forgets a custom resource and reports whether it was tracked.
+func (g *stateGauge) delete(key types.NamespacedName) bool {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	state, tracked := g.states[key]
+	if !tracked {
+		return false
+	}
+	g.gauge.WithLabelValues(key.Namespace, state).Dec()
+	delete(g.states, key)
+	return true
+}
+
+var (
+	clusterStates = newStateGauge(clusters)
+	jobStates     = newStateGauge(jobs)
+	serviceStates = newStateGauge(services)
+)
+
+// SetClusterState records the state of a RayCluster.
+func SetClusterState(namespace string, name string, state string) {
+	clusterStates.set(types.NamespacedName{Namespace: namespace, Name: name}, state)
+}
+
+// DeleteCluster forgets a deleted RayCluster, and counts its deletion if the operator knew it.
+func DeleteCluster(namespace string, name string) {
+	if clusterStates.delete(types.NamespacedName{Namespace: namespace, Name: name}) {
+		DeletedClustersCounterInc(namespace)
+	}
+}
+
+// ObserveClusterReadyDuration records how long a RayCluster took to become ready.
+func ObserveClusterReadyDuration(namespace string, duration time.Duration) {
+	clusterReadyDuration.WithLabelValues(namespace).Observe(duration.Seconds())
+}
+
+// SetJobDeploymentStatus records the deployment status of a RayJob.
+func SetJobDeploymentStatus(namespace string, name string, deploymentStatus string) {
+	jobStates.set(types.NamespacedName{Namespace: namespace, Name: name}, deploymentStatus)
+}
+
+// DeleteJob forgets a deleted RayJob.
+func DeleteJob(namespace string, name string) {
+	jobStates.delete(types.NamespacedName{Namespace: namespace, Name: name})
+}
+
+// ObserveJobDuration records the duration of a finished RayJob.
+func ObserveJobDuration(namespace string, deploymentStatus string, jobStatus string, duration time.Duration) {
+	if jobStatus == "" {
+		jobStatus = unknownLabelValue
+	}
+	jobDuration.WithLabelValues(namespace, deploymentStatus, jobStatus).Observe(duration.Seconds())
+}
+
+// SetServiceStatus records the status of a RayService and the number of its Ray Serve applications by status.
+func SetServiceStatus(namespace string, name string, status string, applicationStatuses []string) {
+	serviceStates.set(types.NamespacedName{Namespace: namespace, Name: name}, status)
+
+	counts := map[string]int{}
+	for _, applicationStatus := range applicationStatuses {
+		if applicationStatus == "" {
+			applicationStatus = unknownLabelValue
+		}
+		counts[applicationStatus]++
+	}
+	// Remove the statuses which no application has anymore, so that the gauge does not report stale series.
+	serveApplications.DeletePartialMatch(prometheus.Labels{"namespace": namespace, "name": name})
+	for applicationStatus, count := range counts {
+		serveApplications.WithLabelValues(namespace, name, applicationStatus).Set(float64(count))
+	}
+}
+
+// DeleteService forgets a deleted RayService.
+func DeleteService(namespace string, name string) {
+	serviceStates.delete(types.NamespacedName{Namespace: namespace, Name: name})
+	serveApplications.DeletePartialMatch(prometheus.Labels{"namespace": namespace, "name": name})
+}
+
+// ObserveServiceUpgradeDuration records how long the pending RayCluster of a RayService took to be promoted.
+func ObserveServiceUpgradeDuration(namespace string, duration time.Duration) {
+	serviceUpgradeDuration.WithLabelValues(namespace).Observe(duration.Seconds())
+}
+
+// dashboardEndpoints maps the path prefixes of the Ray dashboard APIs to the endpoint label, so that the label does not
+// contain the names of jobs or applications.
+var dashboardEndpoints = []struct {
+	prefix   string
+	endpoint string
+}{
+	{"/api/serve/applications/", "serve_applications"},
+	{"/api/jobs/", "jobs"},
+	{"/api/v0/nodes", "nodes"},
+	{"/api/v0/tasks", "tasks"},
+	{"/api/v0/actors", "actors"},
+}
+
+func dashboardEndpoint(path string) string {
+	for _, e := range dashboardEndpoints {
+		if strings.HasPrefix(path, e.prefix) || path == strings.TrimSuffix(e.prefix, "/") {
+			return e.endpoint
+		}
+	}
+	return "other"
+}
+
+// InstrumentDashboardTransport returns a RoundTripper which records the latency and the errors of the requests to the
+// Ray dashboard. A nil RoundTripper instruments http.DefaultTransport, which is looked up on every request like a
+// http.Client without a Transport does.
+func InstrumentDashboardTransport(next http.RoundTripper) http.RoundTripper {
+	return roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		transport := next
+		if transport == nil {
+			transport = http.DefaultTransport
+		}
+		endpoint := dashboardEndpoint(req.URL.Path)
+		start := time.Now()
+		resp, err := transport.RoundTrip(req)
+		dashboardRequestDuration.WithLabelValues(endpoint, req.Method).Observe(time.Since(start).Seconds())
+		if err != nil {
+			dashboardRequestErrors.WithLabelValues(endpoint, req.Method, "").Inc()
+		} else if resp.StatusCode >= http.StatusBadRequest {
+			dashboardRequestErrors.WithLabelValues(endpoint, req.Method, strconv.Itoa(resp.StatusCode)).Inc()
+		}
+		return resp, err
+	})
+}
+
+type roundTripperFunc func(req *http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
@@ -0,0 +1,127 @@
+package metrics
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClusterState(t *testing.T) {
+	namespace := "test-cluster-state"
+	deleted := testutil.ToFloat64(clustersDeletedCount.WithLabelValues(namespace))
+
+	SetClusterState(namespace, "cluster-1", "")
+	SetClusterState(namespace, "cluster-2", "ready")
+	assert.Equal(t, 1.0, testutil.ToFloat64(clusters.WithLabelValues(namespace, unknownLabelValue)))
+	assert.Equal(t, 1.0, testutil.ToFloat64(clusters.WithLabelValues(namespace, "ready")))
+
+	// A state change moves the RayCluster to the new state.
+	SetClusterState(namespace, "cluster-1", "ready")
+	SetClusterState(namespace, "cluster-1", "ready")
+	assert.Equal(t, 0.0, testutil.ToFloat64(clusters.WithLabelValues(namespace, unknownLabelValue)))
+	assert.Equal(t, 2.0, testutil.ToFloat64(clusters.WithLabelValues(namespace, "ready")))
+
+	// Only the deletion of a known RayCluster is counted.
+	DeleteCluster(namespace, "cluster-1")
+	DeleteCluster(namespace, "cluster-1")
+	DeleteCluster(namespace, "cluster-3")
+	assert.Equal(t, 1.0, testutil.ToFloat64(clusters.WithLabelValues(namespace, "ready")))
+	assert.Equal(t, deleted+1, testutil.ToFloat64(clustersDeletedCount.WithLabelValues(namespace)))
+}
+
+func TestJobDeploymentStatus(t *testing.T) {
+	namespace := "test-job-deployment-status"
+
+	SetJobDeploymentStatus(namespace, "job", "Running")
+	assert.Equal(t, 1.0, testutil.ToFloat64(jobs.WithLabelValues(namespace, "Running")))
+	SetJobDeploymentStatus(namespace, "job", "Complete")
+	assert.Equal(t, 0.0, testutil.ToFloat64(jobs.WithLabelValues(namespace, "Running")))
+	assert.Equal(t, 1.0, testutil.ToFloat64(jobs.WithLabelValues(namespace, "Complete")))
+	DeleteJob(namespace, "job")
+	assert.Equal(t, 0.0, testutil.ToFloat64(jobs.WithLabelValues(namespace, "Complete")))
+
+	ObserveJobDuration(namespace, "Failed", "", time.Minute)
+	assert.Equal(t, 1, testutil.CollectAndCount(jobDuration.MustCurryWith(prometheus.Labels{
+		"namespace": namespace, "deployment_status": "Failed", "job_status": unknownLabelValue,
+	})))
+}
+
+func TestServiceStatus(t *testing.T) {
+	namespace := "test-service-status"
+	applications := func(status string) float64 {
+		return testutil.ToFloat64(serveApplications.WithLabelValues(namespace, "service", status))
+	}
+
+	SetServiceStatus(namespace, "service", "Running", []string{"RUNNING", "RUNNING", "DEPLOYING"})
+	assert.Equal(t, 1.0, testutil.ToFloat64(services.WithLabelValues(namespace, "Running")))
+	assert.Equal(t, 2.0, applications("RUNNING"))
+	assert.Equal(t, 1.0, applications("DEPLOYING"))
+
+	// The statuses which no application has anymore are removed.
+	SetServiceStatus(namespace, "service", "Running", []string{"RUNNING", "RUNNING", "RUNNING"})
+	assert.Equal(t, 3.0, applications("RUNNING"))
+	assert.Equal(t, 1, testutil.CollectAndCount(serveApplications.MustCurryWith(prometheus.Labels{"namespace": namespace})))
+
+	DeleteService(namespace, "service")
+	assert.Equal(t, 0.0, testutil.ToFloat64(services.WithLabelValues(namespace, "Running")))
+	assert.Equal(t, 0, testutil.CollectAndCount(serveApplications.MustCurryWith(prometheus.Labels{"namespace": namespace})))
+}
+
+func TestDashboardEndpoint(t *testing.T) {
+	tests := map[string]string{
+		"/api/serve/applications/":  "serve_applications",
+		"/api/jobs/":                "jobs",
+		"/api/jobs/raysubmit_12345": "jobs",
+		"/api/v0/nodes":             "nodes",
+		"/api/v0/tasks":             "tasks",
+		"/api/v0/actors":            "actors",
+		"/api/version":              "other",
+	}
+	for path, expected := range tests {
+		assert.Equal(t, expected, dashboardEndpoint(path), path)
+	}
+}
+
+func TestInstrumentDashboardTransport(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodDelete {
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+	client := http.Client{Transport: InstrumentDashboardTransport(nil)}
+	errorsTotal := func(method string, code string) float64 {
+		return testutil.ToFloat64(dashboardRequestErrors.WithLabelValues("jobs", method, code))
+	}
+	notFound := errorsTotal(http.MethodDelete, "404")
+
+	resp, err := client.Get(server.URL + "/api/jobs/")
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, 0.0, errorsTotal(http.MethodGet, "200"))
+
+	req, err := http.NewRequest(http.MethodDelete, server.URL+"/api/jobs/raysubmit_12345", nil)
+	require.NoError(t, err)
+	resp, err = client.Do(req)
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, notFound+1, errorsTotal(http.MethodDelete, "404"))
+
+	// A request without a response is counted with an empty code.
+	failing := InstrumentDashboardTransport(roundTripperFunc(func(*http.Request) (*http.Response, error) {
+		return nil, errors.New("connection refused")
+	}))
+	noResponse := errorsTotal(http.MethodPost, "")
+	req, err = http.NewRequest(http.MethodPost, server.URL+"/api/jobs/", nil)
+	require.NoError(t, err)
+	_, err = failing.RoundTrip(req)
+	require.Error(t, err)
+	assert.Equal(t, noResponse+1, errorsTotal(http.MethodPost, ""))
+}
@@ -5,16 +5,22 @@ import (
 	"fmt"
 	"os"
 	"reflect"
+	"sort"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/utils/pointer"
 
+	configapi "github.com/ray-project/kuberay/ray-operator/apis/config/v1alpha1"
 	"github.com/ray-project/kuberay/ray-operator/controllers/ray/batchscheduler"
+	schedulerinterface "github.com/ray-project/kuberay/ray-operator/controllers/ray/batchscheduler/interface"
 	"github.com/ray-project/kuberay/ray-operator/controllers/ray/common"
+	"github.com/ray-project/kuberay/ray-operator/controllers/ray/metrics"
 	"github.com/ray-project/kuberay/ray-operator/controllers/ray/utils"
+	"github.com/ray-project/kuberay/ray-operator/pkg/features"
 
 	batchv1 "k8s.io/api/batch/v1"
 	rbacv1 "k8s.io/api/rbac/v1"
@@ -24,6 +30,7 @@ import (
 
 	"github.com/go-logr/logr"
 	routev1 "github.com/openshift/api/route/v1"
+	"github.com/robfig/cron/v3"
 	_ "k8s.io/api/apps/v1beta1"
 	"k8s.io/client-go/discovery"
 	"k8s.io/client-go/rest"
@@ -31,9 +38,11 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	networkingv1 "k8s.io/api/networking/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
 	"k8s.io/apimachinery/pkg/api/resource"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/intstr"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -46,13 +55,25 @@ import (
 
 var (
 	DefaultRequeueDuration = 2 * time.Second
-	ForcedClusterUpgrade   bool
-	EnableBatchScheduler   bool
 
 	// Definition of a index field for pod name
 	podUIDIndexField = "metadata.uid"
 )
 
+const (
+	// After each Pod failure of a group within the failure budget window, the creation of new Pods for the group
+	// waits exponentially longer, starting at `podFailureBackoffBase` and capped at `podFailureBackoffMax`.
+	podFailureBackoffBase = 10 * time.Second
+	podFailureBackoffMax  = 5 * time.Minute
+
+	defaultFailureBudgetMaxFailures   = 5
+	defaultFailureBudgetWindowSeconds = 600
+
+	// Reasons of the actions taken by `AutoSuspend`.
+	autoSuspendIdleTTLReason  = "IdleTTL"
+	autoSuspendScheduleReason = "Schedule"
+)
+
 // getDiscoveryClient returns a discovery client for the current reconciler
 func getDiscoveryClient(config *rest.Config) (*discovery.DiscoveryClient, error) {
 	return discovery.NewDiscoveryClientForConfig(config)
@@ -116,6 +137,10 @@ func NewReconciler(mgr manager.Manager, options RayClusterReconcilerOptions) *Ra
 
 		headSidecarContainers:   options.HeadSidecarContainers,
 		workerSidecarContainers: options.WorkerSidecarContainers,
+		podDefaults:             options.PodDefaults,
+		featureGates:            options.FeatureGates,
+		requeue:                 options.Requeue,
+		dashboardClientFunc:     utils.GetRayDashboardClient,
 	}
 }
 
@@ -130,13 +155,48 @@ type RayClusterReconciler struct {
 	BatchSchedulerMgr *batchscheduler.SchedulerManager
 	IsOpenShift       bool
 
+	// optionsLock guards the options below, which can be updated by UpdateOptions while reconciling.
+	optionsLock             sync.RWMutex
 	headSidecarContainers   []corev1.Container
 	workerSidecarContainers []corev1.Container
+	podDefaults             *configapi.PodDefaults
+	featureGates            *features.FeatureGates
+	requeue                 RequeueOptions
+	dashboardClientFunc     func() utils.RayDashboardClientInterface
 }
 
 type RayClusterReconcilerOptions struct {
 	HeadSidecarContainers   []corev1.Container
 	WorkerSidecarContainers []corev1.Container
+	PodDefaults             *configapi.PodDefaults
+	FeatureGates            *features.FeatureGates
+	// Requeue.Interval defaults to DefaultRequeueDuration. Requeue.SteadyStateInterval is the interval of the
+	// unconditional requeue, and defaults to RAYCLUSTER_DEFAULT_REQUEUE_SECONDS_ENV.
+	Requeue RequeueOptions
+}
+
+// UpdateOptions replaces the options of the reconciler, e.g. when the operator Configuration is reloaded.
+// Reconciliations that are already in progress keep using the previous options.
+func (r *RayClusterReconciler) UpdateOptions(options RayClusterReconcilerOptions) {
+	r.optionsLock.Lock()
+	defer r.optionsLock.Unlock()
+	r.headSidecarContainers = options.HeadSidecarContainers
+	r.workerSidecarContainers = options.WorkerSidecarContainers
+	r.podDefaults = options.PodDefaults
+	r.featureGates = options.FeatureGates
+	r.requeue = options.Requeue
+}
+
+func (r *RayClusterReconciler) getOptions() RayClusterReconcilerOptions {
+	r.optionsLock.RLock()
+	defer r.optionsLock.RUnlock()
+	return RayClusterReconcilerOptions{
+		HeadSidecarContainers:   r.headSidecarContainers,
+		WorkerSidecarContainers: r.workerSidecarContainers,
+		PodDefaults:             r.podDefaults,
+		FeatureGates:            r.featureGates,
+		Requeue:                 r.requeue.withDefaults(DefaultRequeueDuration, 0),
+	}
 }
 
 // Reconcile reads that state of the cluster for a RayCluster object and makes changes based on it
@@ -175,6 +235,7 @@ func (r *RayClusterReconciler) Reconcile(ctx context.Context, request ctrl.Reque
 	// No match found
 	if errors.IsNotFound(err) {
 		r.Log.Info("Read request instance not found error!", "name", request.NamespacedName)
+		metrics.DeleteCluster(request.Namespace, request.Name)
 	} else {
 		r.Log.Error(err, "Read request instance error!")
 	}
@@ -182,6 +243,15 @@ func (r *RayClusterReconciler) Reconcile(ctx context.Context, request ctrl.Reque
 	return ctrl.Result{}, client.IgnoreNotFound(err)
 }
 
+// deletePod deletes a Ray Pod and counts its deletion.
+func (r *RayClusterReconciler) deletePod(ctx context.Context, pod *corev1.Pod) error {
+	if err := r.Delete(ctx, pod); err != nil {
+		return err
+	}
+	metrics.DeletedPodsCounterInc(pod.Namespace, pod.Labels[utils.RayNodeGroupLabelKey])
+	return nil
+}
+
 func (r *RayClusterReconciler) deleteAllPods(ctx context.Context, namespace string, filterLabels client.MatchingLabels) (active int, pods corev1.PodList, err error) {
 	if err = r.List(ctx, &pods, client.InNamespace(namespace), filterLabels); err != nil {
 		return 0, pods, err
@@ -194,7 +264,14 @@ func (r *RayClusterReconciler) deleteAllPods(ctx context.Context, namespace stri
 	}
 	if active > 0 {
 		r.Log.Info(fmt.Sprintf("Deleting all pods with labels %v in %q namespace.", filterLabels, namespace))
-		return active, pods, r.DeleteAllOf(ctx, &corev1.Pod{}, client.InNamespace(namespace), filterLabels)
+		if err = r.DeleteAllOf(ctx, &corev1.Pod{}, client.InNamespace(namespace), filterLabels); err != nil {
+			return active, pods, err
+		}
+		for _, pod := range pods.Items {
+			if pod.DeletionTimestamp.IsZero() {
+				metrics.DeletedPodsCounterInc(namespace, pod.Labels[utils.RayNodeGroupLabelKey])
+			}
+		}
 	}
 	return active, pods, nil
 }
@@ -206,11 +283,18 @@ func (r *RayClusterReconciler) rayClusterReconcile(ctx context.Context, request
 	_ = r.Log.WithValues("raycluster", request.NamespacedName)
 	r.Log.Info("reconciling RayCluster", "cluster name", request.Name)
 
-	// The `enableGCSFTRedisCleanup` is a feature flag introduced in KubeRay v1.0.0. It determines whether
-	// the Redis cleanup job should be activated. Users can disable the feature by setting the environment
-	// variable `ENABLE_GCS_FT_REDIS_CLEANUP` to `false`, and undertake the Redis storage namespace cleanup
-	// manually after the RayCluster CR deletion.
-	enableGCSFTRedisCleanup := strings.ToLower(os.Getenv(utils.ENABLE_GCS_FT_REDIS_CLEANUP)) != "false"
+	// The operator-wide feature gates can be overridden for a single RayCluster with the `ray.io/feature-gates` annotation.
+	options := r.getOptions()
+	featureGates, err := options.FeatureGates.WithOverrides(instance.Annotations[utils.RayFeatureGatesAnnotationKey])
+	if err != nil {
+		r.Recorder.Eventf(instance, corev1.EventTypeWarning, "InvalidFeatureGates", "Ignoring the %s annotation: %v", utils.RayFeatureGatesAnnotationKey, err)
+	}
+	ctx = features.IntoContext(ctx, featureGates)
+
+	// The `GCSFaultToleranceRedisCleanup` feature gate determines whether the Redis cleanup job should be activated.
+	// Users can disable the feature and undertake the Redis storage namespace cleanup manually after the RayCluster
+	// CR deletion.
+	enableGCSFTRedisCleanup := features.Enabled(ctx, features.GCSFaultToleranceRedisCleanup)
 
 	if enableGCSFTRedisCleanup && common.IsGCSFaultToleranceEnabled(*instance) {
 		if instance.DeletionTimestamp.IsZero() {
@@ -221,10 +305,10 @@ func (r *RayClusterReconciler) rayClusterReconcile(ctx context.Context, request
 				controllerutil.AddFinalizer(instance, utils.GCSFaultToleranceRedisCleanupFinalizer)
 				if err := r.Update(ctx, instance); err != nil {
 					r.Log.Error(err, fmt.Sprintf("Failed to add the finalizer %s to the RayCluster.", utils.GCSFaultToleranceRedisCleanupFinalizer))
-					return ctrl.Result{RequeueAfter: DefaultRequeueDuration}, err
+					return ctrl.Result{RequeueAfter: options.Requeue.Interval}, err
 				}
 				// Only start the RayCluster reconciliation after the finalizer is added.
-				return ctrl.Result{RequeueAfter: DefaultRequeueDuration}, nil
+				return ctrl.Result{RequeueAfter: options.Requeue.Interval}, nil
 			}
 		} else {
 			r.Log.Info(
@@ -238,21 +322,21 @@ func (r *RayClusterReconciler) rayClusterReconcile(ctx context.Context, request
 				utils.RayNodeTypeLabelKey: string(rayv1.HeadNode),
 			})
 			if err != nil {
-				return ctrl.Result{RequeueAfter: DefaultRequeueDuration}, err
+				return ctrl.Result{RequeueAfter: options.Requeue.Interval}, err
 			}
 			// Delete all worker Pods if they exist.
 			if _, _, err = r.deleteAllPods(ctx, instance.Namespace, client.MatchingLabels{
 				utils.RayClusterLabelKey:  instance.Name,
 				utils.RayNodeTypeLabelKey: string(rayv1.WorkerNode),
 			}); err != nil {
-				return ctrl.Result{RequeueAfter: DefaultRequeueDuration}, err
+				return ctrl.Result{RequeueAfter: options.Requeue.Interval}, err
 			}
 			if numDeletedHeads > 0 {
 				r.Log.Info(fmt.Sprintf(
 					"Wait for the head Pod %s to be terminated before initiating the Redis cleanup process. "+
 						"The storage namespace %s in Redis cannot be fully deleted if the GCS process on the head Pod is still writing to it.",
 					headPods.Items[0].Name, headPods.Items[0].Annotations[utils.RayExternalStorageNSAnnotationKey]))
-				// Requeue after 10 seconds because it takes much longer than DefaultRequeueDuration (2 seconds) for the head Pod to be terminated.
+				// Requeue after 10 seconds because it takes much longer than the requeue interval (2 seconds by default) for the head Pod to be terminated.
 				return ctrl.Result{RequeueAfter: 10 * time.Second}, nil
 			}
 
@@ -260,7 +344,7 @@ func (r *RayClusterReconciler) rayClusterReconcile(ctx context.Context, request
 			filterLabels := client.MatchingLabels{utils.RayClusterLabelKey: instance.Name, utils.RayNodeTypeLabelKey: string(rayv1.RedisCleanupNode)}
 			redisCleanupJobs := batchv1.JobList{}
 			if err := r.List(ctx, &redisCleanupJobs, client.InNamespace(instance.Namespace), filterLabels); err != nil {
-				return ctrl.Result{RequeueAfter: DefaultRequeueDuration}, err
+				return ctrl.Result{RequeueAfter: options.Requeue.Interval}, err
 			}
 
 			if len(redisCleanupJobs.Items) != 0 {
@@ -271,7 +355,7 @@ func (r *RayClusterReconciler) rayClusterReconcile(ctx context.Context, request
 				if condition, finished := utils.IsJobFinished(&redisCleanupJob); finished {
 					controllerutil.RemoveFinalizer(instance, utils.GCSFaultToleranceRedisCleanupFinalizer)
 					if err := r.Update(ctx, instance); err != nil {
-						return ctrl.Result{RequeueAfter: DefaultRequeueDuration}, err
+						return ctrl.Result{RequeueAfter: options.Requeue.Interval}, err
 					}
 					switch condition {
 					case batchv1.JobComplete:
@@ -288,21 +372,21 @@ func (r *RayClusterReconciler) rayClusterReconcile(ctx context.Context, request
 					}
 					return ctrl.Result{}, nil
 				} else { // the redisCleanupJob is still running
-					return ctrl.Result{RequeueAfter: DefaultRequeueDuration}, nil
+					return ctrl.Result{RequeueAfter: options.Requeue.Interval}, nil
 				}
 			} else {
 				redisCleanupJob := r.buildRedisCleanupJob(ctx, *instance)
 				if err := r.Create(ctx, &redisCleanupJob); err != nil {
 					if errors.IsAlreadyExists(err) {
 						r.Log.Info(fmt.Sprintf("Redis cleanup Job already exists. Requeue the RayCluster CR %s.", instance.Name))
-						return ctrl.Result{RequeueAfter: DefaultRequeueDuration}, nil
+						return ctrl.Result{RequeueAfter: options.Requeue.Interval}, nil
 					}
 					r.Log.Error(err, "Failed to create Redis cleanup Job")
-					return ctrl.Result{RequeueAfter: DefaultRequeueDuration}, err
+					return ctrl.Result{RequeueAfter: options.Requeue.Interval}, err
 				}
 				r.Log.Info("Successfully created Redis cleanup Job", "Job name", redisCleanupJob.Name)
 			}
-			return ctrl.Result{RequeueAfter: DefaultRequeueDuration}, nil
+			return ctrl.Result{RequeueAfter: options.Requeue.Interval}, nil
 		}
 	}
 
@@ -315,32 +399,32 @@ func (r *RayClusterReconciler) rayClusterReconcile(ctx context.Context, request
 		if updateErr := r.updateClusterState(ctx, instance, rayv1.Failed); updateErr != nil {
 			r.Log.Error(updateErr, "RayCluster update state error", "cluster name", request.Name)
 		}
-		return ctrl.Result{RequeueAfter: DefaultRequeueDuration}, err
+		return ctrl.Result{RequeueAfter: options.Requeue.Interval}, err
 	}
 
 	if err := r.reconcileAutoscalerRole(ctx, instance); err != nil {
 		if updateErr := r.updateClusterState(ctx, instance, rayv1.Failed); updateErr != nil {
 			r.Log.Error(updateErr, "RayCluster update state error", "cluster name", request.Name)
 		}
-		return ctrl.Result{RequeueAfter: DefaultRequeueDuration}, err
+		return ctrl.Result{RequeueAfter: options.Requeue.Interval}, err
 	}
 	if err := r.reconcileAutoscalerRoleBinding(ctx, instance); err != nil {
 		if updateErr := r.updateClusterState(ctx, instance, rayv1.Failed); updateErr != nil {
 			r.Log.Error(updateErr, "RayCluster update state error", "cluster name", request.Name)
 		}
-		return ctrl.Result{RequeueAfter: DefaultRequeueDuration}, err
+		return ctrl.Result{RequeueAfter: options.Requeue.Interval}, err
 	}
 	if err := r.reconcileIngress(ctx, instance); err != nil {
 		if updateErr := r.updateClusterState(ctx, instance, rayv1.Failed); updateErr != nil {
 			r.Log.Error(updateErr, "RayCluster update state error", "cluster name", request.Name)
 		}
-		return ctrl.Result{RequeueAfter: DefaultRequeueDuration}, err
+		return ctrl.Result{RequeueAfter: options.Requeue.Interval}, err
 	}
 	if err := r.reconcileHeadService(ctx, instance); err != nil {
 		if updateErr := r.updateClusterState(ctx, instance, rayv1.Failed); updateErr != nil {
 			r.Log.Error(updateErr, "RayCluster update state error", "cluster name", request.Name)
 		}
-		return ctrl.Result{RequeueAfter: DefaultRequeueDuration}, err
+		return ctrl.Result{RequeueAfter: options.Requeue.Interval}, err
 	}
 	// Only reconcile the K8s service for Ray Serve when the "ray.io/enable-serve-service" annotation is set to true.
 	if enableServeServiceValue, exist := instance.Annotations[utils.EnableServeServiceKey]; exist && enableServeServiceValue == utils.EnableServeServiceTrue {
@@ -348,46 +432,82 @@ func (r *RayClusterReconciler) rayClusterReconcile(ctx context.Context, request
 			if updateErr := r.updateClusterState(ctx, instance, rayv1.Failed); updateErr != nil {
 				r.Log.Error(updateErr, "RayCluster update state error", "cluster name", request.Name)
 			}
-			return ctrl.Result{RequeueAfter: DefaultRequeueDuration}, err
+			return ctrl.Result{RequeueAfter: options.Requeue.Interval}, err
 		}
 	}
+	if err := r.reconcileAutoSuspend(ctx, instance); err != nil {
+		r.Recorder.Event(instance, corev1.EventTypeWarning, "AutoSuspendError", err.Error())
+		return ctrl.Result{RequeueAfter: options.Requeue.Interval}, err
+	}
+	admitted, err := r.reconcileBatchSchedulerAdmission(ctx, instance)
+	if err != nil {
+		r.Recorder.Event(instance, corev1.EventTypeWarning, "BatchSchedulerAdmissionError", err.Error())
+		return ctrl.Result{RequeueAfter: options.Requeue.Interval}, err
+	}
 	if err := r.reconcilePods(ctx, instance); err != nil {
-		if updateErr := r.updateClusterState(ctx, instance, rayv1.Failed); updateErr != nil {
-			r.Log.Error(updateErr, "RayCluster update state error", "cluster name", request.Name)
-		}
-		if updateErr := r.updateClusterReason(ctx, instance, err.Error()); updateErr != nil {
-			r.Log.Error(updateErr, "RayCluster update reason error", "cluster name", request.Name)
+		meta.SetStatusCondition(&instance.Status.Conditions, metav1.Condition{
+			Type:               string(rayv1.RayClusterReplicaFailure),
+			Status:             metav1.ConditionTrue,
+			Reason:             string(rayv1.PodReconciliationError),
+			Message:            err.Error(),
+			ObservedGeneration: instance.Generation,
+		})
+		// The status also contains the Pod failures recorded by `reconcilePods`, so it is written in a single update.
+		instance.Status.State = rayv1.Failed
+		instance.Status.Reason = err.Error()
+		if updateErr := r.updateRayClusterStatus(ctx, originalRayClusterInstance.Status, instance); updateErr != nil {
+			r.Log.Error(updateErr, "RayCluster update status error", "cluster name", request.Name)
 		}
 		r.Recorder.Event(instance, corev1.EventTypeWarning, string(rayv1.PodReconciliationError), err.Error())
-		return ctrl.Result{RequeueAfter: DefaultRequeueDuration}, err
+		return ctrl.Result{RequeueAfter: options.Requeue.Interval}, err
 	}
 
 	// Calculate the new status for the RayCluster. Note that the function will deep copy `instance` instead of mutating it.
 	newInstance, err := r.calculateStatus(ctx, instance)
 	if err != nil {
 		r.Log.Info("Got error when calculating new status", "cluster name", request.Name, "error", err)
-		return ctrl.Result{RequeueAfter: DefaultRequeueDuration}, err
+		return ctrl.Result{RequeueAfter: options.Requeue.Interval}, err
 	}
 
-	// Check if need to update the status.
-	if r.inconsistentRayClusterStatus(originalRayClusterInstance.Status, newInstance.Status) {
-		r.Log.Info("rayClusterReconcile", "Update CR status", request.Name, "status", newInstance.Status)
-		if err := r.Status().Update(ctx, newInstance); err != nil {
-			r.Log.Info("Got error when updating status", "cluster name", request.Name, "error", err, "RayCluster", newInstance)
-			return ctrl.Result{RequeueAfter: DefaultRequeueDuration}, err
-		}
+	if err := r.updateRayClusterStatus(ctx, originalRayClusterInstance.Status, newInstance); err != nil {
+		r.Log.Info("Got error when updating status", "cluster name", request.Name, "error", err, "RayCluster", newInstance)
+		return ctrl.Result{RequeueAfter: options.Requeue.Interval}, err
 	}
+	recordRayClusterMetrics(originalRayClusterInstance, newInstance)
 
-	// Unconditionally requeue after the number of seconds specified in the
-	// environment variable RAYCLUSTER_DEFAULT_REQUEUE_SECONDS_ENV. If the
-	// environment variable is not set, requeue after the default value.
-	requeueAfterSeconds, err := strconv.Atoi(os.Getenv(utils.RAYCLUSTER_DEFAULT_REQUEUE_SECONDS_ENV))
-	if err != nil {
-		r.Log.Info(fmt.Sprintf("Environment variable %s is not set, using default value of %d seconds", utils.RAYCLUSTER_DEFAULT_REQUEUE_SECONDS_ENV, utils.RAYCLUSTER_DEFAULT_REQUEUE_SECONDS), "cluster name", request.Name)
-		requeueAfterSeconds = utils.RAYCLUSTER_DEFAULT_REQUEUE_SECONDS
+	// Unconditionally requeue after `Requeue.SteadyStateInterval`. If it is not set, requeue after the number
+	// of seconds specified in the environment variable RAYCLUSTER_DEFAULT_REQUEUE_SECONDS_ENV. If the
+	// environment variable is not set either, requeue after the default value.
+	requeueAfter := options.Requeue.SteadyStateInterval
+	if requeueAfter <= 0 {
+		requeueAfterSeconds, err := strconv.Atoi(os.Getenv(utils.RAYCLUSTER_DEFAULT_REQUEUE_SECONDS_ENV))
+		if err != nil {
+			r.Log.Info(fmt.Sprintf("Environment variable %s is not set, using default value of %d seconds", utils.RAYCLUSTER_DEFAULT_REQUEUE_SECONDS_ENV, utils.RAYCLUSTER_DEFAULT_REQUEUE_SECONDS), "cluster name", request.Name)
+			requeueAfterSeconds = utils.RAYCLUSTER_DEFAULT_REQUEUE_SECONDS
+		}
+		requeueAfter = time.Duration(requeueAfterSeconds) * time.Second
+	}
+	// Requeue earlier if the creation of Pods for a group is backing off after Pod failures.
+	for _, failures := range newInstance.Status.GroupFailures {
+		if backoff := getPodCreationBackoff(newInstance, failures.GroupName, time.Now()); backoff > 0 && backoff < requeueAfter {
+			requeueAfter = backoff
+		}
+	}
+	// Requeue earlier if `AutoSuspend` plans to suspend or resume the RayCluster before then.
+	if action := newInstance.Status.NextScheduledAction; action != nil {
+		if untilAction := time.Until(action.Time.Time); untilAction < requeueAfter {
+			requeueAfter = untilAction
+			if requeueAfter < options.Requeue.Interval {
+				requeueAfter = options.Requeue.Interval
+			}
+		}
+	}
+	// Requeue earlier to check whether the batch scheduler has admitted the RayCluster.
+	if !admitted {
+		requeueAfter = options.Requeue.Interval
 	}
-	r.Log.Info("Unconditional requeue after", "cluster name", request.Name, "seconds", requeueAfterSeconds)
-	return ctrl.Result{RequeueAfter: time.Duration(requeueAfterSeconds) * time.Second}, nil
+	r.Log.Info("Unconditional requeue after", "cluster name", request.Name, "seconds", requeueAfter.Seconds())
+	return ctrl.Result{RequeueAfter: requeueAfter}, nil
 }
 
 // Checks whether the old and new RayClusterStatus are inconsistent by comparing different fields. If the only
@@ -412,15 +532,48 @@ func (r *RayClusterReconciler) inconsistentRayClusterStatus(oldStatus rayv1.RayC
 			oldStatus.MinWorkerReplicas, newStatus.MinWorkerReplicas, oldStatus.MaxWorkerReplicas, newStatus.MaxWorkerReplicas))
 		return true
 	}
+	if oldStatus.UpdatedWorkerReplicas != newStatus.UpdatedWorkerReplicas {
+		r.Log.Info("inconsistentRayClusterStatus", "detect inconsistency", fmt.Sprintf(
+			"old UpdatedWorkerReplicas: %d, new UpdatedWorkerReplicas: %d", oldStatus.UpdatedWorkerReplicas, newStatus.UpdatedWorkerReplicas))
+		return true
+	}
 	if !reflect.DeepEqual(oldStatus.Endpoints, newStatus.Endpoints) || !reflect.DeepEqual(oldStatus.Head, newStatus.Head) {
 		r.Log.Info("inconsistentRayClusterStatus", "detect inconsistency", fmt.Sprintf(
 			"old Endpoints: %v, new Endpoints: %v, old Head: %v, new Head: %v",
 			oldStatus.Endpoints, newStatus.Endpoints, oldStatus.Head, newStatus.Head))
 		return true
 	}
+	if !reflect.DeepEqual(oldStatus.Conditions, newStatus.Conditions) {
+		r.Log.Info("inconsistentRayClusterStatus", "detect inconsistency", fmt.Sprintf(
+			"old Conditions: %v, new Conditions: %v", oldStatus.Conditions, newStatus.Conditions))
+		return true
+	}
+	if !reflect.DeepEqual(oldStatus.LastActivityTime, newStatus.LastActivityTime) || !reflect.DeepEqual(oldStatus.LastScheduleTime, newStatus.LastScheduleTime) ||
+		!reflect.DeepEqual(oldStatus.NextScheduledAction, newStatus.NextScheduledAction) {
+		r.Log.Info("inconsistentRayClusterStatus", "detect inconsistency", fmt.Sprintf(
+			"old LastActivityTime: %v, new LastActivityTime: %v, old LastScheduleTime: %v, new LastScheduleTime: %v, old NextScheduledAction: %v, new NextScheduledAction: %v",
+			oldStatus.LastActivityTime, newStatus.LastActivityTime, oldStatus.LastScheduleTime, newStatus.LastScheduleTime,
+			oldStatus.NextScheduledAction, newStatus.NextScheduledAction))
+		return true
+	}
+	if !reflect.DeepEqual(oldStatus.GroupFailures, newStatus.GroupFailures) {
+		r.Log.Info("inconsistentRayClusterStatus", "detect inconsistency", fmt.Sprintf(
+			"old GroupFailures: %v, new GroupFailures: %v", oldStatus.GroupFailures, newStatus.GroupFailures))
+		return true
+	}
 	return false
 }
 
+// updateRayClusterStatus writes the status of the RayCluster if it differs from the status at the beginning of the
+// reconciliation.
+func (r *RayClusterReconciler) updateRayClusterStatus(ctx context.Context, originalStatus rayv1.RayClusterStatus, newInstance *rayv1.RayCluster) error {
+	if !r.inconsistentRayClusterStatus(originalStatus, newInstance.Status) {
+		return nil
+	}
+	r.Log.Info("rayClusterReconcile", "Update CR status", newInstance.Name, "status", newInstance.Status)
+	return r.Status().Update(ctx, newInstance)
+}
+
 func (r *RayClusterReconciler) reconcileIngress(ctx context.Context, instance *rayv1.RayCluster) error {
 	r.Log.Info("Reconciling Ingress")
 	if instance.Spec.HeadGroupSpec.EnableIngress == nil || !*instance.Spec.HeadGroupSpec.EnableIngress {
@@ -581,6 +734,10 @@ func (r *RayClusterReconciler) reconcileServeService(ctx context.Context, instan
 }
 
 func (r *RayClusterReconciler) reconcilePods(ctx context.Context, instance *rayv1.RayCluster) error {
+	if err := r.reconcileRetainedPods(ctx, instance); err != nil {
+		return err
+	}
+
 	// if RayCluster is suspended, delete all pods and skip reconcile
 	if instance.Spec.Suspend != nil && *instance.Spec.Suspend {
 		clusterLabel := client.MatchingLabels{utils.RayClusterLabelKey: instance.Name}
@@ -600,7 +757,7 @@ func (r *RayClusterReconciler) reconcilePods(ctx context.Context, instance *rayv
 	if err := r.List(ctx, &headPods, client.InNamespace(instance.Namespace), filterLabels); err != nil {
 		return err
 	}
-	if EnableBatchScheduler {
+	if features.Enabled(ctx, features.BatchScheduler) {
 		if scheduler, err := r.BatchSchedulerMgr.GetSchedulerForCluster(instance); err == nil {
 			if err := scheduler.DoBatchSchedulingOnSubmission(ctx, instance); err != nil {
 				return err
@@ -620,23 +777,30 @@ func (r *RayClusterReconciler) reconcilePods(ctx context.Context, instance *rayv
 		shouldDelete, reason := shouldDeletePod(headPod, rayv1.HeadNode)
 		r.Log.Info("reconcilePods", "head Pod", headPod.Name, "shouldDelete", shouldDelete, "reason", reason)
 		if shouldDelete {
-			if err := r.Delete(ctx, &headPod); err != nil {
+			retained, err := r.deleteOrRetainPod(ctx, instance, &headPod)
+			if err != nil {
 				return err
 			}
-			r.Recorder.Eventf(instance, corev1.EventTypeNormal, "Deleted",
-				"Deleted head Pod %s; Pod status: %s; Pod restart policy: %s; Ray container terminated status: %v",
-				headPod.Name, headPod.Status.Phase, headPod.Spec.RestartPolicy, getRayContainerStateTerminated(headPod))
-			return fmt.Errorf(reason)
+			if !retained {
+				r.Recorder.Eventf(instance, corev1.EventTypeNormal, "Deleted",
+					"Deleted head Pod %s; Pod status: %s; Pod restart policy: %s; Ray container terminated status: %v",
+					headPod.Name, headPod.Status.Phase, headPod.Spec.RestartPolicy, getRayContainerStateTerminated(headPod))
+			}
+			recordPodFailure(instance, utils.RayHeadGroupName, headPod, time.Now())
+			return groupFailuresError(instance, utils.RayHeadGroupName, fmt.Errorf("%s", reason))
 		}
 	} else if len(headPods.Items) == 0 {
+		if !r.canCreatePods(instance, utils.RayHeadGroupName) {
+			return nil
+		}
 		// Create head Pod if it does not exist.
 		r.Log.Info("reconcilePods", "Found 0 head Pods; creating a head Pod for the RayCluster.", instance.Name)
-		common.CreatedClustersCounterInc(instance.Namespace)
+		metrics.CreatedClustersCounterInc(instance.Namespace)
 		if err := r.createHeadPod(ctx, *instance); err != nil {
-			common.FailedClustersCounterInc(instance.Namespace)
+			metrics.FailedClustersCounterInc(instance.Namespace)
 			return err
 		}
-		common.SuccessfulClustersCounterInc(instance.Namespace)
+		metrics.SuccessfulClustersCounterInc(instance.Namespace)
 	} else if len(headPods.Items) > 1 {
 		r.Log.Info("reconcilePods", fmt.Sprintf("Found %d head Pods; deleting extra head Pods.", len(headPods.Items)), instance.Name)
 		// TODO (kevin85421): In-place update may not be a good idea.
@@ -651,51 +815,39 @@ func (r *RayClusterReconciler) reconcilePods(ctx context.Context, instance *rayv
 		}
 		// delete all the extra head pod pods
 		for _, extraHeadPodToDelete := range headPods.Items {
-			if err := r.Delete(ctx, &extraHeadPodToDelete); err != nil {
+			if err := r.deletePod(ctx, &extraHeadPodToDelete); err != nil {
 				return err
 			}
 		}
 	}
 
-	if ForcedClusterUpgrade {
-		if len(headPods.Items) == 1 {
-			// head node amount is exactly 1, but we need to check if it has been changed
-			res := utils.PodNotMatchingTemplate(headPods.Items[0], instance.Spec.HeadGroupSpec.Template)
-			if res {
-				r.Log.Info(fmt.Sprintf("need to delete old head pod %s", headPods.Items[0].Name))
-				if err := r.Delete(ctx, &headPods.Items[0]); err != nil {
-					return err
-				}
-				return nil
-			}
+	// The head Pod is only replaced when the head group template or its rayStartParams change.
+	if getUpgradeStrategyType(ctx, instance) != rayv1.OnDeleteUpgrade && len(headPods.Items) == 1 {
+		headPod := headPods.Items[0]
+		headTemplateHash, err := utils.GeneratePodTemplateHash(instance.Spec.HeadGroupSpec.Template, instance.Spec.HeadGroupSpec.RayStartParams)
+		if err != nil {
+			return err
 		}
-
-		// check if WorkerGroupSpecs has been changed and we need to kill worker pods
-		for _, worker := range instance.Spec.WorkerGroupSpecs {
-			workerPods := corev1.PodList{}
-			filterLabels = client.MatchingLabels{utils.RayClusterLabelKey: instance.Name, utils.RayNodeGroupLabelKey: worker.GroupName}
-			if err := r.List(ctx, &workerPods, client.InNamespace(instance.Namespace), filterLabels); err != nil {
+		if headPod.DeletionTimestamp == nil && utils.IsPodOutdated(headPod, headTemplateHash, instance.Spec.HeadGroupSpec.Template) {
+			r.Log.Info(fmt.Sprintf("need to delete old head pod %s", headPod.Name))
+			if err := r.deletePod(ctx, &headPod); err != nil {
 				return err
 			}
-			updatedWorkerPods := false
-			for _, item := range workerPods.Items {
-				if utils.PodNotMatchingTemplate(item, worker.Template) {
-					r.Log.Info(fmt.Sprintf("need to delete old worker pod %s", item.Name))
-					if err := r.Delete(ctx, &item); err != nil {
-						r.Log.Info(fmt.Sprintf("error deleting worker pod %s", item.Name))
-						return err
-					}
-					updatedWorkerPods = true
-				}
-			}
-			if updatedWorkerPods {
-				return nil
-			}
+			r.Recorder.Eventf(instance, corev1.EventTypeNormal, "Deleted",
+				"Deleted head Pod %s because the head group template has changed", headPod.Name)
+			return nil
 		}
 	}
 
 	// Reconcile worker pods now
 	for _, worker := range instance.Spec.WorkerGroupSpecs {
+		if utils.GetWorkerGroupNumOfHosts(worker) > 1 {
+			if err := r.reconcileMultiHostWorkerGroup(ctx, instance, worker); err != nil {
+				return err
+			}
+			continue
+		}
+
 		// workerReplicas will store the target number of pods for this worker group.
 		var workerReplicas int32 = utils.GetWorkerGroupDesiredReplicas(ctx, worker)
 		r.Log.Info("reconcilePods", "desired workerReplicas (always adhering to minReplicas/maxReplica)", workerReplicas, "worker group", worker.GroupName, "maxReplicas", worker.MaxReplicas, "minReplicas", worker.MinReplicas, "replicas", worker.Replicas)
@@ -713,33 +865,39 @@ func (r *RayClusterReconciler) reconcilePods(ctx context.Context, instance *rayv
 		for _, workerPod := range workerPods.Items {
 			shouldDelete, reason := shouldDeletePod(workerPod, rayv1.WorkerNode)
 			r.Log.Info("reconcilePods", "worker Pod", workerPod.Name, "shouldDelete", shouldDelete, "reason", reason)
-			// TODO (kevin85421): We may need to allow users to configure how many `Failed` or `Succeeded` Pods should be kept for debugging purposes.
+			// `Failed` or `Succeeded` Pods are retained for debugging instead if the RayCluster has a failed Pod retention policy.
 			if shouldDelete {
 				numDeletedUnhealthyWorkerPods++
 				deletedWorkers[workerPod.Name] = deleted
-				if err := r.Delete(ctx, &workerPod); err != nil {
+				retained, err := r.deleteOrRetainPod(ctx, instance, &workerPod)
+				if err != nil {
 					return err
 				}
-				r.Recorder.Eventf(instance, corev1.EventTypeNormal, "Deleted",
-					"Deleted worker Pod %s; Pod status: %s; Pod restart policy: %s; Ray container terminated status: %v",
-					workerPod.Name, workerPod.Status.Phase, workerPod.Spec.RestartPolicy, getRayContainerStateTerminated(workerPod))
+				if !retained {
+					r.Recorder.Eventf(instance, corev1.EventTypeNormal, "Deleted",
+						"Deleted worker Pod %s; Pod status: %s; Pod restart policy: %s; Ray container terminated status: %v",
+						workerPod.Name, workerPod.Status.Phase, workerPod.Spec.RestartPolicy, getRayContainerStateTerminated(workerPod))
+				}
+				recordPodFailure(instance, worker.GroupName, workerPod, time.Now())
 			}
 		}
 
 		// If we delete unhealthy Pods, we will not create new Pods in this reconciliation.
 		if numDeletedUnhealthyWorkerPods > 0 {
-			return fmt.Errorf("Delete %d unhealthy worker Pods.", numDeletedUnhealthyWorkerPods)
+			return groupFailuresError(instance, worker.GroupName, fmt.Errorf("Delete %d unhealthy worker Pods.", numDeletedUnhealthyWorkerPods))
 		}
 
 		// Always remove the specified WorkersToDelete - regardless of the value of Replicas.
-		// Essentially WorkersToDelete has to be deleted to meet the expectations of the Autoscaler.
+		// Essentially WorkersToDelete has to be deleted to meet the expectations of the Autoscaler. For the same
+		// reason, the `ray.io/do-not-evict` annotation does not apply to them.
 		r.Log.Info("reconcilePods", "removing the pods in the scaleStrategy of", worker.GroupName)
 		for _, podsToDelete := range worker.ScaleStrategy.WorkersToDelete {
 			pod := corev1.Pod{}
 			pod.Name = podsToDelete
 			pod.Namespace = utils.GetNamespace(instance.ObjectMeta)
+			pod.Labels = map[string]string{utils.RayNodeGroupLabelKey: worker.GroupName}
 			r.Log.Info("Deleting pod", "namespace", pod.Namespace, "name", pod.Name)
-			if err := r.Delete(ctx, &pod); err != nil {
+			if err := r.deletePod(ctx, &pod); err != nil {
 				if !errors.IsNotFound(err) {
 					r.Log.Info("reconcilePods", "Fail to delete Pod", pod.Name, "error", err)
 					return err
@@ -758,10 +916,45 @@ func (r *RayClusterReconciler) reconcilePods(ctx context.Context, instance *rayv
 				runningPods.Items = append(runningPods.Items, pod)
 			}
 		}
+		// Replace the Pods created from an outdated worker group template according to the upgrade strategy.
+		// `upgradeWorkerGroup` creates and deletes Pods towards the desired replicas, and the regular scaling logic,
+		// which also removes excess updated Pods, resumes once all the Pods of the worker group are up to date.
+		if upgradeType := getUpgradeStrategyType(ctx, instance); upgradeType != rayv1.OnDeleteUpgrade {
+			templateHash, err := utils.GeneratePodTemplateHash(worker.Template, worker.RayStartParams)
+			if err != nil {
+				return err
+			}
+			var outdatedPods, updatedPods []corev1.Pod
+			for _, pod := range runningPods.Items {
+				// Terminating Pods are unavailable, and they count towards neither the outdated Pods to replace nor
+				// the updated Pods, so that `MaxUnavailable` and `MaxSurge` are applied to the remaining Pods.
+				if pod.DeletionTimestamp != nil {
+					continue
+				}
+				if utils.IsPodOutdated(pod, templateHash, worker.Template) {
+					outdatedPods = append(outdatedPods, pod)
+				} else {
+					updatedPods = append(updatedPods, pod)
+				}
+			}
+			if len(outdatedPods) > 0 {
+				if !r.canCreatePods(instance, worker.GroupName) {
+					continue
+				}
+				if err := r.upgradeWorkerGroup(ctx, instance, worker, upgradeType, workerReplicas, outdatedPods, updatedPods); err != nil {
+					return err
+				}
+				continue
+			}
+		}
+
 		diff := workerReplicas - int32(len(runningPods.Items))
 		r.Log.Info("reconcilePods", "workerReplicas", workerReplicas, "runningPods", len(runningPods.Items), "diff", diff)
 
 		if diff > 0 {
+			if !r.canCreatePods(instance, worker.GroupName) {
+				continue
+			}
 			// pods need to be added
 			r.Log.Info("reconcilePods", "Number workers to add", diff, "Worker group", worker.GroupName)
 			// create all workers of this group
@@ -779,34 +972,36 @@ func (r *RayClusterReconciler) reconcilePods(ctx context.Context, instance *rayv
 			// diff < 0 indicates the need to delete some Pods to match the desired number of replicas. However,
 			// randomly deleting Pods is certainly not ideal. So, if autoscaling is enabled for the cluster, we
 			// will disable random Pod deletion, making Autoscaler the sole decision-maker for Pod deletions.
-			enableInTreeAutoscaling := (instance.Spec.EnableInTreeAutoscaling != nil) && (*instance.Spec.EnableInTreeAutoscaling)
-
-			// TODO (kevin85421): `enableRandomPodDelete` is a feature flag for KubeRay v0.6.0. If users want to use
-			// the old behavior, they can set the environment variable `ENABLE_RANDOM_POD_DELETE` to `true`. When the
-			// default behavior is stable enough, we can remove this feature flag.
-			enableRandomPodDelete := false
-			if enableInTreeAutoscaling {
-				if s := os.Getenv(utils.ENABLE_RANDOM_POD_DELETE); strings.ToLower(s) == "true" {
-					enableRandomPodDelete = true
+			if isRandomPodDeleteEnabled(ctx, instance) {
+				// diff < 0 means that we need to delete some Pods to meet the desired number of replicas. As in the
+				// upgrade path above, terminating Pods are already on their way out, so only the live Pods in excess of
+				// the desired replicas are deleted.
+				removedWorkers := -int(workerReplicas)
+				for _, pod := range runningPods.Items {
+					if pod.DeletionTimestamp == nil {
+						removedWorkers++
+					}
 				}
-			}
-			// Case 1: If Autoscaler is disabled, we will always enable random Pod deletion no matter the value of the feature flag.
-			// Case 2: If Autoscaler is enabled, we will respect the value of the feature flag. If the feature flag environment variable
-			// is not set, we will disable random Pod deletion by default.
-			if !enableInTreeAutoscaling || enableRandomPodDelete {
-				// diff < 0 means that we need to delete some Pods to meet the desired number of replicas.
-				randomlyRemovedWorkers := -diff
-				r.Log.Info("reconcilePods", "Number workers to delete randomly", randomlyRemovedWorkers, "Worker group", worker.GroupName)
-				for i := 0; i < int(randomlyRemovedWorkers); i++ {
-					randomPodToDelete := runningPods.Items[i]
-					r.Log.Info("Randomly deleting Pod", "progress", fmt.Sprintf("%d / %d", i+1, randomlyRemovedWorkers), "with name", randomPodToDelete.Name)
-					if err := r.Delete(ctx, &randomPodToDelete); err != nil {
+				if removedWorkers <= 0 {
+					r.Log.Info("reconcilePods", "all excess workers are already terminating for group", worker.GroupName)
+					continue
+				}
+				podsToDelete := r.selectWorkerPodsToDelete(ctx, instance, runningPods.Items, removedWorkers)
+				r.Log.Info("reconcilePods", "Number workers to delete", removedWorkers, "Worker group", worker.GroupName)
+				if len(podsToDelete) < removedWorkers {
+					r.Recorder.Eventf(instance, corev1.EventTypeWarning, "ScaleDownBlocked",
+						"Only %d of %d worker Pods of group %s can be deleted; the others are annotated with %s",
+						len(podsToDelete), removedWorkers, worker.GroupName, utils.RayDoNotEvictAnnotationKey)
+				}
+				for i, podToDelete := range podsToDelete {
+					r.Log.Info("Deleting Pod to scale down", "progress", fmt.Sprintf("%d / %d", i+1, len(podsToDelete)), "with name", podToDelete.Name)
+					if err := r.deletePod(ctx, &podToDelete); err != nil {
 						if !errors.IsNotFound(err) {
 							return err
 						}
-						r.Log.Info("reconcilePods", "The worker Pod has already been deleted", randomPodToDelete.Name)
+						r.Log.Info("reconcilePods", "The worker Pod has already been deleted", podToDelete.Name)
 					}
-					r.Recorder.Eventf(instance, corev1.EventTypeNormal, "Deleted", "Deleted Pod %s", randomPodToDelete.Name)
+					r.Recorder.Eventf(instance, corev1.EventTypeNormal, "Deleted", "Deleted Pod %s", podToDelete.Name)
 				}
 			} else {
 				r.Log.Info(fmt.Sprintf("Random Pod deletion is disabled for cluster %s. The only decision-maker for Pod deletions is Autoscaler.", instance.Name))
@@ -816,6 +1011,869 @@ func (r *RayClusterReconciler) reconcilePods(ctx context.Context, instance *rayv
 	return nil
 }
 
+// selectWorkerPodsToDelete picks up to `count` worker Pods to delete when scaling down a worker group. Terminating Pods
+// and Pods annotated with `ray.io/do-not-evict: "true"` are never picked. Pending Pods are picked first, followed by running but not ready
+// Pods, idle Pods, and all the other Pods. Within each tier, the most recently created Pods are picked first because
+// they are the least likely to hold state.
+func (r *RayClusterReconciler) selectWorkerPodsToDelete(ctx context.Context, instance *rayv1.RayCluster, pods []corev1.Pod, count int) []corev1.Pod {
+	candidates := make([]corev1.Pod, 0, len(pods))
+	for _, pod := range pods {
+		if pod.DeletionTimestamp == nil && pod.Annotations[utils.RayDoNotEvictAnnotationKey] != "true" {
+			candidates = append(candidates, pod)
+		}
+	}
+
+	var idleNodeIPs map[string]bool
+	if instance.Annotations[utils.RayIdleAwareScaleDownAnnotationKey] == "true" {
+		idleNodeIPs = r.getIdleNodeIPs(ctx, instance)
+	}
+	sort.SliceStable(candidates, func(i, j int) bool {
+		pi, pj := getScaleDownTier(candidates[i], idleNodeIPs), getScaleDownTier(candidates[j], idleNodeIPs)
+		if pi != pj {
+			return pi < pj
+		}
+		return candidates[j].CreationTimestamp.Before(&candidates[i].CreationTimestamp)
+	})
+
+	if count > len(candidates) {
+		count = len(candidates)
+	}
+	return candidates[:count]
+}
+
+// getScaleDownTier returns the tier of a worker Pod for scale-down. Pods in lower tiers are deleted first.
+func getScaleDownTier(pod corev1.Pod, idleNodeIPs map[string]bool) int {
+	switch {
+	case pod.Status.Phase != corev1.PodRunning:
+		return 0
+	case !utils.IsRunningAndReady(&pod):
+		return 1
+	case idleNodeIPs[pod.Status.PodIP]:
+		return 2
+	default:
+		return 3
+	}
+}
+
+// getIdleNodeIPs queries the Ray dashboard for the IPs of the worker nodes without running tasks or alive actors. The
+// scale-down falls back to the other criteria if the dashboard is unreachable, so errors are only logged.
+func (r *RayClusterReconciler) getIdleNodeIPs(ctx context.Context, instance *rayv1.RayCluster) map[string]bool {
+	dashboardURL, err := utils.FetchHeadServiceURL(ctx, r.Client, instance, utils.DashboardPortName)
+	if err != nil || dashboardURL == "" {
+		r.Log.Info("getIdleNodeIPs", "failed to get the dashboard URL of the RayCluster", instance.Name, "error", err)
+		return nil
+	}
+	rayDashboardClient := r.dashboardClientFunc()
+	rayDashboardClient.InitClient(dashboardURL)
+	ips, err := rayDashboardClient.ListIdleNodeIPs(ctx)
+	if err != nil {
+		r.Log.Info("getIdleNodeIPs", "failed to list the idle nodes of the RayCluster", instance.Name, "error", err)
+		return nil
+	}
+	idleNodeIPs := make(map[string]bool, len(ips))
+	for _, ip := range ips {
+		idleNodeIPs[ip] = true
+	}
+	return idleNodeIPs
+}
+
+// reconcileAutoSuspend suspends or resumes the RayCluster according to `AutoSuspend` by updating `Spec.Suspend`, so
+// that the Pods are deleted or created by the regular suspension logic in `reconcilePods`. The schedules are only
+// evaluated when they are due, so users can still suspend or resume the RayCluster manually in between.
+func (r *RayClusterReconciler) reconcileAutoSuspend(ctx context.Context, instance *rayv1.RayCluster) error {
+	autoSuspend := instance.Spec.AutoSuspend
+	if autoSuspend == nil {
+		instance.Status.LastActivityTime = nil
+		instance.Status.LastScheduleTime = nil
+		instance.Status.NextScheduledAction = nil
+		return nil
+	}
+	suspendSchedule, resumeSchedule, err := getAutoSuspendSchedules(autoSuspend)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	status := instance.Status.DeepCopy()
+	suspended := instance.Spec.Suspend != nil && *instance.Spec.Suspend
+	shouldSuspend := suspended
+	var reason string
+
+	// If both schedules were due since the last evaluation, the one that was due last wins.
+	if suspendSchedule != nil || resumeSchedule != nil {
+		if status.LastScheduleTime == nil {
+			status.LastScheduleTime = &metav1.Time{Time: now}
+		}
+		lastSuspendTime := getLastScheduleTime(suspendSchedule, status.LastScheduleTime.Time, now)
+		lastResumeTime := getLastScheduleTime(resumeSchedule, status.LastScheduleTime.Time, now)
+		if !lastSuspendTime.IsZero() || !lastResumeTime.IsZero() {
+			shouldSuspend = lastSuspendTime.After(lastResumeTime)
+			if shouldSuspend {
+				reason = fmt.Sprintf("the suspend schedule was due at %s", lastSuspendTime.Format(time.RFC3339))
+			} else {
+				reason = fmt.Sprintf("the resume schedule was due at %s", lastResumeTime.Format(time.RFC3339))
+			}
+			status.LastScheduleTime = &metav1.Time{Time: now}
+		}
+	} else {
+		status.LastScheduleTime = nil
+	}
+
+	// The idle TTL only applies to a running RayCluster. It starts counting when the RayCluster is created or resumed.
+	if shouldSuspend || autoSuspend.IdleTTLSeconds == nil {
+		status.LastActivityTime = nil
+	} else if status.LastActivityTime == nil {
+		status.LastActivityTime = &metav1.Time{Time: now}
+	} else if idle, err := r.isRayClusterIdle(ctx, instance); err != nil {
+		r.Log.Info("reconcileAutoSuspend", "failed to check whether the RayCluster is idle", instance.Name, "error", err)
+	} else if !idle {
+		status.LastActivityTime = &metav1.Time{Time: now}
+	} else if idleTTL := time.Duration(*autoSuspend.IdleTTLSeconds) * time.Second; now.Sub(status.LastActivityTime.Time) >= idleTTL {
+		shouldSuspend = true
+		reason = fmt.Sprintf("it has had no running Ray jobs or tasks since %s", status.LastActivityTime.Format(time.RFC3339))
+		status.LastActivityTime = nil
+	}
+
+	if shouldSuspend != suspended {
+		instance.Spec.Suspend = &shouldSuspend
+		if err := r.Update(ctx, instance); err != nil {
+			return err
+		}
+		if shouldSuspend {
+			r.Recorder.Eventf(instance, corev1.EventTypeNormal, "AutoSuspended", "Suspended the RayCluster because %s", reason)
+		} else {
+			r.Recorder.Eventf(instance, corev1.EventTypeNormal, "AutoResumed", "Resumed the RayCluster because %s", reason)
+		}
+	}
+
+	// `Update` overwrites the status with the one stored in the API server, so the new status is set afterwards.
+	status.NextScheduledAction = getNextScheduledAction(autoSuspend, status, shouldSuspend, suspendSchedule, resumeSchedule, now)
+	instance.Status = *status
+	return nil
+}
+
+// reconcileBatchSchedulerAdmission submits the RayCluster to its batch scheduler if the scheduler admits RayClusters as
+// a whole, e.g. Kueue. The scheduler suspends the RayCluster by updating `Spec.Suspend` until it is admitted, so that
+// the Pods are deleted or created by the regular suspension logic in `reconcilePods`. It returns whether the RayCluster
+// is admitted.
+func (r *RayClusterReconciler) reconcileBatchSchedulerAdmission(ctx context.Context, instance *rayv1.RayCluster) (bool, error) {
+	if !features.Enabled(ctx, features.BatchScheduler) {
+		return true, nil
+	}
+	scheduler, err := r.BatchSchedulerMgr.GetSchedulerForCluster(instance)
+	if err != nil {
+		return false, err
+	}
+	admissionScheduler, ok := scheduler.(schedulerinterface.AdmissionBatchScheduler)
+	if !ok {
+		return true, nil
+	}
+
+	original := instance.DeepCopy()
+	admitted, err := admissionScheduler.AdmitRayCluster(ctx, instance)
+	if err != nil {
+		return false, err
+	}
+	if reflect.DeepEqual(original.Labels, instance.Labels) && reflect.DeepEqual(original.Annotations, instance.Annotations) &&
+		reflect.DeepEqual(original.Spec.Suspend, instance.Spec.Suspend) {
+		return admitted, nil
+	}
+	wasSuspended := original.Spec.Suspend != nil && *original.Spec.Suspend
+	// `Update` overwrites the status with the one stored in the API server, so the status is restored afterwards.
+	status := instance.Status.DeepCopy()
+	if err := r.Update(ctx, instance); err != nil {
+		return false, err
+	}
+	instance.Status = *status
+	if suspended := instance.Spec.Suspend != nil && *instance.Spec.Suspend; suspended != wasSuspended {
+		if suspended {
+			r.Recorder.Eventf(instance, corev1.EventTypeNormal, "Suspended", "Suspended the RayCluster until the %s batch scheduler admits it", scheduler.Name())
+		} else {
+			r.Recorder.Eventf(instance, corev1.EventTypeNormal, "Resumed", "Resumed the RayCluster because the %s batch scheduler admitted it", scheduler.Name())
+		}
+	}
+	return admitted, nil
+}
+
+// getAutoSuspendSchedules parses the suspend and resume schedules of `AutoSuspend` in its time zone. A schedule is nil
+// if it is not set.
+func getAutoSuspendSchedules(autoSuspend *rayv1.RayClusterAutoSuspend) (suspendSchedule cron.Schedule, resumeSchedule cron.Schedule, err error) {
+	timeZone := "UTC"
+	if autoSuspend.TimeZone != "" {
+		timeZone = autoSuspend.TimeZone
+	}
+	if autoSuspend.SuspendSchedule != "" {
+		if suspendSchedule, err = cron.ParseStandard(fmt.Sprintf("CRON_TZ=%s %s", timeZone, autoSuspend.SuspendSchedule)); err != nil {
+			return nil, nil, fmt.Errorf("invalid suspendSchedule %q: %w", autoSuspend.SuspendSchedule, err)
+		}
+	}
+	if autoSuspend.ResumeSchedule != "" {
+		if resumeSchedule, err = cron.ParseStandard(fmt.Sprintf("CRON_TZ=%s %s", timeZone, autoSuspend.ResumeSchedule)); err != nil {
+			return nil, nil, fmt.Errorf("invalid resumeSchedule %q: %w", autoSuspend.ResumeSchedule, err)
+		}
+	}
+	return suspendSchedule, resumeSchedule, nil
+}
+
+// getLastScheduleTime returns the last time a schedule was due in (since, now], or the zero time if it was not due.
+// Only the first 1000 times are considered, so a frequent schedule that was not evaluated for a long time resolves
+// to an earlier time.
+func getLastScheduleTime(schedule cron.Schedule, since time.Time, now time.Time) time.Time {
+	var lastScheduleTime time.Time
+	if schedule == nil {
+		return lastScheduleTime
+	}
+	for t, i := schedule.Next(since), 0; !t.IsZero() && !t.After(now) && i < 1000; t, i = schedule.Next(t), i+1 {
+		lastScheduleTime = t
+	}
+	return lastScheduleTime
+}
+
+// getNextScheduledAction returns the next action of `AutoSuspend`. A running RayCluster is suspended by the suspend
+// schedule or when the idle TTL expires, whichever comes first, and a suspended RayCluster is resumed by the resume schedule.
+func getNextScheduledAction(autoSuspend *rayv1.RayClusterAutoSuspend, status *rayv1.RayClusterStatus, suspended bool, suspendSchedule cron.Schedule, resumeSchedule cron.Schedule, now time.Time) *rayv1.RayClusterScheduledAction {
+	if suspended {
+		if resumeSchedule == nil {
+			return nil
+		}
+		return &rayv1.RayClusterScheduledAction{Action: rayv1.ResumeRayCluster, Time: metav1.NewTime(resumeSchedule.Next(now)), Reason: autoSuspendScheduleReason}
+	}
+
+	var action *rayv1.RayClusterScheduledAction
+	if suspendSchedule != nil {
+		action = &rayv1.RayClusterScheduledAction{Action: rayv1.SuspendRayCluster, Time: metav1.NewTime(suspendSchedule.Next(now)), Reason: autoSuspendScheduleReason}
+	}
+	if autoSuspend.IdleTTLSeconds != nil && status.LastActivityTime != nil {
+		idleDeadline := status.LastActivityTime.Add(time.Duration(*autoSuspend.IdleTTLSeconds) * time.Second)
+		if action == nil || idleDeadline.Before(action.Time.Time) {
+			action = &rayv1.RayClusterScheduledAction{Action: rayv1.SuspendRayCluster, Time: metav1.NewTime(idleDeadline), Reason: autoSuspendIdleTTLReason}
+		}
+	}
+	return action
+}
+
+// isRayClusterIdle queries the Ray dashboard for whether the RayCluster has no pending or running Ray jobs and no running Ray tasks.
+func (r *RayClusterReconciler) isRayClusterIdle(ctx context.Context, instance *rayv1.RayCluster) (bool, error) {
+	dashboardURL, err := utils.FetchHeadServiceURL(ctx, r.Client, instance, utils.DashboardPortName)
+	if err != nil {
+		return false, err
+	}
+	rayDashboardClient := r.dashboardClientFunc()
+	rayDashboardClient.InitClient(dashboardURL)
+	return rayDashboardClient.IsClusterIdle(ctx)
+}
+
+// getFailureBudget returns the number of Pod failures tolerated for each group and the window in which they are counted.
+func getFailureBudget(instance *rayv1.RayCluster) (int32, time.Duration) {
+	maxFailures, windowSeconds := int32(defaultFailureBudgetMaxFailures), int32(defaultFailureBudgetWindowSeconds)
+	if budget := instance.Spec.FailureBudget; budget != nil {
+		if budget.MaxFailures != nil {
+			maxFailures = *budget.MaxFailures
+		}
+		if budget.WindowSeconds != nil {
+			windowSeconds = *budget.WindowSeconds
+		}
+	}
+	return maxFailures, time.Duration(windowSeconds) * time.Second
+}
+
+// getGroupTemplateHash returns the hash of the current template and rayStartParams of a group, or an empty string if
+// the group does not exist.
+func getGroupTemplateHash(instance *rayv1.RayCluster, groupName string) string {
+	if groupName == utils.RayHeadGroupName {
+		templateHash, _ := utils.GeneratePodTemplateHash(instance.Spec.HeadGroupSpec.Template, instance.Spec.HeadGroupSpec.RayStartParams)
+		return templateHash
+	}
+	for _, worker := range instance.Spec.WorkerGroupSpecs {
+		if worker.GroupName == groupName {
+			templateHash, _ := utils.GeneratePodTemplateHash(worker.Template, worker.RayStartParams)
+			return templateHash
+		}
+	}
+	return ""
+}
+
+// getGroupFailures returns the Pod failures of a group recorded for its current template and rayStartParams, or nil.
+func getGroupFailures(instance *rayv1.RayCluster, groupName string) *rayv1.RayClusterGroupFailures {
+	templateHash := getGroupTemplateHash(instance, groupName)
+	for i := range instance.Status.GroupFailures {
+		failures := &instance.Status.GroupFailures[i]
+		if failures.GroupName == groupName && failures.TemplateHash == templateHash {
+			return failures
+		}
+	}
+	return nil
+}
+
+// removeStaleGroupFailures removes the Pod failures recorded for an earlier template or rayStartParams of a group, for
+// removed groups, or when the RayCluster has no failure budget. Other spec changes, such as suspending the RayCluster
+// or scaling a group, do not reset the failures.
+func removeStaleGroupFailures(instance *rayv1.RayCluster) {
+	var groupFailures []rayv1.RayClusterGroupFailures
+	for _, failures := range instance.Status.GroupFailures {
+		templateHash := getGroupTemplateHash(instance, failures.GroupName)
+		if instance.Spec.FailureBudget != nil && templateHash != "" && failures.TemplateHash == templateHash {
+			groupFailures = append(groupFailures, failures)
+		}
+	}
+	instance.Status.GroupFailures = groupFailures
+}
+
+// recordPodFailure records the failure of a Pod of a group in the RayCluster status if the RayCluster has a failure
+// budget. Only the failures within the failure budget window are kept.
+func recordPodFailure(instance *rayv1.RayCluster, groupName string, pod corev1.Pod, now time.Time) {
+	if instance.Spec.FailureBudget == nil {
+		return
+	}
+	removeStaleGroupFailures(instance)
+	failures := getGroupFailures(instance, groupName)
+	if failures == nil {
+		instance.Status.GroupFailures = append(instance.Status.GroupFailures, rayv1.RayClusterGroupFailures{
+			GroupName:    groupName,
+			TemplateHash: getGroupTemplateHash(instance, groupName),
+		})
+		failures = &instance.Status.GroupFailures[len(instance.Status.GroupFailures)-1]
+	}
+
+	maxFailures, window := getFailureBudget(instance)
+	failureTimes := []metav1.Time{}
+	for _, failureTime := range failures.FailureTimes {
+		if now.Sub(failureTime.Time) < window {
+			failureTimes = append(failureTimes, failureTime)
+		}
+	}
+	failureTimes = append(failureTimes, metav1.NewTime(now))
+	// `maxFailures + 1` failures are enough to tell that the group exceeded the failure budget.
+	if len(failureTimes) > int(maxFailures)+1 {
+		failureTimes = failureTimes[len(failureTimes)-int(maxFailures)-1:]
+	}
+	failures.FailureTimes = failureTimes
+	failures.LastTerminationMessage = getPodTerminationMessage(pod)
+	if int32(len(failureTimes)) > maxFailures {
+		failures.BudgetExceeded = true
+	}
+}
+
+// getPodTerminationMessage describes how the Ray container of a failed Pod terminated.
+func getPodTerminationMessage(pod corev1.Pod) string {
+	if terminated := getRayContainerStateTerminated(pod); terminated != nil {
+		return fmt.Sprintf("the Ray container of Pod %s terminated with exit code %d; reason: %s; message: %s",
+			pod.Name, terminated.ExitCode, terminated.Reason, terminated.Message)
+	}
+	return fmt.Sprintf("Pod %s is %s; reason: %s; message: %s", pod.Name, pod.Status.Phase, pod.Status.Reason, pod.Status.Message)
+}
+
+// getFailureBudgetExceededReason returns the reason of the Failed state of a RayCluster whose group exceeded the failure budget.
+func getFailureBudgetExceededReason(instance *rayv1.RayCluster, failures rayv1.RayClusterGroupFailures) string {
+	maxFailures, window := getFailureBudget(instance)
+	return fmt.Sprintf("Group %s exceeded the failure budget of %d Pod failures in %v; last failure: %s",
+		failures.GroupName, maxFailures, window, failures.LastTerminationMessage)
+}
+
+// getPodCreationBackoff returns how long the KubeRay operator still waits before it creates new Pods for a group
+// because of the group's recent Pod failures.
+func getPodCreationBackoff(instance *rayv1.RayCluster, groupName string, now time.Time) time.Duration {
+	failures := getGroupFailures(instance, groupName)
+	if instance.Spec.FailureBudget == nil || failures == nil || len(failures.FailureTimes) == 0 {
+		return 0
+	}
+	_, window := getFailureBudget(instance)
+	backoff := time.Duration(0)
+	for _, failureTime := range failures.FailureTimes {
+		if now.Sub(failureTime.Time) >= window {
+			continue
+		}
+		if backoff == 0 {
+			backoff = podFailureBackoffBase
+		} else if backoff *= 2; backoff > podFailureBackoffMax {
+			backoff = podFailureBackoffMax
+		}
+	}
+	lastFailureTime := failures.FailureTimes[len(failures.FailureTimes)-1]
+	if remaining := lastFailureTime.Add(backoff).Sub(now); remaining > 0 {
+		return remaining
+	}
+	return 0
+}
+
+// canCreatePods returns whether the KubeRay operator can create new Pods for a group. No Pods are created for a group
+// that exceeded the failure budget or while the creation is backing off after Pod failures.
+func (r *RayClusterReconciler) canCreatePods(instance *rayv1.RayCluster, groupName string) bool {
+	if failures := getGroupFailures(instance, groupName); failures != nil && failures.BudgetExceeded {
+		r.Log.Info("canCreatePods", "The group exceeded the failure budget and no Pods are created for it", groupName)
+		return false
+	}
+	if backoff := getPodCreationBackoff(instance, groupName, time.Now()); backoff > 0 {
+		r.Log.Info("canCreatePods", "The creation of Pods is backing off after Pod failures", groupName, "backoff", backoff)
+		return false
+	}
+	return true
+}
+
+// groupFailuresError returns an error naming the group if the group exceeded the failure budget, and `err` otherwise.
+// The Pod failures recorded in the RayCluster status are written together with the rest of the status.
+func groupFailuresError(instance *rayv1.RayCluster, groupName string, err error) error {
+	if instance.Spec.FailureBudget == nil {
+		return err
+	}
+	if failures := getGroupFailures(instance, groupName); failures != nil && failures.BudgetExceeded {
+		return fmt.Errorf("%s", getFailureBudgetExceededReason(instance, *failures))
+	}
+	return err
+}
+
+// isRandomPodDeleteEnabled returns whether KubeRay may delete worker Pods of its own accord to match the desired
+// number of replicas.
+func isRandomPodDeleteEnabled(ctx context.Context, instance *rayv1.RayCluster) bool {
+	enableInTreeAutoscaling := (instance.Spec.EnableInTreeAutoscaling != nil) && (*instance.Spec.EnableInTreeAutoscaling)
+
+	// TODO (kevin85421): `RandomPodDelete` is a feature gate for KubeRay v0.6.0. If users want to use
+	// the old behavior, they can enable the feature gate. When the default behavior is stable enough,
+	// we can remove this feature gate.
+	// Case 1: If Autoscaler is disabled, we will always enable random Pod deletion no matter the value of the feature gate.
+	// Case 2: If Autoscaler is enabled, we will respect the value of the feature gate, which is disabled by default.
+	return !enableInTreeAutoscaling || features.Enabled(ctx, features.RandomPodDelete)
+}
+
+// reconcileMultiHostWorkerGroup reconciles a worker group whose replicas consist of `NumOfHosts` Pods each. The Pods of
+// a replica are created together and share the same replica index, and the whole replica is deleted when any of its
+// hosts fails, is missing, or is listed in `WorkersToDelete`.
+func (r *RayClusterReconciler) reconcileMultiHostWorkerGroup(ctx context.Context, instance *rayv1.RayCluster, worker rayv1.WorkerGroupSpec) error {
+	numOfHosts := utils.GetWorkerGroupNumOfHosts(worker)
+	workerReplicas := utils.GetWorkerGroupDesiredReplicas(ctx, worker)
+	r.Log.Info("reconcileMultiHostWorkerGroup", "desired workerReplicas (always adhering to minReplicas/maxReplica)", workerReplicas, "numOfHosts", numOfHosts, "worker group", worker.GroupName)
+
+	workerPods := corev1.PodList{}
+	filterLabels := client.MatchingLabels{utils.RayClusterLabelKey: instance.Name, utils.RayNodeGroupLabelKey: worker.GroupName}
+	if err := r.List(ctx, &workerPods, client.InNamespace(instance.Namespace), filterLabels); err != nil {
+		return err
+	}
+
+	// Group the Pods by replica index. Pods without a valid replica or host index, such as Pods created before
+	// `NumOfHosts` was changed, do not belong to any consistent replica and are deleted.
+	replicas := make(map[int32][]corev1.Pod)
+	var podsToDelete []corev1.Pod
+	for _, pod := range workerPods.Items {
+		replicaIndex, replicaErr := strconv.ParseInt(pod.Labels[utils.RayWorkerReplicaIndexLabelKey], 10, 32)
+		hostIndex, hostErr := strconv.ParseInt(pod.Labels[utils.RayHostIndexLabelKey], 10, 32)
+		if replicaErr != nil || hostErr != nil || replicaIndex < 0 || hostIndex < 0 || hostIndex >= int64(numOfHosts) {
+			r.Log.Info("reconcileMultiHostWorkerGroup", "worker Pod does not belong to a valid replica", pod.Name)
+			podsToDelete = append(podsToDelete, pod)
+			continue
+		}
+		replicas[int32(replicaIndex)] = append(replicas[int32(replicaIndex)], pod)
+	}
+
+	// Delete the whole replica if any of its hosts is unhealthy or missing, or if two Pods claim the same host index.
+	// A replica is only ever created as a whole, so the missing hosts are not recreated individually: if the informer
+	// cache is stale, they may still exist and would be duplicated.
+	numDeletedUnhealthyReplicas := 0
+	for replicaIndex, pods := range replicas {
+		shouldDeleteReplica, reason := shouldDeleteReplica(pods, numOfHosts)
+		r.Log.Info("reconcileMultiHostWorkerGroup", "replica", replicaIndex, "shouldDelete", shouldDeleteReplica, "reason", reason)
+		if shouldDeleteReplica {
+			numDeletedUnhealthyReplicas++
+			for _, pod := range pods {
+				if shouldDelete, _ := shouldDeletePod(pod, rayv1.WorkerNode); shouldDelete {
+					recordPodFailure(instance, worker.GroupName, pod, time.Now())
+				}
+			}
+			if err := r.deleteWorkerReplica(ctx, instance, worker.GroupName, replicaIndex, pods, reason); err != nil {
+				return err
+			}
+			delete(replicas, replicaIndex)
+		}
+	}
+	for i := range podsToDelete {
+		if err := r.deletePod(ctx, &podsToDelete[i]); err != nil && !errors.IsNotFound(err) {
+			return err
+		}
+		r.Recorder.Eventf(instance, corev1.EventTypeNormal, "Deleted",
+			"Deleted worker Pod %s which does not belong to a replica of the multi-host worker group %s", podsToDelete[i].Name, worker.GroupName)
+	}
+
+	// If we delete unhealthy replicas, we will not create new Pods in this reconciliation. The deleted replicas are
+	// recreated as a whole in the next reconciliation.
+	if numDeletedUnhealthyReplicas > 0 || len(podsToDelete) > 0 {
+		return groupFailuresError(instance, worker.GroupName, fmt.Errorf("delete %d unhealthy replicas and %d Pods without a valid replica in worker group %s",
+			numDeletedUnhealthyReplicas, len(podsToDelete), worker.GroupName))
+	}
+
+	// Always remove the replicas of the specified WorkersToDelete - regardless of the value of Replicas.
+	// Deleting a single host would leave the rest of the replica unusable, so the whole replica is deleted.
+	workersToDelete := make(map[string]struct{}, len(worker.ScaleStrategy.WorkersToDelete))
+	for _, podName := range worker.ScaleStrategy.WorkersToDelete {
+		workersToDelete[podName] = struct{}{}
+	}
+	for replicaIndex, pods := range replicas {
+		for _, pod := range pods {
+			if _, ok := workersToDelete[pod.Name]; ok {
+				reason := fmt.Sprintf("the worker Pod %s is listed in workersToDelete", pod.Name)
+				if err := r.deleteWorkerReplica(ctx, instance, worker.GroupName, replicaIndex, pods, reason); err != nil {
+					return err
+				}
+				delete(replicas, replicaIndex)
+				break
+			}
+		}
+	}
+
+	// Replace the replicas created from an outdated worker group template according to the upgrade strategy. The
+	// deleted replicas are recreated from the current template below. `MaxSurge` does not apply to multi-host worker
+	// groups, so at most `MaxUnavailable` ready replicas are replaced at a time. Outdated replicas are kept while the
+	// creation of new replicas is backing off after Pod failures.
+	canCreatePods := r.canCreatePods(instance, worker.GroupName)
+	if upgradeType := getUpgradeStrategyType(ctx, instance); upgradeType != rayv1.OnDeleteUpgrade && canCreatePods {
+		templateHash, err := utils.GeneratePodTemplateHash(worker.Template, worker.RayStartParams)
+		if err != nil {
+			return err
+		}
+		var outdatedReplicas []int32
+		readyReplicas := int32(0)
+		for replicaIndex, pods := range replicas {
+			if isReplicaReady(pods, numOfHosts) {
+				readyReplicas++
+			}
+			for _, pod := range pods {
+				if pod.DeletionTimestamp == nil && utils.IsPodOutdated(pod, templateHash, worker.Template) {
+					outdatedReplicas = append(outdatedReplicas, replicaIndex)
+					break
+				}
+			}
+		}
+		deletionBudget := int32(len(outdatedReplicas))
+		if upgradeType == rayv1.RollingUpdateUpgrade {
+			maxUnavailable, _, err := getRollingUpdateParameters(worker, workerReplicas)
+			if err != nil {
+				return err
+			}
+			deletionBudget = readyReplicas - (workerReplicas - maxUnavailable)
+		}
+		// Outdated replicas which are not ready do not count towards availability, so they are deleted first.
+		sort.Slice(outdatedReplicas, func(i, j int) bool {
+			return !isReplicaReady(replicas[outdatedReplicas[i]], numOfHosts) && isReplicaReady(replicas[outdatedReplicas[j]], numOfHosts)
+		})
+		for _, replicaIndex := range outdatedReplicas {
+			if isReplicaReady(replicas[replicaIndex], numOfHosts) {
+				if deletionBudget <= 0 {
+					break
+				}
+				deletionBudget--
+			}
+			if err := r.deleteWorkerReplica(ctx, instance, worker.GroupName, replicaIndex, replicas[replicaIndex], "the worker group template has changed"); err != nil {
+				return err
+			}
+			delete(replicas, replicaIndex)
+		}
+	}
+
+	replicaIndices := make([]int32, 0, len(replicas))
+	for replicaIndex := range replicas {
+		replicaIndices = append(replicaIndices, replicaIndex)
+	}
+	sort.Slice(replicaIndices, func(i, j int) bool { return replicaIndices[i] < replicaIndices[j] })
+
+	diff := workerReplicas - int32(len(replicaIndices))
+	r.Log.Info("reconcileMultiHostWorkerGroup", "workerReplicas", workerReplicas, "existing replicas", len(replicaIndices), "diff", diff)
+
+	if diff > 0 && canCreatePods {
+		// Create the new replicas with the lowest unused replica indices, so that the indices stay compact.
+		r.Log.Info("reconcileMultiHostWorkerGroup", "Number replicas to add", diff, "Worker group", worker.GroupName)
+		for replicaIndex := int32(0); diff > 0; replicaIndex++ {
+			if _, ok := replicas[replicaIndex]; ok {
+				continue
+			}
+			for hostIndex := int32(0); hostIndex < numOfHosts; hostIndex++ {
+				if err := r.createMultiHostWorkerPod(ctx, *instance, *worker.DeepCopy(), replicaIndex, hostIndex); err != nil {
+					return err
+				}
+			}
+			diff--
+		}
+	} else if diff < 0 {
+		// See `isRandomPodDeleteEnabled` for the reason why the Autoscaler may be the sole decision-maker for deletions.
+		if !isRandomPodDeleteEnabled(ctx, instance) {
+			r.Log.Info(fmt.Sprintf("Random Pod deletion is disabled for cluster %s. The only decision-maker for Pod deletions is Autoscaler.", instance.Name))
+			return nil
+		}
+		// Delete the replicas with the highest replica indices first. Replicas with a Pod annotated with
+		// `ray.io/do-not-evict: "true"` are skipped.
+		numToDelete := int(-diff)
+		for i := len(replicaIndices) - 1; i >= 0 && numToDelete > 0; i-- {
+			replicaIndex := replicaIndices[i]
+			if hasDoNotEvictPod(replicas[replicaIndex]) {
+				continue
+			}
+			if err := r.deleteWorkerReplica(ctx, instance, worker.GroupName, replicaIndex, replicas[replicaIndex], "scaling down the worker group"); err != nil {
+				return err
+			}
+			numToDelete--
+		}
+		if numToDelete > 0 {
+			r.Recorder.Eventf(instance, corev1.EventTypeWarning, "ScaleDownBlocked",
+				"Only %d of %d worker replicas of group %s can be deleted; the others have Pods annotated with %s",
+				int(-diff)-numToDelete, -diff, worker.GroupName, utils.RayDoNotEvictAnnotationKey)
+		}
+	}
+	return nil
+}
+
+// hasDoNotEvictPod returns whether any Pod of a replica of a multi-host worker group is annotated with `ray.io/do-not-evict: "true"`.
+func hasDoNotEvictPod(pods []corev1.Pod) bool {
+	for _, pod := range pods {
+		if pod.Annotations[utils.RayDoNotEvictAnnotationKey] == "true" {
+			return true
+		}
+	}
+	return false
+}
+
+// isReplicaReady returns whether all the hosts of a replica of a multi-host worker group are running and ready.
+// A replica with a terminating host is unavailable.
+func isReplicaReady(pods []corev1.Pod, numOfHosts int32) bool {
+	if int32(len(pods)) < numOfHosts {
+		return false
+	}
+	for i := range pods {
+		if pods[i].DeletionTimestamp != nil || !utils.IsRunningAndReady(&pods[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+// getUpgradeStrategyType returns the upgrade strategy of the RayCluster. RayClusters without an upgrade strategy keep
+// the behavior of the `ForcedClusterUpgrade` feature gate.
+func getUpgradeStrategyType(ctx context.Context, instance *rayv1.RayCluster) rayv1.RayClusterUpgradeType {
+	if instance.Spec.UpgradeStrategy != nil && instance.Spec.UpgradeStrategy.Type != "" {
+		return instance.Spec.UpgradeStrategy.Type
+	}
+	if features.Enabled(ctx, features.ForcedClusterUpgrade) {
+		return rayv1.RecreateUpgrade
+	}
+	return rayv1.OnDeleteUpgrade
+}
+
+// getRollingUpdateParameters resolves the `MaxUnavailable` and `MaxSurge` of a worker group against its desired replicas.
+// Like Deployments, `MaxUnavailable` is rounded down, `MaxSurge` is rounded up, and `MaxUnavailable` is set to 1 if both are 0
+// so that the rolling update can make progress.
+func getRollingUpdateParameters(worker rayv1.WorkerGroupSpec, workerReplicas int32) (maxUnavailable int32, maxSurge int32, err error) {
+	maxUnavailableValue := intstr.FromInt(1)
+	maxSurgeValue := intstr.FromInt(0)
+	if worker.RollingUpdate != nil {
+		if worker.RollingUpdate.MaxUnavailable != nil {
+			maxUnavailableValue = *worker.RollingUpdate.MaxUnavailable
+		}
+		if worker.RollingUpdate.MaxSurge != nil {
+			maxSurgeValue = *worker.RollingUpdate.MaxSurge
+		}
+	}
+	unavailable, err := intstr.GetScaledValueFromIntOrPercent(&maxUnavailableValue, int(workerReplicas), false)
+	if err != nil {
+		return 0, 0, fmt.Errorf("invalid maxUnavailable of worker group %s: %w", worker.GroupName, err)
+	}
+	surge, err := intstr.GetScaledValueFromIntOrPercent(&maxSurgeValue, int(workerReplicas), true)
+	if err != nil {
+		return 0, 0, fmt.Errorf("invalid maxSurge of worker group %s: %w", worker.GroupName, err)
+	}
+	if unavailable == 0 && surge == 0 {
+		unavailable = 1
+	}
+	return int32(unavailable), int32(surge), nil
+}
+
+// upgradeWorkerGroup replaces the outdated Pods of a single-host worker group according to the upgrade strategy.
+// With `Recreate`, all the outdated Pods are deleted at once. With `RollingUpdate`, new Pods are created up to `MaxSurge`
+// Pods above the desired replicas, and outdated Pods are deleted while at least `workerReplicas - MaxUnavailable` Pods
+// stay ready. The next batch starts once the new Pods are ready, because Pod status changes trigger a reconciliation.
+func (r *RayClusterReconciler) upgradeWorkerGroup(ctx context.Context, instance *rayv1.RayCluster, worker rayv1.WorkerGroupSpec, upgradeType rayv1.RayClusterUpgradeType, workerReplicas int32, outdatedPods []corev1.Pod, updatedPods []corev1.Pod) error {
+	r.Log.Info("upgradeWorkerGroup", "worker group", worker.GroupName, "upgrade strategy", upgradeType,
+		"outdated Pods", len(outdatedPods), "updated Pods", len(updatedPods), "workerReplicas", workerReplicas)
+
+	deletionBudget := int32(len(outdatedPods))
+	if upgradeType == rayv1.RollingUpdateUpgrade {
+		maxUnavailable, maxSurge, err := getRollingUpdateParameters(worker, workerReplicas)
+		if err != nil {
+			return err
+		}
+
+		numToCreate := workerReplicas - int32(len(updatedPods))
+		if maxTotal := workerReplicas + maxSurge - int32(len(outdatedPods)+len(updatedPods)); maxTotal < numToCreate {
+			numToCreate = maxTotal
+		}
+		for i := int32(0); i < numToCreate; i++ {
+			r.Log.Info("upgradeWorkerGroup", "creating worker for group", worker.GroupName, fmt.Sprintf("index %d", i), fmt.Sprintf("in total %d", numToCreate))
+			if err := r.createWorkerPod(ctx, *instance, *worker.DeepCopy()); err != nil {
+				return err
+			}
+		}
+
+		readyPods := int32(0)
+		for _, pods := range [][]corev1.Pod{outdatedPods, updatedPods} {
+			for i := range pods {
+				if utils.IsRunningAndReady(&pods[i]) {
+					readyPods++
+				}
+			}
+		}
+		deletionBudget = readyPods - (workerReplicas - maxUnavailable)
+	}
+
+	// Outdated Pods which are not ready do not count towards availability, so they are deleted first.
+	sort.SliceStable(outdatedPods, func(i, j int) bool {
+		return !utils.IsRunningAndReady(&outdatedPods[i]) && utils.IsRunningAndReady(&outdatedPods[j])
+	})
+	for i := range outdatedPods {
+		pod := outdatedPods[i]
+		if utils.IsRunningAndReady(&pod) {
+			if deletionBudget <= 0 {
+				break
+			}
+			deletionBudget--
+		}
+		r.Log.Info(fmt.Sprintf("need to delete old worker pod %s", pod.Name))
+		if err := r.deletePod(ctx, &pod); err != nil {
+			if !errors.IsNotFound(err) {
+				return err
+			}
+			r.Log.Info("upgradeWorkerGroup", "The worker Pod has already been deleted", pod.Name)
+		}
+		r.Recorder.Eventf(instance, corev1.EventTypeNormal, "Deleted",
+			"Deleted worker Pod %s because the template of worker group %s has changed", pod.Name, worker.GroupName)
+	}
+	return nil
+}
+
+// shouldDeleteReplica returns whether a replica of a multi-host worker group should be deleted and the reason.
+// A replica is deleted as a whole if any of its hosts should be deleted or is missing, or if multiple Pods have the
+// same host index.
+func shouldDeleteReplica(pods []corev1.Pod, numOfHosts int32) (bool, string) {
+	hostIndices := make(map[string]string, len(pods))
+	for _, pod := range pods {
+		if shouldDelete, reason := shouldDeletePod(pod, rayv1.WorkerNode); shouldDelete {
+			return true, reason
+		}
+		hostIndex := pod.Labels[utils.RayHostIndexLabelKey]
+		if podName, ok := hostIndices[hostIndex]; ok {
+			return true, fmt.Sprintf("The worker Pods %s and %s have the same host index %s.", podName, pod.Name, hostIndex)
+		}
+		hostIndices[hostIndex] = pod.Name
+	}
+	if int32(len(hostIndices)) < numOfHosts {
+		return true, fmt.Sprintf("Only %d of the %d hosts of the replica exist.", len(hostIndices), numOfHosts)
+	}
+	return false, "All the hosts of the replica are healthy."
+}
+
+// deleteWorkerReplica deletes all the Pods of a replica of a multi-host worker group.
+func (r *RayClusterReconciler) deleteWorkerReplica(ctx context.Context, instance *rayv1.RayCluster, groupName string, replicaIndex int32, pods []corev1.Pod, reason string) error {
+	numDeletedPods := 0
+	for i := range pods {
+		r.Log.Info("Deleting pod", "namespace", pods[i].Namespace, "name", pods[i].Name, "replica", replicaIndex)
+		retained, err := r.deleteOrRetainPod(ctx, instance, &pods[i])
+		if err != nil {
+			if !errors.IsNotFound(err) {
+				return err
+			}
+			r.Log.Info("deleteWorkerReplica", "The worker Pod has already been deleted", pods[i].Name)
+		}
+		if !retained {
+			numDeletedPods++
+		}
+	}
+	r.Recorder.Eventf(instance, corev1.EventTypeNormal, "Deleted",
+		"Deleted %d Pods of replica %d of worker group %s; reason: %s", numDeletedPods, replicaIndex, groupName, reason)
+	return nil
+}
+
+// deleteOrRetainPod deletes a Pod unless the RayCluster's failed Pod retention policy keeps it for debugging. A
+// retained Pod is relabeled out of the RayCluster so that a replacement Pod is created. It returns whether the Pod
+// was retained.
+func (r *RayClusterReconciler) deleteOrRetainPod(ctx context.Context, instance *rayv1.RayCluster, pod *corev1.Pod) (bool, error) {
+	maxPodsPerGroup, _ := getFailedPodRetention(instance)
+	isTerminated := pod.Status.Phase == corev1.PodFailed || pod.Status.Phase == corev1.PodSucceeded
+	if maxPodsPerGroup == 0 || !isTerminated {
+		return false, r.deletePod(ctx, pod)
+	}
+
+	patch := client.MergeFrom(pod.DeepCopy())
+	delete(pod.Labels, utils.RayClusterLabelKey)
+	if pod.Labels == nil {
+		pod.Labels = make(map[string]string)
+	}
+	pod.Labels[utils.RayRetainedFromClusterLabelKey] = instance.Name
+	if pod.Annotations == nil {
+		pod.Annotations = make(map[string]string)
+	}
+	pod.Annotations[utils.RayRetainedAtAnnotationKey] = time.Now().UTC().Format(time.RFC3339)
+	if err := r.Patch(ctx, pod, patch); err != nil {
+		return false, err
+	}
+	r.Log.Info("deleteOrRetainPod", "Retained the failed Pod for debugging", pod.Name)
+	r.Recorder.Eventf(instance, corev1.EventTypeNormal, "RetainedFailedPod",
+		"Retained %s Pod %s of group %s for debugging; Pod status: %s; Ray container terminated status: %v",
+		pod.Labels[utils.RayNodeTypeLabelKey], pod.Name, pod.Labels[utils.RayNodeGroupLabelKey], pod.Status.Phase, getRayContainerStateTerminated(*pod))
+	return true, nil
+}
+
+// reconcileRetainedPods deletes the retained failed Pods of the RayCluster that exceed its failed Pod retention policy.
+// The most recently retained Pods of each group are kept. The TTL is checked at each reconciliation.
+func (r *RayClusterReconciler) reconcileRetainedPods(ctx context.Context, instance *rayv1.RayCluster) error {
+	retainedPods := corev1.PodList{}
+	filterLabels := client.MatchingLabels{utils.RayRetainedFromClusterLabelKey: instance.Name}
+	if err := r.List(ctx, &retainedPods, client.InNamespace(instance.Namespace), filterLabels); err != nil {
+		return err
+	}
+
+	maxPodsPerGroup, ttl := getFailedPodRetention(instance)
+	groups := make(map[string][]corev1.Pod)
+	for _, pod := range retainedPods.Items {
+		groupName := pod.Labels[utils.RayNodeGroupLabelKey]
+		groups[groupName] = append(groups[groupName], pod)
+	}
+	now := time.Now()
+	for _, pods := range groups {
+		sort.SliceStable(pods, func(i, j int) bool { return getPodRetainedAt(pods[j]).Before(getPodRetainedAt(pods[i])) })
+		for i := range pods {
+			expired := ttl != nil && now.After(getPodRetainedAt(pods[i]).Add(*ttl))
+			if int32(i) < maxPodsPerGroup && !expired {
+				continue
+			}
+			r.Log.Info("reconcileRetainedPods", "Deleting the retained failed Pod", pods[i].Name)
+			if err := r.deletePod(ctx, &pods[i]); err != nil && !errors.IsNotFound(err) {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// getFailedPodRetention returns the maximum number of retained failed Pods per group and their TTL. Failed Pods are
+// not retained if the RayCluster has no failed Pod retention policy.
+func getFailedPodRetention(instance *rayv1.RayCluster) (int32, *time.Duration) {
+	policy := instance.Spec.FailedPodRetention
+	if policy == nil {
+		return 0, nil
+	}
+	maxPodsPerGroup := int32(1)
+	if policy.MaxPodsPerGroup != nil {
+		maxPodsPerGroup = *policy.MaxPodsPerGroup
+	}
+	if policy.TTLSeconds == nil {
+		return maxPodsPerGroup, nil
+	}
+	ttl := time.Duration(*policy.TTLSeconds) * time.Second
+	return maxPodsPerGroup, &ttl
+}
+
+// getPodRetainedAt returns when a failed Pod was retained, falling back to its creation time.
+func getPodRetainedAt(pod corev1.Pod) time.Time {
+	if retainedAt, err := time.Parse(time.RFC3339, pod.Annotations[utils.RayRetainedAtAnnotationKey]); err == nil {
+		return retainedAt
+	}
+	return pod.CreationTimestamp.Time
+}
+
 // shouldDeletePod returns whether the Pod should be deleted and the reason
 //
 // @param pod: The Pod to be checked.
@@ -968,9 +2026,9 @@ func (r *RayClusterReconciler) createHeadPod(ctx context.Context, instance rayv1
 		Name:      pod.Name,
 		Namespace: pod.Namespace,
 	}
-	if EnableBatchScheduler {
+	if features.Enabled(ctx, features.BatchScheduler) {
 		if scheduler, err := r.BatchSchedulerMgr.GetSchedulerForCluster(&instance); err == nil {
-			scheduler.AddMetadataToPod(&instance, "headgroup", &pod)
+			scheduler.AddMetadataToPod(&instance, utils.RayHeadGroupName, &pod)
 		} else {
 			return err
 		}
@@ -991,6 +2049,8 @@ func (r *RayClusterReconciler) createHeadPod(ctx context.Context, instance rayv1
 		} else {
 			return err
 		}
+	} else {
+		metrics.CreatedPodsCounterInc(instance.Namespace, utils.RayHeadGroupName)
 	}
 	r.Recorder.Eventf(&instance, corev1.EventTypeNormal, "Created", "Created head pod %s", pod.Name)
 	return nil
@@ -999,11 +2059,22 @@ func (r *RayClusterReconciler) createHeadPod(ctx context.Context, instance rayv1
 func (r *RayClusterReconciler) createWorkerPod(ctx context.Context, instance rayv1.RayCluster, worker rayv1.WorkerGroupSpec) error {
 	// build the pod then create it
 	pod := r.buildWorkerPod(ctx, instance, worker)
+	return r.submitWorkerPod(ctx, instance, worker, pod)
+}
+
+// createMultiHostWorkerPod creates the Pod of the given host of a replica of a multi-host worker group.
+func (r *RayClusterReconciler) createMultiHostWorkerPod(ctx context.Context, instance rayv1.RayCluster, worker rayv1.WorkerGroupSpec, replicaIndex int32, hostIndex int32) error {
+	pod := r.buildWorkerPod(ctx, instance, worker)
+	common.SetMultiHostPodIdentity(&pod, utils.GetWorkerGroupNumOfHosts(worker), replicaIndex, hostIndex)
+	return r.submitWorkerPod(ctx, instance, worker, pod)
+}
+
+func (r *RayClusterReconciler) submitWorkerPod(ctx context.Context, instance rayv1.RayCluster, worker rayv1.WorkerGroupSpec, pod corev1.Pod) error {
 	podIdentifier := types.NamespacedName{
 		Name:      pod.Name,
 		Namespace: pod.Namespace,
 	}
-	if EnableBatchScheduler {
+	if features.Enabled(ctx, features.BatchScheduler) {
 		if scheduler, err := r.BatchSchedulerMgr.GetSchedulerForCluster(&instance); err == nil {
 			scheduler.AddMetadataToPod(&instance, worker.GroupName, &pod)
 		} else {
@@ -1027,6 +2098,8 @@ func (r *RayClusterReconciler) createWorkerPod(ctx context.Context, instance ray
 			r.Log.Error(fmt.Errorf("createWorkerPod error"), "error creating pod", "pod", pod, "err = ", err)
 			return err
 		}
+	} else {
+		metrics.CreatedPodsCounterInc(instance.Namespace, worker.GroupName)
 	}
 	r.Log.Info("Created pod", "Pod ", pod.GenerateName)
 	r.Recorder.Eventf(&instance, corev1.EventTypeNormal, "Created", "Created worker pod %s", pod.Name)
@@ -1035,19 +2108,30 @@ func (r *RayClusterReconciler) createWorkerPod(ctx context.Context, instance ray
 
 // Build head instance pod(s).
 func (r *RayClusterReconciler) buildHeadPod(ctx context.Context, instance rayv1.RayCluster) corev1.Pod {
+	// Hash the head group before building the Pod, because building the Pod fills in missing rayStartParams.
+	templateHash, err := utils.GeneratePodTemplateHash(instance.Spec.HeadGroupSpec.Template, instance.Spec.HeadGroupSpec.RayStartParams)
+	if err != nil {
+		r.Log.Error(err, "Failed to generate the template hash for the head pod")
+	}
 	podName := strings.ToLower(instance.Name + utils.DashSymbol + string(rayv1.HeadNode) + utils.DashSymbol)
 	podName = utils.CheckName(podName)                                            // making sure the name is valid
 	fqdnRayIP := utils.GenerateFQDNServiceName(ctx, instance, instance.Namespace) // Fully Qualified Domain Name
 	// The Ray head port used by workers to connect to the cluster (GCS server port for Ray >= 1.11.0, Redis port for older Ray.)
 	headPort := common.GetHeadPort(instance.Spec.HeadGroupSpec.RayStartParams)
 	autoscalingEnabled := instance.Spec.EnableInTreeAutoscaling
-	podConf := common.DefaultHeadPodTemplate(ctx, instance, instance.Spec.HeadGroupSpec, podName, headPort)
-	if len(r.headSidecarContainers) > 0 {
-		podConf.Spec.Containers = append(podConf.Spec.Containers, r.headSidecarContainers...)
+	options := r.getOptions()
+	headSpec := instance.Spec.HeadGroupSpec
+	if headSpec.Template, err = common.ApplyPodDefaults(headSpec.Template, options.PodDefaults, rayv1.HeadNode, instance.Namespace); err != nil {
+		r.Log.Error(err, "Failed to apply the pod defaults to the head pod")
+	}
+	podConf := common.DefaultHeadPodTemplate(ctx, instance, headSpec, podName, headPort)
+	if len(options.HeadSidecarContainers) > 0 {
+		podConf.Spec.Containers = append(podConf.Spec.Containers, options.HeadSidecarContainers...)
 	}
 	r.Log.Info("head pod labels", "labels", podConf.Labels)
 	creatorCRDType := getCreatorCRDType(instance)
 	pod := common.BuildPod(ctx, podConf, rayv1.HeadNode, instance.Spec.HeadGroupSpec.RayStartParams, headPort, autoscalingEnabled, creatorCRDType, fqdnRayIP)
+	setPodTemplateHash(&pod, templateHash)
 	// Set raycluster instance as the owner and controller
 	if err := controllerutil.SetControllerReference(&instance, &pod, r.Scheme); err != nil {
 		r.Log.Error(err, "Failed to set controller reference for raycluster pod")
@@ -1062,6 +2146,11 @@ func getCreatorCRDType(instance rayv1.RayCluster) utils.CRDType {
 
 // Build worker instance pods.
 func (r *RayClusterReconciler) buildWorkerPod(ctx context.Context, instance rayv1.RayCluster, worker rayv1.WorkerGroupSpec) corev1.Pod {
+	// Hash the worker group before building the Pod, because building the Pod fills in missing rayStartParams.
+	templateHash, err := utils.GeneratePodTemplateHash(worker.Template, worker.RayStartParams)
+	if err != nil {
+		r.Log.Error(err, "Failed to generate the template hash for the worker pod")
+	}
 	podName := strings.ToLower(instance.Name + utils.DashSymbol + string(rayv1.WorkerNode) + utils.DashSymbol + worker.GroupName + utils.DashSymbol)
 	podName = utils.CheckName(podName)                                            // making sure the name is valid
 	fqdnRayIP := utils.GenerateFQDNServiceName(ctx, instance, instance.Namespace) // Fully Qualified Domain Name
@@ -1069,12 +2158,17 @@ func (r *RayClusterReconciler) buildWorkerPod(ctx context.Context, instance rayv
 	// The Ray head port used by workers to connect to the cluster (GCS server port for Ray >= 1.11.0, Redis port for older Ray.)
 	headPort := common.GetHeadPort(instance.Spec.HeadGroupSpec.RayStartParams)
 	autoscalingEnabled := instance.Spec.EnableInTreeAutoscaling
+	options := r.getOptions()
+	if worker.Template, err = common.ApplyPodDefaults(worker.Template, options.PodDefaults, rayv1.WorkerNode, instance.Namespace); err != nil {
+		r.Log.Error(err, "Failed to apply the pod defaults to the worker pod")
+	}
 	podTemplateSpec := common.DefaultWorkerPodTemplate(ctx, instance, worker, podName, fqdnRayIP, headPort)
-	if len(r.workerSidecarContainers) > 0 {
-		podTemplateSpec.Spec.Containers = append(podTemplateSpec.Spec.Containers, r.workerSidecarContainers...)
+	if len(options.WorkerSidecarContainers) > 0 {
+		podTemplateSpec.Spec.Containers = append(podTemplateSpec.Spec.Containers, options.WorkerSidecarContainers...)
 	}
 	creatorCRDType := getCreatorCRDType(instance)
 	pod := common.BuildPod(ctx, podTemplateSpec, rayv1.WorkerNode, worker.RayStartParams, headPort, autoscalingEnabled, creatorCRDType, fqdnRayIP)
+	setPodTemplateHash(&pod, templateHash)
 	// Set raycluster instance as the owner and controller
 	if err := controllerutil.SetControllerReference(&instance, &pod, r.Scheme); err != nil {
 		r.Log.Error(err, "Failed to set controller reference for raycluster pod")
@@ -1083,6 +2177,18 @@ func (r *RayClusterReconciler) buildWorkerPod(ctx context.Context, instance rayv
 	return pod
 }
 
+// setPodTemplateHash records the hash of the template that the Pod was created from. An empty hash is not recorded,
+// so that the Pod is compared against the template with `utils.PodNotMatchingTemplate` instead.
+func setPodTemplateHash(pod *corev1.Pod, templateHash string) {
+	if templateHash == "" {
+		return
+	}
+	if pod.Annotations == nil {
+		pod.Annotations = make(map[string]string)
+	}
+	pod.Annotations[utils.RayPodTemplateHashAnnotationKey] = templateHash
+}
+
 func (r *RayClusterReconciler) buildRedisCleanupJob(ctx context.Context, instance rayv1.RayCluster) batchv1.Job {
 	pod := r.buildHeadPod(ctx, instance)
 	pod.Labels[utils.RayNodeTypeLabelKey] = string(rayv1.RedisCleanupNode)
@@ -1170,12 +2276,18 @@ func (r *RayClusterReconciler) SetupWithManager(mgr ctrl.Manager, reconcileConcu
 		Owns(&corev1.Pod{}).
 		Owns(&corev1.Service{})
 
-	if EnableBatchScheduler {
-		b = batchscheduler.ConfigureReconciler(b)
+	if r.featureGates.Enabled(features.BatchScheduler) {
+		var err error
+		if b, err = batchscheduler.ConfigureReconciler(b, mgr.GetConfig()); err != nil {
+			return err
+		}
 	}
 
 	return b.
-		WithOptions(controller.Options{MaxConcurrentReconciles: reconcileConcurrency}).
+		WithOptions(controller.Options{
+			MaxConcurrentReconciles: reconcileConcurrency,
+			RateLimiter:             newErrorRateLimiter(r.getOptions().Requeue),
+		}).
 		Complete(r)
 }
 
@@ -1192,8 +2304,9 @@ func (r *RayClusterReconciler) calculateStatus(ctx context.Context, instance *ra
 		return nil, err
 	}
 
-	newInstance.Status.AvailableWorkerReplicas = utils.CalculateAvailableReplicas(runtimePods)
+	newInstance.Status.AvailableWorkerReplicas = utils.CalculateAvailableReplicas(newInstance, runtimePods)
 	newInstance.Status.DesiredWorkerReplicas = utils.CalculateDesiredReplicas(ctx, newInstance)
+	newInstance.Status.UpdatedWorkerReplicas = utils.CalculateUpdatedReplicas(newInstance, runtimePods)
 	newInstance.Status.MinWorkerReplicas = utils.CalculateMinReplicas(newInstance)
 	newInstance.Status.MaxWorkerReplicas = utils.CalculateMaxReplicas(newInstance)
 
@@ -1217,10 +2330,23 @@ func (r *RayClusterReconciler) calculateStatus(ctx context.Context, instance *ra
 		}
 	}
 
+	// A RayCluster stays Failed while one of its groups exceeds the failure budget.
+	removeStaleGroupFailures(newInstance)
+	for _, failures := range newInstance.Status.GroupFailures {
+		if failures.BudgetExceeded {
+			newInstance.Status.State = rayv1.Failed
+			newInstance.Status.Reason = getFailureBudgetExceededReason(newInstance, failures)
+			break
+		}
+	}
+
 	if newInstance.Spec.Suspend != nil && *newInstance.Spec.Suspend && len(runtimePods.Items) == 0 {
 		newInstance.Status.State = rayv1.Suspended
 	}
 
+	setRayClusterConditions(newInstance, runtimePods)
+	setRayClusterUpgradeCondition(ctx, newInstance, runtimePods)
+
 	if err := r.updateEndpoints(ctx, newInstance); err != nil {
 		return nil, err
 	}
@@ -1235,6 +2361,127 @@ func (r *RayClusterReconciler) calculateStatus(ctx context.Context, instance *ra
 	return newInstance, nil
 }
 
+// setRayClusterConditions updates `instance.Status.Conditions` based on the Pods of the RayCluster. It is only called
+// after the Pods have been reconciled successfully, so the `ReplicaFailure` condition is removed.
+func setRayClusterConditions(instance *rayv1.RayCluster, runtimePods corev1.PodList) {
+	headPodReady := false
+	for i := range runtimePods.Items {
+		pod := &runtimePods.Items[i]
+		if pod.Labels[utils.RayNodeTypeLabelKey] == string(rayv1.HeadNode) && utils.IsRunningAndReady(pod) {
+			headPodReady = true
+		}
+	}
+	// A replica of a multi-host worker group is only ready when the Pods of all its hosts are ready.
+	readyWorkerReplicas := utils.CalculateReadyReplicas(instance, runtimePods)
+
+	headPodReadyCondition := metav1.Condition{
+		Type:               string(rayv1.HeadPodReady),
+		Status:             metav1.ConditionTrue,
+		Reason:             "HeadPodRunningAndReady",
+		Message:            "The head Pod is running and ready",
+		ObservedGeneration: instance.Generation,
+	}
+	if !headPodReady {
+		headPodReadyCondition.Status = metav1.ConditionFalse
+		headPodReadyCondition.Reason = "HeadPodNotReady"
+		headPodReadyCondition.Message = "The head Pod is not found or not ready"
+	}
+	meta.SetStatusCondition(&instance.Status.Conditions, headPodReadyCondition)
+
+	allWorkersReadyCondition := metav1.Condition{
+		Type:               string(rayv1.AllWorkersReady),
+		Status:             metav1.ConditionTrue,
+		Reason:             "AllWorkersRunningAndReady",
+		ObservedGeneration: instance.Generation,
+	}
+	if readyWorkerReplicas < instance.Status.DesiredWorkerReplicas {
+		allWorkersReadyCondition.Status = metav1.ConditionFalse
+		allWorkersReadyCondition.Reason = "WorkersNotReady"
+	}
+	allWorkersReadyCondition.Message = fmt.Sprintf("%d/%d worker replicas are running and ready", readyWorkerReplicas, instance.Status.DesiredWorkerReplicas)
+	meta.SetStatusCondition(&instance.Status.Conditions, allWorkersReadyCondition)
+
+	// `Provisioned` stays true after all Pods have been ready once, so that restarting a single Pod does not
+	// flip the condition. It is reset when the RayCluster is suspended and all its Pods are deleted.
+	switch {
+	case instance.Status.State == rayv1.Suspended:
+		meta.SetStatusCondition(&instance.Status.Conditions, metav1.Condition{
+			Type:               string(rayv1.RayClusterProvisioned),
+			Status:             metav1.ConditionFalse,
+			Reason:             "RayClusterSuspended",
+			Message:            "The RayCluster is suspended",
+			ObservedGeneration: instance.Generation,
+		})
+	case headPodReady && readyWorkerReplicas >= instance.Status.DesiredWorkerReplicas:
+		meta.SetStatusCondition(&instance.Status.Conditions, metav1.Condition{
+			Type:               string(rayv1.RayClusterProvisioned),
+			Status:             metav1.ConditionTrue,
+			Reason:             "AllPodsRunningAndReady",
+			Message:            "All Ray Pods have been running and ready at least once",
+			ObservedGeneration: instance.Generation,
+		})
+	case !meta.IsStatusConditionTrue(instance.Status.Conditions, string(rayv1.RayClusterProvisioned)):
+		meta.SetStatusCondition(&instance.Status.Conditions, metav1.Condition{
+			Type:               string(rayv1.RayClusterProvisioned),
+			Status:             metav1.ConditionFalse,
+			Reason:             "PodsNotReady",
+			Message:            "Waiting for all Ray Pods to be running and ready",
+			ObservedGeneration: instance.Generation,
+		})
+	}
+
+	meta.RemoveStatusCondition(&instance.Status.Conditions, string(rayv1.RayClusterReplicaFailure))
+}
+
+// recordRayClusterMetrics records the state of a RayCluster, and how long it took to be provisioned when the
+// `Provisioned` condition becomes true. A resumed RayCluster is measured from its creation as well.
+func recordRayClusterMetrics(oldInstance *rayv1.RayCluster, newInstance *rayv1.RayCluster) {
+	metrics.SetClusterState(newInstance.Namespace, newInstance.Name, string(newInstance.Status.State))
+
+	provisioned := meta.FindStatusCondition(newInstance.Status.Conditions, string(rayv1.RayClusterProvisioned))
+	if provisioned == nil || provisioned.Status != metav1.ConditionTrue ||
+		meta.IsStatusConditionTrue(oldInstance.Status.Conditions, string(rayv1.RayClusterProvisioned)) {
+		return
+	}
+	metrics.ObserveClusterReadyDuration(newInstance.Namespace, provisioned.LastTransitionTime.Sub(newInstance.CreationTimestamp.Time))
+}
+
+// setRayClusterUpgradeCondition updates the `UpgradeInProgress` condition based on whether any worker Pods were created
+// from outdated worker group templates. The condition is removed when outdated Pods are not replaced by the KubeRay operator.
+func setRayClusterUpgradeCondition(ctx context.Context, instance *rayv1.RayCluster, runtimePods corev1.PodList) {
+	upgradeType := getUpgradeStrategyType(ctx, instance)
+	if upgradeType == rayv1.OnDeleteUpgrade {
+		meta.RemoveStatusCondition(&instance.Status.Conditions, string(rayv1.RayClusterUpgradeInProgress))
+		return
+	}
+
+	outdatedPods := 0
+	for _, worker := range instance.Spec.WorkerGroupSpecs {
+		templateHash, _ := utils.GeneratePodTemplateHash(worker.Template, worker.RayStartParams)
+		for _, pod := range runtimePods.Items {
+			if pod.Labels[utils.RayNodeTypeLabelKey] == string(rayv1.WorkerNode) && pod.Labels[utils.RayNodeGroupLabelKey] == worker.GroupName &&
+				pod.DeletionTimestamp == nil && utils.IsPodOutdated(pod, templateHash, worker.Template) {
+				outdatedPods++
+			}
+		}
+	}
+
+	upgradeInProgressCondition := metav1.Condition{
+		Type:               string(rayv1.RayClusterUpgradeInProgress),
+		Status:             metav1.ConditionFalse,
+		Reason:             "AllWorkersUpdated",
+		Message:            "All worker Pods are created from the current worker group templates",
+		ObservedGeneration: instance.Generation,
+	}
+	if outdatedPods > 0 {
+		upgradeInProgressCondition.Status = metav1.ConditionTrue
+		upgradeInProgressCondition.Reason = string(upgradeType)
+		upgradeInProgressCondition.Message = fmt.Sprintf("%d worker Pods are created from outdated worker group templates; %d/%d worker replicas are updated",
+			outdatedPods, instance.Status.UpdatedWorkerReplicas, instance.Status.DesiredWorkerReplicas)
+	}
+	meta.SetStatusCondition(&instance.Status.Conditions, upgradeInProgressCondition)
+}
+
 // Best effort to obtain the ip of the head node.
 func (r *RayClusterReconciler) getHeadPodIP(ctx context.Context, instance *rayv1.RayCluster) (string, error) {
 	runtimePods := corev1.PodList{}
@@ -17,14 +17,16 @@ package ray
 
 import (
 	"context"
-	"os"
+	"strings"
 	"testing"
 	"time"
 
+	configapi "github.com/ray-project/kuberay/ray-operator/apis/config/v1alpha1"
 	rayv1 "github.com/ray-project/kuberay/ray-operator/apis/ray/v1"
 	"github.com/ray-project/kuberay/ray-operator/controllers/ray/common"
 	"github.com/ray-project/kuberay/ray-operator/controllers/ray/utils"
 	"github.com/ray-project/kuberay/ray-operator/pkg/client/clientset/versioned/scheme"
+	"github.com/ray-project/kuberay/ray-operator/pkg/features"
 
 	. "github.com/onsi/ginkgo/v2"
 	"github.com/stretchr/testify/assert"
@@ -33,6 +35,7 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	rbacv1 "k8s.io/api/rbac/v1"
 	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/labels"
@@ -401,19 +404,18 @@ func TestReconcile_RemoveWorkersToDelete_RandomDelete(t *testing.T) {
 	// However, we should refactor the tests in the future.
 
 	// This test makes some assumptions about the testRayCluster object.
-	// (1) 1 workerGroup (2) The goal state of the workerGroup is 3 replicas. (3) ENABLE_RANDOM_POD_DELETE is set to true.
-	defer os.Unsetenv(utils.ENABLE_RANDOM_POD_DELETE)
+	// (1) 1 workerGroup (2) The goal state of the workerGroup is 3 replicas. (3) The RandomPodDelete feature gate is enabled.
 
 	assert.Equal(t, 1, len(testRayCluster.Spec.WorkerGroupSpecs), "This test assumes only one worker group.")
 	expectedNumWorkerPods := int(*testRayCluster.Spec.WorkerGroupSpecs[0].Replicas)
 	assert.Equal(t, 3, expectedNumWorkerPods, "This test assumes the expected number of worker pods is 3.")
 
 	// Pod random deletion is enabled in the following two cases:
-	// Case 1: If Autoscaler is disabled, we will always enable random Pod deletion no matter the value of the feature flag.
-	// Case 2: If Autoscaler is enabled, we will respect the value of the feature flag. If the feature flag environment variable
-	// 		   is not set, we will disable random Pod deletion by default.
-	// Here, we enable the Autoscaler and set the feature flag `ENABLE_RANDOM_POD_DELETE` to true to enable random Pod deletion.
-	os.Setenv(utils.ENABLE_RANDOM_POD_DELETE, "true")
+	// Case 1: If Autoscaler is disabled, we will always enable random Pod deletion no matter the value of the feature gate.
+	// Case 2: If Autoscaler is enabled, we will respect the value of the feature gate, which is disabled by default.
+	// Here, we enable the Autoscaler and the feature gate `RandomPodDelete` to enable random Pod deletion.
+	featureGates, err := features.NewFeatureGates(map[string]bool{string(features.RandomPodDelete): true})
+	assert.Nil(t, err)
 	enableInTreeAutoscaling := true
 
 	tests := map[string]struct {
@@ -455,7 +457,7 @@ func TestReconcile_RemoveWorkersToDelete_RandomDelete(t *testing.T) {
 		t.Run(name, func(t *testing.T) {
 			// Initialize a fake client with newScheme and runtimeObjects.
 			fakeClient := clientFake.NewClientBuilder().WithRuntimeObjects(testPods...).Build()
-			ctx := context.Background()
+			ctx := features.IntoContext(context.Background(), featureGates)
 			podList := corev1.PodList{}
 			err := fakeClient.List(ctx, &podList, client.InNamespace(namespaceStr))
 			assert.Nil(t, err, "Fail to get pod list")
@@ -522,17 +524,15 @@ func TestReconcile_RemoveWorkersToDelete_NoRandomDelete(t *testing.T) {
 
 	// This test makes some assumptions about the testRayCluster object.
 	// (1) 1 workerGroup (2) The goal state of the workerGroup is 3 replicas. (3) Disable random Pod deletion.
-	defer os.Unsetenv(utils.ENABLE_RANDOM_POD_DELETE)
 
 	assert.Equal(t, 1, len(testRayCluster.Spec.WorkerGroupSpecs), "This test assumes only one worker group.")
 	expectedNumWorkerPods := int(*testRayCluster.Spec.WorkerGroupSpecs[0].Replicas)
 	assert.Equal(t, 3, expectedNumWorkerPods, "This test assumes the expected number of worker pods is 3.")
 
-	// If Autoscaler is enabled, we will respect the value of the feature flag. If the feature flag environment variable
-	// is not set, we will disable random Pod deletion by default. Hence, this test will disable random Pod deletion.
+	// If Autoscaler is enabled, we will respect the value of the feature gate `RandomPodDelete`, which is disabled
+	// by default. Hence, this test will disable random Pod deletion.
 	// In this case, the cluster won't achieve the target state (i.e., `expectedNumWorkerPods` worker Pods) in one reconciliation.
 	// Instead, the Ray Autoscaler will gradually scale down the cluster in subsequent reconciliations until it reaches the target state.
-	os.Unsetenv(utils.ENABLE_RANDOM_POD_DELETE)
 	enableInTreeAutoscaling := true
 
 	tests := map[string]struct {
@@ -654,6 +654,68 @@ func TestReconcile_RandomDelete_OK(t *testing.T) {
 	}
 }
 
+func TestReconcile_RandomDelete_TerminatingPod(t *testing.T) {
+	setupTest(t)
+
+	// A terminating worker Pod is neither deleted again nor counted towards the Pods to delete, regardless of the
+	// scale-down tier it would fall into, so that exactly the excess live Pods are deleted.
+	tests := map[string]struct {
+		phase corev1.PodPhase
+		ready corev1.ConditionStatus
+	}{
+		"pending terminating Pod": {phase: corev1.PodPending, ready: corev1.ConditionFalse},
+		"ready terminating Pod":   {phase: corev1.PodRunning, ready: corev1.ConditionTrue},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			cluster := testRayCluster.DeepCopy()
+			cluster.Spec.EnableInTreeAutoscaling = nil
+			cluster.Spec.WorkerGroupSpecs[0].Replicas = pointer.Int32(2)
+			cluster.Spec.WorkerGroupSpecs[0].ScaleStrategy.WorkersToDelete = []string{}
+
+			// pod1 to pod4 are live, and pod5 is terminating.
+			var objects []runtime.Object
+			for _, obj := range testPods {
+				pod := obj.(*corev1.Pod).DeepCopy()
+				if pod.Name == "pod5" {
+					deletionTimestamp := metav1.Now()
+					pod.DeletionTimestamp = &deletionTimestamp
+					pod.Finalizers = []string{"ray.io/test"}
+					pod.Status.Phase = tc.phase
+					pod.Status.Conditions = []corev1.PodCondition{{Type: corev1.PodReady, Status: tc.ready}}
+				}
+				objects = append(objects, pod)
+			}
+			fakeClient := clientFake.NewClientBuilder().WithRuntimeObjects(objects...).Build()
+			ctx := context.Background()
+			testRayClusterReconciler := &RayClusterReconciler{
+				Client:   fakeClient,
+				Recorder: &record.FakeRecorder{},
+				Scheme:   scheme.Scheme,
+				Log:      ctrl.Log.WithName("controllers").WithName("RayCluster"),
+			}
+
+			err := testRayClusterReconciler.reconcilePods(ctx, cluster)
+			assert.Nil(t, err, "Fail to reconcile Pods")
+
+			podList := corev1.PodList{}
+			err = fakeClient.List(ctx, &podList, &client.ListOptions{LabelSelector: workerSelector, Namespace: namespaceStr})
+			assert.Nil(t, err, "Fail to get pod list after reconcile")
+			livePods := 0
+			for _, pod := range podList.Items {
+				if pod.DeletionTimestamp == nil {
+					livePods++
+				} else {
+					assert.Equal(t, "pod5", pod.Name)
+				}
+			}
+			assert.Equal(t, 2, livePods, "Only the live Pods in excess of the desired replicas should be deleted")
+			assert.Equal(t, 3, len(podList.Items), "The terminating Pod should be left alone")
+		})
+	}
+}
+
 func TestReconcile_PodDeleted_Diff0_OK(t *testing.T) {
 	setupTest(t)
 
@@ -831,7 +893,6 @@ func TestReconcile_Diff0_WorkersToDelete_OK(t *testing.T) {
 func TestReconcile_PodCrash_DiffLess0_OK(t *testing.T) {
 	setupTest(t)
 
-	defer os.Unsetenv(utils.ENABLE_RANDOM_POD_DELETE)
 	// TODO (kevin85421): The tests in this file are not independent. As a workaround,
 	// I added the assertion to prevent the test logic from being affected by other changes.
 	// However, we should refactor the tests in the future.
@@ -854,14 +915,14 @@ func TestReconcile_PodCrash_DiffLess0_OK(t *testing.T) {
 	oldNumWorkerPods := len(testPods) - numHeadPods
 
 	tests := map[string]struct {
-		ENABLE_RANDOM_POD_DELETE bool
+		enableRandomPodDelete bool
 	}{
-		// When Autoscaler is enabled, the random Pod deletion is controleld by the feature flag `ENABLE_RANDOM_POD_DELETE`.
+		// When Autoscaler is enabled, the random Pod deletion is controlled by the feature gate `RandomPodDelete`.
 		"Enable random Pod deletion": {
-			ENABLE_RANDOM_POD_DELETE: true,
+			enableRandomPodDelete: true,
 		},
 		"Disable random Pod deletion": {
-			ENABLE_RANDOM_POD_DELETE: false,
+			enableRandomPodDelete: false,
 		},
 	}
 
@@ -885,16 +946,14 @@ func TestReconcile_PodCrash_DiffLess0_OK(t *testing.T) {
 				Log:      ctrl.Log.WithName("controllers").WithName("RayCluster"),
 			}
 
-			if tc.ENABLE_RANDOM_POD_DELETE {
-				os.Setenv(utils.ENABLE_RANDOM_POD_DELETE, "true")
-			} else {
-				os.Setenv(utils.ENABLE_RANDOM_POD_DELETE, "false")
-			}
+			featureGates, err := features.NewFeatureGates(map[string]bool{string(features.RandomPodDelete): tc.enableRandomPodDelete})
+			assert.Nil(t, err)
+			ctx = features.IntoContext(ctx, featureGates)
 			cluster := testRayCluster.DeepCopy()
-			// Case 1: ENABLE_RANDOM_POD_DELETE is true.
+			// Case 1: RandomPodDelete is enabled.
 			// 	Since the desired state of the workerGroup is 3 replicas, the controller will delete a worker Pod randomly.
 			//  After the deletion, the number of worker Pods should be 3.
-			// Case 2: ENABLE_RANDOM_POD_DELETE is false.
+			// Case 2: RandomPodDelete is disabled.
 			//  Only the Pod in the `workersToDelete` will be deleted. After the deletion, the number of worker Pods should be 4.
 			err = testRayClusterReconciler.reconcilePods(ctx, cluster)
 			assert.Nil(t, err, "Fail to reconcile Pods")
@@ -905,13 +964,13 @@ func TestReconcile_PodCrash_DiffLess0_OK(t *testing.T) {
 			})
 			assert.Nil(t, err, "Fail to get pod list after reconcile")
 
-			if tc.ENABLE_RANDOM_POD_DELETE {
-				// Case 1: ENABLE_RANDOM_POD_DELETE is true.
+			if tc.enableRandomPodDelete {
+				// Case 1: RandomPodDelete is enabled.
 				assert.Equal(t, expectedNumWorkerPods, len(podList.Items))
 				assert.Equal(t, expectedNumWorkerPods, getNotFailedPodItemNum(podList),
 					"Replica number is wrong after reconcile expect %d actual %d", expectReplicaNum, getNotFailedPodItemNum(podList))
 			} else {
-				// Case 2: ENABLE_RANDOM_POD_DELETE is false.
+				// Case 2: RandomPodDelete is disabled.
 				assert.Equal(t, expectedNumWorkerPods+1, len(podList.Items))
 				assert.Equal(t, expectedNumWorkerPods+1, getNotFailedPodItemNum(podList),
 					"Replica number is wrong after reconcile expect %d actual %d", expectReplicaNum, getNotFailedPodItemNum(podList))
@@ -1555,6 +1614,15 @@ func TestInconsistentRayClusterStatus(t *testing.T) {
 	newStatus = oldStatus.DeepCopy()
 	newStatus.ObservedGeneration = oldStatus.ObservedGeneration + 1
 	assert.False(t, r.inconsistentRayClusterStatus(oldStatus, *newStatus))
+
+	// Case 11: `Conditions` is different => return true
+	newStatus = oldStatus.DeepCopy()
+	meta.SetStatusCondition(&newStatus.Conditions, metav1.Condition{
+		Type:   string(rayv1.HeadPodReady),
+		Status: metav1.ConditionTrue,
+		Reason: "HeadPodRunningAndReady",
+	})
+	assert.True(t, r.inconsistentRayClusterStatus(oldStatus, *newStatus))
 }
 
 func TestCalculateStatus(t *testing.T) {
@@ -1604,6 +1672,58 @@ func TestCalculateStatus(t *testing.T) {
 	assert.Equal(t, headServiceIP, newInstance.Status.Head.ServiceIP)
 }
 
+func TestSetRayClusterConditions(t *testing.T) {
+	readyPod := func(name string, nodeType rayv1.RayNodeType) corev1.Pod {
+		return corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:   name,
+				Labels: map[string]string{utils.RayNodeTypeLabelKey: string(nodeType)},
+			},
+			Status: corev1.PodStatus{
+				Phase:      corev1.PodRunning,
+				Conditions: []corev1.PodCondition{{Type: corev1.PodReady, Status: corev1.ConditionTrue}},
+			},
+		}
+	}
+	cluster := &rayv1.RayCluster{
+		ObjectMeta: metav1.ObjectMeta{Generation: 3},
+		Status: rayv1.RayClusterStatus{
+			DesiredWorkerReplicas: 2,
+			Conditions: []metav1.Condition{
+				{Type: string(rayv1.RayClusterReplicaFailure), Status: metav1.ConditionTrue, Reason: string(rayv1.PodReconciliationError)},
+			},
+		},
+	}
+
+	// Only the head Pod is ready.
+	pods := corev1.PodList{Items: []corev1.Pod{readyPod("head", rayv1.HeadNode), readyPod("worker-1", rayv1.WorkerNode)}}
+	setRayClusterConditions(cluster, pods)
+	assert.True(t, meta.IsStatusConditionTrue(cluster.Status.Conditions, string(rayv1.HeadPodReady)))
+	assert.True(t, meta.IsStatusConditionFalse(cluster.Status.Conditions, string(rayv1.AllWorkersReady)))
+	assert.True(t, meta.IsStatusConditionFalse(cluster.Status.Conditions, string(rayv1.RayClusterProvisioned)))
+	assert.Nil(t, meta.FindStatusCondition(cluster.Status.Conditions, string(rayv1.RayClusterReplicaFailure)))
+	assert.Equal(t, int64(3), meta.FindStatusCondition(cluster.Status.Conditions, string(rayv1.HeadPodReady)).ObservedGeneration)
+
+	// All Pods are ready.
+	pods.Items = append(pods.Items, readyPod("worker-2", rayv1.WorkerNode))
+	setRayClusterConditions(cluster, pods)
+	assert.True(t, meta.IsStatusConditionTrue(cluster.Status.Conditions, string(rayv1.AllWorkersReady)))
+	assert.True(t, meta.IsStatusConditionTrue(cluster.Status.Conditions, string(rayv1.RayClusterProvisioned)))
+
+	// `Provisioned` stays true when a worker Pod is no longer ready.
+	pods.Items = pods.Items[:2]
+	setRayClusterConditions(cluster, pods)
+	assert.True(t, meta.IsStatusConditionFalse(cluster.Status.Conditions, string(rayv1.AllWorkersReady)))
+	assert.True(t, meta.IsStatusConditionTrue(cluster.Status.Conditions, string(rayv1.RayClusterProvisioned)))
+
+	// `Provisioned` is reset when the RayCluster is suspended.
+	cluster.Status.State = rayv1.Suspended
+	cluster.Status.DesiredWorkerReplicas = 0
+	setRayClusterConditions(cluster, corev1.PodList{})
+	assert.True(t, meta.IsStatusConditionFalse(cluster.Status.Conditions, string(rayv1.HeadPodReady)))
+	assert.True(t, meta.IsStatusConditionFalse(cluster.Status.Conditions, string(rayv1.RayClusterProvisioned)))
+}
+
 func Test_TerminatedWorkers_NoAutoscaler(t *testing.T) {
 	setupTest(t)
 
@@ -1976,8 +2096,6 @@ func Test_ShouldDeletePod(t *testing.T) {
 func Test_RedisCleanupFeatureFlag(t *testing.T) {
 	setupTest(t)
 
-	defer os.Unsetenv(utils.ENABLE_GCS_FT_REDIS_CLEANUP)
-
 	newScheme := runtime.NewScheme()
 	_ = rayv1.AddToScheme(newScheme)
 	_ = corev1.AddToScheme(newScheme)
@@ -1991,36 +2109,53 @@ func Test_RedisCleanupFeatureFlag(t *testing.T) {
 	gcsFTEnabledCluster.Spec.EnableInTreeAutoscaling = nil
 	ctx := context.Background()
 
-	// The KubeRay operator environment variable `ENABLE_GCS_FT_REDIS_CLEANUP` is used to enable/disable
-	// the GCS FT Redis cleanup feature. If the feature flag is not set, the GCS FT Redis cleanup feature
-	// is enabled by default.
+	// The feature gate `GCSFaultToleranceRedisCleanup` is used to enable/disable the GCS FT Redis cleanup feature.
+	// If the feature gate is not set, the GCS FT Redis cleanup feature is enabled by default. The operator-wide
+	// feature gate can be overridden for a single RayCluster with the `ray.io/feature-gates` annotation.
 	tests := map[string]struct {
-		enableGCSFTRedisCleanup string
-		expectedNumFinalizers   int
+		featureGates          map[string]bool
+		annotation            string
+		expectedNumFinalizers int
 	}{
 		"Enable GCS FT Redis cleanup": {
-			enableGCSFTRedisCleanup: "true",
-			expectedNumFinalizers:   1,
+			featureGates:          map[string]bool{string(features.GCSFaultToleranceRedisCleanup): true},
+			expectedNumFinalizers: 1,
 		},
 		"Disable GCS FT Redis cleanup": {
-			enableGCSFTRedisCleanup: "false",
-			expectedNumFinalizers:   0,
+			featureGates:          map[string]bool{string(features.GCSFaultToleranceRedisCleanup): false},
+			expectedNumFinalizers: 0,
+		},
+		"Feature gate is not set": {
+			expectedNumFinalizers: 1,
 		},
-		"Feature flag is not set": {
-			enableGCSFTRedisCleanup: "unset",
-			expectedNumFinalizers:   1,
+		"Disable GCS FT Redis cleanup for the RayCluster": {
+			annotation:            "GCSFaultToleranceRedisCleanup=false",
+			expectedNumFinalizers: 0,
+		},
+		"Enable GCS FT Redis cleanup for the RayCluster": {
+			featureGates:          map[string]bool{string(features.GCSFaultToleranceRedisCleanup): false},
+			annotation:            "GCSFaultToleranceRedisCleanup=true",
+			expectedNumFinalizers: 1,
+		},
+		"Invalid annotation is ignored": {
+			annotation:            "GCSFaultToleranceRedisCleanup=maybe",
+			expectedNumFinalizers: 1,
+		},
+		"Annotation enabling the batch scheduler is ignored": {
+			annotation:            "GCSFaultToleranceRedisCleanup=false,BatchScheduler=true",
+			expectedNumFinalizers: 1,
 		},
 	}
 
 	for name, tc := range tests {
 		t.Run(name, func(t *testing.T) {
-			if tc.enableGCSFTRedisCleanup == "unset" {
-				os.Unsetenv(utils.ENABLE_GCS_FT_REDIS_CLEANUP)
-			} else {
-				os.Setenv(utils.ENABLE_GCS_FT_REDIS_CLEANUP, tc.enableGCSFTRedisCleanup)
-			}
+			featureGates, err := features.NewFeatureGates(tc.featureGates)
+			assert.Nil(t, err)
 
 			cluster := gcsFTEnabledCluster.DeepCopy()
+			if tc.annotation != "" {
+				cluster.Annotations[utils.RayFeatureGatesAnnotationKey] = tc.annotation
+			}
 			fakeClient := clientFake.NewClientBuilder().
 				WithScheme(newScheme).
 				WithObjects(cluster).
@@ -2029,21 +2164,22 @@ func Test_RedisCleanupFeatureFlag(t *testing.T) {
 
 			// Initialize the reconciler
 			testRayClusterReconciler := &RayClusterReconciler{
-				Client:   fakeClient,
-				Recorder: &record.FakeRecorder{},
-				Scheme:   newScheme,
-				Log:      ctrl.Log.WithName("controllers").WithName("RayCluster"),
+				Client:       fakeClient,
+				Recorder:     &record.FakeRecorder{},
+				Scheme:       newScheme,
+				Log:          ctrl.Log.WithName("controllers").WithName("RayCluster"),
+				featureGates: featureGates,
 			}
 
 			rayClusterList := rayv1.RayClusterList{}
-			err := fakeClient.List(ctx, &rayClusterList, client.InNamespace(namespaceStr))
+			err = fakeClient.List(ctx, &rayClusterList, client.InNamespace(namespaceStr))
 			assert.Nil(t, err, "Fail to get RayCluster list")
 			assert.Equal(t, 1, len(rayClusterList.Items))
 			assert.Equal(t, 0, len(rayClusterList.Items[0].Finalizers))
 
 			request := ctrl.Request{NamespacedName: types.NamespacedName{Name: cluster.Name, Namespace: cluster.Namespace}}
 			_, err = testRayClusterReconciler.rayClusterReconcile(ctx, request, cluster)
-			if tc.enableGCSFTRedisCleanup == "false" {
+			if tc.expectedNumFinalizers == 0 {
 				// No finalizer should be added to the RayCluster. The head service and Ray Pods should be created.
 				// The head service's ClusterIP is empty, so the function `getHeadServiceIP` will return an error
 				// to requeue the request when it tries to update the RayCluster's status.
@@ -2340,6 +2476,593 @@ func TestReconcile_Replicas_Optional(t *testing.T) {
 	}
 }
 
+func TestReconcile_MultiHostWorkerGroup(t *testing.T) {
+	setupTest(t)
+
+	// The worker group has 2 replicas, and each replica consists of 2 hosts.
+	cluster := testRayCluster.DeepCopy()
+	cluster.Spec.EnableInTreeAutoscaling = pointer.Bool(false)
+	cluster.Spec.WorkerGroupSpecs[0].NumOfHosts = 2
+	cluster.Spec.WorkerGroupSpecs[0].Replicas = pointer.Int32(2)
+	cluster.Spec.WorkerGroupSpecs[0].MinReplicas = pointer.Int32(1)
+	cluster.Spec.WorkerGroupSpecs[0].MaxReplicas = pointer.Int32(5)
+	cluster.Spec.WorkerGroupSpecs[0].ScaleStrategy.WorkersToDelete = []string{}
+
+	// Only the head Pod exists at the beginning.
+	fakeClient := clientFake.NewClientBuilder().WithRuntimeObjects(testPods[0]).Build()
+	ctx := context.Background()
+	testRayClusterReconciler := &RayClusterReconciler{
+		Client:   fakeClient,
+		Recorder: &record.FakeRecorder{},
+		Scheme:   scheme.Scheme,
+		Log:      ctrl.Log.WithName("controllers").WithName("RayCluster"),
+	}
+
+	// getReplicas returns the worker Pods grouped by replica index and host index.
+	getReplicas := func() map[string]map[string]corev1.Pod {
+		podList := corev1.PodList{}
+		err := fakeClient.List(ctx, &podList, &client.ListOptions{LabelSelector: workerSelector, Namespace: namespaceStr})
+		assert.Nil(t, err, "Fail to get pod list")
+		replicas := make(map[string]map[string]corev1.Pod)
+		for _, pod := range podList.Items {
+			replicaIndex := pod.Labels[utils.RayWorkerReplicaIndexLabelKey]
+			if _, ok := replicas[replicaIndex]; !ok {
+				replicas[replicaIndex] = make(map[string]corev1.Pod)
+			}
+			replicas[replicaIndex][pod.Labels[utils.RayHostIndexLabelKey]] = pod
+		}
+		return replicas
+	}
+
+	// All the hosts of the 2 replicas are created together.
+	err := testRayClusterReconciler.reconcilePods(ctx, cluster)
+	assert.Nil(t, err, "Fail to reconcile Pods")
+	replicas := getReplicas()
+	assert.Equal(t, 2, len(replicas))
+	for _, replicaIndex := range []string{"0", "1"} {
+		assert.Equal(t, 2, len(replicas[replicaIndex]), "replica %s should have 2 hosts", replicaIndex)
+		for _, hostIndex := range []string{"0", "1"} {
+			env := replicas[replicaIndex][hostIndex].Spec.Containers[utils.RayContainerIndex].Env
+			hostIndexEnv, ok := utils.EnvVarByName(utils.RAY_HOST_INDEX, env)
+			assert.True(t, ok)
+			assert.Equal(t, hostIndex, hostIndexEnv.Value)
+			replicaIndexEnv, ok := utils.EnvVarByName(utils.RAY_WORKER_REPLICA_INDEX, env)
+			assert.True(t, ok)
+			assert.Equal(t, replicaIndex, replicaIndexEnv.Value)
+		}
+	}
+
+	// When one host fails, the whole replica is deleted.
+	failedPod := replicas["1"]["0"]
+	failedPod.Status.Phase = corev1.PodFailed
+	err = fakeClient.Status().Update(ctx, &failedPod)
+	assert.Nil(t, err, "Fail to update Pod status")
+	err = testRayClusterReconciler.reconcilePods(ctx, cluster)
+	assert.NotNil(t, err, "reconcilePods should return an error after deleting an unhealthy replica")
+	replicas = getReplicas()
+	assert.Equal(t, 1, len(replicas))
+	assert.Equal(t, 2, len(replicas["0"]))
+
+	// The replica is recreated with the same replica index in the next reconciliation.
+	err = testRayClusterReconciler.reconcilePods(ctx, cluster)
+	assert.Nil(t, err, "Fail to reconcile Pods")
+	replicas = getReplicas()
+	assert.Equal(t, 2, len(replicas))
+	assert.Equal(t, 2, len(replicas["1"]))
+
+	// A replica with a missing host is deleted and recreated as a whole instead of recreating only the missing host.
+	survivingPod, missingPod := replicas["1"]["0"], replicas["1"]["1"]
+	err = fakeClient.Delete(ctx, &missingPod)
+	assert.Nil(t, err, "Fail to delete Pod")
+	err = testRayClusterReconciler.reconcilePods(ctx, cluster)
+	assert.NotNil(t, err, "reconcilePods should return an error after deleting an incomplete replica")
+	replicas = getReplicas()
+	assert.Equal(t, 1, len(replicas))
+	assert.NotContains(t, replicas, "1")
+	err = testRayClusterReconciler.reconcilePods(ctx, cluster)
+	assert.Nil(t, err, "Fail to reconcile Pods")
+	replicas = getReplicas()
+	assert.Equal(t, 2, len(replicas))
+	assert.Equal(t, 2, len(replicas["1"]))
+	assert.NotEqual(t, survivingPod.Name, replicas["1"]["0"].Name)
+
+	// Listing a single host in WorkersToDelete deletes its whole replica.
+	cluster.Spec.WorkerGroupSpecs[0].Replicas = pointer.Int32(1)
+	cluster.Spec.WorkerGroupSpecs[0].ScaleStrategy.WorkersToDelete = []string{replicas["0"]["1"].Name}
+	err = testRayClusterReconciler.reconcilePods(ctx, cluster)
+	assert.Nil(t, err, "Fail to reconcile Pods")
+	replicas = getReplicas()
+	assert.Equal(t, 1, len(replicas))
+	assert.Equal(t, 2, len(replicas["1"]))
+
+	// Scaling down deletes whole replicas, starting from the highest replica index.
+	cluster.Spec.WorkerGroupSpecs[0].ScaleStrategy.WorkersToDelete = []string{}
+	cluster.Spec.WorkerGroupSpecs[0].Replicas = pointer.Int32(3)
+	err = testRayClusterReconciler.reconcilePods(ctx, cluster)
+	assert.Nil(t, err, "Fail to reconcile Pods")
+	assert.Equal(t, 3, len(getReplicas()))
+	cluster.Spec.WorkerGroupSpecs[0].Replicas = pointer.Int32(2)
+	err = testRayClusterReconciler.reconcilePods(ctx, cluster)
+	assert.Nil(t, err, "Fail to reconcile Pods")
+	replicas = getReplicas()
+	assert.Equal(t, 2, len(replicas))
+	assert.Contains(t, replicas, "0")
+	assert.Contains(t, replicas, "1")
+}
+
+func TestReconcile_UpgradeStrategy(t *testing.T) {
+	setupTest(t)
+
+	// newUpgradeTestCluster returns a RayCluster with 3 worker replicas, and a fake client and a reconciler
+	// which have already created the worker Pods from the current templates and marked them ready.
+	newUpgradeTestCluster := func(upgradeType rayv1.RayClusterUpgradeType) (*rayv1.RayCluster, client.Client, *RayClusterReconciler) {
+		cluster := testRayCluster.DeepCopy()
+		cluster.Spec.EnableInTreeAutoscaling = pointer.Bool(false)
+		cluster.Spec.UpgradeStrategy = &rayv1.RayClusterUpgradeStrategy{Type: upgradeType}
+		cluster.Spec.WorkerGroupSpecs[0].Replicas = pointer.Int32(3)
+		cluster.Spec.WorkerGroupSpecs[0].ScaleStrategy.WorkersToDelete = []string{}
+		maxUnavailable := intstr.FromInt(1)
+		maxSurge := intstr.FromInt(1)
+		cluster.Spec.WorkerGroupSpecs[0].RollingUpdate = &rayv1.WorkerGroupRollingUpdate{MaxUnavailable: &maxUnavailable, MaxSurge: &maxSurge}
+
+		headPod := testPods[0].(*corev1.Pod).DeepCopy()
+		headTemplateHash, err := utils.GeneratePodTemplateHash(cluster.Spec.HeadGroupSpec.Template, cluster.Spec.HeadGroupSpec.RayStartParams)
+		assert.Nil(t, err)
+		headPod.Annotations = map[string]string{utils.RayPodTemplateHashAnnotationKey: headTemplateHash}
+
+		fakeClient := clientFake.NewClientBuilder().WithRuntimeObjects(headPod).Build()
+		reconciler := &RayClusterReconciler{
+			Client:   fakeClient,
+			Recorder: &record.FakeRecorder{},
+			Scheme:   scheme.Scheme,
+			Log:      ctrl.Log.WithName("controllers").WithName("RayCluster"),
+		}
+		err = reconciler.reconcilePods(context.Background(), cluster)
+		assert.Nil(t, err, "Fail to reconcile Pods")
+		return cluster, fakeClient, reconciler
+	}
+
+	ctx := context.Background()
+	listWorkerPods := func(fakeClient client.Client) []corev1.Pod {
+		podList := corev1.PodList{}
+		err := fakeClient.List(ctx, &podList, &client.ListOptions{LabelSelector: workerSelector, Namespace: namespaceStr})
+		assert.Nil(t, err, "Fail to get pod list")
+		return podList.Items
+	}
+	markPodsReady := func(fakeClient client.Client) {
+		for _, pod := range listWorkerPods(fakeClient) {
+			pod.Status.Phase = corev1.PodRunning
+			pod.Status.Conditions = []corev1.PodCondition{{Type: corev1.PodReady, Status: corev1.ConditionTrue}}
+			assert.Nil(t, fakeClient.Status().Update(ctx, &pod), "Fail to update Pod status")
+		}
+	}
+	// countPods returns the number of outdated and updated worker Pods.
+	countPods := func(cluster *rayv1.RayCluster, fakeClient client.Client) (int, int) {
+		worker := cluster.Spec.WorkerGroupSpecs[0]
+		templateHash, err := utils.GeneratePodTemplateHash(worker.Template, worker.RayStartParams)
+		assert.Nil(t, err)
+		outdated, updated := 0, 0
+		for _, pod := range listWorkerPods(fakeClient) {
+			if utils.IsPodOutdated(pod, templateHash, worker.Template) {
+				outdated++
+			} else {
+				updated++
+			}
+		}
+		return outdated, updated
+	}
+	headPodExists := func(fakeClient client.Client) bool {
+		podList := corev1.PodList{}
+		err := fakeClient.List(ctx, &podList, client.InNamespace(namespaceStr), client.MatchingLabels{utils.RayNodeTypeLabelKey: string(rayv1.HeadNode)})
+		assert.Nil(t, err, "Fail to get pod list")
+		return len(podList.Items) == 1
+	}
+
+	t.Run("RollingUpdate", func(t *testing.T) {
+		cluster, fakeClient, reconciler := newUpgradeTestCluster(rayv1.RollingUpdateUpgrade)
+		markPodsReady(fakeClient)
+		cluster.Spec.WorkerGroupSpecs[0].Template.Spec.Containers[0].Image = "rayproject/ray:2.10.0"
+
+		// The first batch surges 1 new Pod and deletes 1 outdated Pod.
+		assert.Nil(t, reconciler.reconcilePods(ctx, cluster))
+		outdated, updated := countPods(cluster, fakeClient)
+		assert.Equal(t, 2, outdated)
+		assert.Equal(t, 1, updated)
+
+		// Only 2 Pods are ready, so no more outdated Pods are deleted until the new Pods are ready.
+		assert.Nil(t, reconciler.reconcilePods(ctx, cluster))
+		assert.Nil(t, reconciler.reconcilePods(ctx, cluster))
+		outdated, updated = countPods(cluster, fakeClient)
+		assert.Equal(t, 2, outdated)
+		assert.Equal(t, 2, updated)
+
+		markPodsReady(fakeClient)
+		assert.Nil(t, reconciler.reconcilePods(ctx, cluster))
+		outdated, updated = countPods(cluster, fakeClient)
+		assert.Equal(t, 0, outdated)
+		assert.Equal(t, 2, updated)
+
+		// Once all the Pods are up to date, the worker group is scaled back to the desired replicas.
+		assert.Nil(t, reconciler.reconcilePods(ctx, cluster))
+		outdated, updated = countPods(cluster, fakeClient)
+		assert.Equal(t, 0, outdated)
+		assert.Equal(t, 3, updated)
+		assert.True(t, headPodExists(fakeClient), "the head Pod should not be deleted when only the worker group template changes")
+	})
+
+	t.Run("Recreate", func(t *testing.T) {
+		cluster, fakeClient, reconciler := newUpgradeTestCluster(rayv1.RecreateUpgrade)
+		markPodsReady(fakeClient)
+		cluster.Spec.WorkerGroupSpecs[0].Template.Spec.Containers[0].Image = "rayproject/ray:2.10.0"
+
+		assert.Nil(t, reconciler.reconcilePods(ctx, cluster))
+		outdated, updated := countPods(cluster, fakeClient)
+		assert.Equal(t, 0, outdated)
+		assert.Equal(t, 0, updated)
+		assert.True(t, headPodExists(fakeClient))
+
+		assert.Nil(t, reconciler.reconcilePods(ctx, cluster))
+		outdated, updated = countPods(cluster, fakeClient)
+		assert.Equal(t, 0, outdated)
+		assert.Equal(t, 3, updated)
+
+		// Changing the head group template replaces the head Pod.
+		cluster.Spec.HeadGroupSpec.Template.Spec.Containers[0].Image = "rayproject/ray:2.10.0"
+		assert.Nil(t, reconciler.reconcilePods(ctx, cluster))
+		assert.False(t, headPodExists(fakeClient))
+	})
+
+	t.Run("OnDelete", func(t *testing.T) {
+		cluster, fakeClient, reconciler := newUpgradeTestCluster(rayv1.OnDeleteUpgrade)
+		markPodsReady(fakeClient)
+		cluster.Spec.HeadGroupSpec.Template.Spec.Containers[0].Image = "rayproject/ray:2.10.0"
+		cluster.Spec.WorkerGroupSpecs[0].Template.Spec.Containers[0].Image = "rayproject/ray:2.10.0"
+
+		assert.Nil(t, reconciler.reconcilePods(ctx, cluster))
+		outdated, updated := countPods(cluster, fakeClient)
+		assert.Equal(t, 3, outdated)
+		assert.Equal(t, 0, updated)
+		assert.True(t, headPodExists(fakeClient))
+	})
+}
+
+func TestSetRayClusterUpgradeCondition(t *testing.T) {
+	cluster := &rayv1.RayCluster{
+		Spec: rayv1.RayClusterSpec{
+			UpgradeStrategy: &rayv1.RayClusterUpgradeStrategy{Type: rayv1.RollingUpdateUpgrade},
+			WorkerGroupSpecs: []rayv1.WorkerGroupSpec{
+				{GroupName: "small-group", Replicas: pointer.Int32(2), MinReplicas: pointer.Int32(0), MaxReplicas: pointer.Int32(5)},
+			},
+		},
+	}
+	templateHash, err := utils.GeneratePodTemplateHash(cluster.Spec.WorkerGroupSpecs[0].Template, cluster.Spec.WorkerGroupSpecs[0].RayStartParams)
+	assert.Nil(t, err)
+	workerPod := func(templateHash string) corev1.Pod {
+		return corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Labels: map[string]string{
+					utils.RayNodeTypeLabelKey:  string(rayv1.WorkerNode),
+					utils.RayNodeGroupLabelKey: "small-group",
+				},
+				Annotations: map[string]string{utils.RayPodTemplateHashAnnotationKey: templateHash},
+			},
+		}
+	}
+	runtimePods := corev1.PodList{Items: []corev1.Pod{workerPod(templateHash), workerPod("outdated")}}
+	ctx := context.Background()
+
+	cluster.Status.DesiredWorkerReplicas = 2
+	cluster.Status.UpdatedWorkerReplicas = utils.CalculateUpdatedReplicas(cluster, runtimePods)
+	assert.Equal(t, int32(1), cluster.Status.UpdatedWorkerReplicas)
+	setRayClusterUpgradeCondition(ctx, cluster, runtimePods)
+	condition := meta.FindStatusCondition(cluster.Status.Conditions, string(rayv1.RayClusterUpgradeInProgress))
+	assert.NotNil(t, condition)
+	assert.Equal(t, metav1.ConditionTrue, condition.Status)
+	assert.Equal(t, string(rayv1.RollingUpdateUpgrade), condition.Reason)
+
+	runtimePods.Items[1] = workerPod(templateHash)
+	setRayClusterUpgradeCondition(ctx, cluster, runtimePods)
+	assert.True(t, meta.IsStatusConditionFalse(cluster.Status.Conditions, string(rayv1.RayClusterUpgradeInProgress)))
+
+	// The condition is removed when outdated Pods are not replaced by the KubeRay operator.
+	cluster.Spec.UpgradeStrategy.Type = rayv1.OnDeleteUpgrade
+	setRayClusterUpgradeCondition(ctx, cluster, runtimePods)
+	assert.Nil(t, meta.FindStatusCondition(cluster.Status.Conditions, string(rayv1.RayClusterUpgradeInProgress)))
+}
+
+func TestReconcile_FailedPodRetention(t *testing.T) {
+	setupTest(t)
+
+	cluster := testRayCluster.DeepCopy()
+	cluster.Spec.EnableInTreeAutoscaling = pointer.Bool(false)
+	cluster.Spec.WorkerGroupSpecs[0].Replicas = pointer.Int32(1)
+	cluster.Spec.WorkerGroupSpecs[0].MinReplicas = pointer.Int32(0)
+	cluster.Spec.WorkerGroupSpecs[0].ScaleStrategy.WorkersToDelete = []string{}
+	cluster.Spec.FailedPodRetention = &rayv1.FailedPodRetentionPolicy{MaxPodsPerGroup: pointer.Int32(1), TTLSeconds: pointer.Int32(600)}
+
+	// Only the head Pod exists at the beginning.
+	fakeClient := clientFake.NewClientBuilder().WithRuntimeObjects(testPods[0]).Build()
+	ctx := context.Background()
+	recorder := record.NewFakeRecorder(100)
+	testRayClusterReconciler := &RayClusterReconciler{
+		Client:   fakeClient,
+		Recorder: recorder,
+		Scheme:   scheme.Scheme,
+		Log:      ctrl.Log.WithName("controllers").WithName("RayCluster"),
+	}
+	listPods := func(selector labels.Selector) []corev1.Pod {
+		podList := corev1.PodList{}
+		err := fakeClient.List(ctx, &podList, &client.ListOptions{LabelSelector: selector, Namespace: namespaceStr})
+		assert.Nil(t, err, "Fail to get pod list")
+		return podList.Items
+	}
+	retainedSelector := labels.SelectorFromSet(labels.Set{utils.RayRetainedFromClusterLabelKey: cluster.Name})
+	failWorkerPod := func() corev1.Pod {
+		workerPods := listPods(workerSelector)
+		assert.Equal(t, 1, len(workerPods))
+		workerPods[0].Status.Phase = corev1.PodFailed
+		err := fakeClient.Status().Update(ctx, &workerPods[0])
+		assert.Nil(t, err, "Fail to update Pod status")
+		err = testRayClusterReconciler.reconcilePods(ctx, cluster)
+		assert.NotNil(t, err, "reconcilePods should return an error after removing an unhealthy Pod")
+		return workerPods[0]
+	}
+
+	err := testRayClusterReconciler.reconcilePods(ctx, cluster)
+	assert.Nil(t, err, "Fail to reconcile Pods")
+
+	// The failed worker Pod is relabeled out of the RayCluster instead of being deleted.
+	firstFailedPod := failWorkerPod()
+	assert.Equal(t, 0, len(listPods(workerSelector)))
+	retainedPods := listPods(retainedSelector)
+	assert.Equal(t, 1, len(retainedPods))
+	assert.Equal(t, firstFailedPod.Name, retainedPods[0].Name)
+	assert.NotContains(t, retainedPods[0].Labels, utils.RayClusterLabelKey)
+	assert.Contains(t, retainedPods[0].Annotations, utils.RayRetainedAtAnnotationKey)
+	var events []string
+	for len(recorder.Events) > 0 {
+		events = append(events, <-recorder.Events)
+	}
+	assert.Contains(t, strings.Join(events, "\n"), "Retained worker Pod "+firstFailedPod.Name)
+
+	// A replacement worker Pod is created.
+	err = testRayClusterReconciler.reconcilePods(ctx, cluster)
+	assert.Nil(t, err, "Fail to reconcile Pods")
+	assert.Equal(t, 1, len(listPods(workerSelector)))
+
+	// Only the most recently retained Pod is kept.
+	retainedPods[0].Annotations[utils.RayRetainedAtAnnotationKey] = time.Now().Add(-time.Minute).UTC().Format(time.RFC3339)
+	err = fakeClient.Update(ctx, &retainedPods[0])
+	assert.Nil(t, err, "Fail to update Pod")
+	secondFailedPod := failWorkerPod()
+	err = testRayClusterReconciler.reconcilePods(ctx, cluster)
+	assert.Nil(t, err, "Fail to reconcile Pods")
+	retainedPods = listPods(retainedSelector)
+	assert.Equal(t, 1, len(retainedPods))
+	assert.Equal(t, secondFailedPod.Name, retainedPods[0].Name)
+
+	// Retained Pods are deleted after the TTL.
+	retainedPods[0].Annotations[utils.RayRetainedAtAnnotationKey] = time.Now().Add(-time.Hour).UTC().Format(time.RFC3339)
+	err = fakeClient.Update(ctx, &retainedPods[0])
+	assert.Nil(t, err, "Fail to update Pod")
+	err = testRayClusterReconciler.reconcilePods(ctx, cluster)
+	assert.Nil(t, err, "Fail to reconcile Pods")
+	assert.Equal(t, 0, len(listPods(retainedSelector)))
+
+	// Failed Pods are deleted without a retention policy.
+	cluster.Spec.FailedPodRetention = nil
+	failWorkerPod()
+	assert.Equal(t, 0, len(listPods(workerSelector)))
+	assert.Equal(t, 0, len(listPods(retainedSelector)))
+}
+
+func TestReconcile_FailureBudget(t *testing.T) {
+	setupTest(t)
+
+	newScheme := runtime.NewScheme()
+	_ = rayv1.AddToScheme(newScheme)
+	_ = corev1.AddToScheme(newScheme)
+
+	cluster := testRayCluster.DeepCopy()
+	cluster.Spec.EnableInTreeAutoscaling = pointer.Bool(false)
+	cluster.Spec.WorkerGroupSpecs[0].Replicas = pointer.Int32(1)
+	cluster.Spec.WorkerGroupSpecs[0].MinReplicas = pointer.Int32(0)
+	cluster.Spec.WorkerGroupSpecs[0].ScaleStrategy.WorkersToDelete = []string{}
+	cluster.Spec.FailureBudget = &rayv1.RayClusterFailureBudget{MaxFailures: pointer.Int32(1), WindowSeconds: pointer.Int32(600)}
+	groupName := cluster.Spec.WorkerGroupSpecs[0].GroupName
+	headService, err := common.BuildServiceForHeadPod(context.Background(), *cluster, nil, nil)
+	assert.Nil(t, err, "Failed to build head service.")
+	headService.Spec.ClusterIP = "aaa.bbb.ccc.ddd"
+
+	fakeClient := clientFake.NewClientBuilder().WithScheme(newScheme).
+		WithRuntimeObjects(testPods[0].DeepCopyObject(), headService, cluster).WithStatusSubresource(cluster).Build()
+	ctx := context.Background()
+	testRayClusterReconciler := &RayClusterReconciler{
+		Client:   fakeClient,
+		Recorder: &record.FakeRecorder{},
+		Scheme:   newScheme,
+		Log:      ctrl.Log.WithName("controllers").WithName("RayCluster"),
+	}
+	listWorkerPods := func() []corev1.Pod {
+		podList := corev1.PodList{}
+		err := fakeClient.List(ctx, &podList, &client.ListOptions{LabelSelector: workerSelector, Namespace: namespaceStr})
+		assert.Nil(t, err, "Fail to get pod list")
+		return podList.Items
+	}
+	failWorkerPod := func() error {
+		workerPods := listWorkerPods()
+		assert.Equal(t, 1, len(workerPods))
+		workerPods[0].Status.Phase = corev1.PodFailed
+		workerPods[0].Status.ContainerStatuses = []corev1.ContainerStatus{{
+			Name:  workerPods[0].Spec.Containers[utils.RayContainerIndex].Name,
+			State: corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{ExitCode: 137, Reason: "OOMKilled"}},
+		}}
+		err := fakeClient.Status().Update(ctx, &workerPods[0])
+		assert.Nil(t, err, "Fail to update Pod status")
+		return testRayClusterReconciler.reconcilePods(ctx, cluster)
+	}
+	// expireBackoff moves the recorded failures back in time so that the creation backoff has passed.
+	expireBackoff := func() {
+		failures := getGroupFailures(cluster, groupName)
+		for i := range failures.FailureTimes {
+			failures.FailureTimes[i] = metav1.NewTime(failures.FailureTimes[i].Add(-time.Minute))
+		}
+		assert.Zero(t, getPodCreationBackoff(cluster, groupName, time.Now()))
+	}
+
+	err = testRayClusterReconciler.reconcilePods(ctx, cluster)
+	assert.Nil(t, err, "Fail to reconcile Pods")
+
+	// The first failure is recorded in the status, and the creation of the replacement Pod backs off.
+	err = failWorkerPod()
+	assert.ErrorContains(t, err, "unhealthy worker Pods")
+	failures := getGroupFailures(cluster, groupName)
+	assert.NotNil(t, failures)
+	assert.Equal(t, 1, len(failures.FailureTimes))
+	assert.False(t, failures.BudgetExceeded)
+	assert.Contains(t, failures.LastTerminationMessage, "OOMKilled")
+	assert.Greater(t, getPodCreationBackoff(cluster, groupName, time.Now()), time.Duration(0))
+	err = testRayClusterReconciler.reconcilePods(ctx, cluster)
+	assert.Nil(t, err, "Fail to reconcile Pods")
+	assert.Equal(t, 0, len(listWorkerPods()))
+
+	// The replacement Pod is created after the backoff.
+	expireBackoff()
+	err = testRayClusterReconciler.reconcilePods(ctx, cluster)
+	assert.Nil(t, err, "Fail to reconcile Pods")
+
+	// The second failure exceeds the failure budget, so no Pods are created for the group anymore.
+	err = failWorkerPod()
+	assert.ErrorContains(t, err, "Group "+groupName+" exceeded the failure budget")
+	assert.ErrorContains(t, err, "OOMKilled")
+	assert.True(t, getGroupFailures(cluster, groupName).BudgetExceeded)
+	expireBackoff()
+	err = testRayClusterReconciler.reconcilePods(ctx, cluster)
+	assert.Nil(t, err, "Fail to reconcile Pods")
+	assert.Equal(t, 0, len(listWorkerPods()))
+
+	// The RayCluster stays Failed with the reason naming the group.
+	newInstance, err := testRayClusterReconciler.calculateStatus(ctx, cluster)
+	assert.Nil(t, err)
+	assert.Equal(t, rayv1.Failed, newInstance.Status.State)
+	assert.Contains(t, newInstance.Status.Reason, "Group "+groupName+" exceeded the failure budget")
+
+	// Spec changes which do not touch the group's template, such as suspending and resuming the RayCluster, keep the failures.
+	cluster.Generation++
+	assert.False(t, testRayClusterReconciler.canCreatePods(cluster, groupName))
+	assert.True(t, getGroupFailures(cluster, groupName).BudgetExceeded)
+
+	// The failures are discarded once the group's template changes.
+	cluster.Spec.WorkerGroupSpecs[0].Template.Spec.Containers[utils.RayContainerIndex].Image = "rayproject/ray:2.10.0"
+	assert.True(t, testRayClusterReconciler.canCreatePods(cluster, groupName))
+	err = testRayClusterReconciler.reconcilePods(ctx, cluster)
+	assert.Nil(t, err, "Fail to reconcile Pods")
+	assert.Equal(t, 1, len(listWorkerPods()))
+	newInstance, err = testRayClusterReconciler.calculateStatus(ctx, cluster)
+	assert.Nil(t, err)
+	assert.Empty(t, newInstance.Status.GroupFailures)
+}
+
+func TestGetPodCreationBackoff(t *testing.T) {
+	now := time.Now()
+	cluster := &rayv1.RayCluster{
+		Spec: rayv1.RayClusterSpec{
+			WorkerGroupSpecs: []rayv1.WorkerGroupSpec{{GroupName: "small-group"}},
+			FailureBudget:    &rayv1.RayClusterFailureBudget{MaxFailures: pointer.Int32(10)},
+		},
+	}
+	assert.Zero(t, getPodCreationBackoff(cluster, "small-group", now))
+
+	// The backoff doubles after each failure within the window, starting from the last failure.
+	for i := 0; i < 3; i++ {
+		recordPodFailure(cluster, "small-group", corev1.Pod{Spec: corev1.PodSpec{Containers: []corev1.Container{{Name: "ray-worker"}}}}, now)
+	}
+	assert.Equal(t, 4*podFailureBackoffBase, getPodCreationBackoff(cluster, "small-group", now))
+	assert.Equal(t, 4*podFailureBackoffBase-time.Second, getPodCreationBackoff(cluster, "small-group", now.Add(time.Second)))
+
+	// The backoff is capped.
+	for i := 0; i < 7; i++ {
+		recordPodFailure(cluster, "small-group", corev1.Pod{Spec: corev1.PodSpec{Containers: []corev1.Container{{Name: "ray-worker"}}}}, now)
+	}
+	assert.False(t, getGroupFailures(cluster, "small-group").BudgetExceeded)
+	assert.Equal(t, podFailureBackoffMax, getPodCreationBackoff(cluster, "small-group", now))
+
+	// Failures outside of the window are not counted.
+	assert.Zero(t, getPodCreationBackoff(cluster, "small-group", now.Add(time.Duration(defaultFailureBudgetWindowSeconds)*time.Second)))
+}
+
+func TestSelectWorkerPodsToDelete(t *testing.T) {
+	newScheme := runtime.NewScheme()
+	_ = rayv1.AddToScheme(newScheme)
+	_ = corev1.AddToScheme(newScheme)
+
+	cluster := &rayv1.RayCluster{
+		ObjectMeta: metav1.ObjectMeta{Name: "raycluster-sample", Namespace: namespaceStr},
+	}
+	headSvcName, err := utils.GenerateHeadServiceName(utils.RayClusterCRD, cluster.Spec, cluster.Name)
+	assert.Nil(t, err)
+	headSvc := &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{Name: headSvcName, Namespace: namespaceStr},
+		Spec: corev1.ServiceSpec{
+			Ports: []corev1.ServicePort{{Name: utils.DashboardPortName, Port: 8265}},
+		},
+	}
+	fakeDashboardClient := &utils.FakeRayDashboardClient{}
+	r := &RayClusterReconciler{
+		Client:              clientFake.NewClientBuilder().WithScheme(newScheme).WithRuntimeObjects(headSvc).Build(),
+		Recorder:            &record.FakeRecorder{},
+		Scheme:              newScheme,
+		Log:                 ctrl.Log.WithName("controllers").WithName("RayCluster"),
+		dashboardClientFunc: func() utils.RayDashboardClientInterface { return fakeDashboardClient },
+	}
+
+	now := time.Now()
+	workerPod := func(name string, phase corev1.PodPhase, ready corev1.ConditionStatus, age time.Duration) corev1.Pod {
+		return corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:              name,
+				CreationTimestamp: metav1.NewTime(now.Add(-age)),
+			},
+			Status: corev1.PodStatus{
+				Phase:      phase,
+				PodIP:      name + "-ip",
+				Conditions: []corev1.PodCondition{{Type: corev1.PodReady, Status: ready}},
+			},
+		}
+	}
+	pods := []corev1.Pod{
+		workerPod("old-busy", corev1.PodRunning, corev1.ConditionTrue, 3*time.Hour),
+		workerPod("old-idle", corev1.PodRunning, corev1.ConditionTrue, 2*time.Hour),
+		workerPod("new-busy", corev1.PodRunning, corev1.ConditionTrue, time.Hour),
+		workerPod("not-ready", corev1.PodRunning, corev1.ConditionFalse, 4*time.Hour),
+		workerPod("pending", corev1.PodPending, corev1.ConditionFalse, 5*time.Hour),
+		workerPod("protected", corev1.PodPending, corev1.ConditionFalse, 0),
+		workerPod("terminating", corev1.PodPending, corev1.ConditionFalse, 0),
+	}
+	pods[5].Annotations = map[string]string{utils.RayDoNotEvictAnnotationKey: "true"}
+	deletionTimestamp := metav1.NewTime(now)
+	pods[6].DeletionTimestamp = &deletionTimestamp
+	podNames := func(pods []corev1.Pod) []string {
+		names := []string{}
+		for _, pod := range pods {
+			names = append(names, pod.Name)
+		}
+		return names
+	}
+
+	// Pending Pods, not ready Pods, and then the most recently created Pods are deleted first.
+	ctx := context.Background()
+	assert.Equal(t, []string{"pending", "not-ready", "new-busy"}, podNames(r.selectWorkerPodsToDelete(ctx, cluster, pods, 3)))
+
+	// Idle Pods are preferred when the RayCluster opts into the idle-aware scale-down.
+	cluster.Annotations = map[string]string{utils.RayIdleAwareScaleDownAnnotationKey: "true"}
+	fakeDashboardClient.SetIdleNodeIPs([]string{"old-idle-ip"})
+	assert.Equal(t, []string{"pending", "not-ready", "old-idle"}, podNames(r.selectWorkerPodsToDelete(ctx, cluster, pods, 3)))
+
+	// Terminating Pods and Pods annotated with `ray.io/do-not-evict` are never deleted.
+	assert.Equal(t, []string{"pending", "not-ready", "old-idle", "new-busy", "old-busy"}, podNames(r.selectWorkerPodsToDelete(ctx, cluster, pods, 7)))
+}
+
 func TestSumGPUs(t *testing.T) {
 	nvidiaGPUResourceName := corev1.ResourceName("nvidia.com/gpu")
 	googleTPUResourceName := corev1.ResourceName("google.com/tpu")
@@ -2443,3 +3166,125 @@ func TestDeleteAllPods(t *testing.T) {
 	assert.Equal(t, 2, len(pods.Items))
 	assert.Subset(t, []string{"deleted", "other"}, []string{pods.Items[0].Name, pods.Items[1].Name})
 }
+
+func TestReconcile_AutoSuspend(t *testing.T) {
+	setupTest(t)
+
+	newScheme := runtime.NewScheme()
+	_ = rayv1.AddToScheme(newScheme)
+	_ = corev1.AddToScheme(newScheme)
+
+	cluster := testRayCluster.DeepCopy()
+	cluster.Spec.AutoSuspend = &rayv1.RayClusterAutoSuspend{
+		IdleTTLSeconds: pointer.Int32(60),
+		ResumeSchedule: "0 8 * * *",
+		TimeZone:       "America/Los_Angeles",
+	}
+	headService, err := common.BuildServiceForHeadPod(context.Background(), *cluster, nil, nil)
+	assert.Nil(t, err, "Failed to build head service.")
+
+	fakeClient := clientFake.NewClientBuilder().WithScheme(newScheme).
+		WithRuntimeObjects(headService, cluster).WithStatusSubresource(cluster).Build()
+	ctx := context.Background()
+	fakeDashboardClient := &utils.FakeRayDashboardClient{}
+	recorder := record.NewFakeRecorder(10)
+	testRayClusterReconciler := &RayClusterReconciler{
+		Client:              fakeClient,
+		Recorder:            recorder,
+		Scheme:              newScheme,
+		Log:                 ctrl.Log.WithName("controllers").WithName("RayCluster"),
+		dashboardClientFunc: func() utils.RayDashboardClientInterface { return fakeDashboardClient },
+	}
+	isSuspended := func() bool {
+		rayCluster := rayv1.RayCluster{}
+		err := fakeClient.Get(ctx, client.ObjectKeyFromObject(cluster), &rayCluster)
+		assert.Nil(t, err, "Fail to get RayCluster")
+		return rayCluster.Spec.Suspend != nil && *rayCluster.Spec.Suspend
+	}
+
+	// The idle TTL starts counting when the RayCluster is first reconciled.
+	err = testRayClusterReconciler.reconcileAutoSuspend(ctx, cluster)
+	assert.Nil(t, err)
+	assert.NotNil(t, cluster.Status.LastActivityTime)
+	assert.NotNil(t, cluster.Status.LastScheduleTime)
+	action := cluster.Status.NextScheduledAction
+	assert.NotNil(t, action)
+	assert.Equal(t, rayv1.SuspendRayCluster, action.Action)
+	assert.Equal(t, "IdleTTL", action.Reason)
+	assert.Equal(t, cluster.Status.LastActivityTime.Add(time.Minute), action.Time.Time)
+
+	// A busy RayCluster is not suspended even if the idle TTL has passed.
+	lastActivityTime := metav1.NewTime(time.Now().Add(-2 * time.Minute))
+	cluster.Status.LastActivityTime = &lastActivityTime
+	err = testRayClusterReconciler.reconcileAutoSuspend(ctx, cluster)
+	assert.Nil(t, err)
+	assert.False(t, isSuspended())
+	assert.True(t, cluster.Status.LastActivityTime.After(lastActivityTime.Time))
+
+	// An idle RayCluster is suspended after the idle TTL, and the next action is the resume schedule.
+	fakeDashboardClient.SetClusterIdle(true)
+	cluster.Status.LastActivityTime = &lastActivityTime
+	err = testRayClusterReconciler.reconcileAutoSuspend(ctx, cluster)
+	assert.Nil(t, err)
+	assert.True(t, isSuspended())
+	assert.Contains(t, <-recorder.Events, "AutoSuspended")
+	assert.Nil(t, cluster.Status.LastActivityTime)
+	action = cluster.Status.NextScheduledAction
+	assert.NotNil(t, action)
+	assert.Equal(t, rayv1.ResumeRayCluster, action.Action)
+	assert.Equal(t, "Schedule", action.Reason)
+	location, err := time.LoadLocation("America/Los_Angeles")
+	assert.Nil(t, err)
+	assert.Equal(t, 8, action.Time.In(location).Hour())
+
+	// The RayCluster is resumed once the resume schedule is due.
+	lastScheduleTime := metav1.NewTime(time.Now().Add(-25 * time.Hour))
+	cluster.Status.LastScheduleTime = &lastScheduleTime
+	err = testRayClusterReconciler.reconcileAutoSuspend(ctx, cluster)
+	assert.Nil(t, err)
+	assert.False(t, isSuspended())
+	assert.Contains(t, <-recorder.Events, "AutoResumed")
+	assert.NotNil(t, cluster.Status.LastActivityTime)
+	assert.Equal(t, rayv1.SuspendRayCluster, cluster.Status.NextScheduledAction.Action)
+
+	// Removing autoSuspend clears the status.
+	cluster.Spec.AutoSuspend = nil
+	err = testRayClusterReconciler.reconcileAutoSuspend(ctx, cluster)
+	assert.Nil(t, err)
+	assert.Nil(t, cluster.Status.LastActivityTime)
+	assert.Nil(t, cluster.Status.LastScheduleTime)
+	assert.Nil(t, cluster.Status.NextScheduledAction)
+}
+
+func TestUpdateOptions(t *testing.T) {
+	setupTest(t)
+	ctx := context.Background()
+
+	r := &RayClusterReconciler{
+		Log:    ctrl.Log.WithName("controllers").WithName("RayCluster"),
+		Scheme: scheme.Scheme,
+	}
+	headPod := r.buildHeadPod(ctx, *testRayCluster.DeepCopy())
+	workerPod := r.buildWorkerPod(ctx, *testRayCluster.DeepCopy(), testRayCluster.Spec.WorkerGroupSpecs[0])
+	numHeadContainers, numWorkerContainers := len(headPod.Spec.Containers), len(workerPod.Spec.Containers)
+
+	// Pods built after the options are updated use the new options.
+	featureGates, err := features.NewFeatureGates(map[string]bool{string(features.GCSFaultToleranceRedisCleanup): false})
+	assert.Nil(t, err)
+	r.UpdateOptions(RayClusterReconcilerOptions{
+		HeadSidecarContainers:   []corev1.Container{{Name: "head-sidecar", Image: "busybox"}},
+		WorkerSidecarContainers: []corev1.Container{{Name: "worker-sidecar", Image: "busybox"}},
+		PodDefaults: &configapi.PodDefaults{
+			Worker: &configapi.RayPodDefaults{ImageRegistry: "registry.example.com"},
+		},
+		FeatureGates: featureGates,
+	})
+	headPod = r.buildHeadPod(ctx, *testRayCluster.DeepCopy())
+	workerPod = r.buildWorkerPod(ctx, *testRayCluster.DeepCopy(), testRayCluster.Spec.WorkerGroupSpecs[0])
+	assert.Len(t, headPod.Spec.Containers, numHeadContainers+1)
+	assert.Equal(t, "head-sidecar", headPod.Spec.Containers[numHeadContainers].Name)
+	assert.Len(t, workerPod.Spec.Containers, numWorkerContainers+1)
+	assert.Equal(t, "worker-sidecar", workerPod.Spec.Containers[numWorkerContainers].Name)
+	assert.True(t, strings.HasPrefix(workerPod.Spec.Containers[utils.RayContainerIndex].Image, "registry.example.com/"))
+	assert.False(t, r.getOptions().FeatureGates.Enabled(features.GCSFaultToleranceRedisCleanup))
+}
@@ -3,21 +3,29 @@ package ray
 import (
 	"context"
 	"fmt"
+	"reflect"
+	"sync"
 	"time"
 
 	"github.com/go-logr/logr"
 	batchv1 "k8s.io/api/batch/v1"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/tools/record"
 	"k8s.io/utils/pointer"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 	"sigs.k8s.io/controller-runtime/pkg/manager"
 
+	"github.com/ray-project/kuberay/ray-operator/controllers/ray/batchscheduler"
+	schedulerinterface "github.com/ray-project/kuberay/ray-operator/controllers/ray/batchscheduler/interface"
 	"github.com/ray-project/kuberay/ray-operator/controllers/ray/common"
+	"github.com/ray-project/kuberay/ray-operator/controllers/ray/metrics"
 	"github.com/ray-project/kuberay/ray-operator/controllers/ray/utils"
+	"github.com/ray-project/kuberay/ray-operator/pkg/features"
 
 	"k8s.io/apimachinery/pkg/runtime"
 	ctrl "sigs.k8s.io/controller-runtime"
@@ -27,9 +35,15 @@ import (
 )
 
 const (
+	// RayJobDefaultRequeueDuration is the default interval for requeueing a RayJob which is not in a steady state.
 	RayJobDefaultRequeueDuration    = 3 * time.Second
 	RayJobDefaultClusterSelectorKey = "ray.io/cluster"
 	PythonUnbufferedEnvVarName      = "PYTHONUNBUFFERED"
+
+	// The delay before re-submitting a failed Ray job starts at RayJobRetryBaseBackoff and doubles
+	// with every failed attempt, up to RayJobRetryMaxBackoff. This follows the Kubernetes Job backoff.
+	RayJobRetryBaseBackoff = 10 * time.Second
+	RayJobRetryMaxBackoff  = 6 * time.Minute
 )
 
 // RayJobReconciler reconciles a RayJob object
@@ -40,16 +54,52 @@ type RayJobReconciler struct {
 	Recorder record.EventRecorder
 
 	dashboardClientFunc func() utils.RayDashboardClientInterface
+	BatchSchedulerMgr   *batchscheduler.SchedulerManager
+
+	// optionsLock guards the options below, which can be updated by UpdateOptions while reconciling.
+	optionsLock  sync.RWMutex
+	featureGates *features.FeatureGates
+	requeue      RequeueOptions
+}
+
+type RayJobReconcilerOptions struct {
+	// FeatureGates are the operator-wide feature gates, which can be overridden for a single RayJob with the
+	// `ray.io/feature-gates` annotation. Nil means the default feature gates.
+	FeatureGates *features.FeatureGates
+	// Requeue.Interval defaults to RayJobDefaultRequeueDuration, and Requeue.SteadyStateInterval is used for a running
+	// Ray job and defaults to RayJobSteadyStateRequeueDuration.
+	Requeue RequeueOptions
 }
 
 // NewRayJobReconciler returns a new reconcile.Reconciler
-func NewRayJobReconciler(mgr manager.Manager, dashboardClientFunc func() utils.RayDashboardClientInterface) *RayJobReconciler {
+func NewRayJobReconciler(mgr manager.Manager, dashboardClientFunc func() utils.RayDashboardClientInterface, options RayJobReconcilerOptions) *RayJobReconciler {
 	return &RayJobReconciler{
 		Client:              mgr.GetClient(),
 		Scheme:              mgr.GetScheme(),
 		Log:                 ctrl.Log.WithName("controllers").WithName("RayJob"),
 		Recorder:            mgr.GetEventRecorderFor("rayjob-controller"),
 		dashboardClientFunc: dashboardClientFunc,
+		BatchSchedulerMgr:   batchscheduler.NewSchedulerManager(mgr.GetConfig()),
+		featureGates:        options.FeatureGates,
+		requeue:             options.Requeue,
+	}
+}
+
+// UpdateOptions replaces the options of the reconciler, e.g. when the operator Configuration is reloaded.
+// Reconciliations that are already in progress keep using the previous options.
+func (r *RayJobReconciler) UpdateOptions(options RayJobReconcilerOptions) {
+	r.optionsLock.Lock()
+	defer r.optionsLock.Unlock()
+	r.featureGates = options.FeatureGates
+	r.requeue = options.Requeue
+}
+
+func (r *RayJobReconciler) getOptions() RayJobReconcilerOptions {
+	r.optionsLock.RLock()
+	defer r.optionsLock.RUnlock()
+	return RayJobReconcilerOptions{
+		FeatureGates: r.featureGates,
+		Requeue:      r.requeue.withDefaults(RayJobDefaultRequeueDuration, RayJobSteadyStateRequeueDuration),
 	}
 }
 
@@ -74,6 +124,7 @@ func NewRayJobReconciler(mgr manager.Manager, dashboardClientFunc func() utils.R
 // Reconcile used to bridge the desired state with the current state
 func (r *RayJobReconciler) Reconcile(ctx context.Context, request ctrl.Request) (ctrl.Result, error) {
 	r.Log.Info("reconciling RayJob", "NamespacedName", request.NamespacedName)
+	options := r.getOptions()
 
 	// Get RayJob instance
 	var err error
@@ -82,12 +133,14 @@ func (r *RayJobReconciler) Reconcile(ctx context.Context, request ctrl.Request)
 		if errors.IsNotFound(err) {
 			// Request object not found, could have been deleted after reconcile request. Stop reconciliation.
 			r.Log.Info("RayJob resource not found. Ignoring since object must be deleted", "name", request.NamespacedName)
+			metrics.DeleteJob(request.Namespace, request.Name)
 			return ctrl.Result{}, nil
 		}
 		// Error reading the object - requeue the request.
 		r.Log.Error(err, "Failed to get RayJob")
-		return ctrl.Result{RequeueAfter: RayJobDefaultRequeueDuration}, err
+		return ctrl.Result{RequeueAfter: options.Requeue.Interval}, err
 	}
+	metrics.SetJobDeploymentStatus(rayJobInstance.Namespace, rayJobInstance.Name, string(rayJobInstance.Status.JobDeploymentStatus))
 
 	if !rayJobInstance.ObjectMeta.DeletionTimestamp.IsZero() {
 		r.Log.Info("RayJob is being deleted", "DeletionTimestamp", rayJobInstance.ObjectMeta.DeletionTimestamp)
@@ -107,14 +160,26 @@ func (r *RayJobReconciler) Reconcile(ctx context.Context, request ctrl.Request)
 		err := r.Update(ctx, rayJobInstance)
 		if err != nil {
 			r.Log.Error(err, "Failed to remove finalizer for RayJob")
-			return ctrl.Result{RequeueAfter: RayJobDefaultRequeueDuration}, err
+			return ctrl.Result{RequeueAfter: options.Requeue.Interval}, err
 		}
-		return ctrl.Result{RequeueAfter: RayJobDefaultRequeueDuration}, err
+		return ctrl.Result{RequeueAfter: options.Requeue.Interval}, err
+	}
+
+	// The operator-wide feature gates can be overridden for a single RayJob with the `ray.io/feature-gates` annotation.
+	featureGates, err := options.FeatureGates.WithOverrides(rayJobInstance.Annotations[utils.RayFeatureGatesAnnotationKey])
+	if err != nil {
+		r.Recorder.Eventf(rayJobInstance, corev1.EventTypeWarning, "InvalidFeatureGates", "Ignoring the %s annotation: %v", utils.RayFeatureGatesAnnotationKey, err)
+	}
+	ctx = features.IntoContext(ctx, featureGates)
+
+	if err := r.reconcileBatchSchedulerAdmission(ctx, rayJobInstance); err != nil {
+		r.Recorder.Event(rayJobInstance, corev1.EventTypeWarning, "BatchSchedulerAdmissionError", err.Error())
+		return ctrl.Result{RequeueAfter: options.Requeue.Interval}, err
 	}
 
 	if err := validateRayJobSpec(rayJobInstance); err != nil {
 		r.Log.Error(err, "The RayJob spec is invalid")
-		return ctrl.Result{RequeueAfter: RayJobDefaultRequeueDuration}, err
+		return ctrl.Result{RequeueAfter: options.Requeue.Interval}, err
 	}
 
 	// Please do NOT modify `originalRayJobInstance` in the following code.
@@ -128,42 +193,45 @@ func (r *RayJobReconciler) Reconcile(ctx context.Context, request ctrl.Request)
 			controllerutil.AddFinalizer(rayJobInstance, utils.RayJobStopJobFinalizer)
 			if err := r.Update(ctx, rayJobInstance); err != nil {
 				r.Log.Error(err, "Failed to update RayJob with finalizer")
-				return ctrl.Result{RequeueAfter: RayJobDefaultRequeueDuration}, err
+				return ctrl.Result{RequeueAfter: options.Requeue.Interval}, err
 			}
 		}
 		// Set `Status.JobDeploymentStatus` to `JobDeploymentStatusInitializing`, and initialize `Status.JobId`
 		// and `Status.RayClusterName` prior to avoid duplicate job submissions and cluster creations.
 		r.Log.Info("JobDeploymentStatusNew", "RayJob", rayJobInstance.Name)
 		if err = r.initRayJobStatusIfNeed(ctx, rayJobInstance); err != nil {
-			return ctrl.Result{RequeueAfter: RayJobDefaultRequeueDuration}, err
+			return ctrl.Result{RequeueAfter: options.Requeue.Interval}, err
 		}
 	case rayv1.JobDeploymentStatusInitializing:
 		if shouldUpdate := r.updateStatusToSuspendingIfNeeded(ctx, rayJobInstance); shouldUpdate {
 			break
 		}
+		if shouldUpdate := r.checkActiveDeadlineAndUpdateStatusIfNeeded(rayJobInstance); shouldUpdate {
+			break
+		}
 
 		var rayClusterInstance *rayv1.RayCluster
 		if rayClusterInstance, err = r.getOrCreateRayClusterInstance(ctx, rayJobInstance); err != nil {
-			return ctrl.Result{RequeueAfter: RayJobDefaultRequeueDuration}, err
+			return ctrl.Result{RequeueAfter: options.Requeue.Interval}, err
 		}
 
 		// Check the current status of RayCluster before submitting.
 		if clientURL := rayJobInstance.Status.DashboardURL; clientURL == "" {
 			if rayClusterInstance.Status.State != rayv1.Ready {
 				r.Log.Info("Wait for the RayCluster.Status.State to be ready before submitting the job.", "RayCluster", rayClusterInstance.Name, "State", rayClusterInstance.Status.State)
-				return ctrl.Result{RequeueAfter: RayJobDefaultRequeueDuration}, err
+				return ctrl.Result{RequeueAfter: options.Requeue.Interval}, err
 			}
 
 			if clientURL, err = utils.FetchHeadServiceURL(ctx, r.Client, rayClusterInstance, utils.DashboardPortName); err != nil || clientURL == "" {
 				r.Log.Error(err, "Failed to get the dashboard URL after the RayCluster is ready!", "RayCluster", rayClusterInstance.Name)
-				return ctrl.Result{RequeueAfter: RayJobDefaultRequeueDuration}, err
+				return ctrl.Result{RequeueAfter: options.Requeue.Interval}, err
 			}
 			rayJobInstance.Status.DashboardURL = clientURL
 		}
 
 		if rayJobInstance.Spec.SubmissionMode == rayv1.K8sJobMode {
 			if err := r.createK8sJobIfNeed(ctx, rayJobInstance, rayClusterInstance); err != nil {
-				return ctrl.Result{RequeueAfter: RayJobDefaultRequeueDuration}, err
+				return ctrl.Result{RequeueAfter: options.Requeue.Interval}, err
 			}
 		}
 
@@ -178,12 +246,12 @@ func (r *RayJobReconciler) Reconcile(ctx context.Context, request ctrl.Request)
 		// TODO (kevin85421): For light-weight mode, calculate the number of failed retries and transition
 		// the status to `Complete` if the number of failed retries exceeds the threshold.
 		if rayJobInstance.Spec.SubmissionMode == rayv1.K8sJobMode {
-			// If the Job reaches the backoff limit, transition the status to `Complete`.
+			// If the Job reaches its backoff limit, the attempt is considered failed. See checkK8sJobAndUpdateStatusIfNeeded.
 			job := &batchv1.Job{}
 			namespacedName := getK8sJobNamespacedName(rayJobInstance)
 			if err := r.Client.Get(ctx, namespacedName, job); err != nil {
 				r.Log.Error(err, "Failed to get the submitter Kubernetes Job", "NamespacedName", namespacedName)
-				return ctrl.Result{RequeueAfter: RayJobDefaultRequeueDuration}, err
+				return ctrl.Result{RequeueAfter: options.Requeue.Interval}, err
 			}
 			if shouldUpdate := r.checkK8sJobAndUpdateStatusIfNeeded(ctx, rayJobInstance, job); shouldUpdate {
 				break
@@ -194,7 +262,7 @@ func (r *RayJobReconciler) Reconcile(ctx context.Context, request ctrl.Request)
 		// TODO (kevin85421): Maybe we only need to `get` the RayCluster because the RayCluster should have been created
 		// before transitioning the status from `Initializing` to `Running`.
 		if rayClusterInstance, err = r.getOrCreateRayClusterInstance(ctx, rayJobInstance); err != nil {
-			return ctrl.Result{RequeueAfter: RayJobDefaultRequeueDuration}, err
+			return ctrl.Result{RequeueAfter: options.Requeue.Interval}, err
 		}
 
 		// Check the current status of ray jobs
@@ -207,28 +275,98 @@ func (r *RayJobReconciler) Reconcile(ctx context.Context, request ctrl.Request)
 				r.Log.Info("The Ray job was not found. Submit a Ray job via an HTTP request.", "JobId", rayJobInstance.Status.JobId)
 				if _, err := rayDashboardClient.SubmitJob(ctx, rayJobInstance); err != nil {
 					r.Log.Error(err, "Failed to submit the Ray job", "JobId", rayJobInstance.Status.JobId)
-					return ctrl.Result{RequeueAfter: RayJobDefaultRequeueDuration}, err
+					return ctrl.Result{RequeueAfter: options.Requeue.Interval}, err
 				}
-				return ctrl.Result{RequeueAfter: RayJobDefaultRequeueDuration}, nil
+				return ctrl.Result{RequeueAfter: options.Requeue.Interval}, nil
 			}
 			r.Log.Error(err, "Failed to get job info", "JobId", rayJobInstance.Status.JobId)
-			return ctrl.Result{RequeueAfter: RayJobDefaultRequeueDuration}, err
+			return ctrl.Result{RequeueAfter: options.Requeue.Interval}, err
 		}
 		r.Log.Info("GetJobInfo", "Job Info", jobInfo)
 
 		// If the JobStatus is in a terminal status, such as SUCCEEDED, FAILED, or STOPPED, it is impossible for the Ray job
-		// to transition to any other. Additionally, RayJob does not currently support retries. Hence, we can mark the RayJob
-		// as "Complete" to avoid unnecessary reconciliation.
+		// to transition to any other. A FAILED Ray job is re-submitted as a new Ray job if the RayJob has not reached its
+		// backoff limit yet. Otherwise, we can mark the RayJob as "Complete" or "Failed" to avoid unnecessary reconciliation.
 		jobDeploymentStatus := rayv1.JobDeploymentStatusRunning
-		if rayv1.IsJobTerminal(jobInfo.JobStatus) {
+		if jobInfo.JobStatus == rayv1.JobStatusFailed {
+			jobDeploymentStatus = recordFailedAttempt(rayJobInstance, rayv1.AppFailed)
+		} else if rayv1.IsJobTerminal(jobInfo.JobStatus) {
 			jobDeploymentStatus = rayv1.JobDeploymentStatusComplete
 		}
 		// Always update RayClusterStatus along with JobStatus and JobDeploymentStatus updates.
 		rayJobInstance.Status.RayClusterStatus = rayClusterInstance.Status
 		rayJobInstance.Status.JobStatus = jobInfo.JobStatus
 		rayJobInstance.Status.Message = jobInfo.Message
-		rayJobInstance.Status.StartTime = utils.ConvertUnixTimeToMetav1Time(jobInfo.StartTime)
 		rayJobInstance.Status.JobDeploymentStatus = jobDeploymentStatus
+
+		// If the Ray job is still running after `ActiveDeadlineSeconds`, stop it and mark the RayJob as `Failed`.
+		if jobDeploymentStatus == rayv1.JobDeploymentStatusRunning && isActiveDeadlineExceeded(rayJobInstance, time.Now()) {
+			r.Log.Info("The RayJob has exceeded its activeDeadlineSeconds. Stop the Ray job and transition the status to `Failed`.",
+				"RayJob", rayJobInstance.Name, "JobId", rayJobInstance.Status.JobId, "ActiveDeadlineSeconds", *rayJobInstance.Spec.ActiveDeadlineSeconds)
+			if err := rayDashboardClient.StopJob(ctx, rayJobInstance.Status.JobId); err != nil {
+				r.Log.Error(err, "Failed to stop the Ray job which exceeded its deadline", "JobId", rayJobInstance.Status.JobId)
+				return ctrl.Result{RequeueAfter: options.Requeue.Interval}, err
+			}
+			r.Recorder.Eventf(rayJobInstance, corev1.EventTypeWarning, string(rayv1.DeadlineExceeded),
+				"Stopped the Ray job %s because it was active for longer than %d seconds", rayJobInstance.Status.JobId, *rayJobInstance.Spec.ActiveDeadlineSeconds)
+			rayJobInstance.Status.JobStatus = rayv1.JobStatusStopped
+			setActiveDeadlineExceeded(rayJobInstance)
+		}
+	case rayv1.JobDeploymentStatusRetrying:
+		if shouldUpdate := r.updateStatusToSuspendingIfNeeded(ctx, rayJobInstance); shouldUpdate {
+			break
+		}
+
+		// If the submitter Kubernetes Job has failed, the Ray job of the failed attempt may still be running.
+		// Stop it so that it doesn't compete with the new attempt for the resources of the RayCluster.
+		if !shouldRetryOnNewCluster(rayJobInstance) && !rayv1.IsJobTerminal(rayJobInstance.Status.JobStatus) && rayJobInstance.Status.DashboardURL != "" {
+			rayDashboardClient := r.dashboardClientFunc()
+			rayDashboardClient.InitClient(rayJobInstance.Status.DashboardURL)
+			if err := rayDashboardClient.StopJob(ctx, rayJobInstance.Status.JobId); err != nil {
+				r.Log.Info("Failed to stop the Ray job of the failed attempt", "JobId", rayJobInstance.Status.JobId, "error", err)
+			}
+		}
+		if shouldUpdate := r.checkActiveDeadlineAndUpdateStatusIfNeeded(rayJobInstance); shouldUpdate {
+			break
+		}
+
+		// Release the resources of the failed attempt before re-submitting the Ray job. The submitter Kubernetes Job
+		// has the same name as the RayJob, so it must be deleted before a new one can be created.
+		isJobDeleted, err := r.deleteSubmitterJob(ctx, rayJobInstance)
+		if err != nil {
+			return ctrl.Result{RequeueAfter: options.Requeue.Interval}, err
+		}
+		isClusterDeleted := true
+		if shouldRetryOnNewCluster(rayJobInstance) {
+			if isClusterDeleted, err = r.deleteClusterResources(ctx, rayJobInstance); err != nil {
+				return ctrl.Result{RequeueAfter: options.Requeue.Interval}, err
+			}
+		}
+		if !isClusterDeleted || !isJobDeleted {
+			r.Log.Info("The resources of the failed attempt have not been released yet. " +
+				"Wait for the resources to be deleted before re-submitting the Ray job.")
+			return ctrl.Result{RequeueAfter: options.Requeue.Interval}, nil
+		}
+
+		if delay := getRetryDelay(rayJobInstance, time.Now()); delay > 0 {
+			r.Log.Info("Wait for the backoff before re-submitting the Ray job", "RayJob", rayJobInstance.Name, "Failed", rayJobInstance.Status.Failed, "Delay", delay)
+			return ctrl.Result{RequeueAfter: delay}, nil
+		}
+
+		r.Log.Info("Re-submit the Ray job. Transition the status from `Retrying` to `New`.", "RayJob", rayJobInstance.Name,
+			"Failed", rayJobInstance.Status.Failed, "BackoffLimit", rayJobInstance.Spec.BackoffLimit, "RetryPolicy", rayJobInstance.Spec.RetryPolicy)
+		r.Recorder.Eventf(rayJobInstance, corev1.EventTypeNormal, "Retrying", "Re-submitting the Ray job after %d failed attempt(s)", rayJobInstance.Status.Failed)
+		if shouldRetryOnNewCluster(rayJobInstance) {
+			rayJobInstance.Status.RayClusterStatus = rayv1.RayClusterStatus{}
+			rayJobInstance.Status.RayClusterName = ""
+			rayJobInstance.Status.DashboardURL = ""
+		}
+		// A new Ray job ID is initialized in the `New` status, see initRayJobStatusIfNeed.
+		rayJobInstance.Status.JobId = ""
+		rayJobInstance.Status.Message = ""
+		rayJobInstance.Status.EndTime = nil
+		rayJobInstance.Status.JobStatus = rayv1.JobStatusNew
+		rayJobInstance.Status.JobDeploymentStatus = rayv1.JobDeploymentStatusNew
 	case rayv1.JobDeploymentStatusSuspending:
 		// The `suspend` operation should be atomic. In other words, if users set the `suspend` flag to true and then immediately
 		// set it back to false, either all of the RayJob's associated resources should be cleaned up, or no resources should be
@@ -240,16 +378,16 @@ func (r *RayJobReconciler) Reconcile(ctx context.Context, request ctrl.Request)
 		// users need to set the Pod's preStop hook by themselves.
 		isClusterDeleted, err := r.deleteClusterResources(ctx, rayJobInstance)
 		if err != nil {
-			return ctrl.Result{RequeueAfter: RayJobDefaultRequeueDuration}, err
+			return ctrl.Result{RequeueAfter: options.Requeue.Interval}, err
 		}
 		isJobDeleted, err := r.deleteSubmitterJob(ctx, rayJobInstance)
 		if err != nil {
-			return ctrl.Result{RequeueAfter: RayJobDefaultRequeueDuration}, err
+			return ctrl.Result{RequeueAfter: options.Requeue.Interval}, err
 		}
 		if !isClusterDeleted || !isJobDeleted {
 			r.Log.Info("The release of the compute resources has not been completed yet. " +
 				"Wait for the resources to be deleted before the status transitions to avoid a resource leak.")
-			return ctrl.Result{RequeueAfter: RayJobDefaultRequeueDuration}, nil
+			return ctrl.Result{RequeueAfter: options.Requeue.Interval}, nil
 		}
 
 		// Reset the RayCluster and Ray job related status.
@@ -258,6 +396,8 @@ func (r *RayJobReconciler) Reconcile(ctx context.Context, request ctrl.Request)
 		rayJobInstance.Status.DashboardURL = ""
 		rayJobInstance.Status.JobId = ""
 		rayJobInstance.Status.Message = ""
+		// A resumed RayJob gets a new `ActiveDeadlineSeconds`.
+		rayJobInstance.Status.StartTime = nil
 		// Reset the JobStatus to JobStatusNew and transition the JobDeploymentStatus to `Suspended`.
 		rayJobInstance.Status.JobStatus = rayv1.JobStatusNew
 		rayJobInstance.Status.JobDeploymentStatus = rayv1.JobDeploymentStatusSuspended
@@ -269,10 +409,10 @@ func (r *RayJobReconciler) Reconcile(ctx context.Context, request ctrl.Request)
 			break
 		}
 		// TODO (kevin85421): We may not need to requeue the RayJob if it has already been suspended.
-		return ctrl.Result{RequeueAfter: RayJobDefaultRequeueDuration}, nil
-	case rayv1.JobDeploymentStatusComplete:
+		return ctrl.Result{RequeueAfter: options.Requeue.Interval}, nil
+	case rayv1.JobDeploymentStatusComplete, rayv1.JobDeploymentStatusFailed:
 		// If this RayJob uses an existing RayCluster (i.e., ClusterSelector is set), we should not delete the RayCluster.
-		r.Log.Info("JobDeploymentStatusComplete", "RayJob", rayJobInstance.Name, "ShutdownAfterJobFinishes", rayJobInstance.Spec.ShutdownAfterJobFinishes, "ClusterSelector", rayJobInstance.Spec.ClusterSelector)
+		r.Log.Info(fmt.Sprintf("JobDeploymentStatus%s", rayJobInstance.Status.JobDeploymentStatus), "RayJob", rayJobInstance.Name, "ShutdownAfterJobFinishes", rayJobInstance.Spec.ShutdownAfterJobFinishes, "ClusterSelector", rayJobInstance.Spec.ClusterSelector)
 		if rayJobInstance.Spec.ShutdownAfterJobFinishes && len(rayJobInstance.Spec.ClusterSelector) == 0 {
 			ttlSeconds := rayJobInstance.Spec.TTLSecondsAfterFinished
 			nowTime := time.Now()
@@ -292,24 +432,24 @@ func (r *RayJobReconciler) Reconcile(ctx context.Context, request ctrl.Request)
 				// We only need to delete the RayCluster. We don't need to delete the submitter Kubernetes Job so that users can still access
 				// the driver logs. In addition, a completed Kubernetes Job does not actually use any compute resources.
 				if _, err = r.deleteClusterResources(ctx, rayJobInstance); err != nil {
-					return ctrl.Result{RequeueAfter: RayJobDefaultRequeueDuration}, err
+					return ctrl.Result{RequeueAfter: options.Requeue.Interval}, err
 				}
 			}
 		}
-		// If the RayJob is completed, we should not requeue it.
+		// If the RayJob is completed or failed, we should not requeue it.
 		return ctrl.Result{}, nil
 	default:
 		r.Log.Info("Unknown JobDeploymentStatus", "JobDeploymentStatus", rayJobInstance.Status.JobDeploymentStatus)
-		return ctrl.Result{RequeueAfter: RayJobDefaultRequeueDuration}, nil
+		return ctrl.Result{RequeueAfter: options.Requeue.Interval}, nil
 	}
 
 	// This is the only place where we update the RayJob status. Please do NOT add any code
 	// between the above switch statement and the following code.
 	if err = r.updateRayJobStatus(ctx, originalRayJobInstance, rayJobInstance); err != nil {
 		r.Log.Info("Failed to update RayJob status", "error", err)
-		return ctrl.Result{RequeueAfter: RayJobDefaultRequeueDuration}, err
+		return ctrl.Result{RequeueAfter: options.Requeue.Interval}, err
 	}
-	return ctrl.Result{RequeueAfter: RayJobDefaultRequeueDuration}, nil
+	return ctrl.Result{RequeueAfter: getRequeueDuration(rayJobInstance, options.Requeue, time.Now())}, nil
 }
 
 // createK8sJobIfNeed creates a Kubernetes Job for the RayJob if it doesn't exist.
@@ -497,6 +637,7 @@ func (r *RayJobReconciler) SetupWithManager(mgr ctrl.Manager) error {
 		Owns(&rayv1.RayCluster{}).
 		Owns(&corev1.Service{}).
 		Owns(&batchv1.Job{}).
+		WithOptions(controller.Options{RateLimiter: newErrorRateLimiter(r.getOptions().Requeue)}).
 		Complete(r)
 }
 
@@ -513,6 +654,10 @@ func (r *RayJobReconciler) initRayJobStatusIfNeed(ctx context.Context, rayJob *r
 	if rayJob.Status.JobId == "" {
 		if rayJob.Spec.JobId != "" {
 			rayJob.Status.JobId = rayJob.Spec.JobId
+			// Ray doesn't allow reusing the submission ID of a previous attempt.
+			if rayJob.Status.Failed > 0 {
+				rayJob.Status.JobId = fmt.Sprintf("%s-retry-%d", rayJob.Spec.JobId, rayJob.Status.Failed)
+			}
 		} else {
 			rayJob.Status.JobId = utils.GenerateRayJobId(rayJob.Name)
 		}
@@ -536,6 +681,10 @@ func (r *RayJobReconciler) initRayJobStatusIfNeed(ctx context.Context, rayJob *r
 	if rayJob.Status.JobStatus == "" {
 		rayJob.Status.JobStatus = rayv1.JobStatusNew
 	}
+	// The start time is kept when the Ray job is retried, so that `ActiveDeadlineSeconds` covers all attempts.
+	if rayJob.Status.StartTime == nil {
+		rayJob.Status.StartTime = &metav1.Time{Time: time.Now()}
+	}
 	rayJob.Status.JobDeploymentStatus = rayv1.JobDeploymentStatusInitializing
 	return nil
 }
@@ -549,19 +698,90 @@ func (r *RayJobReconciler) updateRayJobStatus(ctx context.Context, oldRayJob *ra
 	if oldRayJobStatus.JobStatus != newRayJobStatus.JobStatus ||
 		oldRayJobStatus.JobDeploymentStatus != newRayJobStatus.JobDeploymentStatus {
 
-		if newRayJobStatus.JobDeploymentStatus == rayv1.JobDeploymentStatusComplete {
+		switch newRayJobStatus.JobDeploymentStatus {
+		case rayv1.JobDeploymentStatusComplete, rayv1.JobDeploymentStatusFailed, rayv1.JobDeploymentStatusRetrying:
 			newRayJob.Status.EndTime = &metav1.Time{Time: time.Now()}
 		}
+		setRayJobConditions(newRayJob)
 
 		r.Log.Info("updateRayJobStatus", "old JobStatus", oldRayJobStatus.JobStatus, "new JobStatus", newRayJobStatus.JobStatus,
 			"old JobDeploymentStatus", oldRayJobStatus.JobDeploymentStatus, "new JobDeploymentStatus", newRayJobStatus.JobDeploymentStatus)
 		if err := r.Status().Update(ctx, newRayJob); err != nil {
 			return err
 		}
+		recordRayJobMetrics(oldRayJobStatus, newRayJob)
 	}
 	return nil
 }
 
+// recordRayJobMetrics records the deployment status of a RayJob, and its duration once it completes or fails.
+func recordRayJobMetrics(oldRayJobStatus rayv1.RayJobStatus, newRayJob *rayv1.RayJob) {
+	newRayJobStatus := newRayJob.Status
+	metrics.SetJobDeploymentStatus(newRayJob.Namespace, newRayJob.Name, string(newRayJobStatus.JobDeploymentStatus))
+
+	if oldRayJobStatus.JobDeploymentStatus == newRayJobStatus.JobDeploymentStatus || newRayJobStatus.EndTime == nil {
+		return
+	}
+	switch newRayJobStatus.JobDeploymentStatus {
+	case rayv1.JobDeploymentStatusComplete, rayv1.JobDeploymentStatusFailed:
+		startTime := newRayJob.CreationTimestamp.Time
+		if newRayJobStatus.StartTime != nil {
+			startTime = newRayJobStatus.StartTime.Time
+		}
+		metrics.ObserveJobDuration(newRayJob.Namespace, string(newRayJobStatus.JobDeploymentStatus), string(newRayJobStatus.JobStatus),
+			newRayJobStatus.EndTime.Sub(startTime))
+	}
+}
+
+// setRayJobConditions derives `rayJob.Status.Conditions` from the JobStatus and JobDeploymentStatus of the RayJob.
+func setRayJobConditions(rayJob *rayv1.RayJob) {
+	jobSubmittedCondition := metav1.Condition{
+		Type:               string(rayv1.JobSubmitted),
+		Status:             metav1.ConditionTrue,
+		Reason:             "JobAccepted",
+		Message:            fmt.Sprintf("The Ray job %s has been accepted by the RayCluster", rayJob.Status.JobId),
+		ObservedGeneration: rayJob.Generation,
+	}
+	if rayJob.Status.JobStatus == rayv1.JobStatusNew {
+		jobSubmittedCondition.Status = metav1.ConditionFalse
+		jobSubmittedCondition.Reason = "JobNotSubmitted"
+		jobSubmittedCondition.Message = "The Ray job has not been accepted by the RayCluster yet"
+	}
+	meta.SetStatusCondition(&rayJob.Status.Conditions, jobSubmittedCondition)
+
+	completeCondition := metav1.Condition{
+		Type:               string(rayv1.RayJobComplete),
+		Status:             metav1.ConditionFalse,
+		Reason:             string(rayJob.Status.JobDeploymentStatus),
+		ObservedGeneration: rayJob.Generation,
+	}
+	if rayJob.Status.JobDeploymentStatus == rayv1.JobDeploymentStatusComplete {
+		completeCondition.Status = metav1.ConditionTrue
+		completeCondition.Reason = "JobFinished"
+		completeCondition.Message = fmt.Sprintf("The Ray job finished with status %s", rayJob.Status.JobStatus)
+	}
+	if completeCondition.Reason == "" {
+		completeCondition.Reason = "New"
+	}
+	meta.SetStatusCondition(&rayJob.Status.Conditions, completeCondition)
+
+	failedCondition := metav1.Condition{
+		Type:               string(rayv1.RayJobFailed),
+		Status:             metav1.ConditionFalse,
+		Reason:             "NotFailed",
+		ObservedGeneration: rayJob.Generation,
+	}
+	if rayJob.Status.JobDeploymentStatus == rayv1.JobDeploymentStatusFailed {
+		failedCondition.Status = metav1.ConditionTrue
+		failedCondition.Reason = string(rayJob.Status.Reason)
+		failedCondition.Message = rayJob.Status.Message
+	}
+	if failedCondition.Reason == "" {
+		failedCondition.Reason = string(rayv1.JobDeploymentStatusFailed)
+	}
+	meta.SetStatusCondition(&rayJob.Status.Conditions, failedCondition)
+}
+
 func (r *RayJobReconciler) getOrCreateRayClusterInstance(ctx context.Context, rayJobInstance *rayv1.RayJob) (*rayv1.RayCluster, error) {
 	rayClusterInstanceName := rayJobInstance.Status.RayClusterName
 	r.Log.Info("try to find existing RayCluster instance", "name", rayClusterInstanceName)
@@ -631,14 +851,52 @@ func (r *RayJobReconciler) constructRayClusterForRayJob(rayJobInstance *rayv1.Ra
 	return rayCluster, nil
 }
 
+// reconcileBatchSchedulerAdmission submits the RayJob to its batch scheduler if the scheduler admits RayJobs as a whole,
+// e.g. Kueue. The scheduler suspends the RayJob by updating `Spec.Suspend` until it is admitted, so that the RayCluster
+// is deleted or created by the regular suspension logic.
+func (r *RayJobReconciler) reconcileBatchSchedulerAdmission(ctx context.Context, rayJob *rayv1.RayJob) error {
+	if !features.Enabled(ctx, features.BatchScheduler) {
+		return nil
+	}
+	scheduler, err := r.BatchSchedulerMgr.GetSchedulerForRayJob(rayJob)
+	if err != nil {
+		return err
+	}
+	admissionScheduler, ok := scheduler.(schedulerinterface.AdmissionBatchScheduler)
+	if !ok {
+		return nil
+	}
+
+	original := rayJob.DeepCopy()
+	if _, err := admissionScheduler.AdmitRayJob(ctx, rayJob); err != nil {
+		return err
+	}
+	if reflect.DeepEqual(original.Labels, rayJob.Labels) && reflect.DeepEqual(original.Annotations, rayJob.Annotations) &&
+		original.Spec.Suspend == rayJob.Spec.Suspend {
+		return nil
+	}
+	if err := r.Update(ctx, rayJob); err != nil {
+		return err
+	}
+	if rayJob.Spec.Suspend != original.Spec.Suspend {
+		if rayJob.Spec.Suspend {
+			r.Recorder.Eventf(rayJob, corev1.EventTypeNormal, "Suspended", "Suspended the RayJob until the %s batch scheduler admits it", scheduler.Name())
+		} else {
+			r.Recorder.Eventf(rayJob, corev1.EventTypeNormal, "Resumed", "Resumed the RayJob because the %s batch scheduler admitted it", scheduler.Name())
+		}
+	}
+	return nil
+}
+
 func (r *RayJobReconciler) updateStatusToSuspendingIfNeeded(ctx context.Context, rayJob *rayv1.RayJob) bool {
 	if !rayJob.Spec.Suspend {
 		return false
 	}
-	// In KubeRay, only `Running` and `Initializing` are allowed to transition to `Suspending`.
+	// In KubeRay, only `Running`, `Initializing` and `Retrying` are allowed to transition to `Suspending`.
 	validTransitions := map[rayv1.JobDeploymentStatus]struct{}{
 		rayv1.JobDeploymentStatusRunning:      {},
 		rayv1.JobDeploymentStatusInitializing: {},
+		rayv1.JobDeploymentStatusRetrying:     {},
 	}
 	if _, ok := validTransitions[rayJob.Status.JobDeploymentStatus]; !ok {
 		r.Log.Info("The current status is not allowed to transition to `Suspending`", "RayJob", rayJob.Name, "JobDeploymentStatus", rayJob.Status.JobDeploymentStatus)
@@ -652,15 +910,97 @@ func (r *RayJobReconciler) updateStatusToSuspendingIfNeeded(ctx context.Context,
 func (r *RayJobReconciler) checkK8sJobAndUpdateStatusIfNeeded(ctx context.Context, rayJob *rayv1.RayJob, job *batchv1.Job) bool {
 	for _, cond := range job.Status.Conditions {
 		if cond.Type == batchv1.JobFailed && cond.Status == corev1.ConditionTrue {
-			r.Log.Info("The submitter Kubernetes Job has failed. Attempting to transition the status to `Complete`.", "RayJob", rayJob.Name, "Submitter K8s Job", job.Name, "Reason", cond.Reason, "Message", cond.Message)
 			rayJob.Status.Message = "The submitter Kubernetes Job is failed. Reason: " + cond.Reason + ". Message: " + cond.Message
-			rayJob.Status.JobDeploymentStatus = rayv1.JobDeploymentStatusComplete
+			rayJob.Status.JobDeploymentStatus = recordFailedAttempt(rayJob, rayv1.SubmissionFailed)
+			r.Log.Info(fmt.Sprintf("The submitter Kubernetes Job has failed. Attempting to transition the status to `%s`.", rayJob.Status.JobDeploymentStatus), "RayJob", rayJob.Name, "Submitter K8s Job", job.Name, "Reason", cond.Reason, "Message", cond.Message)
 			return true
 		}
 	}
 	return false
 }
 
+// recordFailedAttempt increments the number of failed attempts of the RayJob. It returns `Retrying` if the Ray job
+// should be re-submitted, or `Failed` with the given reason if the RayJob has reached its backoff limit.
+func recordFailedAttempt(rayJob *rayv1.RayJob, reason rayv1.JobFailedReason) rayv1.JobDeploymentStatus {
+	rayJob.Status.Failed++
+	if rayJob.Spec.BackoffLimit != nil && rayJob.Status.Failed <= *rayJob.Spec.BackoffLimit {
+		return rayv1.JobDeploymentStatusRetrying
+	}
+	rayJob.Status.Reason = reason
+	return rayv1.JobDeploymentStatusFailed
+}
+
+// checkActiveDeadlineAndUpdateStatusIfNeeded transitions the RayJob to `Failed` if it exceeds its `ActiveDeadlineSeconds`
+// while no Ray job is running, e.g. while its RayCluster is starting or while it waits to be retried. The RayCluster
+// is deleted afterwards if `ShutdownAfterJobFinishes` is set, like for any other failed RayJob.
+func (r *RayJobReconciler) checkActiveDeadlineAndUpdateStatusIfNeeded(rayJob *rayv1.RayJob) bool {
+	if !isActiveDeadlineExceeded(rayJob, time.Now()) {
+		return false
+	}
+	r.Log.Info("The RayJob has exceeded its activeDeadlineSeconds. Transition the status to `Failed`.",
+		"RayJob", rayJob.Name, "JobDeploymentStatus", rayJob.Status.JobDeploymentStatus, "ActiveDeadlineSeconds", *rayJob.Spec.ActiveDeadlineSeconds)
+	r.Recorder.Eventf(rayJob, corev1.EventTypeWarning, string(rayv1.DeadlineExceeded),
+		"The RayJob was active for longer than %d seconds", *rayJob.Spec.ActiveDeadlineSeconds)
+	setActiveDeadlineExceeded(rayJob)
+	return true
+}
+
+// setActiveDeadlineExceeded marks the RayJob as `Failed` because of its `ActiveDeadlineSeconds`. The RayJob is not retried.
+func setActiveDeadlineExceeded(rayJob *rayv1.RayJob) {
+	rayJob.Status.Message = fmt.Sprintf("The RayJob was active for longer than activeDeadlineSeconds (%d)", *rayJob.Spec.ActiveDeadlineSeconds)
+	rayJob.Status.Reason = rayv1.DeadlineExceeded
+	rayJob.Status.JobDeploymentStatus = rayv1.JobDeploymentStatusFailed
+}
+
+// isActiveDeadlineExceeded returns whether the RayJob has been active for longer than `ActiveDeadlineSeconds`.
+func isActiveDeadlineExceeded(rayJob *rayv1.RayJob, now time.Time) bool {
+	if rayJob.Spec.ActiveDeadlineSeconds == nil || rayJob.Status.StartTime == nil {
+		return false
+	}
+	deadline := rayJob.Status.StartTime.Add(time.Duration(*rayJob.Spec.ActiveDeadlineSeconds) * time.Second)
+	return !now.Before(deadline)
+}
+
+// getRequeueDuration returns when to reconcile a RayJob again after it was reconciled successfully. A running Ray job
+// is polled every `SteadyStateInterval`, but no later than its `ActiveDeadlineSeconds`, so that the deadline is enforced in time.
+func getRequeueDuration(rayJob *rayv1.RayJob, requeue RequeueOptions, now time.Time) time.Duration {
+	if rayJob.Status.JobDeploymentStatus != rayv1.JobDeploymentStatusRunning || rayJob.Status.JobStatus != rayv1.JobStatusRunning {
+		return requeue.Interval
+	}
+	requeueAfter := requeue.SteadyStateInterval
+	if rayJob.Spec.ActiveDeadlineSeconds != nil && rayJob.Status.StartTime != nil {
+		deadline := rayJob.Status.StartTime.Add(time.Duration(*rayJob.Spec.ActiveDeadlineSeconds) * time.Second)
+		if untilDeadline := deadline.Sub(now); untilDeadline < requeueAfter {
+			requeueAfter = untilDeadline
+		}
+	}
+	if requeueAfter < requeue.Interval {
+		requeueAfter = requeue.Interval
+	}
+	return requeueAfter
+}
+
+// getRetryDelay returns how long to wait before re-submitting the failed Ray job. The backoff starts at
+// RayJobRetryBaseBackoff after the first failed attempt and is doubled for each further one.
+func getRetryDelay(rayJob *rayv1.RayJob, now time.Time) time.Duration {
+	if rayJob.Status.EndTime == nil {
+		return 0
+	}
+	backoff := RayJobRetryBaseBackoff
+	for i := int32(1); i < rayJob.Status.Failed && backoff < RayJobRetryMaxBackoff; i++ {
+		backoff *= 2
+	}
+	if backoff > RayJobRetryMaxBackoff {
+		backoff = RayJobRetryMaxBackoff
+	}
+	return rayJob.Status.EndTime.Add(backoff).Sub(now)
+}
+
+// shouldRetryOnNewCluster returns whether the RayCluster of a failed attempt should be replaced by a new one.
+func shouldRetryOnNewCluster(rayJob *rayv1.RayJob) bool {
+	return rayJob.Spec.RetryPolicy == rayv1.RetryOnNewCluster && len(rayJob.Spec.ClusterSelector) == 0
+}
+
 func validateRayJobSpec(rayJob *rayv1.RayJob) error {
 	// KubeRay has some limitations for the suspend operation. The limitations are a subset of the limitations of
 	// Kueue (https://kueue.sigs.k8s.io/docs/tasks/run_rayjobs/#c-limitations). For example, KubeRay allows users
@@ -671,6 +1011,15 @@ func validateRayJobSpec(rayJob *rayv1.RayJob) error {
 	if rayJob.Spec.Suspend && len(rayJob.Spec.ClusterSelector) != 0 {
 		return fmt.Errorf("the ClusterSelector mode doesn't support the suspend operation")
 	}
+	if rayJob.Spec.BackoffLimit != nil && *rayJob.Spec.BackoffLimit < 0 {
+		return fmt.Errorf("backoffLimit must be a non-negative integer")
+	}
+	if rayJob.Spec.ActiveDeadlineSeconds != nil && *rayJob.Spec.ActiveDeadlineSeconds <= 0 {
+		return fmt.Errorf("activeDeadlineSeconds must be a positive integer")
+	}
+	if rayJob.Spec.RetryPolicy == rayv1.RetryOnNewCluster && len(rayJob.Spec.ClusterSelector) != 0 {
+		return fmt.Errorf("the ClusterSelector mode doesn't support the %s retry policy", rayv1.RetryOnNewCluster)
+	}
 	if rayJob.Spec.RayClusterSpec == nil && len(rayJob.Spec.ClusterSelector) == 0 {
 		return fmt.Errorf("one of RayClusterSpec or ClusterSelector must be set")
 	}
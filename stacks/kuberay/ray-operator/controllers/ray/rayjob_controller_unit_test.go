@@ -3,16 +3,20 @@ package ray
 import (
 	"context"
 	"testing"
+	"time"
 
 	rayv1 "github.com/ray-project/kuberay/ray-operator/apis/ray/v1"
 	utils "github.com/ray-project/kuberay/ray-operator/controllers/ray/utils"
 	"github.com/stretchr/testify/assert"
 	batchv1 "k8s.io/api/batch/v1"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/pointer"
 	ctrl "sigs.k8s.io/controller-runtime"
 	clientFake "sigs.k8s.io/controller-runtime/pkg/client/fake"
 )
@@ -328,6 +332,40 @@ func TestUpdateRayJobStatus(t *testing.T) {
 	}
 }
 
+func TestSetRayJobConditions(t *testing.T) {
+	rayJob := &rayv1.RayJob{
+		ObjectMeta: metav1.ObjectMeta{Generation: 1},
+		Status: rayv1.RayJobStatus{
+			JobDeploymentStatus: rayv1.JobDeploymentStatusInitializing,
+		},
+	}
+	setRayJobConditions(rayJob)
+	assert.True(t, meta.IsStatusConditionFalse(rayJob.Status.Conditions, string(rayv1.JobSubmitted)))
+	assert.True(t, meta.IsStatusConditionFalse(rayJob.Status.Conditions, string(rayv1.RayJobComplete)))
+	assert.True(t, meta.IsStatusConditionFalse(rayJob.Status.Conditions, string(rayv1.RayJobFailed)))
+
+	rayJob.Status.JobDeploymentStatus = rayv1.JobDeploymentStatusRunning
+	rayJob.Status.JobStatus = rayv1.JobStatusRunning
+	setRayJobConditions(rayJob)
+	assert.True(t, meta.IsStatusConditionTrue(rayJob.Status.Conditions, string(rayv1.JobSubmitted)))
+	assert.True(t, meta.IsStatusConditionFalse(rayJob.Status.Conditions, string(rayv1.RayJobComplete)))
+
+	rayJob.Status.JobDeploymentStatus = rayv1.JobDeploymentStatusComplete
+	rayJob.Status.JobStatus = rayv1.JobStatusSucceeded
+	setRayJobConditions(rayJob)
+	assert.True(t, meta.IsStatusConditionTrue(rayJob.Status.Conditions, string(rayv1.RayJobComplete)))
+	assert.True(t, meta.IsStatusConditionFalse(rayJob.Status.Conditions, string(rayv1.RayJobFailed)))
+
+	rayJob.Status.JobDeploymentStatus = rayv1.JobDeploymentStatusFailed
+	rayJob.Status.JobStatus = rayv1.JobStatusStopped
+	rayJob.Status.Reason = rayv1.DeadlineExceeded
+	setRayJobConditions(rayJob)
+	failedCondition := meta.FindStatusCondition(rayJob.Status.Conditions, string(rayv1.RayJobFailed))
+	assert.Equal(t, metav1.ConditionTrue, failedCondition.Status)
+	assert.Equal(t, string(rayv1.DeadlineExceeded), failedCondition.Reason)
+	assert.True(t, meta.IsStatusConditionFalse(rayJob.Status.Conditions, string(rayv1.RayJobComplete)))
+}
+
 func TestValidateRayJobSpec(t *testing.T) {
 	err := validateRayJobSpec(&rayv1.RayJob{})
 	assert.Error(t, err, "The RayJob is invalid because both `RayClusterSpec` and `ClusterSelector` are empty")
@@ -365,4 +403,486 @@ func TestValidateRayJobSpec(t *testing.T) {
 		},
 	})
 	assert.Error(t, err, "The RayJob is invalid because the runtimeEnvYAML is invalid.")
+
+	err = validateRayJobSpec(&rayv1.RayJob{
+		Spec: rayv1.RayJobSpec{
+			BackoffLimit:   pointer.Int32(-1),
+			RayClusterSpec: &rayv1.RayClusterSpec{},
+		},
+	})
+	assert.Error(t, err, "The RayJob is invalid because the backoffLimit is negative.")
+
+	err = validateRayJobSpec(&rayv1.RayJob{
+		Spec: rayv1.RayJobSpec{
+			RetryPolicy: rayv1.RetryOnNewCluster,
+			ClusterSelector: map[string]string{
+				"key": "value",
+			},
+		},
+	})
+	assert.Error(t, err, "The RayJob is invalid because the ClusterSelector mode doesn't support the NewCluster retry policy.")
+
+	err = validateRayJobSpec(&rayv1.RayJob{
+		Spec: rayv1.RayJobSpec{
+			BackoffLimit:   pointer.Int32(3),
+			RetryPolicy:    rayv1.RetryOnNewCluster,
+			RayClusterSpec: &rayv1.RayClusterSpec{},
+		},
+	})
+	assert.NoError(t, err, "The RayJob is valid.")
+
+	err = validateRayJobSpec(&rayv1.RayJob{
+		Spec: rayv1.RayJobSpec{
+			ActiveDeadlineSeconds: pointer.Int32(0),
+			RayClusterSpec:        &rayv1.RayClusterSpec{},
+		},
+	})
+	assert.Error(t, err, "The RayJob is invalid because the activeDeadlineSeconds is not positive.")
+}
+
+func TestRecordFailedAttempt(t *testing.T) {
+	tests := map[string]struct {
+		backoffLimit   *int32
+		failed         int32
+		expectedStatus rayv1.JobDeploymentStatus
+	}{
+		"BackoffLimit is not set": {
+			backoffLimit:   nil,
+			failed:         0,
+			expectedStatus: rayv1.JobDeploymentStatusFailed,
+		},
+		"BackoffLimit is 0": {
+			backoffLimit:   pointer.Int32(0),
+			failed:         0,
+			expectedStatus: rayv1.JobDeploymentStatusFailed,
+		},
+		"BackoffLimit is not reached": {
+			backoffLimit:   pointer.Int32(2),
+			failed:         1,
+			expectedStatus: rayv1.JobDeploymentStatusRetrying,
+		},
+		"BackoffLimit is reached": {
+			backoffLimit:   pointer.Int32(2),
+			failed:         2,
+			expectedStatus: rayv1.JobDeploymentStatusFailed,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			rayJob := &rayv1.RayJob{
+				Spec: rayv1.RayJobSpec{
+					BackoffLimit: tc.backoffLimit,
+				},
+				Status: rayv1.RayJobStatus{
+					Failed: tc.failed,
+				},
+			}
+			assert.Equal(t, tc.expectedStatus, recordFailedAttempt(rayJob, rayv1.AppFailed))
+			assert.Equal(t, tc.failed+1, rayJob.Status.Failed)
+			if tc.expectedStatus == rayv1.JobDeploymentStatusFailed {
+				assert.Equal(t, rayv1.AppFailed, rayJob.Status.Reason)
+			} else {
+				assert.Empty(t, rayJob.Status.Reason)
+			}
+		})
+	}
+}
+
+func TestGetRetryDelay(t *testing.T) {
+	now := time.Now()
+	tests := map[string]struct {
+		endTime       *metav1.Time
+		failed        int32
+		expectedDelay time.Duration
+	}{
+		"EndTime is not set": {
+			endTime:       nil,
+			failed:        1,
+			expectedDelay: 0,
+		},
+		"First failed attempt": {
+			endTime:       &metav1.Time{Time: now},
+			failed:        1,
+			expectedDelay: RayJobRetryBaseBackoff,
+		},
+		"Backoff doubles with every failed attempt": {
+			endTime:       &metav1.Time{Time: now.Add(-5 * time.Second)},
+			failed:        3,
+			expectedDelay: 4*RayJobRetryBaseBackoff - 5*time.Second,
+		},
+		"Backoff is capped": {
+			endTime:       &metav1.Time{Time: now},
+			failed:        100,
+			expectedDelay: RayJobRetryMaxBackoff,
+		},
+		"Backoff has elapsed": {
+			endTime:       &metav1.Time{Time: now.Add(-time.Hour)},
+			failed:        1,
+			expectedDelay: RayJobRetryBaseBackoff - time.Hour,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			rayJob := &rayv1.RayJob{
+				Status: rayv1.RayJobStatus{
+					EndTime: tc.endTime,
+					Failed:  tc.failed,
+				},
+			}
+			assert.Equal(t, tc.expectedDelay, getRetryDelay(rayJob, now))
+		})
+	}
+}
+
+func TestInitRayJobStatusIfNeedForRetry(t *testing.T) {
+	rayJobReconciler := &RayJobReconciler{
+		Log: ctrl.Log.WithName("controllers").WithName("RayJob"),
+	}
+	rayJob := &rayv1.RayJob{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test-rayjob",
+			Namespace: "default",
+		},
+		Spec: rayv1.RayJobSpec{
+			JobId: "my-job",
+		},
+		Status: rayv1.RayJobStatus{
+			RayClusterName: "test-raycluster",
+			Failed:         2,
+		},
+	}
+
+	err := rayJobReconciler.initRayJobStatusIfNeed(context.Background(), rayJob)
+	assert.NoError(t, err)
+	assert.Equal(t, "my-job-retry-2", rayJob.Status.JobId)
+	assert.Equal(t, "test-raycluster", rayJob.Status.RayClusterName)
+	assert.Equal(t, rayv1.JobDeploymentStatusInitializing, rayJob.Status.JobDeploymentStatus)
+}
+
+func TestReconcileRetryingRayJob(t *testing.T) {
+	newScheme := runtime.NewScheme()
+	_ = rayv1.AddToScheme(newScheme)
+	_ = batchv1.AddToScheme(newScheme)
+	_ = corev1.AddToScheme(newScheme)
+
+	tests := map[string]struct {
+		retryPolicy            rayv1.RayJobRetryPolicy
+		expectedRayClusterName string
+	}{
+		"Retry on the same RayCluster": {
+			retryPolicy:            rayv1.RetryOnSameCluster,
+			expectedRayClusterName: "test-raycluster",
+		},
+		"Retry on a new RayCluster": {
+			retryPolicy:            rayv1.RetryOnNewCluster,
+			expectedRayClusterName: "",
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			rayJob := &rayv1.RayJob{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "test-rayjob",
+					Namespace: "default",
+				},
+				Spec: rayv1.RayJobSpec{
+					BackoffLimit:   pointer.Int32(3),
+					RetryPolicy:    tc.retryPolicy,
+					SubmissionMode: rayv1.K8sJobMode,
+					RayClusterSpec: &rayv1.RayClusterSpec{},
+				},
+				Status: rayv1.RayJobStatus{
+					JobId:               "test-rayjob-abcde",
+					RayClusterName:      "test-raycluster",
+					DashboardURL:        "test-raycluster-head-svc.default.svc.cluster.local:8265",
+					JobStatus:           rayv1.JobStatusFailed,
+					JobDeploymentStatus: rayv1.JobDeploymentStatusRetrying,
+					EndTime:             &metav1.Time{Time: time.Now().Add(-time.Minute)},
+					Failed:              1,
+				},
+			}
+			rayCluster := &rayv1.RayCluster{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "test-raycluster",
+					Namespace: "default",
+				},
+			}
+			k8sJob := &batchv1.Job{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "test-rayjob",
+					Namespace: "default",
+				},
+			}
+
+			fakeClient := clientFake.NewClientBuilder().
+				WithScheme(newScheme).
+				WithRuntimeObjects(rayJob, rayCluster, k8sJob).
+				WithStatusSubresource(rayJob).Build()
+			ctx := context.Background()
+
+			rayJobReconciler := &RayJobReconciler{
+				Client:              fakeClient,
+				Recorder:            &record.FakeRecorder{},
+				Scheme:              newScheme,
+				Log:                 ctrl.Log.WithName("controllers").WithName("RayJob"),
+				dashboardClientFunc: func() utils.RayDashboardClientInterface { return &utils.FakeRayDashboardClient{} },
+			}
+			namespacedName := types.NamespacedName{Namespace: rayJob.Namespace, Name: rayJob.Name}
+
+			// The first reconciliation deletes the resources of the failed attempt, and the second one
+			// transitions the status to `New` once they are gone.
+			for i := 0; i < 2; i++ {
+				_, err := rayJobReconciler.Reconcile(ctx, ctrl.Request{NamespacedName: namespacedName})
+				assert.NoError(t, err)
+			}
+
+			err := fakeClient.Get(ctx, namespacedName, rayJob)
+			assert.NoError(t, err)
+			assert.Equal(t, rayv1.JobDeploymentStatusNew, rayJob.Status.JobDeploymentStatus)
+			assert.Equal(t, rayv1.JobStatusNew, rayJob.Status.JobStatus)
+			assert.Equal(t, tc.expectedRayClusterName, rayJob.Status.RayClusterName)
+			assert.Empty(t, rayJob.Status.JobId)
+			assert.Nil(t, rayJob.Status.EndTime)
+			assert.Equal(t, int32(1), rayJob.Status.Failed)
+
+			err = fakeClient.Get(ctx, types.NamespacedName{Namespace: k8sJob.Namespace, Name: k8sJob.Name}, k8sJob)
+			assert.True(t, errors.IsNotFound(err), "The submitter Kubernetes Job should be deleted")
+			err = fakeClient.Get(ctx, types.NamespacedName{Namespace: rayCluster.Namespace, Name: rayCluster.Name}, rayCluster)
+			if tc.retryPolicy == rayv1.RetryOnNewCluster {
+				assert.True(t, errors.IsNotFound(err), "The RayCluster should be deleted")
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestIsActiveDeadlineExceeded(t *testing.T) {
+	now := time.Now()
+	tests := map[string]struct {
+		activeDeadlineSeconds *int32
+		startTime             *metav1.Time
+		expected              bool
+	}{
+		"ActiveDeadlineSeconds is not set": {
+			activeDeadlineSeconds: nil,
+			startTime:             &metav1.Time{Time: now.Add(-time.Hour)},
+			expected:              false,
+		},
+		"StartTime is not set": {
+			activeDeadlineSeconds: pointer.Int32(60),
+			startTime:             nil,
+			expected:              false,
+		},
+		"Deadline is not exceeded": {
+			activeDeadlineSeconds: pointer.Int32(60),
+			startTime:             &metav1.Time{Time: now.Add(-30 * time.Second)},
+			expected:              false,
+		},
+		"Deadline is exceeded": {
+			activeDeadlineSeconds: pointer.Int32(60),
+			startTime:             &metav1.Time{Time: now.Add(-time.Minute)},
+			expected:              true,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			rayJob := &rayv1.RayJob{
+				Spec: rayv1.RayJobSpec{
+					ActiveDeadlineSeconds: tc.activeDeadlineSeconds,
+				},
+				Status: rayv1.RayJobStatus{
+					StartTime: tc.startTime,
+				},
+			}
+			assert.Equal(t, tc.expected, isActiveDeadlineExceeded(rayJob, now))
+		})
+	}
+}
+
+func TestReconcileRayJobDeadlineExceeded(t *testing.T) {
+	newScheme := runtime.NewScheme()
+	_ = rayv1.AddToScheme(newScheme)
+	_ = corev1.AddToScheme(newScheme)
+
+	rayJob := &rayv1.RayJob{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test-rayjob",
+			Namespace: "default",
+		},
+		Spec: rayv1.RayJobSpec{
+			ActiveDeadlineSeconds: pointer.Int32(60),
+			BackoffLimit:          pointer.Int32(3),
+			SubmissionMode:        rayv1.HTTPMode,
+			RayClusterSpec:        &rayv1.RayClusterSpec{},
+		},
+		Status: rayv1.RayJobStatus{
+			JobId:               "test-rayjob-abcde",
+			RayClusterName:      "test-raycluster",
+			DashboardURL:        "test-raycluster-head-svc.default.svc.cluster.local:8265",
+			JobStatus:           rayv1.JobStatusRunning,
+			JobDeploymentStatus: rayv1.JobDeploymentStatusRunning,
+			StartTime:           &metav1.Time{Time: time.Now().Add(-2 * time.Minute)},
+		},
+	}
+	rayCluster := &rayv1.RayCluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test-raycluster",
+			Namespace: "default",
+		},
+	}
+
+	fakeClient := clientFake.NewClientBuilder().
+		WithScheme(newScheme).
+		WithRuntimeObjects(rayJob, rayCluster).
+		WithStatusSubresource(rayJob).Build()
+	ctx := context.Background()
+
+	fakeDashboardClient := &utils.FakeRayDashboardClient{}
+	getJobInfo := func(context.Context, string) (*utils.RayJobInfo, error) {
+		return &utils.RayJobInfo{JobStatus: rayv1.JobStatusRunning}, nil
+	}
+	fakeDashboardClient.GetJobInfoMock.Store(&getJobInfo)
+
+	rayJobReconciler := &RayJobReconciler{
+		Client:              fakeClient,
+		Recorder:            &record.FakeRecorder{},
+		Scheme:              newScheme,
+		Log:                 ctrl.Log.WithName("controllers").WithName("RayJob"),
+		dashboardClientFunc: func() utils.RayDashboardClientInterface { return fakeDashboardClient },
+	}
+	namespacedName := types.NamespacedName{Namespace: rayJob.Namespace, Name: rayJob.Name}
+	_, err := rayJobReconciler.Reconcile(ctx, ctrl.Request{NamespacedName: namespacedName})
+	assert.NoError(t, err)
+
+	err = fakeClient.Get(ctx, namespacedName, rayJob)
+	assert.NoError(t, err)
+	// The RayJob is not retried even though the backoff limit is not reached.
+	assert.Equal(t, rayv1.JobDeploymentStatusFailed, rayJob.Status.JobDeploymentStatus)
+	assert.Equal(t, rayv1.DeadlineExceeded, rayJob.Status.Reason)
+	assert.Equal(t, rayv1.JobStatusStopped, rayJob.Status.JobStatus)
+	assert.NotNil(t, rayJob.Status.EndTime)
+}
+
+func TestReconcileRayJobDeadlineExceededWhileInitializing(t *testing.T) {
+	newScheme := runtime.NewScheme()
+	_ = rayv1.AddToScheme(newScheme)
+	_ = corev1.AddToScheme(newScheme)
+
+	// The RayCluster never becomes ready.
+	rayJob := &rayv1.RayJob{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test-rayjob",
+			Namespace: "default",
+		},
+		Spec: rayv1.RayJobSpec{
+			ActiveDeadlineSeconds:    pointer.Int32(60),
+			ShutdownAfterJobFinishes: true,
+			RayClusterSpec:           &rayv1.RayClusterSpec{},
+		},
+		Status: rayv1.RayJobStatus{
+			JobId:               "test-rayjob-abcde",
+			RayClusterName:      "test-raycluster",
+			JobStatus:           rayv1.JobStatusNew,
+			JobDeploymentStatus: rayv1.JobDeploymentStatusInitializing,
+			StartTime:           &metav1.Time{Time: time.Now().Add(-2 * time.Minute)},
+		},
+	}
+	rayCluster := &rayv1.RayCluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test-raycluster",
+			Namespace: "default",
+		},
+	}
+
+	fakeClient := clientFake.NewClientBuilder().
+		WithScheme(newScheme).
+		WithRuntimeObjects(rayJob, rayCluster).
+		WithStatusSubresource(rayJob).Build()
+	ctx := context.Background()
+
+	rayJobReconciler := &RayJobReconciler{
+		Client:   fakeClient,
+		Recorder: &record.FakeRecorder{},
+		Scheme:   newScheme,
+		Log:      ctrl.Log.WithName("controllers").WithName("RayJob"),
+	}
+	namespacedName := types.NamespacedName{Namespace: rayJob.Namespace, Name: rayJob.Name}
+	_, err := rayJobReconciler.Reconcile(ctx, ctrl.Request{NamespacedName: namespacedName})
+	assert.NoError(t, err)
+
+	err = fakeClient.Get(ctx, namespacedName, rayJob)
+	assert.NoError(t, err)
+	assert.Equal(t, rayv1.JobDeploymentStatusFailed, rayJob.Status.JobDeploymentStatus)
+	assert.Equal(t, rayv1.DeadlineExceeded, rayJob.Status.Reason)
+	assert.NotNil(t, rayJob.Status.EndTime)
+
+	// The RayCluster of the failed RayJob is deleted because of `ShutdownAfterJobFinishes`.
+	_, err = rayJobReconciler.Reconcile(ctx, ctrl.Request{NamespacedName: namespacedName})
+	assert.NoError(t, err)
+	err = fakeClient.Get(ctx, types.NamespacedName{Namespace: rayCluster.Namespace, Name: rayCluster.Name}, rayCluster)
+	assert.True(t, errors.IsNotFound(err), "The RayCluster should be deleted")
+}
+
+func TestGetRequeueDuration(t *testing.T) {
+	now := time.Now()
+	requeue := RequeueOptions{Interval: 3 * time.Second, SteadyStateInterval: 10 * time.Second}
+	tests := map[string]struct {
+		jobDeploymentStatus   rayv1.JobDeploymentStatus
+		jobStatus             rayv1.JobStatus
+		startTime             *metav1.Time
+		activeDeadlineSeconds *int32
+		expectedDuration      time.Duration
+	}{
+		"RayJob is initializing": {
+			jobDeploymentStatus: rayv1.JobDeploymentStatusInitializing,
+			jobStatus:           rayv1.JobStatusNew,
+			expectedDuration:    requeue.Interval,
+		},
+		"Ray job is pending": {
+			jobDeploymentStatus: rayv1.JobDeploymentStatusRunning,
+			jobStatus:           rayv1.JobStatusPending,
+			expectedDuration:    requeue.Interval,
+		},
+		"Ray job is running": {
+			jobDeploymentStatus: rayv1.JobDeploymentStatusRunning,
+			jobStatus:           rayv1.JobStatusRunning,
+			startTime:           &metav1.Time{Time: now},
+			expectedDuration:    requeue.SteadyStateInterval,
+		},
+		"Ray job reaches its deadline before the steady state interval": {
+			jobDeploymentStatus:   rayv1.JobDeploymentStatusRunning,
+			jobStatus:             rayv1.JobStatusRunning,
+			startTime:             &metav1.Time{Time: now.Add(-55 * time.Second)},
+			activeDeadlineSeconds: pointer.Int32(60),
+			expectedDuration:      5 * time.Second,
+		},
+		"Ray job is about to reach its deadline": {
+			jobDeploymentStatus:   rayv1.JobDeploymentStatusRunning,
+			jobStatus:             rayv1.JobStatusRunning,
+			startTime:             &metav1.Time{Time: now.Add(-59 * time.Second)},
+			activeDeadlineSeconds: pointer.Int32(60),
+			expectedDuration:      requeue.Interval,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			rayJob := &rayv1.RayJob{
+				Spec: rayv1.RayJobSpec{
+					ActiveDeadlineSeconds: tc.activeDeadlineSeconds,
+				},
+				Status: rayv1.RayJobStatus{
+					JobDeploymentStatus: tc.jobDeploymentStatus,
+					JobStatus:           tc.jobStatus,
+					StartTime:           tc.startTime,
+				},
+			}
+			assert.Equal(t, tc.expectedDuration, getRequeueDuration(rayJob, requeue, now))
+		})
+	}
 }
@@ -3,10 +3,11 @@ package ray
 import (
 	"context"
 	"fmt"
-	"os"
 	"reflect"
+	"sort"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	"k8s.io/apimachinery/pkg/util/json"
@@ -15,6 +16,7 @@ import (
 	networkingv1 "k8s.io/api/networking/v1"
 
 	"github.com/ray-project/kuberay/ray-operator/controllers/ray/common"
+	"github.com/ray-project/kuberay/ray-operator/controllers/ray/metrics"
 
 	cmap "github.com/orcaman/concurrent-map/v2"
 
@@ -22,13 +24,16 @@ import (
 	fmtErrors "github.com/pkg/errors"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/tools/record"
 	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
 	"sigs.k8s.io/controller-runtime/pkg/manager"
 	"sigs.k8s.io/controller-runtime/pkg/predicate"
 
 	"github.com/ray-project/kuberay/ray-operator/controllers/ray/utils"
+	"github.com/ray-project/kuberay/ray-operator/pkg/features"
 
 	"k8s.io/apimachinery/pkg/runtime"
 	ctrl "sigs.k8s.io/controller-runtime"
@@ -38,9 +43,12 @@ import (
 )
 
 const (
-	ServiceDefaultRequeueDuration   = 2 * time.Second
+	// ServiceDefaultRequeueDuration is the default interval for requeueing a RayService which is not in a steady state.
+	ServiceDefaultRequeueDuration = 2 * time.Second
+	// RayClusterDeletionDelayDuration is the default delay before deleting a RayCluster which no longer serves traffic.
 	RayClusterDeletionDelayDuration = 60 * time.Second
-	ENABLE_ZERO_DOWNTIME            = "ENABLE_ZERO_DOWNTIME"
+	// Deprecated: Use the `ZeroDowntimeUpgrade` feature gate instead.
+	ENABLE_ZERO_DOWNTIME = "ENABLE_ZERO_DOWNTIME"
 )
 
 // RayServiceReconciler reconciles a RayService object
@@ -56,10 +64,51 @@ type RayServiceReconciler struct {
 
 	dashboardClientFunc func() utils.RayDashboardClientInterface
 	httpProxyClientFunc func() utils.RayHttpProxyClientInterface
+
+	errorBackoff *errorBackoff
+
+	// optionsLock guards the options below, which can be updated by UpdateOptions while reconciling.
+	optionsLock             sync.RWMutex
+	featureGates            *features.FeatureGates
+	requeue                 RequeueOptions
+	rayClusterDeletionDelay time.Duration
+}
+
+type RayServiceReconcilerOptions struct {
+	FeatureGates *features.FeatureGates
+	// Requeue.Interval defaults to ServiceDefaultRequeueDuration, and Requeue.SteadyStateInterval is used for a healthy
+	// RayService without a pending RayCluster and defaults to ServiceSteadyStateRequeueDuration.
+	Requeue RequeueOptions
+	// RayClusterDeletionDelay defaults to RayClusterDeletionDelayDuration.
+	RayClusterDeletionDelay time.Duration
+}
+
+// UpdateOptions replaces the options of the reconciler, e.g. when the operator Configuration is reloaded.
+// Reconciliations that are already in progress keep using the previous options.
+func (r *RayServiceReconciler) UpdateOptions(options RayServiceReconcilerOptions) {
+	r.optionsLock.Lock()
+	defer r.optionsLock.Unlock()
+	r.featureGates = options.FeatureGates
+	r.requeue = options.Requeue
+	r.rayClusterDeletionDelay = options.RayClusterDeletionDelay
+}
+
+func (r *RayServiceReconciler) getOptions() RayServiceReconcilerOptions {
+	r.optionsLock.RLock()
+	defer r.optionsLock.RUnlock()
+	options := RayServiceReconcilerOptions{
+		FeatureGates:            r.featureGates,
+		Requeue:                 r.requeue.withDefaults(ServiceDefaultRequeueDuration, ServiceSteadyStateRequeueDuration),
+		RayClusterDeletionDelay: r.rayClusterDeletionDelay,
+	}
+	if options.RayClusterDeletionDelay <= 0 {
+		options.RayClusterDeletionDelay = RayClusterDeletionDelayDuration
+	}
+	return options
 }
 
 // NewRayServiceReconciler returns a new reconcile.Reconciler
-func NewRayServiceReconciler(mgr manager.Manager, dashboardClientFunc func() utils.RayDashboardClientInterface, httpProxyClientFunc func() utils.RayHttpProxyClientInterface) *RayServiceReconciler {
+func NewRayServiceReconciler(mgr manager.Manager, dashboardClientFunc func() utils.RayDashboardClientInterface, httpProxyClientFunc func() utils.RayHttpProxyClientInterface, options RayServiceReconcilerOptions) *RayServiceReconciler {
 	return &RayServiceReconciler{
 		Client:                       mgr.GetClient(),
 		Scheme:                       mgr.GetScheme(),
@@ -70,6 +119,11 @@ func NewRayServiceReconciler(mgr manager.Manager, dashboardClientFunc func() uti
 
 		dashboardClientFunc: dashboardClientFunc,
 		httpProxyClientFunc: httpProxyClientFunc,
+		errorBackoff:        newErrorBackoff(options.Requeue.withDefaults(ServiceDefaultRequeueDuration, ServiceSteadyStateRequeueDuration)),
+
+		featureGates:            options.FeatureGates,
+		requeue:                 options.Requeue,
+		rayClusterDeletionDelay: options.RayClusterDeletionDelay,
 	}
 }
 
@@ -108,15 +162,26 @@ func (r *RayServiceReconciler) Reconcile(ctx context.Context, request ctrl.Reque
 
 	var rayServiceInstance *rayv1.RayService
 	var err error
-	var ctrlResult ctrl.Result
 
 	// Resolve the CR from request.
 	if rayServiceInstance, err = r.getRayServiceInstance(ctx, request); err != nil {
+		if errors.IsNotFound(err) {
+			metrics.DeleteService(request.Namespace, request.Name)
+		}
 		return ctrl.Result{}, client.IgnoreNotFound(err)
 	}
 	originalRayServiceInstance := rayServiceInstance.DeepCopy()
+	defer recordRayServiceMetrics(rayServiceInstance)
 	r.cleanUpServeConfigCache(rayServiceInstance)
 
+	// The operator-wide feature gates can be overridden for a single RayService with the `ray.io/feature-gates` annotation.
+	options := r.getOptions()
+	featureGates, err := options.FeatureGates.WithOverrides(rayServiceInstance.Annotations[utils.RayFeatureGatesAnnotationKey])
+	if err != nil {
+		r.Recorder.Eventf(rayServiceInstance, corev1.EventTypeWarning, "InvalidFeatureGates", "Ignoring the %s annotation: %v", utils.RayFeatureGatesAnnotationKey, err)
+	}
+	ctx = features.IntoContext(ctx, featureGates)
+
 	// TODO (kevin85421): ObservedGeneration should be used to determine whether to update this CR or not.
 	rayServiceInstance.Status.ObservedGeneration = rayServiceInstance.ObjectMeta.Generation
 
@@ -126,18 +191,19 @@ func (r *RayServiceReconciler) Reconcile(ctx context.Context, request ctrl.Reque
 	var pendingRayClusterInstance *rayv1.RayCluster
 	if activeRayClusterInstance, pendingRayClusterInstance, err = r.reconcileRayCluster(ctx, rayServiceInstance); err != nil {
 		err = r.updateState(ctx, rayServiceInstance, rayv1.FailedToGetOrCreateRayCluster, err)
-		return ctrl.Result{RequeueAfter: ServiceDefaultRequeueDuration}, client.IgnoreNotFound(err)
+		return ctrl.Result{RequeueAfter: options.Requeue.Interval}, client.IgnoreNotFound(err)
 	}
 
 	// Check if we need to create pending RayCluster.
 	if rayServiceInstance.Status.PendingServiceStatus.RayClusterName != "" && pendingRayClusterInstance == nil {
 		// Update RayService Status since reconcileRayCluster may mark RayCluster restart.
+		setRayServiceConditions(rayServiceInstance)
 		if errStatus := r.Status().Update(ctx, rayServiceInstance); errStatus != nil {
 			logger.Error(errStatus, "Fail to update status of RayService after RayCluster changes", "rayServiceInstance", rayServiceInstance)
-			return ctrl.Result{RequeueAfter: ServiceDefaultRequeueDuration}, nil
+			return ctrl.Result{RequeueAfter: r.errorBackoff.next(request, options.Requeue.Interval)}, nil
 		}
 		logger.Info("Done reconcileRayCluster update status, enter next loop to create new ray cluster.")
-		return ctrl.Result{RequeueAfter: ServiceDefaultRequeueDuration}, nil
+		return ctrl.Result{RequeueAfter: options.Requeue.Interval}, nil
 	}
 
 	/*
@@ -149,9 +215,9 @@ func (r *RayServiceReconciler) Reconcile(ctx context.Context, request ctrl.Reque
 	if activeRayClusterInstance != nil && pendingRayClusterInstance == nil {
 		logger.Info("Reconciling the Serve component. Only the active Ray cluster exists.")
 		rayServiceInstance.Status.PendingServiceStatus = rayv1.RayServiceStatus{}
-		if ctrlResult, isReady, err = r.reconcileServe(ctx, rayServiceInstance, activeRayClusterInstance, true, logger); err != nil {
+		if _, isReady, err = r.reconcileServe(ctx, rayServiceInstance, activeRayClusterInstance, true, logger); err != nil {
 			logger.Error(err, "Fail to reconcileServe.")
-			return ctrlResult, nil
+			return ctrl.Result{RequeueAfter: r.errorBackoff.next(request, options.Requeue.Interval)}, nil
 		}
 	} else if activeRayClusterInstance != nil && pendingRayClusterInstance != nil {
 		logger.Info("Reconciling the Serve component. Active and pending Ray clusters exist.")
@@ -160,15 +226,15 @@ func (r *RayServiceReconciler) Reconcile(ctx context.Context, request ctrl.Reque
 			logger.Error(err, "Failed to update active Ray cluster's status.")
 		}
 
-		if ctrlResult, isReady, err = r.reconcileServe(ctx, rayServiceInstance, pendingRayClusterInstance, false, logger); err != nil {
+		if _, isReady, err = r.reconcileServe(ctx, rayServiceInstance, pendingRayClusterInstance, false, logger); err != nil {
 			logger.Error(err, "Fail to reconcileServe.")
-			return ctrlResult, nil
+			return ctrl.Result{RequeueAfter: r.errorBackoff.next(request, options.Requeue.Interval)}, nil
 		}
 	} else if activeRayClusterInstance == nil && pendingRayClusterInstance != nil {
 		rayServiceInstance.Status.ActiveServiceStatus = rayv1.RayServiceStatus{}
-		if ctrlResult, isReady, err = r.reconcileServe(ctx, rayServiceInstance, pendingRayClusterInstance, false, logger); err != nil {
+		if _, isReady, err = r.reconcileServe(ctx, rayServiceInstance, pendingRayClusterInstance, false, logger); err != nil {
 			logger.Error(err, "Fail to reconcileServe.")
-			return ctrlResult, nil
+			return ctrl.Result{RequeueAfter: r.errorBackoff.next(request, options.Requeue.Interval)}, nil
 		}
 	} else {
 		logger.Info("Reconciling the Serve component. No Ray cluster exists.")
@@ -176,10 +242,13 @@ func (r *RayServiceReconciler) Reconcile(ctx context.Context, request ctrl.Reque
 		rayServiceInstance.Status.PendingServiceStatus = rayv1.RayServiceStatus{}
 	}
 
+	// The Serve component was reconciled without errors, so the next error starts the backoff over.
+	r.errorBackoff.reset(request)
+
 	if !isReady {
-		logger.Info(fmt.Sprintf("Ray Serve applications are not ready to serve requests: checking again in %ss", ServiceDefaultRequeueDuration))
-		r.Recorder.Eventf(rayServiceInstance, "Normal", "ServiceNotReady", "The service is not ready yet. Controller will perform a round of actions in %s.", ServiceDefaultRequeueDuration)
-		return ctrl.Result{RequeueAfter: ServiceDefaultRequeueDuration}, nil
+		logger.Info(fmt.Sprintf("Ray Serve applications are not ready to serve requests: checking again in %s", options.Requeue.Interval))
+		r.Recorder.Eventf(rayServiceInstance, "Normal", "ServiceNotReady", "The service is not ready yet. Controller will perform a round of actions in %s.", options.Requeue.Interval)
+		return ctrl.Result{RequeueAfter: options.Requeue.Interval}, nil
 	}
 
 	// Get the ready Ray cluster instance for service and ingress update.
@@ -200,28 +269,34 @@ func (r *RayServiceReconciler) Reconcile(ctx context.Context, request ctrl.Reque
 	if rayClusterInstance != nil {
 		if err := r.reconcileServices(ctx, rayServiceInstance, rayClusterInstance, utils.HeadService); err != nil {
 			err = r.updateState(ctx, rayServiceInstance, rayv1.FailedToUpdateService, err)
-			return ctrl.Result{RequeueAfter: ServiceDefaultRequeueDuration}, err
+			return ctrl.Result{RequeueAfter: options.Requeue.Interval}, err
 		}
 		if err := r.labelHeadPodForServeStatus(ctx, rayClusterInstance); err != nil {
 			err = r.updateState(ctx, rayServiceInstance, rayv1.FailedToUpdateServingPodLabel, err)
-			return ctrl.Result{RequeueAfter: ServiceDefaultRequeueDuration}, err
+			return ctrl.Result{RequeueAfter: options.Requeue.Interval}, err
 		}
 		if err := r.reconcileServices(ctx, rayServiceInstance, rayClusterInstance, utils.ServingService); err != nil {
 			err = r.updateState(ctx, rayServiceInstance, rayv1.FailedToUpdateService, err)
-			return ctrl.Result{RequeueAfter: ServiceDefaultRequeueDuration}, err
+			return ctrl.Result{RequeueAfter: options.Requeue.Interval}, err
 		}
 	}
 
 	// Final status update for any CR modification.
+	setRayServiceConditions(rayServiceInstance)
 	if r.inconsistentRayServiceStatuses(originalRayServiceInstance.Status, rayServiceInstance.Status) {
 		rayServiceInstance.Status.LastUpdateTime = &metav1.Time{Time: time.Now()}
 		if errStatus := r.Status().Update(ctx, rayServiceInstance); errStatus != nil {
 			logger.Error(errStatus, "Failed to update RayService status", "rayServiceInstance", rayServiceInstance)
-			return ctrl.Result{RequeueAfter: ServiceDefaultRequeueDuration}, errStatus
+			return ctrl.Result{RequeueAfter: options.Requeue.Interval}, errStatus
 		}
 	}
 
-	return ctrl.Result{RequeueAfter: ServiceDefaultRequeueDuration}, nil
+	// Poll the Ray Serve applications less frequently once the RayService is healthy and has no RayCluster to
+	// upgrade to. Changes to the RayService and the RayClusters it owns trigger a reconciliation anyway.
+	if activeRayClusterInstance != nil && pendingRayClusterInstance == nil {
+		return ctrl.Result{RequeueAfter: options.Requeue.SteadyStateInterval}, nil
+	}
+	return ctrl.Result{RequeueAfter: options.Requeue.Interval}, nil
 }
 
 // Checks whether the old and new RayServiceStatus are inconsistent by comparing different fields.
@@ -295,9 +370,76 @@ func (r *RayServiceReconciler) inconsistentRayServiceStatuses(oldStatus rayv1.Ra
 		return true
 	}
 
+	if !reflect.DeepEqual(oldStatus.Conditions, newStatus.Conditions) {
+		r.Log.Info("inconsistentRayServiceStatus RayService Conditions changed")
+		return true
+	}
+
 	return false
 }
 
+// setRayServiceConditions derives `rayServiceInstance.Status.Conditions` from the other fields of the RayService status.
+func setRayServiceConditions(rayServiceInstance *rayv1.RayService) {
+	status := &rayServiceInstance.Status
+	generation := rayServiceInstance.Generation
+
+	readyCondition := metav1.Condition{
+		Type:               string(rayv1.RayServiceReady),
+		Status:             metav1.ConditionFalse,
+		Reason:             string(status.ServiceStatus),
+		ObservedGeneration: generation,
+	}
+	if status.ServiceStatus == rayv1.Running {
+		readyCondition.Status = metav1.ConditionTrue
+		readyCondition.Message = "The Serve applications are running and the RayService is serving traffic"
+	}
+	if readyCondition.Reason == "" {
+		readyCondition.Reason = "Initializing"
+	}
+	meta.SetStatusCondition(&status.Conditions, readyCondition)
+
+	serveAppsHealthyCondition := metav1.Condition{
+		Type:               string(rayv1.ServeAppsHealthy),
+		Status:             metav1.ConditionTrue,
+		Reason:             "AllServeAppsRunning",
+		Message:            "All Serve applications on the active RayCluster are RUNNING",
+		ObservedGeneration: generation,
+	}
+	if status.ActiveServiceStatus.RayClusterName == "" {
+		serveAppsHealthyCondition.Status = metav1.ConditionFalse
+		serveAppsHealthyCondition.Reason = "NoActiveRayCluster"
+		serveAppsHealthyCondition.Message = "The RayService does not have an active RayCluster yet"
+	} else {
+		unhealthyApps := []string{}
+		for appName, app := range status.ActiveServiceStatus.Applications {
+			if app.Status != rayv1.ApplicationStatusEnum.RUNNING {
+				unhealthyApps = append(unhealthyApps, appName)
+			}
+		}
+		if len(unhealthyApps) != 0 {
+			sort.Strings(unhealthyApps)
+			serveAppsHealthyCondition.Status = metav1.ConditionFalse
+			serveAppsHealthyCondition.Reason = "ServeAppsNotRunning"
+			serveAppsHealthyCondition.Message = fmt.Sprintf("Serve applications not RUNNING: %s", strings.Join(unhealthyApps, ", "))
+		}
+	}
+	meta.SetStatusCondition(&status.Conditions, serveAppsHealthyCondition)
+
+	upgradeInProgressCondition := metav1.Condition{
+		Type:               string(rayv1.UpgradeInProgress),
+		Status:             metav1.ConditionFalse,
+		Reason:             "NoPendingRayCluster",
+		ObservedGeneration: generation,
+	}
+	if status.ActiveServiceStatus.RayClusterName != "" && status.PendingServiceStatus.RayClusterName != "" {
+		upgradeInProgressCondition.Status = metav1.ConditionTrue
+		upgradeInProgressCondition.Reason = "PendingRayClusterPreparing"
+		upgradeInProgressCondition.Message = fmt.Sprintf("The pending RayCluster %s is being prepared to replace the active RayCluster %s",
+			status.PendingServiceStatus.RayClusterName, status.ActiveServiceStatus.RayClusterName)
+	}
+	meta.SetStatusCondition(&status.Conditions, upgradeInProgressCondition)
+}
+
 // SetupWithManager sets up the controller with the Manager.
 func (r *RayServiceReconciler) SetupWithManager(mgr ctrl.Manager) error {
 	return ctrl.NewControllerManagedBy(mgr).
@@ -309,6 +451,7 @@ func (r *RayServiceReconciler) SetupWithManager(mgr ctrl.Manager) error {
 		Owns(&rayv1.RayCluster{}).
 		Owns(&corev1.Service{}).
 		Owns(&networkingv1.Ingress{}).
+		WithOptions(controller.Options{RateLimiter: newErrorRateLimiter(r.getOptions().Requeue)}).
 		Complete(r)
 }
 
@@ -327,6 +470,7 @@ func (r *RayServiceReconciler) getRayServiceInstance(ctx context.Context, reques
 
 func (r *RayServiceReconciler) updateState(ctx context.Context, rayServiceInstance *rayv1.RayService, status rayv1.ServiceStatus, err error) error {
 	rayServiceInstance.Status.ServiceStatus = status
+	setRayServiceConditions(rayServiceInstance)
 	if errStatus := r.Status().Update(ctx, rayServiceInstance); errStatus != nil {
 		return fmtErrors.Errorf("combined error: %v %v", err, errStatus)
 	}
@@ -358,17 +502,14 @@ func (r *RayServiceReconciler) reconcileRayCluster(ctx context.Context, rayServi
 	clusterAction := r.shouldPrepareNewRayCluster(rayServiceInstance, activeRayCluster)
 	if clusterAction == RolloutNew {
 		// For LLM serving, some users might not have sufficient GPU resources to run two RayClusters simultaneously.
-		// Therefore, KubeRay offers ENABLE_ZERO_DOWNTIME as a feature flag for zero-downtime upgrades.
-		enableZeroDowntime := true
-		if s := os.Getenv(ENABLE_ZERO_DOWNTIME); strings.ToLower(s) == "false" {
-			enableZeroDowntime = false
-		}
+		// Therefore, KubeRay offers the ZeroDowntimeUpgrade feature gate for zero-downtime upgrades.
+		enableZeroDowntime := features.Enabled(ctx, features.ZeroDowntimeUpgrade)
 		if enableZeroDowntime || !enableZeroDowntime && activeRayCluster == nil {
 			// Add a pending cluster name. In the next reconcile loop, shouldPrepareNewRayCluster will return DoNothing and we will
 			// actually create the pending RayCluster instance.
 			r.markRestartAndAddPendingClusterName(rayServiceInstance)
 		} else {
-			r.Log.Info("Zero-downtime upgrade is disabled (ENABLE_ZERO_DOWNTIME: false). Skip preparing a new RayCluster.")
+			r.Log.Info("Zero-downtime upgrade is disabled (ZeroDowntimeUpgrade: false). Skip preparing a new RayCluster.")
 		}
 		return activeRayCluster, nil, nil
 	} else if clusterAction == Update {
@@ -409,7 +550,7 @@ func (r *RayServiceReconciler) cleanUpRayClusterInstance(ctx context.Context, ra
 		if rayClusterInstance.Name != rayServiceInstance.Status.ActiveServiceStatus.RayClusterName && rayClusterInstance.Name != rayServiceInstance.Status.PendingServiceStatus.RayClusterName {
 			cachedTimestamp, exists := r.RayClusterDeletionTimestamps.Get(rayClusterInstance.Name)
 			if !exists {
-				deletionTimestamp := metav1.Now().Add(RayClusterDeletionDelayDuration)
+				deletionTimestamp := metav1.Now().Add(r.getOptions().RayClusterDeletionDelay)
 				r.RayClusterDeletionTimestamps.Set(rayClusterInstance.Name, deletionTimestamp)
 				r.Log.V(1).Info(fmt.Sprintf("Scheduled dangling RayCluster "+
 					"%s for deletion at %s", rayClusterInstance.Name, deletionTimestamp))
@@ -886,6 +1027,16 @@ func (r *RayServiceReconciler) markRestartAndAddPendingClusterName(rayServiceIns
 	}
 }
 
+// recordRayServiceMetrics records the status of a RayService and the statuses of the Serve applications of its
+// active RayCluster.
+func recordRayServiceMetrics(rayServiceInstance *rayv1.RayService) {
+	applicationStatuses := make([]string, 0, len(rayServiceInstance.Status.ActiveServiceStatus.Applications))
+	for _, application := range rayServiceInstance.Status.ActiveServiceStatus.Applications {
+		applicationStatuses = append(applicationStatuses, application.Status)
+	}
+	metrics.SetServiceStatus(rayServiceInstance.Namespace, rayServiceInstance.Name, string(rayServiceInstance.Status.ServiceStatus), applicationStatuses)
+}
+
 func (r *RayServiceReconciler) updateRayClusterInfo(rayServiceInstance *rayv1.RayService, healthyClusterName string) {
 	r.Log.V(1).Info("updateRayClusterInfo", "ActiveRayClusterName", rayServiceInstance.Status.ActiveServiceStatus.RayClusterName, "healthyClusterName", healthyClusterName)
 	if rayServiceInstance.Status.ActiveServiceStatus.RayClusterName != healthyClusterName {
@@ -990,6 +1141,7 @@ func (r *RayServiceReconciler) updateStatusForActiveCluster(ctx context.Context,
 // Reconciles the Serve applications on the RayCluster. Returns (ctrl.Result, isReady, error).
 // The `isReady` flag indicates whether the RayCluster is ready to handle incoming traffic.
 func (r *RayServiceReconciler) reconcileServe(ctx context.Context, rayServiceInstance *rayv1.RayService, rayClusterInstance *rayv1.RayCluster, isActive bool, logger logr.Logger) (ctrl.Result, bool, error) {
+	requeueInterval := r.getOptions().Requeue.Interval
 	rayServiceInstance.Status.ActiveServiceStatus.RayClusterStatus = rayClusterInstance.Status
 	var err error
 	var clientURL string
@@ -1015,13 +1167,13 @@ func (r *RayServiceReconciler) reconcileServe(ctx context.Context, rayServiceIns
 		} else {
 			logger.Info("Skipping the update of Serve deployments because the Ray head Pod is not ready.")
 		}
-		return ctrl.Result{RequeueAfter: ServiceDefaultRequeueDuration}, false, err
+		return ctrl.Result{RequeueAfter: requeueInterval}, false, err
 	}
 
 	// TODO(architkulkarni): Check the RayVersion. If < 2.8.0, error.
 
 	if clientURL, err = utils.FetchHeadServiceURL(ctx, r.Client, rayClusterInstance, utils.DashboardPortName); err != nil || clientURL == "" {
-		return ctrl.Result{RequeueAfter: ServiceDefaultRequeueDuration}, false, err
+		return ctrl.Result{RequeueAfter: requeueInterval}, false, err
 	}
 	rayDashboardClient := r.dashboardClientFunc()
 	rayDashboardClient.InitClient(clientURL)
@@ -1030,7 +1182,7 @@ func (r *RayServiceReconciler) reconcileServe(ctx context.Context, rayServiceIns
 	if shouldUpdate {
 		if err = r.updateServeDeployment(ctx, rayServiceInstance, rayDashboardClient, rayClusterInstance.Name); err != nil {
 			err = r.updateState(ctx, rayServiceInstance, rayv1.WaitForServeDeploymentReady, err)
-			return ctrl.Result{RequeueAfter: ServiceDefaultRequeueDuration}, false, err
+			return ctrl.Result{RequeueAfter: requeueInterval}, false, err
 		}
 
 		r.Recorder.Eventf(rayServiceInstance, "Normal", "SubmittedServeDeployment",
@@ -1040,24 +1192,29 @@ func (r *RayServiceReconciler) reconcileServe(ctx context.Context, rayServiceIns
 	var isReady bool
 	if isReady, err = r.getAndCheckServeStatus(ctx, rayDashboardClient, rayServiceStatus); err != nil {
 		err = r.updateState(ctx, rayServiceInstance, rayv1.FailedToGetServeDeploymentStatus, err)
-		return ctrl.Result{RequeueAfter: ServiceDefaultRequeueDuration}, false, err
+		return ctrl.Result{RequeueAfter: requeueInterval}, false, err
 	}
 
 	logger.Info("Check serve health", "isReady", isReady, "isActive", isActive)
 
 	if isReady {
 		rayServiceInstance.Status.ServiceStatus = rayv1.Running
+		// The pending RayCluster of an upgrade is promoted. The first RayCluster of a RayService is not an upgrade.
+		if activeClusterName := rayServiceInstance.Status.ActiveServiceStatus.RayClusterName; activeClusterName != "" && activeClusterName != rayClusterInstance.Name {
+			metrics.ObserveServiceUpgradeDuration(rayServiceInstance.Namespace, time.Since(rayClusterInstance.CreationTimestamp.Time))
+		}
 		r.updateRayClusterInfo(rayServiceInstance, rayClusterInstance.Name)
 		r.Recorder.Event(rayServiceInstance, "Normal", "Running", "The Serve applicaton is now running and healthy.")
 	} else {
 		rayServiceInstance.Status.ServiceStatus = rayv1.WaitForServeDeploymentReady
+		setRayServiceConditions(rayServiceInstance)
 		if err := r.Status().Update(ctx, rayServiceInstance); err != nil {
-			return ctrl.Result{RequeueAfter: ServiceDefaultRequeueDuration}, false, err
+			return ctrl.Result{RequeueAfter: requeueInterval}, false, err
 		}
 		logger.Info("Mark cluster as waiting for Serve deployments", "rayCluster", rayClusterInstance)
 	}
 
-	return ctrl.Result{RequeueAfter: ServiceDefaultRequeueDuration}, isReady, nil
+	return ctrl.Result{RequeueAfter: requeueInterval}, isReady, nil
 }
 
 func (r *RayServiceReconciler) labelHeadPodForServeStatus(ctx context.Context, rayClusterInstance *rayv1.RayCluster) error {
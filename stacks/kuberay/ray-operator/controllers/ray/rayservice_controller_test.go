@@ -18,7 +18,6 @@ package ray
 import (
 	"context"
 	"fmt"
-	"os"
 	"time"
 
 	"github.com/ray-project/kuberay/ray-operator/controllers/ray/utils"
@@ -317,10 +316,7 @@ var _ = Context("Inside the default namespace", func() {
 		})
 
 		It("Disable zero-downtime upgrade", func() {
-			// Disable zero-downtime upgrade.
-			os.Setenv("ENABLE_ZERO_DOWNTIME", "false")
-
-			// Try to trigger a zero-downtime upgrade.
+			// Try to trigger a zero-downtime upgrade while disabling it for the RayService.
 			oldRayVersion := myRayService.Spec.RayClusterSpec.RayVersion
 			newRayVersion := "2.198.0"
 			Expect(oldRayVersion).ShouldNot(Equal(newRayVersion))
@@ -328,6 +324,10 @@ var _ = Context("Inside the default namespace", func() {
 				Eventually(
 					getResourceFunc(ctx, client.ObjectKey{Name: myRayService.Name, Namespace: "default"}, myRayService),
 					time.Second*3, time.Millisecond*500).Should(BeNil(), "My myRayService  = %v", myRayService.Name)
+				if myRayService.Annotations == nil {
+					myRayService.Annotations = map[string]string{}
+				}
+				myRayService.Annotations[utils.RayFeatureGatesAnnotationKey] = "ZeroDowntimeUpgrade=false"
 				myRayService.Spec.RayClusterSpec.RayVersion = newRayVersion
 				return k8sClient.Update(ctx, myRayService)
 			})
@@ -338,19 +338,18 @@ var _ = Context("Inside the default namespace", func() {
 				getPreparingRayClusterNameFunc(ctx, myRayService),
 				time.Second*5, time.Millisecond*500).Should(BeEmpty(), "Pending RayCluster name  = %v", myRayService.Status.PendingServiceStatus.RayClusterName)
 
-			// Set the RayVersion back to the old value to avoid triggering the zero-downtime upgrade.
+			// Set the RayVersion back to the old value to avoid triggering the zero-downtime upgrade, and enable
+			// zero-downtime upgrade again.
 			err = retry.RetryOnConflict(retry.DefaultRetry, func() error {
 				Eventually(
 					getResourceFunc(ctx, client.ObjectKey{Name: myRayService.Name, Namespace: "default"}, myRayService),
 					time.Second*3, time.Millisecond*500).Should(BeNil(), "My myRayService  = %v", myRayService.Name)
+				delete(myRayService.Annotations, utils.RayFeatureGatesAnnotationKey)
 				myRayService.Spec.RayClusterSpec.RayVersion = oldRayVersion
 				return k8sClient.Update(ctx, myRayService)
 			})
 			Expect(err).NotTo(HaveOccurred(), "failed to update test RayService resource")
 
-			// Enable zero-downtime upgrade again.
-			os.Unsetenv("ENABLE_ZERO_DOWNTIME")
-
 			// Zero-downtime upgrade should not be triggered.
 			Consistently(
 				getPreparingRayClusterNameFunc(ctx, myRayService),
@@ -3,7 +3,6 @@ package ray
 import (
 	"context"
 	"fmt"
-	"os"
 	"reflect"
 	"strconv"
 	"testing"
@@ -13,8 +12,10 @@ import (
 	rayv1 "github.com/ray-project/kuberay/ray-operator/apis/ray/v1"
 	"github.com/ray-project/kuberay/ray-operator/controllers/ray/utils"
 	"github.com/ray-project/kuberay/ray-operator/pkg/client/clientset/versioned/scheme"
+	"github.com/ray-project/kuberay/ray-operator/pkg/features"
 	"github.com/stretchr/testify/assert"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/client-go/tools/record"
@@ -208,6 +209,48 @@ func TestInconsistentRayServiceStatuses(t *testing.T) {
 	// Test 2: Test RayServiceStatus
 	newStatus = oldStatus.DeepCopy()
 	assert.False(t, r.inconsistentRayServiceStatuses(oldStatus, *newStatus))
+
+	// Test 3: Update Conditions only.
+	newStatus = oldStatus.DeepCopy()
+	newStatus.Conditions = []metav1.Condition{{Type: string(rayv1.RayServiceReady), Status: metav1.ConditionFalse, Reason: "Restarting"}}
+	assert.True(t, r.inconsistentRayServiceStatuses(oldStatus, *newStatus))
+}
+
+func TestSetRayServiceConditions(t *testing.T) {
+	rayService := &rayv1.RayService{
+		ObjectMeta: metav1.ObjectMeta{Generation: 2},
+	}
+
+	// No active RayCluster yet.
+	setRayServiceConditions(rayService)
+	assert.True(t, meta.IsStatusConditionFalse(rayService.Status.Conditions, string(rayv1.RayServiceReady)))
+	assert.Equal(t, "NoActiveRayCluster", meta.FindStatusCondition(rayService.Status.Conditions, string(rayv1.ServeAppsHealthy)).Reason)
+	assert.True(t, meta.IsStatusConditionFalse(rayService.Status.Conditions, string(rayv1.UpgradeInProgress)))
+
+	// The active RayCluster is serving traffic and a pending RayCluster is being prepared.
+	rayService.Status.ServiceStatus = rayv1.Running
+	rayService.Status.ActiveServiceStatus = rayv1.RayServiceStatus{
+		RayClusterName: "active-cluster",
+		Applications: map[string]rayv1.AppStatus{
+			"app-1": {Status: rayv1.ApplicationStatusEnum.RUNNING},
+			"app-2": {Status: rayv1.ApplicationStatusEnum.RUNNING},
+		},
+	}
+	rayService.Status.PendingServiceStatus.RayClusterName = "pending-cluster"
+	setRayServiceConditions(rayService)
+	assert.True(t, meta.IsStatusConditionTrue(rayService.Status.Conditions, string(rayv1.RayServiceReady)))
+	assert.True(t, meta.IsStatusConditionTrue(rayService.Status.Conditions, string(rayv1.ServeAppsHealthy)))
+	assert.True(t, meta.IsStatusConditionTrue(rayService.Status.Conditions, string(rayv1.UpgradeInProgress)))
+	assert.Equal(t, int64(2), meta.FindStatusCondition(rayService.Status.Conditions, string(rayv1.UpgradeInProgress)).ObservedGeneration)
+
+	// A Serve application becomes unhealthy and the upgrade is done.
+	rayService.Status.ActiveServiceStatus.Applications["app-2"] = rayv1.AppStatus{Status: rayv1.ApplicationStatusEnum.UNHEALTHY}
+	rayService.Status.PendingServiceStatus.RayClusterName = ""
+	setRayServiceConditions(rayService)
+	serveAppsHealthy := meta.FindStatusCondition(rayService.Status.Conditions, string(rayv1.ServeAppsHealthy))
+	assert.Equal(t, metav1.ConditionFalse, serveAppsHealthy.Status)
+	assert.Contains(t, serveAppsHealthy.Message, "app-2")
+	assert.True(t, meta.IsStatusConditionFalse(rayService.Status.Conditions, string(rayv1.UpgradeInProgress)))
 }
 
 func TestInconsistentRayServiceStatus(t *testing.T) {
@@ -715,7 +758,6 @@ applications:
 }
 
 func TestReconcileRayCluster(t *testing.T) {
-	defer os.Unsetenv(ENABLE_ZERO_DOWNTIME)
 	// Create a new scheme with CRDs schemes.
 	newScheme := runtime.NewScheme()
 	_ = rayv1.AddToScheme(newScheme)
@@ -789,10 +831,8 @@ func TestReconcileRayCluster(t *testing.T) {
 	for name, tc := range tests {
 		t.Run(name, func(t *testing.T) {
 			// Enable or disable zero-downtime upgrade.
-			defer os.Unsetenv(ENABLE_ZERO_DOWNTIME)
-			if !tc.enableZeroDowntime {
-				os.Setenv(ENABLE_ZERO_DOWNTIME, "false")
-			}
+			featureGates, err := features.NewFeatureGates(map[string]bool{string(features.ZeroDowntimeUpgrade): tc.enableZeroDowntime})
+			assert.Nil(t, err)
 			runtimeObjects := []runtime.Object{}
 			if tc.activeCluster != nil {
 				runtimeObjects = append(runtimeObjects, tc.activeCluster.DeepCopy())
@@ -810,7 +850,7 @@ func TestReconcileRayCluster(t *testing.T) {
 				service.Status.ActiveServiceStatus.RayClusterName = tc.activeCluster.Name
 			}
 			assert.Equal(t, "", service.Status.PendingServiceStatus.RayClusterName)
-			_, _, err = r.reconcileRayCluster(ctx, service)
+			_, _, err = r.reconcileRayCluster(features.IntoContext(ctx, featureGates), service)
 			assert.Nil(t, err)
 
 			// If KubeRay operator is preparing a new cluster, the `PendingServiceStatus.RayClusterName` should be set by calling the function `markRestart`.
@@ -0,0 +1,88 @@
+package ray
+
+import (
+	"time"
+
+	"golang.org/x/time/rate"
+	"k8s.io/client-go/util/workqueue"
+	ctrl "sigs.k8s.io/controller-runtime"
+)
+
+const (
+	// ServiceSteadyStateRequeueDuration is the default interval for checking the Ray Serve applications of a healthy RayService.
+	ServiceSteadyStateRequeueDuration = 30 * time.Second
+	// RayJobSteadyStateRequeueDuration is the default interval for checking the status of a running Ray job.
+	RayJobSteadyStateRequeueDuration = 10 * time.Second
+
+	// The requeue delay after a failed reconciliation starts at DefaultErrorBackoffBase and doubles with every
+	// consecutive failure of the same custom resource, up to DefaultErrorBackoffMax.
+	DefaultErrorBackoffBase = 1 * time.Second
+	DefaultErrorBackoffMax  = 5 * time.Minute
+)
+
+// RequeueOptions configures when a reconciler requeues a custom resource. Zero values use the defaults of the reconciler.
+type RequeueOptions struct {
+	// Interval is the requeue interval of a custom resource which is not in a steady state yet.
+	Interval time.Duration
+	// SteadyStateInterval is the requeue interval of a healthy custom resource in a steady state.
+	SteadyStateInterval time.Duration
+	// ErrorBackoffBase and ErrorBackoffMax bound the exponential backoff of a custom resource whose reconciliation
+	// fails. They are only read when the reconciler is created.
+	ErrorBackoffBase time.Duration
+	ErrorBackoffMax  time.Duration
+}
+
+// withDefaults returns the options with the zero values replaced by the given defaults.
+func (o RequeueOptions) withDefaults(interval time.Duration, steadyStateInterval time.Duration) RequeueOptions {
+	if o.Interval <= 0 {
+		o.Interval = interval
+	}
+	if o.SteadyStateInterval <= 0 {
+		o.SteadyStateInterval = steadyStateInterval
+	}
+	if o.ErrorBackoffBase <= 0 {
+		o.ErrorBackoffBase = DefaultErrorBackoffBase
+	}
+	if o.ErrorBackoffMax <= 0 {
+		o.ErrorBackoffMax = DefaultErrorBackoffMax
+	}
+	if o.ErrorBackoffMax < o.ErrorBackoffBase {
+		o.ErrorBackoffMax = o.ErrorBackoffBase
+	}
+	return o
+}
+
+// newErrorRateLimiter returns the rate limiter of the requests of a controller. A request whose reconciliation returns
+// an error is requeued with per-object exponential backoff, and the overall rate is limited like the default rate
+// limiter of controller-runtime.
+func newErrorRateLimiter(options RequeueOptions) workqueue.RateLimiter {
+	return workqueue.NewMaxOfRateLimiter(
+		workqueue.NewItemExponentialFailureRateLimiter(options.ErrorBackoffBase, options.ErrorBackoffMax),
+		&workqueue.BucketRateLimiter{Limiter: rate.NewLimiter(rate.Limit(10), 100)},
+	)
+}
+
+// errorBackoff requeues custom resources with per-object exponential backoff on the error paths which requeue
+// without returning an error to controller-runtime. A nil *errorBackoff requeues after the given interval.
+type errorBackoff struct {
+	limiter workqueue.RateLimiter
+}
+
+func newErrorBackoff(options RequeueOptions) *errorBackoff {
+	return &errorBackoff{limiter: workqueue.NewItemExponentialFailureRateLimiter(options.ErrorBackoffBase, options.ErrorBackoffMax)}
+}
+
+// next records a failed reconciliation of the request and returns the delay before it should be retried.
+func (b *errorBackoff) next(request ctrl.Request, interval time.Duration) time.Duration {
+	if b == nil {
+		return interval
+	}
+	return b.limiter.When(request.NamespacedName)
+}
+
+// reset forgets the failed reconciliations of the request after it was reconciled successfully.
+func (b *errorBackoff) reset(request ctrl.Request) {
+	if b != nil {
+		b.limiter.Forget(request.NamespacedName)
+	}
+}
@@ -0,0 +1,52 @@
+package ray
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+)
+
+func TestRequeueOptionsWithDefaults(t *testing.T) {
+	options := RequeueOptions{}.withDefaults(2*time.Second, 30*time.Second)
+	assert.Equal(t, RequeueOptions{
+		Interval:            2 * time.Second,
+		SteadyStateInterval: 30 * time.Second,
+		ErrorBackoffBase:    DefaultErrorBackoffBase,
+		ErrorBackoffMax:     DefaultErrorBackoffMax,
+	}, options)
+
+	options = RequeueOptions{
+		Interval:            5 * time.Second,
+		SteadyStateInterval: time.Minute,
+		ErrorBackoffBase:    10 * time.Minute,
+	}.withDefaults(2*time.Second, 30*time.Second)
+	assert.Equal(t, 5*time.Second, options.Interval)
+	assert.Equal(t, time.Minute, options.SteadyStateInterval)
+	// The maximum backoff is never less than the base backoff.
+	assert.Equal(t, 10*time.Minute, options.ErrorBackoffBase)
+	assert.Equal(t, 10*time.Minute, options.ErrorBackoffMax)
+}
+
+func TestErrorBackoff(t *testing.T) {
+	backoff := newErrorBackoff(RequeueOptions{ErrorBackoffBase: time.Second, ErrorBackoffMax: 5 * time.Second})
+	request := ctrl.Request{NamespacedName: types.NamespacedName{Namespace: "default", Name: "rayservice-sample"}}
+	otherRequest := ctrl.Request{NamespacedName: types.NamespacedName{Namespace: "default", Name: "other"}}
+
+	// The backoff doubles with every consecutive failure of the same object, up to the maximum.
+	for _, expected := range []time.Duration{time.Second, 2 * time.Second, 4 * time.Second, 5 * time.Second} {
+		assert.Equal(t, expected, backoff.next(request, 2*time.Second))
+	}
+	// The failures of other objects are counted separately.
+	assert.Equal(t, time.Second, backoff.next(otherRequest, 2*time.Second))
+
+	backoff.reset(request)
+	assert.Equal(t, time.Second, backoff.next(request, 2*time.Second))
+
+	// A nil *errorBackoff requeues after the interval.
+	var noBackoff *errorBackoff
+	assert.Equal(t, 2*time.Second, noBackoff.next(request, 2*time.Second))
+	noBackoff.reset(request)
+}
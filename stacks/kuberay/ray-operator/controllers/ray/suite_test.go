@@ -111,11 +111,16 @@ var _ = BeforeSuite(func(ctx SpecContext) {
 		return fakeRayDashboardClient
 	}, func() utils.RayHttpProxyClientInterface {
 		return fakeRayHttpProxyClient
+	}, RayServiceReconcilerOptions{
+		// The fake Ray dashboard client does not trigger reconciliations, so poll healthy RayServices as often as the others.
+		Requeue: RequeueOptions{SteadyStateInterval: ServiceDefaultRequeueDuration},
 	}).SetupWithManager(mgr)
 	Expect(err).NotTo(HaveOccurred(), "failed to setup RayService controller")
 
 	err = NewRayJobReconciler(mgr, func() utils.RayDashboardClientInterface {
 		return fakeRayDashboardClient
+	}, RayJobReconcilerOptions{
+		Requeue: RequeueOptions{SteadyStateInterval: RayJobDefaultRequeueDuration},
 	}).SetupWithManager(mgr)
 	Expect(err).NotTo(HaveOccurred(), "failed to setup RayJob controller")
 
@@ -1,5 +1,7 @@
 package utils
 
+import rayv1 "github.com/ray-project/kuberay/ray-operator/apis/ray/v1"
+
 const (
 
 	// Default application name
@@ -23,8 +25,26 @@ const (
 	HashWithoutReplicasAndWorkersToDeleteKey = "ray.io/hash-without-replicas-and-workers-to-delete"
 	NumWorkerGroupsKey                       = "ray.io/num-worker-groups"
 
+	// RayHeadGroupName is the value of the `ray.io/group` label of head Pods.
+	RayHeadGroupName = "headgroup"
+
+	// RayPodTemplateHashAnnotationKey stores the hash of the head or worker group template and rayStartParams
+	// that a Pod was created from. It is used to detect outdated Pods when the templates change.
+	RayPodTemplateHashAnnotationKey = "ray.io/pod-template-hash"
+
+	// A replica of a multi-host worker group (`NumOfHosts` > 1) consists of `NumOfHosts` Pods which are created and
+	// deleted together. All the Pods of a replica share the same replica index, and each Pod has a unique host index
+	// in the range [0, NumOfHosts).
+	RayWorkerReplicaIndexLabelKey = "ray.io/worker-group-replica-index"
+	RayHostIndexLabelKey          = "ray.io/replica-host-index"
+
+	// A failed Pod retained for debugging has its `ray.io/cluster` label replaced with this label, so it is no longer
+	// selected as a member of the RayCluster. The time when the Pod was retained is stored in the annotation.
+	RayRetainedFromClusterLabelKey = "ray.io/retained-from-cluster"
+	RayRetainedAtAnnotationKey     = "ray.io/retained-at"
+
 	// In KubeRay, the Ray container must be the first application container in a head or worker Pod.
-	RayContainerIndex = 0
+	RayContainerIndex = rayv1.RayContainerIndex
 
 	// Batch scheduling labels
 	// TODO(tgaddair): consider making these part of the CRD
@@ -32,8 +52,8 @@ const (
 	RayPriorityClassName = "ray.io/priority-class-name"
 
 	// Ray GCS FT related annotations
-	RayFTEnabledAnnotationKey         = "ray.io/ft-enabled"
-	RayExternalStorageNSAnnotationKey = "ray.io/external-storage-namespace"
+	RayFTEnabledAnnotationKey         = rayv1.RayFTEnabledAnnotationKey
+	RayExternalStorageNSAnnotationKey = rayv1.RayExternalStorageNSAnnotationKey
 
 	// If this annotation is set to "true", the KubeRay operator will not modify the container's command.
 	// However, the generated `ray start` command will still be stored in the container's environment variable
@@ -48,6 +68,19 @@ const (
 	EnableServeServiceKey  = "ray.io/enable-serve-service"
 	EnableServeServiceTrue = "true"
 
+	// If this annotation is set to "true" on a worker Pod, the KubeRay operator will not pick the Pod when it scales
+	// down a worker group on its own. The Pod is still deleted when it is listed in `WorkersToDelete`, e.g. by the Ray
+	// autoscaler, or when it is unhealthy.
+	RayDoNotEvictAnnotationKey = "ray.io/do-not-evict"
+
+	// If this annotation is set to "true" on a RayCluster, the KubeRay operator queries the Ray dashboard for idle worker
+	// nodes, which have neither running tasks nor alive actors, and prefers them when it scales down a worker group.
+	RayIdleAwareScaleDownAnnotationKey = "ray.io/idle-aware-scale-down"
+
+	// This annotation overrides the operator-wide feature gates for a single custom resource. Its value is a
+	// comma-separated list of `Feature=bool` pairs, e.g. "RandomPodDelete=true,ZeroDowntimeUpgrade=false".
+	RayFeatureGatesAnnotationKey = "ray.io/feature-gates"
+
 	EnableRayClusterServingServiceTrue  = "true"
 	EnableRayClusterServingServiceFalse = "false"
 
@@ -58,22 +91,22 @@ const (
 	DashSymbol = "-"
 
 	// Use as default port
-	DefaultClientPort = 10001
+	DefaultClientPort = rayv1.DefaultClientPort
 	// For Ray >= 1.11.0, "DefaultRedisPort" actually refers to the GCS server port.
 	// However, the role of this port is unchanged in Ray APIs like ray.init and ray start.
 	// This is the port used by Ray workers and drivers inside the Ray cluster to connect to the Ray head.
-	DefaultRedisPort                = 6379
-	DefaultDashboardPort            = 8265
-	DefaultMetricsPort              = 8080
+	DefaultRedisPort                = rayv1.DefaultRedisPort
+	DefaultDashboardPort            = rayv1.DefaultDashboardPort
+	DefaultMetricsPort              = rayv1.DefaultMetricsPort
 	DefaultDashboardAgentListenPort = 52365
-	DefaultServingPort              = 8000
+	DefaultServingPort              = rayv1.DefaultServingPort
 
-	ClientPortName               = "client"
-	RedisPortName                = "redis"
-	DashboardPortName            = "dashboard"
-	MetricsPortName              = "metrics"
+	ClientPortName               = rayv1.ClientPortName
+	RedisPortName                = rayv1.RedisPortName
+	DashboardPortName            = rayv1.DashboardPortName
+	MetricsPortName              = rayv1.MetricsPortName
 	DashboardAgentListenPortName = "dashboard-agent"
-	ServingPortName              = "serve"
+	ServingPortName              = rayv1.ServingPortName
 
 	// The default AppProtocol for Kubernetes service
 	DefaultServiceAppProtocol = "tcp"
@@ -102,6 +135,12 @@ const (
 	RAYCLUSTER_DEFAULT_REQUEUE_SECONDS      = 300
 	KUBERAY_GEN_RAY_START_CMD               = "KUBERAY_GEN_RAY_START_CMD"
 
+	// Environment variables for the Pods of multi-host worker groups. RAY_HOST_INDEX is the rank of the host
+	// within its replica.
+	RAY_WORKER_REPLICA_INDEX = "RAY_WORKER_REPLICA_INDEX"
+	RAY_HOST_INDEX           = "RAY_HOST_INDEX"
+	RAY_NUM_HOSTS            = "RAY_NUM_HOSTS"
+
 	// Environment variables for RayJob submitter Kubernetes Job.
 	// Example: ray job submit --address=http://$RAY_DASHBOARD_ADDRESS --submission-id=$RAY_JOB_SUBMISSION_ID ...
 	RAY_DASHBOARD_ADDRESS = "RAY_DASHBOARD_ADDRESS"
@@ -115,16 +154,19 @@ const (
 	// deletion should be enabled. Note that this only takes effect when autoscaling
 	// is enabled for the RayCluster. This is a feature flag for v0.6.0, and will be
 	// removed if the default behavior is stable enoguh.
+	// Deprecated: Use the `RandomPodDelete` feature gate instead.
 	ENABLE_RANDOM_POD_DELETE = "ENABLE_RANDOM_POD_DELETE"
 
 	// This KubeRay operator environment variable is used to determine if the Redis
 	// cleanup Job should be enabled. This is a feature flag for v1.0.0.
+	// Deprecated: Use the `GCSFaultToleranceRedisCleanup` feature gate instead.
 	ENABLE_GCS_FT_REDIS_CLEANUP = "ENABLE_GCS_FT_REDIS_CLEANUP"
 
 	// This environment variable for the KubeRay operator is used to determine whether to enable
 	// the injection of readiness and liveness probes into Ray head and worker containers.
 	// Enabling this feature contributes to the robustness of Ray clusters. It is currently a feature
 	// flag for v1.1.0 and will be removed if the behavior proves to be stable enough.
+	// Deprecated: Use the `ProbesInjection` feature gate instead.
 	ENABLE_PROBES_INJECTION = "ENABLE_PROBES_INJECTION"
 
 	// Ray core default configurations
@@ -6,10 +6,9 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"net/url"
 	"time"
 
-	"k8s.io/apimachinery/pkg/util/yaml"
-
 	fmtErrors "github.com/pkg/errors"
 	corev1 "k8s.io/api/core/v1"
 	ctrl "sigs.k8s.io/controller-runtime"
@@ -20,6 +19,7 @@ import (
 	"k8s.io/apimachinery/pkg/util/json"
 
 	rayv1 "github.com/ray-project/kuberay/ray-operator/apis/ray/v1"
+	"github.com/ray-project/kuberay/ray-operator/controllers/ray/metrics"
 )
 
 var (
@@ -28,6 +28,10 @@ var (
 	DeployPathV2     = "/api/serve/applications/"
 	// Job URL paths
 	JobPath = "/api/jobs/"
+	// State API URL paths
+	NodesPath  = "/api/v0/nodes"
+	TasksPath  = "/api/v0/tasks"
+	ActorsPath = "/api/v0/actors"
 )
 
 type RayDashboardClientInterface interface {
@@ -43,6 +47,9 @@ type RayDashboardClientInterface interface {
 	GetJobLog(ctx context.Context, jobName string) (*string, error)
 	StopJob(ctx context.Context, jobName string) error
 	DeleteJob(ctx context.Context, jobName string) error
+	// State API
+	ListIdleNodeIPs(ctx context.Context) ([]string, error)
+	IsClusterIdle(ctx context.Context) (bool, error)
 }
 
 type BaseDashboardClient struct {
@@ -103,7 +110,8 @@ func FetchHeadServiceURL(ctx context.Context, cli client.Client, rayCluster *ray
 
 func (r *RayDashboardClient) InitClient(url string) {
 	r.client = http.Client{
-		Timeout: 120 * time.Second,
+		Timeout:   120 * time.Second,
+		Transport: metrics.InstrumentDashboardTransport(nil),
 	}
 	r.dashboardURL = "http://" + url
 }
@@ -418,6 +426,114 @@ func (r *RayDashboardClient) DeleteJob(ctx context.Context, jobName string) erro
 	return nil
 }
 
+// RayNodeState is the subset of a node returned by the Ray state API that KubeRay uses.
+type RayNodeState struct {
+	NodeID     string `json:"node_id"`
+	NodeIP     string `json:"node_ip"`
+	State      string `json:"state"`
+	IsHeadNode bool   `json:"is_head_node"`
+}
+
+// rayNodeResource is any resource returned by the Ray state API that is placed on a node, such as a task or an actor.
+type rayNodeResource struct {
+	NodeID string `json:"node_id"`
+}
+
+type rayStateAPIResponse struct {
+	Result bool   `json:"result"`
+	Msg    string `json:"msg"`
+	Data   struct {
+		Result struct {
+			Result interface{} `json:"result"`
+		} `json:"result"`
+	} `json:"data"`
+}
+
+// ListIdleNodeIPs returns the IPs of the alive worker nodes that have neither running tasks nor alive actors.
+func (r *RayDashboardClient) ListIdleNodeIPs(ctx context.Context) ([]string, error) {
+	var nodes []RayNodeState
+	if err := r.listStateResources(ctx, NodesPath, "state", "ALIVE", &nodes); err != nil {
+		return nil, err
+	}
+	busyNodes := make(map[string]bool)
+	for _, resource := range []struct{ path, state string }{{TasksPath, "RUNNING"}, {ActorsPath, "ALIVE"}} {
+		var items []rayNodeResource
+		if err := r.listStateResources(ctx, resource.path, "state", resource.state, &items); err != nil {
+			return nil, err
+		}
+		for _, item := range items {
+			busyNodes[item.NodeID] = true
+		}
+	}
+
+	idleNodeIPs := []string{}
+	for _, node := range nodes {
+		if !node.IsHeadNode && !busyNodes[node.NodeID] {
+			idleNodeIPs = append(idleNodeIPs, node.NodeIP)
+		}
+	}
+	return idleNodeIPs, nil
+}
+
+// IsClusterIdle returns whether the Ray cluster has neither pending or running Ray jobs nor running Ray tasks.
+func (r *RayDashboardClient) IsClusterIdle(ctx context.Context) (bool, error) {
+	jobs, err := r.ListJobs(ctx)
+	if err != nil {
+		return false, err
+	}
+	if jobs != nil {
+		for _, job := range *jobs {
+			if job.JobStatus == rayv1.JobStatusPending || job.JobStatus == rayv1.JobStatusRunning {
+				return false, nil
+			}
+		}
+	}
+
+	var tasks []rayNodeResource
+	if err := r.listStateResources(ctx, TasksPath, "state", "RUNNING", &tasks); err != nil {
+		return false, err
+	}
+	return len(tasks) == 0, nil
+}
+
+// listStateResources lists the resources of a Ray state API path whose `filterKey` equals `filterValue`.
+func (r *RayDashboardClient) listStateResources(ctx context.Context, path string, filterKey string, filterValue string, result interface{}) error {
+	query := url.Values{}
+	query.Set("filter_keys", filterKey)
+	query.Set("filter_predicates", "=")
+	query.Set("filter_values", filterValue)
+	query.Set("limit", "10000")
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, r.dashboardURL+path+"?"+query.Encode(), nil)
+	if err != nil {
+		return err
+	}
+
+	resp, err := r.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("listStateResources fail: %s %s", resp.Status, string(body))
+	}
+
+	// The resources are decoded into `result` directly.
+	var stateResp rayStateAPIResponse
+	stateResp.Data.Result.Result = result
+	if err = json.Unmarshal(body, &stateResp); err != nil {
+		return fmt.Errorf("listStateResources fail: %s", string(body))
+	}
+	if !stateResp.Result {
+		return fmt.Errorf("listStateResources fail: %s", stateResp.Msg)
+	}
+	return nil
+}
+
 func ConvertRayJobToReq(rayJob *rayv1.RayJob) (*RayJobRequest, error) {
 	req := &RayJobRequest{
 		Entrypoint:   rayJob.Spec.Entrypoint,
@@ -436,10 +552,9 @@ func ConvertRayJobToReq(rayJob *rayv1.RayJob) (*RayJobRequest, error) {
 }
 
 func UnmarshalRuntimeEnvYAML(runtimeEnvYAML string) (RuntimeEnvType, error) {
-	var runtimeEnv RuntimeEnvType
-	err := yaml.Unmarshal([]byte(runtimeEnvYAML), &runtimeEnv)
+	runtimeEnv, err := rayv1.UnmarshalRuntimeEnvYAML(runtimeEnvYAML)
 	if err != nil {
-		return nil, fmt.Errorf("failed to unmarshal RuntimeEnvYAML: %v: %v", runtimeEnvYAML, err)
+		return nil, err
 	}
 	return runtimeEnv, nil
 }
@@ -146,4 +146,29 @@ var _ = Describe("RayFrameworkGenerator", func() {
 		err := rayDashboardClient.StopJob(context.TODO(), "stop-job-1")
 		Expect(err).To(BeNil())
 	})
+
+	It("Test list idle nodes", func() {
+		httpmock.Activate()
+		defer httpmock.DeactivateAndReset()
+		stateResponse := func(result string) httpmock.Responder {
+			return httpmock.NewStringResponder(200, `{"result": true, "msg": "", "data": {"result": {"total": 3, "result": `+result+`}}}`)
+		}
+		httpmock.RegisterResponder("GET", rayDashboardClient.dashboardURL+NodesPath, stateResponse(`[
+			{"node_id": "head", "node_ip": "10.0.0.1", "state": "ALIVE", "is_head_node": true},
+			{"node_id": "busy-task", "node_ip": "10.0.0.2", "state": "ALIVE", "is_head_node": false},
+			{"node_id": "busy-actor", "node_ip": "10.0.0.3", "state": "ALIVE", "is_head_node": false},
+			{"node_id": "idle", "node_ip": "10.0.0.4", "state": "ALIVE", "is_head_node": false}
+		]`))
+		httpmock.RegisterResponder("GET", rayDashboardClient.dashboardURL+TasksPath, stateResponse(`[{"task_id": "task", "node_id": "busy-task"}]`))
+		httpmock.RegisterResponder("GET", rayDashboardClient.dashboardURL+ActorsPath, stateResponse(`[{"actor_id": "actor", "node_id": "busy-actor"}]`))
+
+		idleNodeIPs, err := rayDashboardClient.ListIdleNodeIPs(context.TODO())
+		Expect(err).To(BeNil())
+		Expect(idleNodeIPs).To(Equal([]string{"10.0.0.4"}))
+
+		httpmock.RegisterResponder("GET", rayDashboardClient.dashboardURL+ActorsPath,
+			httpmock.NewStringResponder(200, `{"result": false, "msg": "state API is unavailable", "data": {}}`))
+		_, err = rayDashboardClient.ListIdleNodeIPs(context.TODO())
+		Expect(err).To(MatchError(ContainSubstring("state API is unavailable")))
+	})
 })
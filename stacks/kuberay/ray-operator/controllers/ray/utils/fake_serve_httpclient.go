@@ -13,6 +13,8 @@ type FakeRayDashboardClient struct {
 	BaseDashboardClient
 	multiAppStatuses map[string]*ServeApplicationStatus
 	serveDetails     ServeDetails
+	idleNodeIPs      []string
+	clusterIdle      bool
 
 	GetJobInfoMock atomic.Pointer[func(context.Context, string) (*RayJobInfo, error)]
 }
@@ -79,3 +81,19 @@ func (r *FakeRayDashboardClient) StopJob(_ context.Context, jobName string) (err
 func (r *FakeRayDashboardClient) DeleteJob(_ context.Context, jobName string) error {
 	return nil
 }
+
+func (r *FakeRayDashboardClient) ListIdleNodeIPs(_ context.Context) ([]string, error) {
+	return r.idleNodeIPs, nil
+}
+
+func (r *FakeRayDashboardClient) SetIdleNodeIPs(idleNodeIPs []string) {
+	r.idleNodeIPs = idleNodeIPs
+}
+
+func (r *FakeRayDashboardClient) IsClusterIdle(_ context.Context) (bool, error) {
+	return r.clusterIdle, nil
+}
+
+func (r *FakeRayDashboardClient) SetClusterIdle(clusterIdle bool) {
+	r.clusterIdle = clusterIdle
+}
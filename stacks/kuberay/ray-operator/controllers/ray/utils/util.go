@@ -240,6 +240,15 @@ func GetWorkerGroupDesiredReplicas(ctx context.Context, workerGroupSpec rayv1.Wo
 	return workerReplicas
 }
 
+// GetWorkerGroupNumOfHosts returns the number of Pods in each replica of the worker group.
+// `NumOfHosts` defaults to 1 in the CRD, and values smaller than 1 are treated as 1.
+func GetWorkerGroupNumOfHosts(workerGroupSpec rayv1.WorkerGroupSpec) int32 {
+	if workerGroupSpec.NumOfHosts < 1 {
+		return 1
+	}
+	return workerGroupSpec.NumOfHosts
+}
+
 // CalculateDesiredReplicas calculate desired worker replicas at the cluster level
 func CalculateDesiredReplicas(ctx context.Context, cluster *rayv1.RayCluster) int32 {
 	count := int32(0)
@@ -250,6 +259,27 @@ func CalculateDesiredReplicas(ctx context.Context, cluster *rayv1.RayCluster) in
 	return count
 }
 
+// CalculateDesiredWorkerPods calculates desired worker Pods at the cluster level. Each replica
+// of a multi-host worker group consists of `NumOfHosts` Pods.
+func CalculateDesiredWorkerPods(ctx context.Context, cluster *rayv1.RayCluster) int32 {
+	count := int32(0)
+	for _, nodeGroup := range cluster.Spec.WorkerGroupSpecs {
+		count += GetWorkerGroupDesiredReplicas(ctx, nodeGroup) * GetWorkerGroupNumOfHosts(nodeGroup)
+	}
+
+	return count
+}
+
+// CalculateMinWorkerPods calculates min worker Pods at the cluster level.
+func CalculateMinWorkerPods(cluster *rayv1.RayCluster) int32 {
+	count := int32(0)
+	for _, nodeGroup := range cluster.Spec.WorkerGroupSpecs {
+		count += *nodeGroup.MinReplicas * GetWorkerGroupNumOfHosts(nodeGroup)
+	}
+
+	return count
+}
+
 // CalculateMinReplicas calculates min worker replicas at the cluster level
 func CalculateMinReplicas(cluster *rayv1.RayCluster) int32 {
 	count := int32(0)
@@ -271,14 +301,65 @@ func CalculateMaxReplicas(cluster *rayv1.RayCluster) int32 {
 }
 
 // CalculateAvailableReplicas calculates available worker replicas at the cluster level
-// A worker is available if its Pod is running
-func CalculateAvailableReplicas(pods corev1.PodList) int32 {
+// A worker is available if its Pod is running. A replica of a multi-host worker group is
+// available if the Pods of all its hosts are running.
+func CalculateAvailableReplicas(cluster *rayv1.RayCluster, pods corev1.PodList) int32 {
+	return countWorkerReplicas(cluster, pods, func(pod *corev1.Pod) bool {
+		return pod.Status.Phase == corev1.PodRunning
+	})
+}
+
+// CalculateUpdatedReplicas calculates the worker replicas whose Pods were all created from the current worker group
+// templates at the cluster level.
+func CalculateUpdatedReplicas(cluster *rayv1.RayCluster, pods corev1.PodList) int32 {
+	templateHashes := make(map[string]string)
+	templates := make(map[string]corev1.PodTemplateSpec)
+	for _, workerGroup := range cluster.Spec.WorkerGroupSpecs {
+		templateHashes[workerGroup.GroupName], _ = GeneratePodTemplateHash(workerGroup.Template, workerGroup.RayStartParams)
+		templates[workerGroup.GroupName] = workerGroup.Template
+	}
+	return countWorkerReplicas(cluster, pods, func(pod *corev1.Pod) bool {
+		groupName := pod.Labels[RayNodeGroupLabelKey]
+		template, ok := templates[groupName]
+		return ok && pod.DeletionTimestamp == nil && !IsPodOutdated(*pod, templateHashes[groupName], template)
+	})
+}
+
+// CalculateReadyReplicas calculates the worker replicas whose Pods are all running and ready at the cluster level.
+func CalculateReadyReplicas(cluster *rayv1.RayCluster, pods corev1.PodList) int32 {
+	return countWorkerReplicas(cluster, pods, IsRunningAndReady)
+}
+
+// countWorkerReplicas counts the worker replicas for which `isCounted` returns true for all the Pods. A replica of a
+// multi-host worker group is only counted once all its `NumOfHosts` Pods, which share the same replica index, are counted.
+func countWorkerReplicas(cluster *rayv1.RayCluster, pods corev1.PodList, isCounted func(pod *corev1.Pod) bool) int32 {
+	numOfHosts := make(map[string]int32)
+	for _, workerGroup := range cluster.Spec.WorkerGroupSpecs {
+		numOfHosts[workerGroup.GroupName] = GetWorkerGroupNumOfHosts(workerGroup)
+	}
+
 	count := int32(0)
-	for _, pod := range pods.Items {
-		if val, ok := pod.Labels["ray.io/node-type"]; !ok || val != string(rayv1.WorkerNode) {
+	countedHosts := make(map[string]int32)
+	for i := range pods.Items {
+		pod := &pods.Items[i]
+		if val, ok := pod.Labels[RayNodeTypeLabelKey]; !ok || val != string(rayv1.WorkerNode) {
+			continue
+		}
+		if !isCounted(pod) {
 			continue
 		}
-		if pod.Status.Phase == corev1.PodRunning {
+		groupName := pod.Labels[RayNodeGroupLabelKey]
+		if numOfHosts[groupName] <= 1 {
+			count++
+			continue
+		}
+		replicaIndex, ok := pod.Labels[RayWorkerReplicaIndexLabelKey]
+		if !ok {
+			continue
+		}
+		replicaKey := groupName + DashSymbol + replicaIndex
+		countedHosts[replicaKey]++
+		if countedHosts[replicaKey] == numOfHosts[groupName] {
 			count++
 		}
 	}
@@ -288,11 +369,11 @@ func CalculateAvailableReplicas(pods corev1.PodList) int32 {
 
 func CalculateDesiredResources(cluster *rayv1.RayCluster) corev1.ResourceList {
 	desiredResourcesList := []corev1.ResourceList{{}}
-	headPodResource := calculatePodResource(cluster.Spec.HeadGroupSpec.Template.Spec)
+	headPodResource := CalculatePodResource(cluster.Spec.HeadGroupSpec.Template.Spec)
 	desiredResourcesList = append(desiredResourcesList, headPodResource)
 	for _, nodeGroup := range cluster.Spec.WorkerGroupSpecs {
-		podResource := calculatePodResource(nodeGroup.Template.Spec)
-		for i := int32(0); i < *nodeGroup.Replicas; i++ {
+		podResource := CalculatePodResource(nodeGroup.Template.Spec)
+		for i := int32(0); i < *nodeGroup.Replicas*GetWorkerGroupNumOfHosts(nodeGroup); i++ {
 			desiredResourcesList = append(desiredResourcesList, podResource)
 		}
 	}
@@ -301,23 +382,27 @@ func CalculateDesiredResources(cluster *rayv1.RayCluster) corev1.ResourceList {
 
 func CalculateMinResources(cluster *rayv1.RayCluster) corev1.ResourceList {
 	minResourcesList := []corev1.ResourceList{{}}
-	headPodResource := calculatePodResource(cluster.Spec.HeadGroupSpec.Template.Spec)
+	headPodResource := CalculatePodResource(cluster.Spec.HeadGroupSpec.Template.Spec)
 	minResourcesList = append(minResourcesList, headPodResource)
 	for _, nodeGroup := range cluster.Spec.WorkerGroupSpecs {
-		podResource := calculatePodResource(nodeGroup.Template.Spec)
-		for i := int32(0); i < *nodeGroup.MinReplicas; i++ {
+		podResource := CalculatePodResource(nodeGroup.Template.Spec)
+		for i := int32(0); i < *nodeGroup.MinReplicas*GetWorkerGroupNumOfHosts(nodeGroup); i++ {
 			minResourcesList = append(minResourcesList, podResource)
 		}
 	}
 	return sumResourceList(minResourcesList)
 }
 
-// calculatePodResource returns the total resources of a pod.
+// CalculatePodResource returns the total resources of a pod.
 // Request values take precedence over limit values.
-func calculatePodResource(podSpec corev1.PodSpec) corev1.ResourceList {
+func CalculatePodResource(podSpec corev1.PodSpec) corev1.ResourceList {
 	podResource := corev1.ResourceList{}
 	for _, container := range podSpec.Containers {
-		containerResource := container.Resources.Requests
+		// Copy the requests, so that the limits are not added to the requests of the Pod spec.
+		containerResource := container.Resources.Requests.DeepCopy()
+		if containerResource == nil {
+			containerResource = corev1.ResourceList{}
+		}
 		for name, quantity := range container.Resources.Limits {
 			if _, ok := containerResource[name]; !ok {
 				containerResource[name] = quantity
@@ -450,6 +535,26 @@ func PodNotMatchingTemplate(pod corev1.Pod, template corev1.PodTemplateSpec) boo
 	return false
 }
 
+// GeneratePodTemplateHash returns the hash of a head or worker group template and its rayStartParams,
+// which is stored in the `RayPodTemplateHashAnnotationKey` annotation of the Pods created from them.
+func GeneratePodTemplateHash(template corev1.PodTemplateSpec, rayStartParams map[string]string) (string, error) {
+	return GenerateJsonHash(struct {
+		Template       corev1.PodTemplateSpec `json:"template"`
+		RayStartParams map[string]string      `json:"rayStartParams,omitempty"`
+	}{template, rayStartParams})
+}
+
+// IsPodOutdated returns whether the Pod was created from an older version of its group's template and rayStartParams.
+// Pods without the `RayPodTemplateHashAnnotationKey` annotation were created by older KubeRay versions, so they are
+// compared against the template with `PodNotMatchingTemplate` instead. Terminating Pods are unavailable and are
+// neither outdated nor updated, so callers should exclude them before calling this function.
+func IsPodOutdated(pod corev1.Pod, templateHash string, template corev1.PodTemplateSpec) bool {
+	if podTemplateHash, ok := pod.Annotations[RayPodTemplateHashAnnotationKey]; ok {
+		return podTemplateHash != templateHash
+	}
+	return PodNotMatchingTemplate(pod, template)
+}
+
 // CompareJsonStruct This is a way to better compare if two objects are the same when they are json/yaml structs. reflect.DeepEqual will fail in some cases.
 func CompareJsonStruct(objA interface{}, objB interface{}) bool {
 	a, err := json.Marshal(objA)
@@ -378,7 +378,7 @@ func TestCalculateAvailableReplicas(t *testing.T) {
 			},
 		},
 	}
-	count := CalculateAvailableReplicas(podList)
+	count := CalculateAvailableReplicas(&rayv1.RayCluster{}, podList)
 	assert.Equal(t, count, int32(1), "expect 1 available replica")
 }
 
@@ -551,6 +551,130 @@ func TestCalculateDesiredReplicas(t *testing.T) {
 	}
 }
 
+func TestCalculateMultiHostReplicas(t *testing.T) {
+	cluster := rayv1.RayCluster{
+		Spec: rayv1.RayClusterSpec{
+			WorkerGroupSpecs: []rayv1.WorkerGroupSpec{
+				{
+					GroupName:   "single-host",
+					Replicas:    pointer.Int32(1),
+					MinReplicas: pointer.Int32(1),
+					MaxReplicas: pointer.Int32(5),
+					NumOfHosts:  1,
+				},
+				{
+					GroupName:   "multi-host",
+					Replicas:    pointer.Int32(2),
+					MinReplicas: pointer.Int32(1),
+					MaxReplicas: pointer.Int32(5),
+					NumOfHosts:  2,
+				},
+			},
+		},
+	}
+	assert.Equal(t, int32(3), CalculateDesiredReplicas(context.Background(), &cluster))
+	assert.Equal(t, int32(5), CalculateDesiredWorkerPods(context.Background(), &cluster))
+	assert.Equal(t, int32(3), CalculateMinWorkerPods(&cluster))
+
+	workerPod := func(groupName string, replicaIndex string, phase corev1.PodPhase) corev1.Pod {
+		labels := map[string]string{
+			RayNodeTypeLabelKey:  string(rayv1.WorkerNode),
+			RayNodeGroupLabelKey: groupName,
+		}
+		if replicaIndex != "" {
+			labels[RayWorkerReplicaIndexLabelKey] = replicaIndex
+		}
+		return corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Labels: labels},
+			Status:     corev1.PodStatus{Phase: phase},
+		}
+	}
+	podList := corev1.PodList{
+		Items: []corev1.Pod{
+			workerPod("single-host", "", corev1.PodRunning),
+			// Both hosts of replica 0 are running.
+			workerPod("multi-host", "0", corev1.PodRunning),
+			workerPod("multi-host", "0", corev1.PodRunning),
+			// Only one host of replica 1 is running.
+			workerPod("multi-host", "1", corev1.PodRunning),
+			workerPod("multi-host", "1", corev1.PodPending),
+		},
+	}
+	assert.Equal(t, int32(2), CalculateAvailableReplicas(&cluster, podList))
+}
+
+func TestCalculateUpdatedReplicas(t *testing.T) {
+	workerGroup := rayv1.WorkerGroupSpec{
+		GroupName: "small-group",
+		Template: corev1.PodTemplateSpec{
+			Spec: corev1.PodSpec{
+				Containers: []corev1.Container{{Name: "ray-worker", Image: "rayproject/ray:2.9.0"}},
+			},
+		},
+	}
+	cluster := rayv1.RayCluster{Spec: rayv1.RayClusterSpec{WorkerGroupSpecs: []rayv1.WorkerGroupSpec{workerGroup}}}
+	templateHash, err := GeneratePodTemplateHash(workerGroup.Template, workerGroup.RayStartParams)
+	assert.Nil(t, err)
+
+	workerPod := func(templateHash string) corev1.Pod {
+		return corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Labels: map[string]string{
+					RayNodeTypeLabelKey:  string(rayv1.WorkerNode),
+					RayNodeGroupLabelKey: workerGroup.GroupName,
+				},
+				Annotations: map[string]string{RayPodTemplateHashAnnotationKey: templateHash},
+			},
+		}
+	}
+	// Terminating Pods are not updated even if they were created from the current template.
+	now := metav1.Now()
+	terminatingPod := workerPod(templateHash)
+	terminatingPod.DeletionTimestamp = &now
+	podList := corev1.PodList{
+		Items: []corev1.Pod{workerPod(templateHash), workerPod("outdated"), terminatingPod},
+	}
+	assert.Equal(t, int32(1), CalculateUpdatedReplicas(&cluster, podList))
+}
+
+func TestGetWorkerGroupNumOfHosts(t *testing.T) {
+	assert.Equal(t, int32(1), GetWorkerGroupNumOfHosts(rayv1.WorkerGroupSpec{}))
+	assert.Equal(t, int32(1), GetWorkerGroupNumOfHosts(rayv1.WorkerGroupSpec{NumOfHosts: 1}))
+	assert.Equal(t, int32(4), GetWorkerGroupNumOfHosts(rayv1.WorkerGroupSpec{NumOfHosts: 4}))
+}
+
+func TestIsPodOutdated(t *testing.T) {
+	template := corev1.PodTemplateSpec{
+		Spec: corev1.PodSpec{
+			Containers: []corev1.Container{{Name: "ray-worker", Image: "rayproject/ray:2.9.0"}},
+		},
+	}
+	rayStartParams := map[string]string{"num-cpus": "1"}
+	templateHash, err := GeneratePodTemplateHash(template, rayStartParams)
+	assert.Nil(t, err)
+
+	// Changing rayStartParams changes the hash.
+	newTemplateHash, err := GeneratePodTemplateHash(template, map[string]string{"num-cpus": "2"})
+	assert.Nil(t, err)
+	assert.NotEqual(t, templateHash, newTemplateHash)
+
+	pod := corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Annotations: map[string]string{RayPodTemplateHashAnnotationKey: templateHash},
+		},
+		Spec:   *template.Spec.DeepCopy(),
+		Status: corev1.PodStatus{Phase: corev1.PodRunning},
+	}
+	assert.False(t, IsPodOutdated(pod, templateHash, template))
+	assert.True(t, IsPodOutdated(pod, newTemplateHash, template))
+
+	// Pods without the annotation are compared against the template.
+	pod.Annotations = nil
+	assert.False(t, IsPodOutdated(pod, newTemplateHash, template))
+	pod.Spec.Containers[0].Image = "rayproject/ray:2.8.0"
+	assert.True(t, IsPodOutdated(pod, templateHash, template))
+}
+
 func TestUnmarshalRuntimeEnv(t *testing.T) {
 	tests := map[string]struct {
 		runtimeEnvYAML string
@@ -4,7 +4,9 @@ import (
 	"flag"
 	"fmt"
 	"os"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/go-logr/zapr"
 	routev1 "github.com/openshift/api/route/v1"
@@ -14,6 +16,7 @@ import (
 	"gopkg.in/natefinch/lumberjack.v2"
 
 	batchv1 "k8s.io/api/batch/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/serializer"
@@ -32,7 +35,9 @@ import (
 	rayv1 "github.com/ray-project/kuberay/ray-operator/apis/ray/v1"
 	"github.com/ray-project/kuberay/ray-operator/controllers/ray"
 	"github.com/ray-project/kuberay/ray-operator/controllers/ray/batchscheduler"
+	"github.com/ray-project/kuberay/ray-operator/controllers/ray/common"
 	"github.com/ray-project/kuberay/ray-operator/controllers/ray/utils"
+	"github.com/ray-project/kuberay/ray-operator/pkg/features"
 	// +kubebuilder:scaffold:imports
 )
 
@@ -64,6 +69,9 @@ func main() {
 	var watchNamespace string
 	var logFile string
 	var configFile string
+	var forcedClusterUpgrade bool
+	var enableBatchScheduler bool
+	var featureGates string
 
 	// TODO: remove flag-based config once Configuration API graduates to v1.
 	flag.BoolVar(&version, "version", false, "Show the version information.")
@@ -79,12 +87,14 @@ func main() {
 		"watch-namespace",
 		"",
 		"Specify a list of namespaces to watch for custom resources, separated by commas. If left empty, all namespaces will be watched.")
-	flag.BoolVar(&ray.ForcedClusterUpgrade, "forced-cluster-upgrade", false,
-		"Forced cluster upgrade flag")
+	flag.BoolVar(&forcedClusterUpgrade, "forced-cluster-upgrade", false,
+		"Deprecated: use --feature-gates=ForcedClusterUpgrade=true. Use Recreate as the upgrade strategy of RayClusters which do not set spec.upgradeStrategy.")
 	flag.StringVar(&logFile, "log-file-path", "",
 		"Synchronize logs to local file")
-	flag.BoolVar(&ray.EnableBatchScheduler, "enable-batch-scheduler", false,
-		"Enable batch scheduler. Currently is volcano, which supports gang scheduler policy.")
+	flag.BoolVar(&enableBatchScheduler, "enable-batch-scheduler", false,
+		"Deprecated: use --feature-gates=BatchScheduler=true. Enable batch scheduler. Currently is volcano, which supports gang scheduler policy, or kueue.")
+	flag.StringVar(&featureGates, "feature-gates", "",
+		"A comma-separated list of Feature=bool pairs, e.g. RandomPodDelete=true,ZeroDowntimeUpgrade=false.")
 	flag.StringVar(&configFile, "config", "", "Path to structured config file. Flags are ignored if config file is set.")
 
 	opts := k8szap.Options{
@@ -101,17 +111,15 @@ func main() {
 	}
 
 	var config configapi.Configuration
+	var configData []byte
 	if configFile != "" {
 		var err error
-		configData, err := os.ReadFile(configFile)
+		configData, err = os.ReadFile(configFile)
 		exitOnError(err, "failed to read config file")
 
 		config, err = decodeConfig(configData, scheme)
 		exitOnError(err, "failed to decode config file")
-
-		// TODO: remove globally-scoped variables
-		ray.ForcedClusterUpgrade = config.ForcedClusterUpgrade
-		ray.EnableBatchScheduler = config.EnableBatchScheduler
+		exitOnError(validateConfig(config), "invalid config file")
 	} else {
 		config.MetricsAddr = metricsAddr
 		config.ProbeAddr = probeAddr
@@ -119,9 +127,12 @@ func main() {
 		config.LeaderElectionNamespace = leaderElectionNamespace
 		config.ReconcileConcurrency = reconcileConcurrency
 		config.WatchNamespace = watchNamespace
-		config.ForcedClusterUpgrade = ray.ForcedClusterUpgrade
+		config.ForcedClusterUpgrade = forcedClusterUpgrade
 		config.LogFile = logFile
-		config.EnableBatchScheduler = ray.EnableBatchScheduler
+		config.EnableBatchScheduler = enableBatchScheduler
+		var err error
+		config.FeatureGates, err = features.ParseFeatureGates(featureGates)
+		exitOnError(err, "invalid --feature-gates flag")
 	}
 
 	if config.LogFile != "" {
@@ -150,12 +161,9 @@ func main() {
 	}
 
 	setupLog.Info("the operator", "version:", os.Getenv("OPERATOR_VERSION"))
-	if ray.ForcedClusterUpgrade {
-		setupLog.Info("Feature flag forced-cluster-upgrade is enabled.")
-	}
-	if ray.EnableBatchScheduler {
-		setupLog.Info("Feature flag enable-batch-scheduler is enabled.")
-	}
+	operatorFeatureGates, err := newFeatureGates(config)
+	exitOnError(err, "invalid feature gates")
+	setupLog.Info("Feature gates", "featureGates", operatorFeatureGates.String())
 
 	// Manager options
 	options := ctrl.Options{
@@ -201,23 +209,51 @@ func main() {
 	mgr, err := ctrl.NewManager(ctrl.GetConfigOrDie(), options)
 	exitOnError(err, "unable to start manager")
 
-	rayClusterOptions := ray.RayClusterReconcilerOptions{
-		HeadSidecarContainers:   config.HeadSidecarContainers,
-		WorkerSidecarContainers: config.WorkerSidecarContainers,
-	}
-	exitOnError(ray.NewReconciler(mgr, rayClusterOptions).SetupWithManager(mgr, config.ReconcileConcurrency),
+	rayClusterReconciler := ray.NewReconciler(mgr, newRayClusterReconcilerOptions(config, operatorFeatureGates))
+	exitOnError(rayClusterReconciler.SetupWithManager(mgr, config.ReconcileConcurrency),
 		"unable to create controller", "controller", "RayCluster")
-	exitOnError(ray.NewRayServiceReconciler(mgr, utils.GetRayDashboardClient, utils.GetRayHttpProxyClient).SetupWithManager(mgr),
+	rayServiceReconciler := ray.NewRayServiceReconciler(mgr, utils.GetRayDashboardClient, utils.GetRayHttpProxyClient,
+		newRayServiceReconcilerOptions(config, operatorFeatureGates))
+	exitOnError(rayServiceReconciler.SetupWithManager(mgr),
 		"unable to create controller", "controller", "RayService")
-	exitOnError(ray.NewRayJobReconciler(mgr, utils.GetRayDashboardClient).SetupWithManager(mgr),
+	rayJobReconciler := ray.NewRayJobReconciler(mgr, utils.GetRayDashboardClient, newRayJobReconcilerOptions(config, operatorFeatureGates))
+	exitOnError(rayJobReconciler.SetupWithManager(mgr),
 		"unable to create controller", "controller", "RayJob")
 
 	if os.Getenv("ENABLE_WEBHOOKS") == "true" {
 		exitOnError((&rayv1.RayCluster{}).SetupWebhookWithManager(mgr),
 			"unable to create webhook", "webhook", "RayCluster")
+		exitOnError((&rayv1.RayJob{}).SetupWebhookWithManager(mgr),
+			"unable to create webhook", "webhook", "RayJob")
+		exitOnError((&rayv1.RayService{}).SetupWebhookWithManager(mgr),
+			"unable to create webhook", "webhook", "RayService")
 	}
 	// +kubebuilder:scaffold:builder
 
+	if configFile != "" {
+		// Apply the reloadable fields of the config file without restarting the operator when it changes.
+		watcher := &configWatcher{
+			path:   configFile,
+			scheme: scheme,
+			log:    ctrl.Log.WithName("config"),
+			apply: func(config configapi.Configuration) error {
+				featureGates, err := newFeatureGates(config)
+				if err != nil {
+					return err
+				}
+				rayClusterReconciler.UpdateOptions(newRayClusterReconcilerOptions(config, featureGates))
+				rayServiceReconciler.UpdateOptions(newRayServiceReconcilerOptions(config, featureGates))
+				rayJobReconciler.UpdateOptions(newRayJobReconcilerOptions(config, featureGates))
+				return nil
+			},
+			recorder:    mgr.GetEventRecorderFor("kuberay-operator"),
+			eventObject: operatorPod(),
+			config:      config,
+			data:        configData,
+		}
+		exitOnError(mgr.Add(watcher), "unable to watch config file")
+	}
+
 	exitOnError(mgr.AddHealthzCheck("healthz", healthz.Ping), "unable to set up health check")
 	exitOnError(mgr.AddReadyzCheck("readyz", healthz.Ping), "unable to set up ready check")
 
@@ -225,6 +261,58 @@ func main() {
 	exitOnError(mgr.Start(ctrl.SetupSignalHandler()), "problem running manager")
 }
 
+func newRayClusterReconcilerOptions(config configapi.Configuration, featureGates *features.FeatureGates) ray.RayClusterReconcilerOptions {
+	requeue := requeueConfiguration(config)
+	return ray.RayClusterReconcilerOptions{
+		HeadSidecarContainers:   config.HeadSidecarContainers,
+		WorkerSidecarContainers: config.WorkerSidecarContainers,
+		PodDefaults:             config.PodDefaults,
+		FeatureGates:            featureGates,
+		Requeue:                 newRequeueOptions(requeue, requeue.RayClusterInterval, requeue.RayClusterSteadyStateInterval),
+	}
+}
+
+func newRayServiceReconcilerOptions(config configapi.Configuration, featureGates *features.FeatureGates) ray.RayServiceReconcilerOptions {
+	requeue := requeueConfiguration(config)
+	return ray.RayServiceReconcilerOptions{
+		FeatureGates:            featureGates,
+		Requeue:                 newRequeueOptions(requeue, requeue.RayServiceInterval, requeue.RayServiceSteadyStateInterval),
+		RayClusterDeletionDelay: durationValue(requeue.RayClusterDeletionDelay),
+	}
+}
+
+func newRayJobReconcilerOptions(config configapi.Configuration, featureGates *features.FeatureGates) ray.RayJobReconcilerOptions {
+	requeue := requeueConfiguration(config)
+	return ray.RayJobReconcilerOptions{
+		FeatureGates: featureGates,
+		Requeue:      newRequeueOptions(requeue, requeue.RayJobInterval, requeue.RayJobSteadyStateInterval),
+	}
+}
+
+func requeueConfiguration(config configapi.Configuration) configapi.RequeueConfiguration {
+	if config.Requeue == nil {
+		return configapi.RequeueConfiguration{}
+	}
+	return *config.Requeue
+}
+
+func newRequeueOptions(requeue configapi.RequeueConfiguration, interval *metav1.Duration, steadyStateInterval *metav1.Duration) ray.RequeueOptions {
+	return ray.RequeueOptions{
+		Interval:            durationValue(interval),
+		SteadyStateInterval: durationValue(steadyStateInterval),
+		ErrorBackoffBase:    durationValue(requeue.ErrorBackoffBase),
+		ErrorBackoffMax:     durationValue(requeue.ErrorBackoffMax),
+	}
+}
+
+// durationValue returns the value of an optional duration, or 0 if it is unset so that the reconcilers use their defaults.
+func durationValue(d *metav1.Duration) time.Duration {
+	if d == nil {
+		return 0
+	}
+	return d.Duration
+}
+
 func cacheSelectors() (map[client.Object]cache.ByObject, error) {
 	label, err := labels.NewRequirement(utils.KubernetesCreatedByLabelKey, selection.Equals, []string{utils.ComponentName})
 	if err != nil {
@@ -244,6 +332,44 @@ func exitOnError(err error, msg string, keysAndValues ...interface{}) {
 	}
 }
 
+// legacyFeatureGateEnvVars maps the deprecated environment variables to the feature gates replacing them.
+var legacyFeatureGateEnvVars = map[string]features.Feature{
+	utils.ENABLE_RANDOM_POD_DELETE:            features.RandomPodDelete,
+	common.EnableInitContainerInjectionEnvKey: features.InitContainerInjection,
+	utils.ENABLE_PROBES_INJECTION:             features.ProbesInjection,
+	utils.ENABLE_GCS_FT_REDIS_CLEANUP:         features.GCSFaultToleranceRedisCleanup,
+	ray.ENABLE_ZERO_DOWNTIME:                  features.ZeroDowntimeUpgrade,
+}
+
+// newFeatureGates returns the operator-wide feature gates. The deprecated environment variables and config fields are
+// applied first, so that `featureGates` takes precedence over them.
+func newFeatureGates(config configapi.Configuration) (*features.FeatureGates, error) {
+	overrides := make(map[string]bool)
+	for envVar, feature := range legacyFeatureGateEnvVars {
+		s := os.Getenv(envVar)
+		if s == "" {
+			continue
+		}
+		enabled, err := strconv.ParseBool(s)
+		if err != nil {
+			setupLog.Info("Ignoring an invalid value of a deprecated environment variable", "environment variable", envVar, "value", s)
+			continue
+		}
+		setupLog.Info("The environment variable is deprecated, use the feature gate instead", "environment variable", envVar, "feature gate", feature)
+		overrides[string(feature)] = enabled
+	}
+	if config.ForcedClusterUpgrade {
+		overrides[string(features.ForcedClusterUpgrade)] = true
+	}
+	if config.EnableBatchScheduler {
+		overrides[string(features.BatchScheduler)] = true
+	}
+	for name, enabled := range config.FeatureGates {
+		overrides[name] = enabled
+	}
+	return features.NewFeatureGates(overrides)
+}
+
 // decodeConfig decodes raw config data and returns the Configuration type.
 func decodeConfig(configData []byte, scheme *runtime.Scheme) (configapi.Configuration, error) {
 	cfg := configapi.Configuration{}
@@ -4,12 +4,15 @@ import (
 	"reflect"
 	"strings"
 	"testing"
+	"time"
 
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/utils/pointer"
 
 	configapi "github.com/ray-project/kuberay/ray-operator/apis/config/v1alpha1"
+	"github.com/ray-project/kuberay/ray-operator/controllers/ray"
+	"github.com/ray-project/kuberay/ray-operator/pkg/features"
 )
 
 func Test_decodeConfig(t *testing.T) {
@@ -97,6 +100,97 @@ workerSidecarContainers:
 			},
 			expectErr: false,
 		},
+		{
+			name: "config with pod defaults",
+			configData: `apiVersion: config.ray.io/v1alpha1
+kind: Configuration
+podDefaults:
+  worker:
+    template:
+      spec:
+        nodeSelector:
+          pool: ray
+    imageRegistry: registry.example.com
+  namespaces:
+  - namespace: team-a
+    head:
+      env:
+      - name: TEAM
+        value: a
+`,
+			expectedConfig: configapi.Configuration{
+				TypeMeta: metav1.TypeMeta{
+					Kind:       "Configuration",
+					APIVersion: "config.ray.io/v1alpha1",
+				},
+				MetricsAddr:          ":8080",
+				ProbeAddr:            ":8082",
+				EnableLeaderElection: pointer.Bool(true),
+				ReconcileConcurrency: 1,
+				PodDefaults: &configapi.PodDefaults{
+					Worker: &configapi.RayPodDefaults{
+						Template: &corev1.PodTemplateSpec{
+							Spec: corev1.PodSpec{NodeSelector: map[string]string{"pool": "ray"}},
+						},
+						ImageRegistry: "registry.example.com",
+					},
+					Namespaces: []configapi.NamespacePodDefaults{
+						{
+							Namespace: "team-a",
+							Head: &configapi.RayPodDefaults{
+								Env: []corev1.EnvVar{{Name: "TEAM", Value: "a"}},
+							},
+						},
+					},
+				},
+			},
+			expectErr: false,
+		},
+		{
+			name: "config with feature gates",
+			configData: `apiVersion: config.ray.io/v1alpha1
+kind: Configuration
+featureGates:
+  RandomPodDelete: true
+  ZeroDowntimeUpgrade: false
+`,
+			expectedConfig: configapi.Configuration{
+				TypeMeta: metav1.TypeMeta{
+					Kind:       "Configuration",
+					APIVersion: "config.ray.io/v1alpha1",
+				},
+				MetricsAddr:          ":8080",
+				ProbeAddr:            ":8082",
+				EnableLeaderElection: pointer.Bool(true),
+				ReconcileConcurrency: 1,
+				FeatureGates:         map[string]bool{"RandomPodDelete": true, "ZeroDowntimeUpgrade": false},
+			},
+			expectErr: false,
+		},
+		{
+			name: "config with requeue intervals",
+			configData: `apiVersion: config.ray.io/v1alpha1
+kind: Configuration
+requeue:
+  rayServiceSteadyStateInterval: 1m
+  errorBackoffMax: 10m
+`,
+			expectedConfig: configapi.Configuration{
+				TypeMeta: metav1.TypeMeta{
+					Kind:       "Configuration",
+					APIVersion: "config.ray.io/v1alpha1",
+				},
+				MetricsAddr:          ":8080",
+				ProbeAddr:            ":8082",
+				EnableLeaderElection: pointer.Bool(true),
+				ReconcileConcurrency: 1,
+				Requeue: &configapi.RequeueConfiguration{
+					RayServiceSteadyStateInterval: &metav1.Duration{Duration: time.Minute},
+					ErrorBackoffMax:               &metav1.Duration{Duration: 10 * time.Minute},
+				},
+			},
+			expectErr: false,
+		},
 		{
 			name: "unknown filed ignored",
 			configData: `apiVersion: config.ray.io/v1alpha1
@@ -189,3 +283,60 @@ reconcileConcurrency: true
 		})
 	}
 }
+
+func Test_newFeatureGates(t *testing.T) {
+	// The deprecated environment variables and config fields are applied before `featureGates`.
+	t.Setenv("ENABLE_RANDOM_POD_DELETE", "true")
+	t.Setenv("ENABLE_PROBES_INJECTION", "False")
+	t.Setenv("ENABLE_ZERO_DOWNTIME", "invalid")
+	featureGates, err := newFeatureGates(configapi.Configuration{
+		EnableBatchScheduler: true,
+		FeatureGates:         map[string]bool{"RandomPodDelete": false},
+	})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	expected := map[features.Feature]bool{
+		features.RandomPodDelete:        false,
+		features.ProbesInjection:        false,
+		features.ZeroDowntimeUpgrade:    true,
+		features.BatchScheduler:         true,
+		features.ForcedClusterUpgrade:   false,
+		features.InitContainerInjection: true,
+	}
+	for feature, enabled := range expected {
+		if featureGates.Enabled(feature) != enabled {
+			t.Errorf("expected %s to be %t", feature, enabled)
+		}
+	}
+
+	if _, err := newFeatureGates(configapi.Configuration{FeatureGates: map[string]bool{"UnknownFeature": true}}); err == nil {
+		t.Error("expected an error for an unknown feature gate")
+	}
+}
+
+func Test_newRayServiceReconcilerOptions(t *testing.T) {
+	options := newRayServiceReconcilerOptions(configapi.Configuration{}, nil)
+	if !reflect.DeepEqual(options, ray.RayServiceReconcilerOptions{}) {
+		t.Errorf("expected the reconciler defaults, got %v", options)
+	}
+
+	options = newRayServiceReconcilerOptions(configapi.Configuration{
+		Requeue: &configapi.RequeueConfiguration{
+			RayServiceInterval:      &metav1.Duration{Duration: 5 * time.Second},
+			RayJobInterval:          &metav1.Duration{Duration: 10 * time.Second},
+			RayClusterDeletionDelay: &metav1.Duration{Duration: time.Minute},
+			ErrorBackoffBase:        &metav1.Duration{Duration: time.Second},
+		},
+	}, nil)
+	expected := ray.RayServiceReconcilerOptions{
+		Requeue: ray.RequeueOptions{
+			Interval:         5 * time.Second,
+			ErrorBackoffBase: time.Second,
+		},
+		RayClusterDeletionDelay: time.Minute,
+	}
+	if !reflect.DeepEqual(options, expected) {
+		t.Errorf("expected %v, got %v", expected, options)
+	}
+}
@@ -0,0 +1,32 @@
+// Code generated by applyconfiguration-gen. DO NOT EDIT.
+
+package v1
+
+// FailedPodRetentionPolicyApplyConfiguration represents an declarative configuration of the FailedPodRetentionPolicy type for use
+// with apply.
+type FailedPodRetentionPolicyApplyConfiguration struct {
+	MaxPodsPerGroup *int32 `json:"maxPodsPerGroup,omitempty"`
+	TTLSeconds      *int32 `json:"ttlSeconds,omitempty"`
+}
+
+// FailedPodRetentionPolicyApplyConfiguration constructs an declarative configuration of the FailedPodRetentionPolicy type for use with
+// apply.
+func FailedPodRetentionPolicy() *FailedPodRetentionPolicyApplyConfiguration {
+	return &FailedPodRetentionPolicyApplyConfiguration{}
+}
+
+// WithMaxPodsPerGroup sets the MaxPodsPerGroup field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the MaxPodsPerGroup field is set to the value of the last call.
+func (b *FailedPodRetentionPolicyApplyConfiguration) WithMaxPodsPerGroup(value int32) *FailedPodRetentionPolicyApplyConfiguration {
+	b.MaxPodsPerGroup = &value
+	return b
+}
+
+// WithTTLSeconds sets the TTLSeconds field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the TTLSeconds field is set to the value of the last call.
+func (b *FailedPodRetentionPolicyApplyConfiguration) WithTTLSeconds(value int32) *FailedPodRetentionPolicyApplyConfiguration {
+	b.TTLSeconds = &value
+	return b
+}
@@ -0,0 +1,50 @@
+// Code generated by applyconfiguration-gen. DO NOT EDIT.
+
+package v1
+
+// RayClusterAutoSuspendApplyConfiguration represents an declarative configuration of the RayClusterAutoSuspend type for use
+// with apply.
+type RayClusterAutoSuspendApplyConfiguration struct {
+	IdleTTLSeconds  *int32  `json:"idleTTLSeconds,omitempty"`
+	SuspendSchedule *string `json:"suspendSchedule,omitempty"`
+	ResumeSchedule  *string `json:"resumeSchedule,omitempty"`
+	TimeZone        *string `json:"timeZone,omitempty"`
+}
+
+// RayClusterAutoSuspendApplyConfiguration constructs an declarative configuration of the RayClusterAutoSuspend type for use with
+// apply.
+func RayClusterAutoSuspend() *RayClusterAutoSuspendApplyConfiguration {
+	return &RayClusterAutoSuspendApplyConfiguration{}
+}
+
+// WithIdleTTLSeconds sets the IdleTTLSeconds field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the IdleTTLSeconds field is set to the value of the last call.
+func (b *RayClusterAutoSuspendApplyConfiguration) WithIdleTTLSeconds(value int32) *RayClusterAutoSuspendApplyConfiguration {
+	b.IdleTTLSeconds = &value
+	return b
+}
+
+// WithSuspendSchedule sets the SuspendSchedule field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the SuspendSchedule field is set to the value of the last call.
+func (b *RayClusterAutoSuspendApplyConfiguration) WithSuspendSchedule(value string) *RayClusterAutoSuspendApplyConfiguration {
+	b.SuspendSchedule = &value
+	return b
+}
+
+// WithResumeSchedule sets the ResumeSchedule field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the ResumeSchedule field is set to the value of the last call.
+func (b *RayClusterAutoSuspendApplyConfiguration) WithResumeSchedule(value string) *RayClusterAutoSuspendApplyConfiguration {
+	b.ResumeSchedule = &value
+	return b
+}
+
+// WithTimeZone sets the TimeZone field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the TimeZone field is set to the value of the last call.
+func (b *RayClusterAutoSuspendApplyConfiguration) WithTimeZone(value string) *RayClusterAutoSuspendApplyConfiguration {
+	b.TimeZone = &value
+	return b
+}
@@ -0,0 +1,32 @@
+// Code generated by applyconfiguration-gen. DO NOT EDIT.
+
+package v1
+
+// RayClusterFailureBudgetApplyConfiguration represents an declarative configuration of the RayClusterFailureBudget type for use
+// with apply.
+type RayClusterFailureBudgetApplyConfiguration struct {
+	MaxFailures   *int32 `json:"maxFailures,omitempty"`
+	WindowSeconds *int32 `json:"windowSeconds,omitempty"`
+}
+
+// RayClusterFailureBudgetApplyConfiguration constructs an declarative configuration of the RayClusterFailureBudget type for use with
+// apply.
+func RayClusterFailureBudget() *RayClusterFailureBudgetApplyConfiguration {
+	return &RayClusterFailureBudgetApplyConfiguration{}
+}
+
+// WithMaxFailures sets the MaxFailures field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the MaxFailures field is set to the value of the last call.
+func (b *RayClusterFailureBudgetApplyConfiguration) WithMaxFailures(value int32) *RayClusterFailureBudgetApplyConfiguration {
+	b.MaxFailures = &value
+	return b
+}
+
+// WithWindowSeconds sets the WindowSeconds field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the WindowSeconds field is set to the value of the last call.
+func (b *RayClusterFailureBudgetApplyConfiguration) WithWindowSeconds(value int32) *RayClusterFailureBudgetApplyConfiguration {
+	b.WindowSeconds = &value
+	return b
+}
@@ -0,0 +1,65 @@
+// Code generated by applyconfiguration-gen. DO NOT EDIT.
+
+package v1
+
+import (
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// RayClusterGroupFailuresApplyConfiguration represents an declarative configuration of the RayClusterGroupFailures type for use
+// with apply.
+type RayClusterGroupFailuresApplyConfiguration struct {
+	GroupName              *string   `json:"groupName,omitempty"`
+	FailureTimes           []v1.Time `json:"failureTimes,omitempty"`
+	LastTerminationMessage *string   `json:"lastTerminationMessage,omitempty"`
+	BudgetExceeded         *bool     `json:"budgetExceeded,omitempty"`
+	TemplateHash           *string   `json:"templateHash,omitempty"`
+}
+
+// RayClusterGroupFailuresApplyConfiguration constructs an declarative configuration of the RayClusterGroupFailures type for use with
+// apply.
+func RayClusterGroupFailures() *RayClusterGroupFailuresApplyConfiguration {
+	return &RayClusterGroupFailuresApplyConfiguration{}
+}
+
+// WithGroupName sets the GroupName field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the GroupName field is set to the value of the last call.
+func (b *RayClusterGroupFailuresApplyConfiguration) WithGroupName(value string) *RayClusterGroupFailuresApplyConfiguration {
+	b.GroupName = &value
+	return b
+}
+
+// WithFailureTimes adds the given value to the FailureTimes field in the declarative configuration
+// and returns the receiver, so that objects can be build by chaining "With" function invocations.
+// If called multiple times, values provided by each call will be appended to the FailureTimes field.
+func (b *RayClusterGroupFailuresApplyConfiguration) WithFailureTimes(values ...v1.Time) *RayClusterGroupFailuresApplyConfiguration {
+	for i := range values {
+		b.FailureTimes = append(b.FailureTimes, values[i])
+	}
+	return b
+}
+
+// WithLastTerminationMessage sets the LastTerminationMessage field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the LastTerminationMessage field is set to the value of the last call.
+func (b *RayClusterGroupFailuresApplyConfiguration) WithLastTerminationMessage(value string) *RayClusterGroupFailuresApplyConfiguration {
+	b.LastTerminationMessage = &value
+	return b
+}
+
+// WithBudgetExceeded sets the BudgetExceeded field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the BudgetExceeded field is set to the value of the last call.
+func (b *RayClusterGroupFailuresApplyConfiguration) WithBudgetExceeded(value bool) *RayClusterGroupFailuresApplyConfiguration {
+	b.BudgetExceeded = &value
+	return b
+}
+
+// WithTemplateHash sets the TemplateHash field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the TemplateHash field is set to the value of the last call.
+func (b *RayClusterGroupFailuresApplyConfiguration) WithTemplateHash(value string) *RayClusterGroupFailuresApplyConfiguration {
+	b.TemplateHash = &value
+	return b
+}
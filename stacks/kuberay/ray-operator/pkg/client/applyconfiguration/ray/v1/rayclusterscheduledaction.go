@@ -0,0 +1,46 @@
+// Code generated by applyconfiguration-gen. DO NOT EDIT.
+
+package v1
+
+import (
+	v1 "github.com/ray-project/kuberay/ray-operator/apis/ray/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// RayClusterScheduledActionApplyConfiguration represents an declarative configuration of the RayClusterScheduledAction type for use
+// with apply.
+type RayClusterScheduledActionApplyConfiguration struct {
+	Action *v1.RayClusterScheduledActionType `json:"action,omitempty"`
+	Time   *metav1.Time                      `json:"time,omitempty"`
+	Reason *string                           `json:"reason,omitempty"`
+}
+
+// RayClusterScheduledActionApplyConfiguration constructs an declarative configuration of the RayClusterScheduledAction type for use with
+// apply.
+func RayClusterScheduledAction() *RayClusterScheduledActionApplyConfiguration {
+	return &RayClusterScheduledActionApplyConfiguration{}
+}
+
+// WithAction sets the Action field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the Action field is set to the value of the last call.
+func (b *RayClusterScheduledActionApplyConfiguration) WithAction(value v1.RayClusterScheduledActionType) *RayClusterScheduledActionApplyConfiguration {
+	b.Action = &value
+	return b
+}
+
+// WithTime sets the Time field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the Time field is set to the value of the last call.
+func (b *RayClusterScheduledActionApplyConfiguration) WithTime(value metav1.Time) *RayClusterScheduledActionApplyConfiguration {
+	b.Time = &value
+	return b
+}
+
+// WithReason sets the Reason field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the Reason field is set to the value of the last call.
+func (b *RayClusterScheduledActionApplyConfiguration) WithReason(value string) *RayClusterScheduledActionApplyConfiguration {
+	b.Reason = &value
+	return b
+}
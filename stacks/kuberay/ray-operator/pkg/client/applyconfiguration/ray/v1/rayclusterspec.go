@@ -5,13 +5,17 @@ package v1
 // RayClusterSpecApplyConfiguration represents an declarative configuration of the RayClusterSpec type for use
 // with apply.
 type RayClusterSpecApplyConfiguration struct {
-	HeadGroupSpec           *HeadGroupSpecApplyConfiguration     `json:"headGroupSpec,omitempty"`
-	WorkerGroupSpecs        []WorkerGroupSpecApplyConfiguration  `json:"workerGroupSpecs,omitempty"`
-	RayVersion              *string                              `json:"rayVersion,omitempty"`
-	EnableInTreeAutoscaling *bool                                `json:"enableInTreeAutoscaling,omitempty"`
-	AutoscalerOptions       *AutoscalerOptionsApplyConfiguration `json:"autoscalerOptions,omitempty"`
-	HeadServiceAnnotations  map[string]string                    `json:"headServiceAnnotations,omitempty"`
-	Suspend                 *bool                                `json:"suspend,omitempty"`
+	HeadGroupSpec           *HeadGroupSpecApplyConfiguration             `json:"headGroupSpec,omitempty"`
+	WorkerGroupSpecs        []WorkerGroupSpecApplyConfiguration          `json:"workerGroupSpecs,omitempty"`
+	RayVersion              *string                                      `json:"rayVersion,omitempty"`
+	EnableInTreeAutoscaling *bool                                        `json:"enableInTreeAutoscaling,omitempty"`
+	AutoscalerOptions       *AutoscalerOptionsApplyConfiguration         `json:"autoscalerOptions,omitempty"`
+	HeadServiceAnnotations  map[string]string                            `json:"headServiceAnnotations,omitempty"`
+	Suspend                 *bool                                        `json:"suspend,omitempty"`
+	UpgradeStrategy         *RayClusterUpgradeStrategyApplyConfiguration `json:"upgradeStrategy,omitempty"`
+	FailedPodRetention      *FailedPodRetentionPolicyApplyConfiguration  `json:"failedPodRetention,omitempty"`
+	FailureBudget           *RayClusterFailureBudgetApplyConfiguration   `json:"failureBudget,omitempty"`
+	AutoSuspend             *RayClusterAutoSuspendApplyConfiguration     `json:"autoSuspend,omitempty"`
 }
 
 // RayClusterSpecApplyConfiguration constructs an declarative configuration of the RayClusterSpec type for use with
@@ -86,3 +90,35 @@ func (b *RayClusterSpecApplyConfiguration) WithSuspend(value bool) *RayClusterSp
 	b.Suspend = &value
 	return b
 }
+
+// WithUpgradeStrategy sets the UpgradeStrategy field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the UpgradeStrategy field is set to the value of the last call.
+func (b *RayClusterSpecApplyConfiguration) WithUpgradeStrategy(value *RayClusterUpgradeStrategyApplyConfiguration) *RayClusterSpecApplyConfiguration {
+	b.UpgradeStrategy = value
+	return b
+}
+
+// WithFailedPodRetention sets the FailedPodRetention field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the FailedPodRetention field is set to the value of the last call.
+func (b *RayClusterSpecApplyConfiguration) WithFailedPodRetention(value *FailedPodRetentionPolicyApplyConfiguration) *RayClusterSpecApplyConfiguration {
+	b.FailedPodRetention = value
+	return b
+}
+
+// WithFailureBudget sets the FailureBudget field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the FailureBudget field is set to the value of the last call.
+func (b *RayClusterSpecApplyConfiguration) WithFailureBudget(value *RayClusterFailureBudgetApplyConfiguration) *RayClusterSpecApplyConfiguration {
+	b.FailureBudget = value
+	return b
+}
+
+// WithAutoSuspend sets the AutoSuspend field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the AutoSuspend field is set to the value of the last call.
+func (b *RayClusterSpecApplyConfiguration) WithAutoSuspend(value *RayClusterAutoSuspendApplyConfiguration) *RayClusterSpecApplyConfiguration {
+	b.AutoSuspend = value
+	return b
+}
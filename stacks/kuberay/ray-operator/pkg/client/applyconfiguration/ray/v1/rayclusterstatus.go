@@ -11,20 +11,26 @@ import (
 // RayClusterStatusApplyConfiguration represents an declarative configuration of the RayClusterStatus type for use
 // with apply.
 type RayClusterStatusApplyConfiguration struct {
-	State                   *v1.ClusterState            `json:"state,omitempty"`
-	AvailableWorkerReplicas *int32                      `json:"availableWorkerReplicas,omitempty"`
-	DesiredWorkerReplicas   *int32                      `json:"desiredWorkerReplicas,omitempty"`
-	MinWorkerReplicas       *int32                      `json:"minWorkerReplicas,omitempty"`
-	MaxWorkerReplicas       *int32                      `json:"maxWorkerReplicas,omitempty"`
-	DesiredCPU              *resource.Quantity          `json:"desiredCPU,omitempty"`
-	DesiredMemory           *resource.Quantity          `json:"desiredMemory,omitempty"`
-	DesiredGPU              *resource.Quantity          `json:"desiredGPU,omitempty"`
-	DesiredTPU              *resource.Quantity          `json:"desiredTPU,omitempty"`
-	LastUpdateTime          *metav1.Time                `json:"lastUpdateTime,omitempty"`
-	Endpoints               map[string]string           `json:"endpoints,omitempty"`
-	Head                    *HeadInfoApplyConfiguration `json:"head,omitempty"`
-	Reason                  *string                     `json:"reason,omitempty"`
-	ObservedGeneration      *int64                      `json:"observedGeneration,omitempty"`
+	State                   *v1.ClusterState                             `json:"state,omitempty"`
+	AvailableWorkerReplicas *int32                                       `json:"availableWorkerReplicas,omitempty"`
+	DesiredWorkerReplicas   *int32                                       `json:"desiredWorkerReplicas,omitempty"`
+	UpdatedWorkerReplicas   *int32                                       `json:"updatedWorkerReplicas,omitempty"`
+	MinWorkerReplicas       *int32                                       `json:"minWorkerReplicas,omitempty"`
+	MaxWorkerReplicas       *int32                                       `json:"maxWorkerReplicas,omitempty"`
+	DesiredCPU              *resource.Quantity                           `json:"desiredCPU,omitempty"`
+	DesiredMemory           *resource.Quantity                           `json:"desiredMemory,omitempty"`
+	DesiredGPU              *resource.Quantity                           `json:"desiredGPU,omitempty"`
+	DesiredTPU              *resource.Quantity                           `json:"desiredTPU,omitempty"`
+	LastUpdateTime          *metav1.Time                                 `json:"lastUpdateTime,omitempty"`
+	Endpoints               map[string]string                            `json:"endpoints,omitempty"`
+	Head                    *HeadInfoApplyConfiguration                  `json:"head,omitempty"`
+	Reason                  *string                                      `json:"reason,omitempty"`
+	ObservedGeneration      *int64                                       `json:"observedGeneration,omitempty"`
+	Conditions              []metav1.Condition                           `json:"conditions,omitempty"`
+	GroupFailures           []RayClusterGroupFailuresApplyConfiguration  `json:"groupFailures,omitempty"`
+	LastActivityTime        *metav1.Time                                 `json:"lastActivityTime,omitempty"`
+	LastScheduleTime        *metav1.Time                                 `json:"lastScheduleTime,omitempty"`
+	NextScheduledAction     *RayClusterScheduledActionApplyConfiguration `json:"nextScheduledAction,omitempty"`
 }
 
 // RayClusterStatusApplyConfiguration constructs an declarative configuration of the RayClusterStatus type for use with
@@ -57,6 +63,14 @@ func (b *RayClusterStatusApplyConfiguration) WithDesiredWorkerReplicas(value int
 	return b
 }
 
+// WithUpdatedWorkerReplicas sets the UpdatedWorkerReplicas field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the UpdatedWorkerReplicas field is set to the value of the last call.
+func (b *RayClusterStatusApplyConfiguration) WithUpdatedWorkerReplicas(value int32) *RayClusterStatusApplyConfiguration {
+	b.UpdatedWorkerReplicas = &value
+	return b
+}
+
 // WithMinWorkerReplicas sets the MinWorkerReplicas field in the declarative configuration to the given value
 // and returns the receiver, so that objects can be built by chaining "With" function invocations.
 // If called multiple times, the MinWorkerReplicas field is set to the value of the last call.
@@ -150,3 +164,50 @@ func (b *RayClusterStatusApplyConfiguration) WithObservedGeneration(value int64)
 	b.ObservedGeneration = &value
 	return b
 }
+
+// WithConditions adds the given value to the Conditions field in the declarative configuration
+// and returns the receiver, so that objects can be build by chaining "With" function invocations.
+// If called multiple times, values provided by each call will be appended to the Conditions field.
+func (b *RayClusterStatusApplyConfiguration) WithConditions(values ...metav1.Condition) *RayClusterStatusApplyConfiguration {
+	for i := range values {
+		b.Conditions = append(b.Conditions, values[i])
+	}
+	return b
+}
+
+// WithGroupFailures adds the given value to the GroupFailures field in the declarative configuration
+// and returns the receiver, so that objects can be build by chaining "With" function invocations.
+// If called multiple times, values provided by each call will be appended to the GroupFailures field.
+func (b *RayClusterStatusApplyConfiguration) WithGroupFailures(values ...*RayClusterGroupFailuresApplyConfiguration) *RayClusterStatusApplyConfiguration {
+	for i := range values {
+		if values[i] == nil {
+			panic("nil value passed to WithGroupFailures")
+		}
+		b.GroupFailures = append(b.GroupFailures, *values[i])
+	}
+	return b
+}
+
+// WithLastActivityTime sets the LastActivityTime field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the LastActivityTime field is set to the value of the last call.
+func (b *RayClusterStatusApplyConfiguration) WithLastActivityTime(value metav1.Time) *RayClusterStatusApplyConfiguration {
+	b.LastActivityTime = &value
+	return b
+}
+
+// WithLastScheduleTime sets the LastScheduleTime field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the LastScheduleTime field is set to the value of the last call.
+func (b *RayClusterStatusApplyConfiguration) WithLastScheduleTime(value metav1.Time) *RayClusterStatusApplyConfiguration {
+	b.LastScheduleTime = &value
+	return b
+}
+
+// WithNextScheduledAction sets the NextScheduledAction field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the NextScheduledAction field is set to the value of the last call.
+func (b *RayClusterStatusApplyConfiguration) WithNextScheduledAction(value *RayClusterScheduledActionApplyConfiguration) *RayClusterStatusApplyConfiguration {
+	b.NextScheduledAction = value
+	return b
+}
@@ -24,6 +24,9 @@ type RayJobSpecApplyConfiguration struct {
 	EntrypointNumCpus        *float32                                  `json:"entrypointNumCpus,omitempty"`
 	EntrypointNumGpus        *float32                                  `json:"entrypointNumGpus,omitempty"`
 	EntrypointResources      *string                                   `json:"entrypointResources,omitempty"`
+	BackoffLimit             *int32                                    `json:"backoffLimit,omitempty"`
+	RetryPolicy              *rayv1.RayJobRetryPolicy                  `json:"retryPolicy,omitempty"`
+	ActiveDeadlineSeconds    *int32                                    `json:"activeDeadlineSeconds,omitempty"`
 }
 
 // RayJobSpecApplyConfiguration constructs an declarative configuration of the RayJobSpec type for use with
@@ -155,3 +158,27 @@ func (b *RayJobSpecApplyConfiguration) WithEntrypointResources(value string) *Ra
 	b.EntrypointResources = &value
 	return b
 }
+
+// WithBackoffLimit sets the BackoffLimit field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the BackoffLimit field is set to the value of the last call.
+func (b *RayJobSpecApplyConfiguration) WithBackoffLimit(value int32) *RayJobSpecApplyConfiguration {
+	b.BackoffLimit = &value
+	return b
+}
+
+// WithRetryPolicy sets the RetryPolicy field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the RetryPolicy field is set to the value of the last call.
+func (b *RayJobSpecApplyConfiguration) WithRetryPolicy(value rayv1.RayJobRetryPolicy) *RayJobSpecApplyConfiguration {
+	b.RetryPolicy = &value
+	return b
+}
+
+// WithActiveDeadlineSeconds sets the ActiveDeadlineSeconds field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the ActiveDeadlineSeconds field is set to the value of the last call.
+func (b *RayJobSpecApplyConfiguration) WithActiveDeadlineSeconds(value int32) *RayJobSpecApplyConfiguration {
+	b.ActiveDeadlineSeconds = &value
+	return b
+}
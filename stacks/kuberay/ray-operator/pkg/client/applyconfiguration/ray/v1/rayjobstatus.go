@@ -20,6 +20,9 @@ type RayJobStatusApplyConfiguration struct {
 	EndTime             *metav1.Time                        `json:"endTime,omitempty"`
 	RayClusterStatus    *RayClusterStatusApplyConfiguration `json:"rayClusterStatus,omitempty"`
 	ObservedGeneration  *int64                              `json:"observedGeneration,omitempty"`
+	Failed              *int32                              `json:"failed,omitempty"`
+	Reason              *v1.JobFailedReason                 `json:"reason,omitempty"`
+	Conditions          []metav1.Condition                  `json:"conditions,omitempty"`
 }
 
 // RayJobStatusApplyConfiguration constructs an declarative configuration of the RayJobStatus type for use with
@@ -107,3 +110,29 @@ func (b *RayJobStatusApplyConfiguration) WithObservedGeneration(value int64) *Ra
 	b.ObservedGeneration = &value
 	return b
 }
+
+// WithFailed sets the Failed field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the Failed field is set to the value of the last call.
+func (b *RayJobStatusApplyConfiguration) WithFailed(value int32) *RayJobStatusApplyConfiguration {
+	b.Failed = &value
+	return b
+}
+
+// WithReason sets the Reason field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the Reason field is set to the value of the last call.
+func (b *RayJobStatusApplyConfiguration) WithReason(value v1.JobFailedReason) *RayJobStatusApplyConfiguration {
+	b.Reason = &value
+	return b
+}
+
+// WithConditions adds the given value to the Conditions field in the declarative configuration
+// and returns the receiver, so that objects can be build by chaining "With" function invocations.
+// If called multiple times, values provided by each call will be appended to the Conditions field.
+func (b *RayJobStatusApplyConfiguration) WithConditions(values ...metav1.Condition) *RayJobStatusApplyConfiguration {
+	for i := range values {
+		b.Conditions = append(b.Conditions, values[i])
+	}
+	return b
+}
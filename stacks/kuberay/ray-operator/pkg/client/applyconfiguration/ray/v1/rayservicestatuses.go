@@ -15,6 +15,7 @@ type RayServiceStatusesApplyConfiguration struct {
 	ServiceStatus        *rayv1.ServiceStatus                `json:"serviceStatus,omitempty"`
 	ObservedGeneration   *int64                              `json:"observedGeneration,omitempty"`
 	LastUpdateTime       *metav1.Time                        `json:"lastUpdateTime,omitempty"`
+	Conditions           []metav1.Condition                  `json:"conditions,omitempty"`
 }
 
 // RayServiceStatusesApplyConfiguration constructs an declarative configuration of the RayServiceStatuses type for use with
@@ -62,3 +63,13 @@ func (b *RayServiceStatusesApplyConfiguration) WithLastUpdateTime(value metav1.T
 	b.LastUpdateTime = &value
 	return b
 }
+
+// WithConditions adds the given value to the Conditions field in the declarative configuration
+// and returns the receiver, so that objects can be build by chaining "With" function invocations.
+// If called multiple times, values provided by each call will be appended to the Conditions field.
+func (b *RayServiceStatusesApplyConfiguration) WithConditions(values ...metav1.Condition) *RayServiceStatusesApplyConfiguration {
+	for i := range values {
+		b.Conditions = append(b.Conditions, values[i])
+	}
+	return b
+}
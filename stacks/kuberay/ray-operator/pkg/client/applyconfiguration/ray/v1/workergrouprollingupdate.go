@@ -0,0 +1,36 @@
+// Code generated by applyconfiguration-gen. DO NOT EDIT.
+
+package v1
+
+import (
+	intstr "k8s.io/apimachinery/pkg/util/intstr"
+)
+
+// WorkerGroupRollingUpdateApplyConfiguration represents an declarative configuration of the WorkerGroupRollingUpdate type for use
+// with apply.
+type WorkerGroupRollingUpdateApplyConfiguration struct {
+	MaxUnavailable *intstr.IntOrString `json:"maxUnavailable,omitempty"`
+	MaxSurge       *intstr.IntOrString `json:"maxSurge,omitempty"`
+}
+
+// WorkerGroupRollingUpdateApplyConfiguration constructs an declarative configuration of the WorkerGroupRollingUpdate type for use with
+// apply.
+func WorkerGroupRollingUpdate() *WorkerGroupRollingUpdateApplyConfiguration {
+	return &WorkerGroupRollingUpdateApplyConfiguration{}
+}
+
+// WithMaxUnavailable sets the MaxUnavailable field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the MaxUnavailable field is set to the value of the last call.
+func (b *WorkerGroupRollingUpdateApplyConfiguration) WithMaxUnavailable(value intstr.IntOrString) *WorkerGroupRollingUpdateApplyConfiguration {
+	b.MaxUnavailable = &value
+	return b
+}
+
+// WithMaxSurge sets the MaxSurge field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the MaxSurge field is set to the value of the last call.
+func (b *WorkerGroupRollingUpdateApplyConfiguration) WithMaxSurge(value intstr.IntOrString) *WorkerGroupRollingUpdateApplyConfiguration {
+	b.MaxSurge = &value
+	return b
+}
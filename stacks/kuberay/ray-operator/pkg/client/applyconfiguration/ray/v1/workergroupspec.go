@@ -9,14 +9,15 @@ import (
 // WorkerGroupSpecApplyConfiguration represents an declarative configuration of the WorkerGroupSpec type for use
 // with apply.
 type WorkerGroupSpecApplyConfiguration struct {
-	GroupName      *string                               `json:"groupName,omitempty"`
-	Replicas       *int32                                `json:"replicas,omitempty"`
-	MinReplicas    *int32                                `json:"minReplicas,omitempty"`
-	MaxReplicas    *int32                                `json:"maxReplicas,omitempty"`
-	NumOfHosts     *int32                                `json:"numOfHosts,omitempty"`
-	RayStartParams map[string]string                     `json:"rayStartParams,omitempty"`
-	Template       *v1.PodTemplateSpecApplyConfiguration `json:"template,omitempty"`
-	ScaleStrategy  *ScaleStrategyApplyConfiguration      `json:"scaleStrategy,omitempty"`
+	GroupName      *string                                     `json:"groupName,omitempty"`
+	Replicas       *int32                                      `json:"replicas,omitempty"`
+	MinReplicas    *int32                                      `json:"minReplicas,omitempty"`
+	MaxReplicas    *int32                                      `json:"maxReplicas,omitempty"`
+	NumOfHosts     *int32                                      `json:"numOfHosts,omitempty"`
+	RayStartParams map[string]string                           `json:"rayStartParams,omitempty"`
+	Template       *v1.PodTemplateSpecApplyConfiguration       `json:"template,omitempty"`
+	ScaleStrategy  *ScaleStrategyApplyConfiguration            `json:"scaleStrategy,omitempty"`
+	RollingUpdate  *WorkerGroupRollingUpdateApplyConfiguration `json:"rollingUpdate,omitempty"`
 }
 
 // WorkerGroupSpecApplyConfiguration constructs an declarative configuration of the WorkerGroupSpec type for use with
@@ -94,3 +95,11 @@ func (b *WorkerGroupSpecApplyConfiguration) WithScaleStrategy(value *ScaleStrate
 	b.ScaleStrategy = value
 	return b
 }
+
+// WithRollingUpdate sets the RollingUpdate field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the RollingUpdate field is set to the value of the last call.
+func (b *WorkerGroupSpecApplyConfiguration) WithRollingUpdate(value *WorkerGroupRollingUpdateApplyConfiguration) *WorkerGroupSpecApplyConfiguration {
+	b.RollingUpdate = value
+	return b
+}
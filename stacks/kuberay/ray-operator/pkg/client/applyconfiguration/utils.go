@@ -17,16 +17,28 @@ func ForKind(kind schema.GroupVersionKind) interface{} {
 		return &rayv1.AppStatusApplyConfiguration{}
 	case v1.SchemeGroupVersion.WithKind("AutoscalerOptions"):
 		return &rayv1.AutoscalerOptionsApplyConfiguration{}
+	case v1.SchemeGroupVersion.WithKind("FailedPodRetentionPolicy"):
+		return &rayv1.FailedPodRetentionPolicyApplyConfiguration{}
 	case v1.SchemeGroupVersion.WithKind("HeadGroupSpec"):
 		return &rayv1.HeadGroupSpecApplyConfiguration{}
 	case v1.SchemeGroupVersion.WithKind("HeadInfo"):
 		return &rayv1.HeadInfoApplyConfiguration{}
 	case v1.SchemeGroupVersion.WithKind("RayCluster"):
 		return &rayv1.RayClusterApplyConfiguration{}
+	case v1.SchemeGroupVersion.WithKind("RayClusterAutoSuspend"):
+		return &rayv1.RayClusterAutoSuspendApplyConfiguration{}
+	case v1.SchemeGroupVersion.WithKind("RayClusterFailureBudget"):
+		return &rayv1.RayClusterFailureBudgetApplyConfiguration{}
+	case v1.SchemeGroupVersion.WithKind("RayClusterGroupFailures"):
+		return &rayv1.RayClusterGroupFailuresApplyConfiguration{}
+	case v1.SchemeGroupVersion.WithKind("RayClusterScheduledAction"):
+		return &rayv1.RayClusterScheduledActionApplyConfiguration{}
 	case v1.SchemeGroupVersion.WithKind("RayClusterSpec"):
 		return &rayv1.RayClusterSpecApplyConfiguration{}
 	case v1.SchemeGroupVersion.WithKind("RayClusterStatus"):
 		return &rayv1.RayClusterStatusApplyConfiguration{}
+	case v1.SchemeGroupVersion.WithKind("RayClusterUpgradeStrategy"):
+		return &rayv1.RayClusterUpgradeStrategyApplyConfiguration{}
 	case v1.SchemeGroupVersion.WithKind("RayJob"):
 		return &rayv1.RayJobApplyConfiguration{}
 	case v1.SchemeGroupVersion.WithKind("RayJobSpec"):
@@ -45,6 +57,8 @@ func ForKind(kind schema.GroupVersionKind) interface{} {
 		return &rayv1.ScaleStrategyApplyConfiguration{}
 	case v1.SchemeGroupVersion.WithKind("ServeDeploymentStatus"):
 		return &rayv1.ServeDeploymentStatusApplyConfiguration{}
+	case v1.SchemeGroupVersion.WithKind("WorkerGroupRollingUpdate"):
+		return &rayv1.WorkerGroupRollingUpdateApplyConfiguration{}
 	case v1.SchemeGroupVersion.WithKind("WorkerGroupSpec"):
 		return &rayv1.WorkerGroupSpecApplyConfiguration{}
 
@@ -0,0 +1,171 @@
+// Package features defines the feature gates of the KubeRay operator.
+//
+// The operator-wide feature gates are configured with the `featureGates` field of the Configuration or the
+// `--feature-gates` flag. A custom resource can override them for itself with the `ray.io/feature-gates`
+// annotation, e.g. `ray.io/feature-gates: "RandomPodDelete=true,ZeroDowntimeUpgrade=false"`, except for the
+// features which configure the operator itself, such as BatchScheduler.
+package features
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Feature is the name of a feature gate.
+type Feature string
+
+const (
+	// ForcedClusterUpgrade uses the Recreate upgrade strategy for RayClusters which do not set spec.upgradeStrategy.
+	ForcedClusterUpgrade Feature = "ForcedClusterUpgrade"
+
+	// BatchScheduler enables the batch scheduler integration. Currently this is supported by Volcano to support gang
+	// scheduling, and by Kueue to admit RayClusters and RayJobs with quotas.
+	BatchScheduler Feature = "BatchScheduler"
+
+	// RandomPodDelete allows the KubeRay operator to delete random worker Pods when it scales down a worker group of a
+	// RayCluster with the Autoscaler enabled. It is always enabled for RayClusters without the Autoscaler.
+	RandomPodDelete Feature = "RandomPodDelete"
+
+	// InitContainerInjection injects an init container into worker Pods that waits for the GCS server to be ready.
+	InitContainerInjection Feature = "InitContainerInjection"
+
+	// ProbesInjection injects readiness and liveness probes into the Ray containers.
+	ProbesInjection Feature = "ProbesInjection"
+
+	// ZeroDowntimeUpgrade prepares a new RayCluster before switching traffic when the RayCluster spec of a RayService changes.
+	ZeroDowntimeUpgrade Feature = "ZeroDowntimeUpgrade"
+
+	// GCSFaultToleranceRedisCleanup cleans up the Redis storage namespace when a RayCluster with GCS fault tolerance is deleted.
+	GCSFaultToleranceRedisCleanup Feature = "GCSFaultToleranceRedisCleanup"
+)
+
+// defaultFeatureGates are the features and whether they are enabled by default.
+var defaultFeatureGates = map[Feature]bool{
+	ForcedClusterUpgrade:          false,
+	BatchScheduler:                false,
+	RandomPodDelete:               false,
+	InitContainerInjection:        true,
+	ProbesInjection:               true,
+	ZeroDowntimeUpgrade:           true,
+	GCSFaultToleranceRedisCleanup: true,
+}
+
+// operatorFeatureGates are the features which can only be configured for the whole operator, because the controllers
+// are set up for them when the operator starts, e.g. the watches of the batch scheduler.
+var operatorFeatureGates = map[Feature]bool{
+	BatchScheduler: true,
+}
+
+// FeatureGates reports whether features are enabled. A nil *FeatureGates reports the defaults.
+type FeatureGates struct {
+	enabled map[Feature]bool
+}
+
+// NewFeatureGates returns the default feature gates with the given features overridden.
+func NewFeatureGates(overrides map[string]bool) (*FeatureGates, error) {
+	var featureGates *FeatureGates
+	return featureGates.with(overrides)
+}
+
+// Enabled reports whether a feature is enabled.
+func (g *FeatureGates) Enabled(feature Feature) bool {
+	if g == nil {
+		return defaultFeatureGates[feature]
+	}
+	return g.enabled[feature]
+}
+
+// WithOverrides returns the feature gates with the features in a comma-separated list of `Feature=bool` pairs
+// overridden, such as the value of the `ray.io/feature-gates` annotation. If the list is invalid, the feature gates
+// are returned unchanged along with the error. The features which configure the operator itself can not be overridden.
+func (g *FeatureGates) WithOverrides(overrides string) (*FeatureGates, error) {
+	if overrides == "" {
+		return g, nil
+	}
+	parsed, err := ParseFeatureGates(overrides)
+	if err != nil {
+		return g, err
+	}
+	for name := range parsed {
+		if operatorFeatureGates[Feature(name)] {
+			return g, fmt.Errorf("feature gate %q can only be configured for the whole operator", name)
+		}
+	}
+	featureGates, err := g.with(parsed)
+	if err != nil {
+		return g, err
+	}
+	return featureGates, nil
+}
+
+// String returns the feature gates as a sorted, comma-separated list of `Feature=bool` pairs.
+func (g *FeatureGates) String() string {
+	pairs := make([]string, 0, len(defaultFeatureGates))
+	for feature := range defaultFeatureGates {
+		pairs = append(pairs, fmt.Sprintf("%s=%t", feature, g.Enabled(feature)))
+	}
+	sort.Strings(pairs)
+	return strings.Join(pairs, ",")
+}
+
+func (g *FeatureGates) with(overrides map[string]bool) (*FeatureGates, error) {
+	enabled := make(map[Feature]bool, len(defaultFeatureGates))
+	for feature := range defaultFeatureGates {
+		enabled[feature] = g.Enabled(feature)
+	}
+	for name, value := range overrides {
+		feature := Feature(name)
+		if _, ok := defaultFeatureGates[feature]; !ok {
+			return nil, fmt.Errorf("unknown feature gate %q", name)
+		}
+		enabled[feature] = value
+	}
+	return &FeatureGates{enabled: enabled}, nil
+}
+
+// ParseFeatureGates parses a comma-separated list of `Feature=bool` pairs.
+func ParseFeatureGates(s string) (map[string]bool, error) {
+	overrides := make(map[string]bool)
+	for _, pair := range strings.Split(s, ",") {
+		pair = strings.TrimSpace(pair)
+		if pair == "" {
+			continue
+		}
+		name, value, found := strings.Cut(pair, "=")
+		if !found {
+			return nil, fmt.Errorf("missing bool value for feature gate %q", pair)
+		}
+		name = strings.TrimSpace(name)
+		enabled, err := strconv.ParseBool(strings.TrimSpace(value))
+		if err != nil {
+			return nil, fmt.Errorf("invalid value %q for feature gate %q", value, name)
+		}
+		if _, ok := defaultFeatureGates[Feature(name)]; !ok {
+			return nil, fmt.Errorf("unknown feature gate %q", name)
+		}
+		overrides[name] = enabled
+	}
+	return overrides, nil
+}
+
+type contextKey struct{}
+
+// IntoContext returns a copy of ctx with the feature gates of the reconciled custom resource.
+func IntoContext(ctx context.Context, featureGates *FeatureGates) context.Context {
+	return context.WithValue(ctx, contextKey{}, featureGates)
+}
+
+// FromContext returns the feature gates stored in ctx, or nil if there are none.
+func FromContext(ctx context.Context) *FeatureGates {
+	featureGates, _ := ctx.Value(contextKey{}).(*FeatureGates)
+	return featureGates
+}
+
+// Enabled reports whether a feature is enabled for the custom resource reconciled with ctx. It reports the default
+// if ctx has no feature gates.
+func Enabled(ctx context.Context, feature Feature) bool {
+	return FromContext(ctx).Enabled(feature)
+}
@@ -0,0 +1,72 @@
+package features
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewFeatureGates(t *testing.T) {
+	featureGates, err := NewFeatureGates(nil)
+	assert.Nil(t, err)
+	for feature, enabled := range defaultFeatureGates {
+		assert.Equal(t, enabled, featureGates.Enabled(feature), feature)
+	}
+
+	featureGates, err = NewFeatureGates(map[string]bool{string(RandomPodDelete): true, string(ZeroDowntimeUpgrade): false})
+	assert.Nil(t, err)
+	assert.True(t, featureGates.Enabled(RandomPodDelete))
+	assert.False(t, featureGates.Enabled(ZeroDowntimeUpgrade))
+	assert.True(t, featureGates.Enabled(ProbesInjection))
+
+	_, err = NewFeatureGates(map[string]bool{"UnknownFeature": true})
+	assert.ErrorContains(t, err, "unknown feature gate")
+}
+
+func TestWithOverrides(t *testing.T) {
+	featureGates, err := NewFeatureGates(map[string]bool{string(BatchScheduler): true})
+	assert.Nil(t, err)
+
+	overridden, err := featureGates.WithOverrides(" RandomPodDelete=true, ZeroDowntimeUpgrade=false ")
+	assert.Nil(t, err)
+	assert.True(t, overridden.Enabled(RandomPodDelete))
+	assert.False(t, overridden.Enabled(ZeroDowntimeUpgrade))
+	assert.True(t, overridden.Enabled(BatchScheduler))
+	// The overrides do not modify the original feature gates.
+	assert.False(t, featureGates.Enabled(RandomPodDelete))
+	assert.True(t, featureGates.Enabled(ZeroDowntimeUpgrade))
+
+	// BatchScheduler requires the batch scheduler watches, which are only set up for the whole operator.
+	for _, overrides := range []string{"RandomPodDelete", "RandomPodDelete=yes", "UnknownFeature=true", "BatchScheduler=true", "RandomPodDelete=true,BatchScheduler=false"} {
+		unchanged, err := featureGates.WithOverrides(overrides)
+		assert.NotNil(t, err, overrides)
+		assert.Same(t, featureGates, unchanged, overrides)
+	}
+
+	// A nil *FeatureGates reports the defaults.
+	var defaults *FeatureGates
+	assert.True(t, defaults.Enabled(InitContainerInjection))
+	overridden, err = defaults.WithOverrides("InitContainerInjection=false")
+	assert.Nil(t, err)
+	assert.False(t, overridden.Enabled(InitContainerInjection))
+}
+
+func TestFeatureGatesString(t *testing.T) {
+	featureGates, err := NewFeatureGates(map[string]bool{string(RandomPodDelete): true})
+	assert.Nil(t, err)
+	assert.Equal(t, "BatchScheduler=false,ForcedClusterUpgrade=false,GCSFaultToleranceRedisCleanup=true,InitContainerInjection=true,"+
+		"ProbesInjection=true,RandomPodDelete=true,ZeroDowntimeUpgrade=true", featureGates.String())
+}
+
+func TestContext(t *testing.T) {
+	ctx := context.Background()
+	assert.Nil(t, FromContext(ctx))
+	assert.True(t, Enabled(ctx, ProbesInjection))
+
+	featureGates, err := NewFeatureGates(map[string]bool{string(ProbesInjection): false})
+	assert.Nil(t, err)
+	ctx = IntoContext(ctx, featureGates)
+	assert.Same(t, featureGates, FromContext(ctx))
+	assert.False(t, Enabled(ctx, ProbesInjection))
+}
@@ -97,9 +97,11 @@ env_vars:
 		test.Expect(GetRayJob(test, rayJob.Namespace, rayJob.Name)).
 			To(WithTransform(RayJobStatus, Equal(rayv1.JobStatusFailed)))
 
-		// And the RayJob deployment status is updated accordingly
+		// And the RayJob deployment status is updated accordingly. The RayJob is not retried because `BackoffLimit` is not set.
 		test.Eventually(RayJob(test, rayJob.Namespace, rayJob.Name)).
-			Should(WithTransform(RayJobDeploymentStatus, Equal(rayv1.JobDeploymentStatusComplete)))
+			Should(WithTransform(RayJobDeploymentStatus, Equal(rayv1.JobDeploymentStatusFailed)))
+		test.Expect(GetRayJob(test, rayJob.Namespace, rayJob.Name)).
+			To(WithTransform(RayJobReason, Equal(rayv1.AppFailed)))
 
 		// In the lightweight submission mode, the submitter Kubernetes Job should not be created.
 		test.Eventually(Jobs(test, namespace.Name)).Should(BeEmpty())